@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage Admin Console API tokens",
+		Long: `Manage API tokens for automation. Each token is granted one or more roles and expires at a fixed time.
+Use a token by setting the "Authorization: Bearer <token>" header on requests to the Admin Console API.
+
+Examples:
+kubectl kots admin-console token create --name ci --role cluster-admin --expires-in 720h -n default
+kubectl kots admin-console token ls -n default
+kubectl kots admin-console token revoke <token id> -n default`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			os.Exit(1)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(AdminConsoleTokenCreateCmd())
+	cmd.AddCommand(AdminConsoleTokenListCmd())
+	cmd.AddCommand(AdminConsoleTokenRevokeCmd())
+
+	return cmd
+}
+
+func AdminConsoleTokenCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an Admin Console API token",
+		Long: `Create an Admin Console API token with one or more roles.
+The token is only printed once and can't be retrieved again.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			name := v.GetString("name")
+			if name == "" {
+				return errors.New("--name is required")
+			}
+
+			roleIDs := v.GetStringSlice("role")
+			if len(roleIDs) == 0 {
+				return errors.New("at least one --role is required")
+			}
+
+			expiresIn := v.GetDuration("expires-in")
+			if expiresIn <= 0 {
+				return errors.New("--expires-in must be greater than zero")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			body, err := json.Marshal(map[string]interface{}{
+				"name":      name,
+				"roleIds":   roleIDs,
+				"expiresAt": time.Now().Add(expiresIn),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request")
+			}
+
+			response := struct {
+				APIToken *apitokentypes.APIToken `json:"apiToken"`
+				Token    string                  `json:"token"`
+			}{}
+			tokensURL := fmt.Sprintf("http://localhost:%d/api/v1/tokens", localPort)
+			if err := sendJSONWithResponse("POST", tokensURL, authSlug, body, &response); err != nil {
+				return errors.Wrap(err, "failed to create token")
+			}
+
+			log.ActionWithoutSpinner("Token %s has been created and expires at %s", response.APIToken.ID, response.APIToken.ExpiresAt.Format(time.RFC3339))
+			log.ActionWithoutSpinner("Store it securely, it will not be shown again:")
+			fmt.Println(response.Token)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("name", "", "a name describing what the token is used for")
+	cmd.Flags().StringSlice("role", []string{}, "role to grant the token. may be specified multiple times")
+	cmd.Flags().Duration("expires-in", 30*24*time.Hour, "how long the token is valid for, at most one year")
+
+	return cmd
+}
+
+func AdminConsoleTokenListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ls",
+		Aliases:       []string{"list"},
+		Short:         "List Admin Console API tokens",
+		Long:          `List Admin Console API tokens, including expired and revoked tokens`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			response := struct {
+				Tokens []*apitokentypes.APIToken `json:"tokens"`
+			}{}
+			tokensURL := fmt.Sprintf("http://localhost:%d/api/v1/tokens", localPort)
+			if err := getJSON(tokensURL, authSlug, &response); err != nil {
+				return errors.Wrap(err, "failed to list tokens")
+			}
+
+			print.APITokens(response.Tokens, v.GetString("output"))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
+
+func AdminConsoleTokenRevokeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "revoke [token id]",
+		Short:         "Revoke an Admin Console API token",
+		Long:          `Revoke an Admin Console API token. Requests using the token are rejected immediately.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			tokenID := args[0]
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			tokenURL := fmt.Sprintf("http://localhost:%d/api/v1/tokens/%s", localPort, url.PathEscape(tokenID))
+			if err := sendJSON("DELETE", tokenURL, authSlug, nil); err != nil {
+				return errors.Wrap(err, "failed to revoke token")
+			}
+
+			log.ActionWithoutSpinner("Token %s has been revoked", tokenID)
+			return nil
+		},
+	}
+
+	return cmd
+}
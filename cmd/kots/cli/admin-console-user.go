@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/print"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage Admin Console user accounts",
+		Long: `Manage named Admin Console user accounts. Each user logs in with their own password and is granted the permissions of their roles.
+
+Examples:
+kubectl kots admin-console user add alice --role support -n default
+kubectl kots admin-console user set-roles alice --role cluster-admin -n default
+kubectl kots admin-console user remove alice -n default`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			os.Exit(1)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(AdminConsoleUserListCmd())
+	cmd.AddCommand(AdminConsoleUserAddCmd())
+	cmd.AddCommand(AdminConsoleUserRemoveCmd())
+	cmd.AddCommand(AdminConsoleUserSetRolesCmd())
+	cmd.AddCommand(AdminConsoleUserResetPasswordCmd())
+
+	return cmd
+}
+
+func AdminConsoleUserListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ls",
+		Aliases:       []string{"list"},
+		Short:         "List Admin Console users",
+		Long:          `List Admin Console users`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			response := struct {
+				Users []*usertypes.LocalUser `json:"users"`
+			}{}
+			usersURL := fmt.Sprintf("http://localhost:%d/api/v1/users", localPort)
+			if err := getJSON(usersURL, authSlug, &response); err != nil {
+				return errors.Wrap(err, "failed to list users")
+			}
+
+			print.LocalUsers(response.Users, v.GetString("output"))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
+
+func AdminConsoleUserAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [username]",
+		Short: "Add an Admin Console user",
+		Long: `Add an Admin Console user with one or more roles.
+The user will be asked to change the password on first login.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			username := args[0]
+
+			roleIDs := v.GetStringSlice("role")
+			if len(roleIDs) == 0 {
+				return errors.New("at least one --role is required")
+			}
+
+			log := logger.NewCLILogger()
+
+			password := v.GetString("password")
+			if password == "" {
+				log.ActionWithoutSpinner("Set the initial password for %s", username)
+				p, err := promptForNewPassword()
+				if err != nil {
+					os.Exit(1)
+				}
+				password = p
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			body, err := json.Marshal(map[string]interface{}{
+				"username": username,
+				"password": password,
+				"roleIds":  roleIDs,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request")
+			}
+
+			usersURL := fmt.Sprintf("http://localhost:%d/api/v1/users", localPort)
+			if err := postJSON(usersURL, authSlug, body); err != nil {
+				return errors.Wrap(err, "failed to add user")
+			}
+
+			log.ActionWithoutSpinner("User %s has been added", username)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("role", []string{}, "role to grant the user. may be specified multiple times")
+	cmd.Flags().String("password", "", "initial password for the user. prompted for if not set")
+
+	return cmd
+}
+
+func AdminConsoleUserRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "remove [username]",
+		Aliases:       []string{"rm"},
+		Short:         "Remove an Admin Console user",
+		Long:          `Remove an Admin Console user. Existing sessions of the user remain valid until they expire.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			username := args[0]
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			userURL := fmt.Sprintf("http://localhost:%d/api/v1/users/%s", localPort, url.PathEscape(username))
+			if err := sendJSON("DELETE", userURL, authSlug, nil); err != nil {
+				return errors.Wrap(err, "failed to remove user")
+			}
+
+			log.ActionWithoutSpinner("User %s has been removed", username)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func AdminConsoleUserSetRolesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "set-roles [username]",
+		Short:         "Replace the roles of an Admin Console user",
+		Long:          `Replace the roles of an Admin Console user`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			username := args[0]
+
+			roleIDs := v.GetStringSlice("role")
+			if len(roleIDs) == 0 {
+				return errors.New("at least one --role is required")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			body, err := json.Marshal(map[string]interface{}{
+				"roleIds": roleIDs,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request")
+			}
+
+			rolesURL := fmt.Sprintf("http://localhost:%d/api/v1/users/%s/roles", localPort, url.PathEscape(username))
+			if err := sendJSON("PUT", rolesURL, authSlug, body); err != nil {
+				return errors.Wrap(err, "failed to set roles")
+			}
+
+			log.ActionWithoutSpinner("Roles for user %s have been updated", username)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("role", []string{}, "role to grant the user. may be specified multiple times")
+
+	return cmd
+}
+
+func AdminConsoleUserResetPasswordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset-password [username]",
+		Short: "Reset the password of an Admin Console user",
+		Long: `Reset the password of an Admin Console user and unlock the account.
+The user will be asked to change the password on next login.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			username := args[0]
+
+			log := logger.NewCLILogger()
+
+			log.ActionWithoutSpinner("Reset the password for %s", username)
+			password, err := promptForNewPassword()
+			if err != nil {
+				os.Exit(1)
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			body, err := json.Marshal(map[string]interface{}{
+				"password": password,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request")
+			}
+
+			passwordURL := fmt.Sprintf("http://localhost:%d/api/v1/users/%s/password", localPort, url.PathEscape(username))
+			if err := sendJSON("PUT", passwordURL, authSlug, body); err != nil {
+				return errors.Wrap(err, "failed to reset password")
+			}
+
+			log.ActionWithoutSpinner("The password for user %s has been reset", username)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -84,6 +84,8 @@ func AdminConsoleCmd() *cobra.Command {
 	cmd.AddCommand(AdminPushImagesCmd())
 	cmd.AddCommand(GarbageCollectImagesCmd())
 	cmd.AddCommand(AdminGenerateManifestsCmd())
+	cmd.AddCommand(AdminConsoleUserCmd())
+	cmd.AddCommand(AdminConsoleTokenCmd())
 
 	return cmd
 }
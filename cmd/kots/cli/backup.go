@@ -39,6 +39,7 @@ func BackupCmd() *cobra.Command {
 	cmd.Flags().Bool("wait", true, "wait for the backup to finish")
 
 	cmd.AddCommand(BackupListCmd())
+	cmd.AddCommand(BackupVerifyCmd())
 
 	return cmd
 }
@@ -74,3 +75,40 @@ func BackupListCmd() *cobra.Command {
 
 	return cmd
 }
+
+func BackupVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "verify [backup name]",
+		Short:         "Verify an instance backup by restoring it into a scratch namespace",
+		Long:          `Restore the apps in an instance backup into temporary namespaces, wait for their status informers to report ready, then delete the namespaces. The result is recorded on the backup.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		Args:          cobra.ExactArgs(1),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			options := snapshot.VerifyInstanceBackupOptions{
+				Namespace:     v.GetString("namespace"),
+				BackupName:    args[0],
+				RunPreflights: v.GetBool("run-preflights"),
+				Wait:          v.GetBool("wait"),
+				Timeout:       v.GetDuration("timeout"),
+			}
+			if err := snapshot.VerifyInstanceBackup(cmd.Context(), options); err != nil {
+				return errors.Wrap(err, "failed to verify instance backup")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "default", "namespace in which kots/kotsadm is installed")
+	cmd.Flags().Bool("run-preflights", false, "run the application preflight checks against the restored backup")
+	cmd.Flags().Bool("wait", true, "wait for the verification to finish")
+	cmd.Flags().Duration("timeout", snapshot.DefaultVerifyInstanceBackupTimeout, "how long to wait for the verification to finish when --wait is set")
+
+	return cmd
+}
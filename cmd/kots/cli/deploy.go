@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+	"github.com/replicatedhq/kots/pkg/upload"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func DeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [appSlug]",
+		Short: "Deploy a version of an application",
+		Long: `Deploy a version of an application.
+
+Examples:
+kubectl kots deploy my-app --sequence 5
+kubectl kots deploy my-app --sequence 5 --skip-preflights --wait`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			appSlug := args[0]
+
+			sequence := v.GetInt64("sequence")
+			if sequence < 0 {
+				return errors.New("--sequence is required")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			version, err := getAppVersion(localPort, authSlug, appSlug, sequence)
+			if err != nil {
+				return err
+			}
+
+			skipPreflights := v.GetBool("skip-preflights")
+			if err := canDeployVersion(version, skipPreflights); err != nil {
+				return err
+			}
+
+			log.ActionWithSpinner("Deploying %s sequence %d", appSlug, sequence)
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"isSkipPreflights":             skipPreflights,
+				"continueWithFailedPreflights": skipPreflights,
+				"isCli":                        true,
+			})
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/deploy", localPort, url.PathEscape(appSlug), sequence)
+			if err := postJSON(url, authSlug, requestBody); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to deploy version")
+			}
+
+			log.FinishSpinner()
+
+			if !v.GetBool("wait") {
+				return nil
+			}
+
+			return waitForVersionDeployed(localPort, authSlug, appSlug, sequence, v.GetDuration("timeout"), log)
+		},
+	}
+
+	cmd.Flags().Int64("sequence", -1, "sequence of the app version to deploy")
+	cmd.Flags().Bool("skip-preflights", false, "deploy the version even if preflight checks failed or are still running")
+	cmd.Flags().Bool("wait", false, "wait for the version to be deployed")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "how long to wait for the version to be deployed when --wait is set")
+
+	return cmd
+}
+
+func RedeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redeploy [appSlug]",
+		Short: "Redeploy the currently deployed version of an application",
+		Long: `Redeploy the currently deployed version of an application.
+
+Examples:
+kubectl kots redeploy my-app
+kubectl kots redeploy my-app --wait`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+			appSlug := args[0]
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+			if err != nil {
+				return err
+			}
+
+			app := &handlertypes.ResponseApp{}
+			appURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s", localPort, url.PathEscape(appSlug))
+			if err := getJSON(appURL, authSlug, app); err != nil {
+				return errors.Wrap(err, "failed to get app")
+			}
+
+			if len(app.Downstreams) == 0 || app.Downstreams[0].CurrentVersion == nil {
+				return errors.Errorf("app %s has no deployed version", appSlug)
+			}
+			currentVersion := app.Downstreams[0].CurrentVersion
+
+			log.ActionWithSpinner("Redeploying %s sequence %d", appSlug, currentVersion.Sequence)
+
+			redeployURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/redeploy", localPort, url.PathEscape(appSlug), currentVersion.Sequence)
+			if err := postJSON(redeployURL, authSlug, []byte("{}")); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to redeploy version")
+			}
+
+			log.FinishSpinner()
+
+			if !v.GetBool("wait") {
+				return nil
+			}
+
+			return waitForVersionDeployed(localPort, authSlug, appSlug, currentVersion.Sequence, v.GetDuration("timeout"), log)
+		},
+	}
+
+	cmd.Flags().Bool("wait", false, "wait for the version to be deployed")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "how long to wait for the version to be deployed when --wait is set")
+
+	return cmd
+}
+
+// connectToKotsadmAPI port forwards to the kotsadm pod and returns the local port and auth slug.
+// Port forwarding stops when stopCh is closed.
+func connectToKotsadmAPI(v *viper.Viper, stopCh chan struct{}, log *logger.CLILogger) (int, string, error) {
+	namespace := v.GetString("namespace")
+	if err := validateNamespace(namespace); err != nil {
+		return 0, "", errors.Wrap(err, "failed to validate namespace")
+	}
+
+	localPort, errChan, err := upload.StartPortForward(namespace, stopCh, log)
+	if err != nil {
+		return 0, "", err
+	}
+
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return 0, "", errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", namespace)
+		if v.GetBool("debug") {
+			return 0, "", errors.Wrap(err, "failed to get kotsadm auth slug")
+		}
+		os.Exit(2) // not returning error here as we don't want to show the entire stack trace to normal users
+	}
+
+	return localPort, authSlug, nil
+}
+
+func getAppVersions(localPort int, authSlug string, appSlug string) ([]downstreamtypes.DownstreamVersion, error) {
+	response := struct {
+		VersionHistory []downstreamtypes.DownstreamVersion `json:"versionHistory"`
+	}{}
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/versions", localPort, url.PathEscape(appSlug))
+	if err := getJSON(url, authSlug, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to get app versions")
+	}
+
+	return response.VersionHistory, nil
+}
+
+func getAppVersion(localPort int, authSlug string, appSlug string, sequence int64) (*downstreamtypes.DownstreamVersion, error) {
+	versions, err := getAppVersions(localPort, authSlug, appSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, version := range versions {
+		if version.Sequence == sequence {
+			return &versions[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("sequence %d not found for app %s", sequence, appSlug)
+}
+
+// canDeployVersion returns an error if the version needs configuration, or if preflights failed or
+// are still running and skipPreflights is not set
+func canDeployVersion(version *downstreamtypes.DownstreamVersion, skipPreflights bool) error {
+	switch version.Status {
+	case storetypes.VersionPendingConfig:
+		return errors.Errorf("sequence %d cannot be deployed because it needs configuration", version.Sequence)
+	case storetypes.VersionPendingPreflight:
+		if !skipPreflights {
+			return errors.Errorf("preflight checks for sequence %d are still running, use --skip-preflights to deploy anyway", version.Sequence)
+		}
+	}
+
+	if skipPreflights || version.PreflightResult == "" {
+		return nil
+	}
+
+	preflightResults := troubleshootpreflight.UploadPreflightResults{}
+	if err := json.Unmarshal([]byte(version.PreflightResult), &preflightResults); err != nil {
+		return errors.Wrap(err, "failed to unmarshal preflight results")
+	}
+
+	failed := len(preflightResults.Errors) > 0
+	for _, result := range preflightResults.Results {
+		if result.IsFail {
+			failed = true
+			break
+		}
+	}
+	if failed {
+		return errors.Errorf("preflight checks for sequence %d failed, use --skip-preflights to deploy anyway", version.Sequence)
+	}
+
+	return nil
+}
+
+func waitForVersionDeployed(localPort int, authSlug string, appSlug string, sequence int64, timeout time.Duration, log *logger.CLILogger) error {
+	log.ActionWithSpinner("Waiting for sequence %d to be deployed", sequence)
+
+	start := time.Now()
+	for {
+		version, err := getAppVersion(localPort, authSlug, appSlug, sequence)
+		if err != nil {
+			log.FinishSpinnerWithError()
+			return err
+		}
+
+		switch version.Status {
+		case storetypes.VersionDeployed:
+			log.FinishSpinner()
+			return nil
+		case storetypes.VersionFailed:
+			log.FinishSpinnerWithError()
+			return errors.Errorf("sequence %d failed to deploy, run \"kubectl kots get deploy-report %s --sequence %d\" for details", sequence, appSlug, sequence)
+		}
+
+		if time.Since(start) > timeout {
+			log.FinishSpinnerWithError()
+			return errors.Errorf("timed out waiting for sequence %d to be deployed, current status is %s", sequence, version.Status)
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func postJSON(url string, authSlug string, body []byte) error {
+	return sendJSON("POST", url, authSlug, body)
+}
+
+func sendJSON(method string, url string, authSlug string, body []byte) error {
+	return sendJSONWithResponse(method, url, authSlug, body, nil)
+}
+
+// sendJSONWithResponse unmarshals the response body into response if it is not nil
+func sendJSONWithResponse(method string, url string, authSlug string, body []byte, response interface{}) error {
+	newReq, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := http.DefaultClient.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, b)
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(b, response); err != nil {
+			return errors.Wrap(err, "failed to unmarshal response")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"testing"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_canDeployVersion(t *testing.T) {
+	failedPreflight := `{"results":[{"isFail":true,"title":"check"}]}`
+	passedPreflight := `{"results":[{"isPass":true,"title":"check"}]}`
+
+	tests := []struct {
+		name           string
+		version        downstreamtypes.DownstreamVersion
+		skipPreflights bool
+		wantErr        bool
+	}{
+		{
+			name:    "passed preflights",
+			version: downstreamtypes.DownstreamVersion{Status: storetypes.VersionPending, PreflightResult: passedPreflight},
+		},
+		{
+			name:    "no preflights",
+			version: downstreamtypes.DownstreamVersion{Status: storetypes.VersionPending},
+		},
+		{
+			name:    "failed preflights",
+			version: downstreamtypes.DownstreamVersion{Status: storetypes.VersionPending, PreflightResult: failedPreflight},
+			wantErr: true,
+		},
+		{
+			name:           "failed preflights skipped",
+			version:        downstreamtypes.DownstreamVersion{Status: storetypes.VersionPending, PreflightResult: failedPreflight},
+			skipPreflights: true,
+		},
+		{
+			name:    "preflights running",
+			version: downstreamtypes.DownstreamVersion{Status: storetypes.VersionPendingPreflight},
+			wantErr: true,
+		},
+		{
+			name:           "needs config",
+			version:        downstreamtypes.DownstreamVersion{Status: storetypes.VersionPendingConfig},
+			skipPreflights: true,
+			wantErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := canDeployVersion(&test.version, test.skipPreflights)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -7,12 +7,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -25,7 +29,10 @@ func GetCmd() *cobra.Command {
 		Use:   "get [resource]",
 		Short: "Display kots resources",
 		Long: `Examples:
-kubectl kots get apps`,
+kubectl kots get apps
+kubectl kots get versions my-app
+kubectl kots get deploy-report my-app --sequence 3
+kubectl kots get audit --app my-app -o json`,
 
 		SilenceUsage:  true,
 		SilenceErrors: false,
@@ -33,7 +40,7 @@ kubectl kots get apps`,
 			viper.BindPFlags(cmd.Flags())
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
+			if len(args) == 0 {
 				cmd.Help()
 				os.Exit(1)
 			}
@@ -48,6 +55,23 @@ kubectl kots get apps`,
 			case "app", "apps":
 				err := getAppsCmd(cmd, args)
 				return errors.Wrap(err, "failed to get apps")
+			case "version", "versions":
+				if len(args) != 2 {
+					cmd.Help()
+					os.Exit(1)
+				}
+				err := getVersionsCmd(cmd, args[1])
+				return errors.Wrap(err, "failed to get versions")
+			case "deploy-report":
+				if len(args) != 2 {
+					cmd.Help()
+					os.Exit(1)
+				}
+				err := getDeployReportCmd(cmd, args[1])
+				return errors.Wrap(err, "failed to get deploy report")
+			case "audit":
+				err := getAuditCmd(cmd)
+				return errors.Wrap(err, "failed to get audit events")
 			default:
 				cmd.Help()
 				os.Exit(1)
@@ -58,6 +82,10 @@ kubectl kots get apps`,
 	}
 
 	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+	cmd.Flags().Int64("sequence", -1, "sequence of the app version to get the deploy report for. defaults to the currently deployed version")
+	cmd.Flags().String("app", "", "only show audit events for this app")
+	cmd.Flags().Int("page", 0, "page of audit events to show, starting at 0")
+	cmd.Flags().Int("page-size", 50, "number of audit events per page")
 
 	return cmd
 }
@@ -175,6 +203,146 @@ func getAppsCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func getVersionsCmd(cmd *cobra.Command, appSlug string) error {
+	v := viper.GetViper()
+
+	log := logger.NewCLILogger()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+	if err != nil {
+		return err
+	}
+
+	versions, err := getAppVersions(localPort, authSlug, appSlug)
+	if err != nil {
+		return err
+	}
+
+	print.Versions(versions, v.GetString("output"))
+
+	return nil
+}
+
+func getDeployReportCmd(cmd *cobra.Command, appSlug string) error {
+	v := viper.GetViper()
+
+	log := logger.NewCLILogger()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+	if err != nil {
+		return err
+	}
+
+	app := &handlertypes.ResponseApp{}
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s", localPort, appSlug)
+	if err := getJSON(url, authSlug, app); err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	if len(app.Downstreams) == 0 {
+		return errors.Errorf("app %s has no downstreams", appSlug)
+	}
+	downstream := app.Downstreams[0]
+
+	sequence := v.GetInt64("sequence")
+	if sequence < 0 {
+		if downstream.CurrentVersion == nil {
+			return errors.Errorf("app %s has no deployed version", appSlug)
+		}
+		sequence = downstream.CurrentVersion.Sequence
+	}
+
+	output := struct {
+		Logs downstreamtypes.DownstreamOutput `json:"logs"`
+	}{}
+	url = fmt.Sprintf("http://localhost:%d/api/v1/app/%s/cluster/%s/sequence/%d/downstreamoutput", localPort, appSlug, downstream.Cluster.ID, sequence)
+	if err := getJSON(url, authSlug, &output); err != nil {
+		return errors.Wrap(err, "failed to get downstream output")
+	}
+
+	report := print.DeployReport{
+		AppSlug:   appSlug,
+		Sequence:  sequence,
+		Resources: output.Logs.Resources,
+	}
+	if report.Resources == nil {
+		report.Resources = []downstreamtypes.DeployedResource{}
+	}
+
+	print.DeployedResources(report, v.GetString("output"))
+
+	return nil
+}
+
+func getAuditCmd(cmd *cobra.Command) error {
+	v := viper.GetViper()
+
+	log := logger.NewCLILogger()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, err := connectToKotsadmAPI(v, stopCh, log)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("currentPage", strconv.Itoa(v.GetInt("page")))
+	query.Set("pageSize", strconv.Itoa(v.GetInt("page-size")))
+	if appSlug := v.GetString("app"); appSlug != "" {
+		query.Set("appSlug", appSlug)
+	}
+
+	response := struct {
+		Events []*audittypes.Event `json:"events"`
+	}{}
+	auditURL := fmt.Sprintf("http://localhost:%d/api/v1/audit?%s", localPort, query.Encode())
+	if err := getJSON(auditURL, authSlug, &response); err != nil {
+		return errors.Wrap(err, "failed to list audit events")
+	}
+
+	print.AuditEvents(response.Events, v.GetString("output"))
+
+	return nil
+}
+
+func getJSON(url string, authSlug string, response interface{}) error {
+	newReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := http.DefaultClient.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, b)
+	}
+
+	if err := json.Unmarshal(b, response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return nil
+}
+
 func getApps(url string, authSlug string) (*handlertypes.ListAppsResponse, error) {
 	newReq, err := http.NewRequest("GET", url, nil)
 	if err != nil {
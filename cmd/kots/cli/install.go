@@ -30,6 +30,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/metrics"
 	"github.com/replicatedhq/kots/pkg/pull"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -220,6 +221,27 @@ func InstallCmd() *cobra.Command {
 
 				IdentityConfig: *identityConfig,
 				IngressConfig:  *ingressConfig,
+
+				GitAuth: upstreamtypes.GitAuth{
+					Username: v.GetString("git-username"),
+					Token:    v.GetString("git-token"),
+				},
+			}
+
+			if sshKeyFile := v.GetString("git-ssh-key"); sshKeyFile != "" {
+				sshKey, err := ioutil.ReadFile(ExpandDir(sshKeyFile))
+				if err != nil {
+					return errors.Wrap(err, "failed to read git ssh key")
+				}
+				deployOptions.GitAuth.SSHPrivateKey = string(sshKey)
+			}
+
+			if publicKeyFile := v.GetString("release-public-key"); publicKeyFile != "" {
+				publicKey, err := ioutil.ReadFile(ExpandDir(publicKeyFile))
+				if err != nil {
+					return errors.Wrap(err, "failed to read release public key")
+				}
+				deployOptions.ReleasePublicKey = string(publicKey)
 			}
 
 			clientset, err := k8sutil.GetClientset()
@@ -396,6 +418,10 @@ func InstallCmd() *cobra.Command {
 	cmd.Flags().Bool("airgap", false, "set to true to run install in airgapped mode. setting --airgap-bundle implies --airgap=true.")
 	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks")
 	cmd.Flags().Bool("disable-image-push", false, "set to true to disable images from being pushed to private registry")
+	cmd.Flags().String("git-username", "", "the username to use with --git-token when cloning a git upstream")
+	cmd.Flags().String("git-token", "", "the access token to use when cloning a git upstream over https")
+	cmd.Flags().String("git-ssh-key", "", "path to a private ssh key to use when cloning a git upstream")
+	cmd.Flags().String("release-public-key", "", "path to a PEM (cosign) or armored GPG public key used to verify the signature of an http upstream release")
 
 	cmd.Flags().String("repo", "", "repo uri to use when installing a helm chart")
 	cmd.Flags().StringSlice("set", []string{}, "values to pass to helm when running helm template")
@@ -1,11 +1,14 @@
 package cli
 
 import (
+	"io/ioutil"
 	"os"
 	"path"
 
+	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/pull"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -58,6 +61,26 @@ func PullCmd() *cobra.Command {
 				HTTPSProxyEnvValue: v.GetString("https-proxy"),
 				NoProxyEnvValue:    v.GetString("no-proxy"),
 				IncludeMinio:       v.GetBool("with-minio"),
+				GitAuth: upstreamtypes.GitAuth{
+					Username: v.GetString("git-username"),
+					Token:    v.GetString("git-token"),
+				},
+			}
+
+			if sshKeyFile := v.GetString("git-ssh-key"); sshKeyFile != "" {
+				sshKey, err := ioutil.ReadFile(ExpandDir(sshKeyFile))
+				if err != nil {
+					return errors.Wrap(err, "failed to read git ssh key")
+				}
+				pullOptions.GitAuth.SSHPrivateKey = string(sshKey)
+			}
+
+			if publicKeyFile := v.GetString("release-public-key"); publicKeyFile != "" {
+				publicKey, err := ioutil.ReadFile(ExpandDir(publicKeyFile))
+				if err != nil {
+					return errors.Wrap(err, "failed to read release public key")
+				}
+				pullOptions.ReleasePublicKey = string(publicKey)
 			}
 
 			if v.GetBool("copy-proxy-env") {
@@ -119,6 +142,10 @@ func PullCmd() *cobra.Command {
 	cmd.Flags().String("registry-password", "", "the password of the local docker registry to use when pushing images (with --rewrite-images)")
 	cmd.Flags().String("helm-version", "v2", "the Helm version with which to render the Helm Chart")
 	cmd.Flags().Bool("with-minio", true, "set to true to include a local minio instance to be used for storage")
+	cmd.Flags().String("git-username", "", "the username to use with --git-token when cloning a git upstream")
+	cmd.Flags().String("git-token", "", "the access token to use when cloning a git upstream over https")
+	cmd.Flags().String("git-ssh-key", "", "path to a private ssh key to use when cloning a git upstream")
+	cmd.Flags().String("release-public-key", "", "path to a PEM (cosign) or armored GPG public key used to verify the signature of an http upstream release")
 
 	return cmd
 }
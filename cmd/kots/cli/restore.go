@@ -3,8 +3,10 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pkg/errors"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/print"
 	"github.com/replicatedhq/kots/pkg/snapshot"
 	"github.com/spf13/cobra"
@@ -30,12 +32,49 @@ func RestoreCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
+			namespaceMapping, err := parseMappingFlag(v.GetStringSlice("namespace-mapping"))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse namespace mapping")
+			}
+			storageClassMapping, err := parseMappingFlag(v.GetStringSlice("storage-class-mapping"))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse storage class mapping")
+			}
+
+			toCluster := v.GetBool("to-cluster")
+			if !toCluster && (len(namespaceMapping) > 0 || len(storageClassMapping) > 0 || v.GetString("kotsadm-registry") != "") {
+				return errors.New("namespace, storage class and registry settings can only be changed when restoring with --to-cluster")
+			}
+
 			options := snapshot.RestoreInstanceBackupOptions{
-				BackupName:      backupName,
-				WaitForApps:     v.GetBool("wait-for-apps"),
-				VeleroNamespace: v.GetString("velero-namespace"),
+				BackupName:          backupName,
+				WaitForApps:         v.GetBool("wait-for-apps"),
+				VeleroNamespace:     v.GetString("velero-namespace"),
+				ToCluster:           toCluster,
+				KotsadmNamespace:    v.GetString("namespace"),
+				NamespaceMapping:    namespaceMapping,
+				StorageClassMapping: storageClassMapping,
 			}
-			_, err := snapshot.RestoreInstanceBackup(cmd.Context(), options)
+
+			if registryEndpoint := v.GetString("kotsadm-registry"); registryEndpoint != "" {
+				registryNamespace := v.GetString("kotsadm-namespace")
+				if registryNamespace == "" {
+					parts := strings.Split(registryEndpoint, "/")
+					if len(parts) > 1 {
+						registryEndpoint = parts[0]
+						registryNamespace = strings.Join(parts[1:], "/")
+					}
+				}
+				options.RegistryOptions = &kotsadmtypes.KotsadmOptions{
+					OverrideRegistry:  registryEndpoint,
+					OverrideNamespace: registryNamespace,
+					Username:          v.GetString("registry-username"),
+					Password:          v.GetString("registry-password"),
+					IsReadOnly:        v.GetBool("disable-image-push"),
+				}
+			}
+
+			_, err = snapshot.RestoreInstanceBackup(cmd.Context(), options)
 			if err != nil {
 				return errors.Wrap(err, "failed to restore instance backup")
 			}
@@ -48,11 +87,33 @@ func RestoreCmd() *cobra.Command {
 	cmd.Flags().String("velero-namespace", "", "namespace in which velero is installed")
 	cmd.Flags().Bool("wait-for-apps", true, "wait for all applications to be restored")
 
+	cmd.Flags().Bool("to-cluster", false, "restore a backup that was taken in a different cluster which uses the same snapshot store")
+	cmd.Flags().StringP("namespace", "n", "", "the namespace to restore the admin console to (defaults to the namespace it was backed up from). only used with --to-cluster")
+	cmd.Flags().StringSlice("namespace-mapping", []string{}, "map a namespace in the backup to a different namespace, in the form old=new. may be specified multiple times. only used with --to-cluster")
+	cmd.Flags().StringSlice("storage-class-mapping", []string{}, "map a storage class in the backup to a different storage class, in the form old=new. may be specified multiple times. only used with --to-cluster")
+	cmd.Flags().String("kotsadm-registry", "", "the registry to point the restored applications at. only used with --to-cluster")
+	cmd.Flags().String("kotsadm-namespace", "", "the namespace/org in the registry to point the restored applications at. only used with --to-cluster")
+	cmd.Flags().String("registry-username", "", "username to use to authenticate with the registry. only used with --to-cluster")
+	cmd.Flags().String("registry-password", "", "password to use to authenticate with the registry. only used with --to-cluster")
+	cmd.Flags().Bool("disable-image-push", false, "set to true to disable images from being pushed to the registry. only used with --to-cluster")
+
 	cmd.AddCommand(RestoreListCmd())
 
 	return cmd
 }
 
+func parseMappingFlag(values []string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid mapping %q, expected the form old=new", value)
+		}
+		mapping[parts[0]] = parts[1]
+	}
+	return mapping, nil
+}
+
 func RestoreListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "ls",
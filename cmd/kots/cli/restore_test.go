@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMappingFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			values: []string{},
+			want:   map[string]string{},
+		},
+		{
+			name:   "multiple",
+			values: []string{"default=prod", "standard=gp2"},
+			want:   map[string]string{"default": "prod", "standard": "gp2"},
+		},
+		{
+			name:    "missing separator",
+			values:  []string{"default"},
+			wantErr: true,
+		},
+		{
+			name:    "missing target",
+			values:  []string{"default="},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMappingFlag(tt.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -42,6 +42,8 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(AppStatusCmd())
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(SetCmd())
+	cmd.AddCommand(DeployCmd())
+	cmd.AddCommand(RedeployCmd())
 
 	addExperimentalCmds(cmd)
 
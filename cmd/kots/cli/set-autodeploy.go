@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func SetAutoDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autodeploy [appSlug]",
+		Short: "Set the auto deploy policy for an application",
+		Long: `Set the auto deploy policy for an application.
+
+Downloaded versions are deployed automatically when preflight checks pass, the version label is
+a semver bump allowed by --mode, and the current time is inside one of the maintenance windows.`,
+		Example: `  kots set autodeploy my-app --mode patch --maintenance-window "0 2 * * * 2h"
+  kots set autodeploy my-app --mode never`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			log := logger.NewCLILogger()
+			appSlug := args[0]
+			namespace := v.GetString("namespace")
+
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			policy, err := getAutoDeployPolicyFromFlags(v)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse auto deploy policy")
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, time.Second*5)
+			if err != nil {
+				return errors.Wrap(err, "failed to find kotsadm pod")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+			if err != nil {
+				return errors.Wrap(err, "failed to start port forwarding")
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+			if err != nil {
+				return errors.Wrap(err, "failed to get kotsadm auth slug")
+			}
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"autoDeployPolicy": policy,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/autodeploy", localPort, url.QueryEscape(appSlug))
+			newRequest, err := http.NewRequest("PUT", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := http.DefaultClient.Do(newRequest)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			respBody, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return errors.Wrap(err, "failed to read server response")
+			}
+
+			response := struct {
+				Error string `json:"error"`
+			}{}
+			_ = json.Unmarshal(respBody, &response)
+
+			if resp.StatusCode != http.StatusOK {
+				if resp.StatusCode == http.StatusNotFound {
+					return errors.Errorf("app with slug %s not found", appSlug)
+				}
+				return errors.Wrapf(errors.New(response.Error), "unexpected status code from %v", resp.StatusCode)
+			}
+
+			log.ActionWithoutSpinner("Auto deploy policy for %s set to %s", appSlug, policy.Mode)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("mode", string(apptypes.AutoDeployNever), "which versions to deploy automatically: never, patch (only patch semver bumps) or any (any semver bump)")
+	cmd.Flags().StringArray("maintenance-window", []string{}, `window in which versions can be deployed, as a cron schedule followed by a duration, e.g. "0 2 * * 6 4h". Can be specified multiple times. Versions can be deployed at any time if not set.`)
+
+	return cmd
+}
+
+func getAutoDeployPolicyFromFlags(v *viper.Viper) (*apptypes.AutoDeployPolicy, error) {
+	policy := &apptypes.AutoDeployPolicy{
+		Mode: apptypes.AutoDeployMode(v.GetString("mode")),
+	}
+
+	switch policy.Mode {
+	case apptypes.AutoDeployNever, apptypes.AutoDeployPatch, apptypes.AutoDeployAny:
+	default:
+		return nil, errors.Errorf("--mode must be one of never, patch or any")
+	}
+
+	for _, window := range v.GetStringSlice("maintenance-window") {
+		maintenanceWindow, err := parseMaintenanceWindow(window)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse maintenance window %q", window)
+		}
+		policy.MaintenanceWindows = append(policy.MaintenanceWindows, *maintenanceWindow)
+	}
+
+	return policy, nil
+}
+
+// parseMaintenanceWindow splits "<cron schedule> <duration>" on the last field
+func parseMaintenanceWindow(window string) (*apptypes.MaintenanceWindow, error) {
+	fields := strings.Fields(window)
+	if len(fields) < 2 {
+		return nil, errors.New("expected a cron schedule followed by a duration")
+	}
+
+	duration := fields[len(fields)-1]
+	if _, err := time.ParseDuration(duration); err != nil {
+		return nil, errors.Wrap(err, "failed to parse duration")
+	}
+
+	return &apptypes.MaintenanceWindow{
+		Schedule: strings.Join(fields[:len(fields)-1], " "),
+		Duration: duration,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"testing"
+
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *apptypes.MaintenanceWindow
+		wantErr bool
+	}{
+		{
+			name:  "cron schedule",
+			input: "0 2 * * 1,3 4h",
+			want:  &apptypes.MaintenanceWindow{Schedule: "0 2 * * 1,3", Duration: "4h"},
+		},
+		{
+			name:  "descriptor",
+			input: "@daily 30m",
+			want:  &apptypes.MaintenanceWindow{Schedule: "@daily", Duration: "30m"},
+		},
+		{
+			name:    "missing duration",
+			input:   "0 2 * * *",
+			wantErr: true,
+		},
+		{
+			name:    "only duration",
+			input:   "2h",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseMaintenanceWindow(test.input)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
@@ -14,9 +14,11 @@ import (
 	cursor "github.com/ahmetalpbalkan/go-cursor"
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/print"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,6 +54,10 @@ func SetConfigCmd() *cobra.Command {
 				return errors.Wrap(err, "failed to validate namespace")
 			}
 
+			if v.GetBool("dry-run") && v.GetBool("deploy") {
+				return errors.New("--dry-run cannot be used with --deploy")
+			}
+
 			if v.GetBool("skip-preflights") && !v.GetBool("deploy") {
 				log.Info("--skip-preflights will be ignored because --deploy is not set")
 			}
@@ -74,8 +80,6 @@ func SetConfigCmd() *cobra.Command {
 			stopCh := make(chan struct{})
 			defer close(stopCh)
 
-			log.ActionWithoutSpinner("Updating %s configuration...", appSlug)
-
 			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
 			if err != nil {
 				return errors.Wrap(err, "failed to start port forwarding")
@@ -101,6 +105,12 @@ func SetConfigCmd() *cobra.Command {
 				merge = true
 			}
 
+			if v.GetBool("dry-run") {
+				return previewConfigValues(localPort, authSlug, appSlug, configValues, merge, v.GetString("output"))
+			}
+
+			log.ActionWithoutSpinner("Updating %s configuration...", appSlug)
+
 			requestPayload := map[string]interface{}{
 				"configValues":   configValues,
 				"merge":          merge,
@@ -134,13 +144,17 @@ func SetConfigCmd() *cobra.Command {
 			}
 
 			response := struct {
-				Error string `json:"error"`
+				Error         string   `json:"error"`
+				RequiredItems []string `json:"requiredItems"`
+				InvalidItems  []string `json:"invalidItems"`
 			}{}
 			_ = json.Unmarshal(respBody, &response)
 
 			if resp.StatusCode != http.StatusOK {
 				if resp.StatusCode == http.StatusNotFound {
 					return errors.Errorf("app with slug %s not found", appSlug)
+				} else if len(response.RequiredItems) > 0 || len(response.InvalidItems) > 0 {
+					return errors.New(response.Error)
 				} else {
 					return errors.Wrapf(errors.New(response.Error), "unexpected status code from %v", resp.StatusCode)
 				}
@@ -161,6 +175,9 @@ func SetConfigCmd() *cobra.Command {
 	cmd.Flags().Bool("deploy", false, "when set, automatically deploy the latest version with the new configuration")
 	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks when deploying new version")
 
+	cmd.Flags().Bool("dry-run", false, "when set, render the application with the new configuration and print the diff against the deployed version without creating a new version")
+	cmd.Flags().StringP("output", "o", "", "output format for --dry-run. supported values: json")
+
 	return cmd
 }
 
@@ -230,3 +247,50 @@ func getConfigValuesFromArgs(v *viper.Viper, args []string) ([]byte, error) {
 
 	return b, nil
 }
+
+func previewConfigValues(localPort int, authSlug string, appSlug string, configValues []byte, merge bool, format string) error {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"configValues": configValues,
+		"merge":        merge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request json")
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/config/values/preview", localPort, url.PathEscape(appSlug))
+	newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read server response")
+	}
+
+	preview := handlertypes.PreviewAppConfigValuesResponse{}
+	_ = json.Unmarshal(respBody, &preview)
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return errors.Errorf("app with slug %s not found", appSlug)
+		}
+		return errors.Wrapf(errors.New(preview.Error), "unexpected status code from %v", resp.StatusCode)
+	}
+
+	print.ConfigPreview(preview, format)
+
+	if len(preview.TemplateErrors) > 0 {
+		return errors.New("failed to render templates with the new configuration")
+	}
+
+	return nil
+}
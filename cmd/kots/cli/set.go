@@ -30,6 +30,7 @@ func SetCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(SetConfigCmd())
+	cmd.AddCommand(SetAutoDeployCmd())
 
 	return cmd
 }
@@ -143,6 +143,7 @@ func VeleroConfigureInternalCmd() *cobra.Command {
 				SkipValidation:    v.GetBool("skip-validation"),
 				ValidateUsingAPod: true,
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -156,6 +157,7 @@ func VeleroConfigureInternalCmd() *cobra.Command {
 	}
 
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the internal store endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	return cmd
 }
@@ -235,6 +237,7 @@ func VeleroConfigureAmazonS3AccessKeyCmd() *cobra.Command {
 				RegistryOptions:  &registryOptions,
 				SkipValidation:   v.GetBool("skip-validation"),
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -253,6 +256,7 @@ func VeleroConfigureAmazonS3AccessKeyCmd() *cobra.Command {
 	cmd.Flags().String("access-key-id", "", "the aws access key id to use for accessing the bucket (required)")
 	cmd.Flags().String("secret-access-key", "", "the aws secret access key to use for accessing the bucket (required)")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the aws s3 endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	cmd.MarkFlagRequired("bucket")
 	cmd.MarkFlagRequired("region")
@@ -299,6 +303,7 @@ func VeleroConfigureAmazonS3InstanceRoleCmd() *cobra.Command {
 				RegistryOptions:  &registryOptions,
 				SkipValidation:   v.GetBool("skip-validation"),
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -315,6 +320,7 @@ func VeleroConfigureAmazonS3InstanceRoleCmd() *cobra.Command {
 	cmd.Flags().String("path", "", "path to a subdirectory in the object store bucket")
 	cmd.Flags().String("region", "", "the region where the bucket exists (required)")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the aws s3 endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	cmd.MarkFlagRequired("bucket")
 	cmd.MarkFlagRequired("region")
@@ -398,6 +404,7 @@ func VeleroConfigureOtherS3Cmd() *cobra.Command {
 				ValidateUsingAPod: true,
 				CACertData:        caCertData,
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -417,6 +424,7 @@ func VeleroConfigureOtherS3Cmd() *cobra.Command {
 	cmd.Flags().String("endpoint", "", "the s3 endpoint. (e.g. http://some-other-s3-endpoint, required)")
 	cmd.Flags().String("cacert", "", "file containing a certificate bundle to use when verifying TLS connections to the object store.")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the s3 endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	cmd.MarkFlagRequired("bucket")
 	cmd.MarkFlagRequired("region")
@@ -508,6 +516,7 @@ func VeleroConfigureGCPServiceAccount() *cobra.Command {
 				RegistryOptions:  &registryOptions,
 				SkipValidation:   v.GetBool("skip-validation"),
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -524,6 +533,7 @@ func VeleroConfigureGCPServiceAccount() *cobra.Command {
 	cmd.Flags().String("path", "", "path to a subdirectory in the object store bucket")
 	cmd.Flags().String("json-file", "", "path to JSON credntials file for veloro (required)")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	cmd.MarkFlagRequired("bucket")
 	cmd.MarkFlagRequired("json-file")
@@ -567,6 +577,7 @@ func VeleroConfigureGCPWorkloadIdentity() *cobra.Command {
 				RegistryOptions:  &registryOptions,
 				SkipValidation:   v.GetBool("skip-validation"),
 			}
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -583,6 +594,7 @@ func VeleroConfigureGCPWorkloadIdentity() *cobra.Command {
 	cmd.Flags().String("path", "", "path to a subdirectory in the object store bucket")
 	cmd.Flags().String("service-account", "", "the service account to use if using Google Cloud instance role (required)")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 
 	cmd.MarkFlagRequired("bucket")
 	cmd.MarkFlagRequired("service-account")
@@ -670,6 +682,7 @@ func VeleroConfigureAzureServicePrincipleCmd() *cobra.Command {
 				SkipValidation:   v.GetBool("skip-validation"),
 			}
 
+			setStoreMigrationOptions(v, &configureStoreOptions)
 			_, err = snapshot.ConfigureStore(cmd.Context(), configureStoreOptions)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure store")
@@ -690,6 +703,7 @@ func VeleroConfigureAzureServicePrincipleCmd() *cobra.Command {
 	cmd.Flags().String("path", "", "path to a subdirectory in the blob storage container")
 	cmd.Flags().String("resource-group", "", "the resource group name of the blob storage container (required)")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the blob storage container")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 	cmd.Flags().String("storage-account", "", "the storage account name of the blob storage container (required)")
 	cmd.Flags().String("subscription-id", "", "the subscription id associated with the blob storage container (required)")
 	cmd.Flags().String("tenant-id", "", "the tenant ID associated with the blob storage container (required)")
@@ -754,6 +768,7 @@ func VeleroConfigureNFSCmd() *cobra.Command {
 				Output:           v.GetString("output"),
 				ForceReset:       v.GetBool("force-reset"),
 				SkipValidation:   v.GetBool("skip-validation"),
+				MigrateBackups:   v.GetBool("migrate-backups"),
 			}
 			return veleroConfigureFileSystem(cmd.Context(), log, opts)
 		},
@@ -764,6 +779,7 @@ func VeleroConfigureNFSCmd() *cobra.Command {
 	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
 	cmd.Flags().Bool("force-reset", false, "bypass the reset prompt and force resetting the nfs path")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the backup store endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 	cmd.Flags().MarkHidden("skip-validation")
 
 	registryFlags(cmd.Flags())
@@ -813,6 +829,7 @@ func VeleroConfigureHostPathCmd() *cobra.Command {
 				Output:           v.GetString("output"),
 				ForceReset:       v.GetBool("force-reset"),
 				SkipValidation:   v.GetBool("skip-validation"),
+				MigrateBackups:   v.GetBool("migrate-backups"),
 			}
 			return veleroConfigureFileSystem(cmd.Context(), log, opts)
 		},
@@ -822,6 +839,7 @@ func VeleroConfigureHostPathCmd() *cobra.Command {
 	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
 	cmd.Flags().Bool("force-reset", false, "bypass the reset prompt and force resetting the host path directory")
 	cmd.Flags().Bool("skip-validation", false, "skip the validation of the backup store endpoint/bucket")
+	cmd.Flags().Bool("migrate-backups", false, "copy existing backups and restic repositories to the new store before switching to it")
 	cmd.Flags().MarkHidden("skip-validation")
 
 	registryFlags(cmd.Flags())
@@ -836,6 +854,7 @@ type VeleroConfigureFileSystemOptions struct {
 	Output           string
 	ForceReset       bool
 	SkipValidation   bool
+	MigrateBackups   bool
 }
 
 func veleroConfigureFileSystem(ctx context.Context, log *logger.CLILogger, opts VeleroConfigureFileSystemOptions) error {
@@ -918,6 +937,11 @@ func veleroConfigureFileSystem(ctx context.Context, log *logger.CLILogger, opts
 		SkipValidation:    opts.SkipValidation,
 		ValidateUsingAPod: true,
 	}
+	if opts.MigrateBackups {
+		configureStoreOptions.MigrateBackups = true
+		configureStoreOptions.MigrateUsingPortForward = true
+		configureStoreOptions.MigrationProgressFn = storeMigrationProgressFn(log)
+	}
 	_, err = snapshot.ConfigureStore(ctx, configureStoreOptions)
 	if err != nil {
 		log.FinishSpinnerWithError()
@@ -929,6 +953,22 @@ func veleroConfigureFileSystem(ctx context.Context, log *logger.CLILogger, opts
 	return nil
 }
 
+func setStoreMigrationOptions(v *viper.Viper, configureStoreOptions *snapshot.ConfigureStoreOptions) {
+	if !v.GetBool("migrate-backups") {
+		return
+	}
+
+	configureStoreOptions.MigrateBackups = true
+	configureStoreOptions.MigrateUsingPortForward = true
+	configureStoreOptions.MigrationProgressFn = storeMigrationProgressFn(logger.NewCLILogger())
+}
+
+func storeMigrationProgressFn(log *logger.CLILogger) func(progress snapshot.StoreMigrationProgress) {
+	return func(progress snapshot.StoreMigrationProgress) {
+		log.ChildActionWithoutSpinner(progress.String())
+	}
+}
+
 func VeleroPrintFileSystemInstructionsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "print-fs-instructions",
@@ -0,0 +1,62 @@
+package applier
+
+const (
+	// KubectlApplier runs the kubectl binary matching the kubectlVersion in the Application spec and is the default
+	KubectlApplier = "kubectl"
+	// ServerSideApplier uses server-side apply through client-go
+	ServerSideApplier = "serverside"
+)
+
+// FieldManager is the field manager used for server-side apply
+const FieldManager = "kotsadm"
+
+type Action string
+
+const (
+	ActionCreated    Action = "created"
+	ActionConfigured Action = "configured"
+	ActionUnchanged  Action = "unchanged"
+	ActionDeleted    Action = "deleted"
+	ActionFailed     Action = "failed"
+)
+
+// ObjectResult is the result of applying or removing a single object
+type ObjectResult struct {
+	Group     string       `json:"group"`
+	Version   string       `json:"version"`
+	Kind      string       `json:"kind"`
+	Namespace string       `json:"namespace,omitempty"`
+	Name      string       `json:"name"`
+	Action    Action       `json:"action"`
+	Error     *ObjectError `json:"error,omitempty"`
+}
+
+// ObjectError is the error returned by the api server for a single object
+type ObjectError struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result is the output of an Apply or Remove call.
+// Objects holds the result of each object that was applied or removed. The kubectl applier builds
+// them from the line kubectl prints for each object.
+type Result struct {
+	Objects []ObjectResult
+	Stdout  []byte
+	Stderr  []byte
+}
+
+// HasErr returns true if any object failed
+func (r *Result) HasErr() bool {
+	for _, o := range r.Objects {
+		if o.Action == ActionFailed {
+			return true
+		}
+	}
+	return false
+}
+
+type Applier interface {
+	Apply(targetNamespace string, slug string, yamlDoc []byte, dryRun bool, wait bool, annotateSlug bool) (*Result, error)
+	Remove(targetNamespace string, yamlDoc []byte, wait bool) (*Result, error)
+}
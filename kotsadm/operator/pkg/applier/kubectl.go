@@ -7,11 +7,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	rest "k8s.io/client-go/rest"
 )
 
+var _ Applier = (*Kubectl)(nil)
+
 type Kubectl struct {
 	kubectl string
 	config  *rest.Config
@@ -51,7 +55,7 @@ func (c *Kubectl) connectArgs() []string {
 	return args
 }
 
-func (c *Kubectl) Remove(targetNamespace string, yamlDoc []byte, wait bool) ([]byte, []byte, error) {
+func (c *Kubectl) Remove(targetNamespace string, yamlDoc []byte, wait bool) (*Result, error) {
 	args := []string{
 		"delete",
 		fmt.Sprintf("--wait=%t", wait),
@@ -73,13 +77,14 @@ func (c *Kubectl) Remove(targetNamespace string, yamlDoc []byte, wait bool) ([]b
 	cmd.Stdin = bytes.NewReader(yamlDoc)
 
 	stdout, stderr, err := Run(cmd)
-	return stdout, stderr, errors.Wrap(err, "failed to run kubectl delete")
+	objects := kubectlObjectResults(yamlDoc, stdout, stderr, err)
+	return &Result{Objects: objects, Stdout: stdout, Stderr: stderr}, errors.Wrap(err, "failed to run kubectl delete")
 }
 
-func (c *Kubectl) Apply(targetNamespace string, slug string, yamlDoc []byte, dryRun bool, wait bool, annotateSlug bool) ([]byte, []byte, error) {
+func (c *Kubectl) Apply(targetNamespace string, slug string, yamlDoc []byte, dryRun bool, wait bool, annotateSlug bool) (*Result, error) {
 	tmp, err := ioutil.TempDir("", "")
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to create temp directory")
+		return nil, errors.Wrap(err, "failed to create temp directory")
 	}
 	defer os.Remove(tmp)
 
@@ -105,7 +110,7 @@ func (c *Kubectl) Apply(targetNamespace string, slug string, yamlDoc []byte, dry
 
 	yamlPath := filepath.Join(tmp, "doc.yaml")
 	if err := ioutil.WriteFile(yamlPath, yamlDoc, 0644); err != nil {
-		return nil, nil, errors.Wrapf(err, "failed to write %s", yamlPath)
+		return nil, errors.Wrapf(err, "failed to write %s", yamlPath)
 	}
 
 	kustomizationPath := filepath.Join(tmp, "kustomization.yaml")
@@ -123,15 +128,84 @@ commonAnnotations:
 `, slug)
 	}
 	if err := ioutil.WriteFile(kustomizationPath, []byte(kustomizationYaml), 0644); err != nil {
-		return nil, nil, errors.Wrapf(err, "failed to write %s", kustomizationPath)
+		return nil, errors.Wrapf(err, "failed to write %s", kustomizationPath)
 	}
 
 	cmd := c.kubectlCommand(args...)
 
 	stdout, stderr, err := Run(cmd)
-	return stdout, stderr, errors.Wrap(err, "failed to run kubectl apply")
+	objects := kubectlObjectResults(yamlDoc, stdout, stderr, err)
+	return &Result{Objects: objects, Stdout: stdout, Stderr: stderr}, errors.Wrap(err, "failed to run kubectl apply")
 }
 
 func (c *Kubectl) kubectlCommand(args ...string) *exec.Cmd {
 	return exec.Command(c.kubectl, append(args, c.connectArgs()...)...)
 }
+
+var (
+	// kubectl apply prints "deployment.apps/web configured", followed by " (dry run)" for dry runs
+	kubectlApplyLine = regexp.MustCompile(`^(\S+)/(\S+) (created|configured|unchanged|deleted)\b`)
+	// kubectl delete prints `deployment.apps "web" deleted`
+	kubectlDeleteLine = regexp.MustCompile(`^(\S+) "([^"]+)" (deleted)\b`)
+)
+
+// kubectlObjectResults builds the per-object results of a kubectl run from the line kubectl prints for each object.
+// When kubectl fails, the objects it didn't report are marked as failed with the errors that mention them.
+func kubectlObjectResults(yamlDoc []byte, stdout []byte, stderr []byte, runErr error) []ObjectResult {
+	objs, err := decodeObjects(yamlDoc)
+	if err != nil {
+		return nil
+	}
+
+	actions := parseKubectlActions(stdout)
+
+	objectResults := []ObjectResult{}
+	for _, obj := range objs {
+		objectResult := newObjectResult(obj)
+
+		if action, ok := actions[fmt.Sprintf("%s/%s", objectResult.resourceString(), objectResult.Name)]; ok {
+			objectResult.Action = action
+		} else if runErr != nil {
+			message := kubectlErrorFor(objectResult.Name, stderr)
+			if message == "" {
+				message = runErr.Error()
+			}
+			objectResult = objectResult.failed(errors.New(message))
+		} else {
+			continue
+		}
+
+		objectResults = append(objectResults, objectResult)
+	}
+
+	return objectResults
+}
+
+// parseKubectlActions maps "<resource>/<name>" to the action kubectl reported for it
+func parseKubectlActions(stdout []byte) map[string]Action {
+	actions := map[string]Action{}
+	for _, line := range strings.Split(string(stdout), "\n") {
+		line = strings.TrimSpace(line)
+		for _, re := range []*regexp.Regexp{kubectlApplyLine, kubectlDeleteLine} {
+			if matches := re.FindStringSubmatch(line); matches != nil {
+				actions[fmt.Sprintf("%s/%s", matches[1], matches[2])] = Action(matches[3])
+				break
+			}
+		}
+	}
+	return actions
+}
+
+// kubectlErrorFor returns the stderr lines that mention the object name, or all of stderr if none do
+func kubectlErrorFor(name string, stderr []byte) string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(stderr)), "\n") {
+		if strings.Contains(line, fmt.Sprintf("%q", name)) || strings.Contains(line, fmt.Sprintf("/%s", name)) {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return strings.TrimSpace(string(stderr))
+	}
+	return strings.Join(lines, "\n")
+}
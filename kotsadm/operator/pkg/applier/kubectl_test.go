@@ -0,0 +1,84 @@
+package applier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseKubectlActions(t *testing.T) {
+	stdout := `configmap/a created
+deployment.apps/b configured (dry run)
+service/c unchanged
+secret "d" deleted
+Warning: something unrelated
+`
+	want := map[string]Action{
+		"configmap/a":       ActionCreated,
+		"deployment.apps/b": ActionConfigured,
+		"service/c":         ActionUnchanged,
+		"secret/d":          ActionDeleted,
+	}
+	if got := parseKubectlActions([]byte(stdout)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKubectlActions() = %v, want %v", got, want)
+	}
+}
+
+func Test_kubectlObjectResults(t *testing.T) {
+	yamlDoc := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+`)
+
+	tests := []struct {
+		name   string
+		stdout string
+		stderr string
+		runErr error
+		want   []ObjectResult
+	}{
+		{
+			name:   "all applied",
+			stdout: "configmap/a created\ndeployment.apps/b unchanged\n",
+			want: []ObjectResult{
+				{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "a", Action: ActionCreated},
+				{Group: "apps", Version: "v1", Kind: "Deployment", Name: "b", Action: ActionUnchanged},
+			},
+		},
+		{
+			name:   "one failed",
+			stdout: "configmap/a created\n",
+			stderr: "Error from server (Invalid): error when creating \"doc.yaml\": Deployment.apps \"b\" is invalid: spec.template: Required value\n",
+			runErr: errors.New("exit status 1"),
+			want: []ObjectResult{
+				{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "a", Action: ActionCreated},
+				{Group: "apps", Version: "v1", Kind: "Deployment", Name: "b", Action: ActionFailed, Error: &ObjectError{
+					Message: "Error from server (Invalid): error when creating \"doc.yaml\": Deployment.apps \"b\" is invalid: spec.template: Required value",
+				}},
+			},
+		},
+		{
+			name:   "failed without output",
+			runErr: errors.New("exit status 1"),
+			want: []ObjectResult{
+				{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "a", Action: ActionFailed, Error: &ObjectError{Message: "exit status 1"}},
+				{Group: "apps", Version: "v1", Kind: "Deployment", Name: "b", Action: ActionFailed, Error: &ObjectError{Message: "exit status 1"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kubectlObjectResults(yamlDoc, []byte(tt.stdout), []byte(tt.stderr), tt.runErr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kubectlObjectResults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
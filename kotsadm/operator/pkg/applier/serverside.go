@@ -0,0 +1,349 @@
+package applier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+const (
+	applyWaitTimeout = 5 * time.Minute
+	removeTimeout    = 5 * time.Minute
+)
+
+var _ Applier = (*ServerSide)(nil)
+
+// ServerSide applies manifests with server-side apply, using FieldManager as the owner of the applied fields
+type ServerSide struct {
+	dynamicClient dynamic.Interface
+	mapper        *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func NewServerSide(config *rest.Config) (*ServerSide, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create discovery client")
+	}
+
+	return &ServerSide{
+		dynamicClient: dynamicClient,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}, nil
+}
+
+func (s *ServerSide) Apply(targetNamespace string, slug string, yamlDoc []byte, dryRun bool, wait bool, annotateSlug bool) (*Result, error) {
+	objs, err := decodeObjects(yamlDoc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifests")
+	}
+
+	result := &Result{}
+	for _, obj := range objs {
+		if annotateSlug {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations["kots.io/app-slug"] = slug
+			obj.SetAnnotations(annotations)
+		}
+
+		result.add(s.applyObject(targetNamespace, obj, dryRun, wait), dryRun)
+	}
+
+	if result.HasErr() {
+		return result, errors.New("failed to apply one or more objects")
+	}
+
+	return result, nil
+}
+
+func (s *ServerSide) Remove(targetNamespace string, yamlDoc []byte, wait bool) (*Result, error) {
+	objs, err := decodeObjects(yamlDoc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifests")
+	}
+
+	result := &Result{}
+	for _, obj := range objs {
+		result.add(s.removeObject(targetNamespace, obj, wait), false)
+	}
+
+	if result.HasErr() {
+		return result, errors.New("failed to remove one or more objects")
+	}
+
+	return result, nil
+}
+
+// applyObject applies the object and, if waitForObserved is set, waits for its controller to observe the applied generation
+func (s *ServerSide) applyObject(targetNamespace string, obj *unstructured.Unstructured, dryRun bool, waitForObserved bool) ObjectResult {
+	objectResult := newObjectResult(obj)
+
+	resource, err := s.resourceFor(targetNamespace, obj)
+	if err != nil {
+		return objectResult.failed(err)
+	}
+	objectResult.Namespace = obj.GetNamespace()
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return objectResult.failed(errors.Wrap(err, "failed to marshal object"))
+	}
+
+	existing, err := resource.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return objectResult.failed(err)
+		}
+		existing = nil
+	}
+
+	force := true
+	patchOptions := metav1.PatchOptions{
+		FieldManager: FieldManager,
+		Force:        &force,
+	}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	applied, err := resource.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, data, patchOptions)
+	if err != nil {
+		return objectResult.failed(err)
+	}
+
+	if waitForObserved && !dryRun {
+		err := wait.PollImmediate(time.Second, applyWaitTimeout, func() (bool, error) {
+			current, err := resource.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return isObserved(current), nil
+		})
+		if err != nil {
+			return objectResult.failed(errors.Wrap(err, "failed to wait for object to be observed"))
+		}
+	}
+
+	if existing == nil {
+		objectResult.Action = ActionCreated
+	} else if objectChanged(existing, applied) {
+		objectResult.Action = ActionConfigured
+	} else {
+		objectResult.Action = ActionUnchanged
+	}
+
+	return objectResult
+}
+
+func (s *ServerSide) removeObject(targetNamespace string, obj *unstructured.Unstructured, waitForDelete bool) ObjectResult {
+	objectResult := newObjectResult(obj)
+
+	resource, err := s.resourceFor(targetNamespace, obj)
+	if err != nil {
+		return objectResult.failed(err)
+	}
+	objectResult.Namespace = obj.GetNamespace()
+
+	propagationPolicy := metav1.DeletePropagationBackground
+	err = resource.Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	})
+	if kuberneteserrors.IsNotFound(err) {
+		objectResult.Action = ActionUnchanged
+		return objectResult
+	} else if err != nil {
+		return objectResult.failed(err)
+	}
+
+	if waitForDelete {
+		err := wait.PollImmediate(time.Second, removeTimeout, func() (bool, error) {
+			_, err := resource.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+			if kuberneteserrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		})
+		if err != nil {
+			return objectResult.failed(errors.Wrap(err, "failed to wait for object to be deleted"))
+		}
+	}
+
+	objectResult.Action = ActionDeleted
+	return objectResult
+}
+
+// isObserved returns true if the object's controller has observed its current generation.
+// Objects that don't report status.observedGeneration are always observed.
+func isObserved(obj *unstructured.Unstructured) bool {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil || !found {
+		return true
+	}
+	return observedGeneration >= obj.GetGeneration()
+}
+
+// resourceFor returns the client for the object's resource and defaults the namespace of namespaced objects
+func (s *ServerSide) resourceFor(targetNamespace string, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the kind may have been added by a crd after discovery was cached
+		s.mapper.Reset()
+		mapping, err = s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rest mapping for %s", gvk.String())
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+		return s.dynamicClient.Resource(mapping.Resource), nil
+	}
+
+	if obj.GetNamespace() == "" {
+		namespace := targetNamespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		obj.SetNamespace(namespace)
+	}
+
+	return s.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+func (r *Result) add(objectResult ObjectResult, dryRun bool) {
+	r.Objects = append(r.Objects, objectResult)
+
+	if objectResult.Error != nil {
+		r.Stderr = append(r.Stderr, []byte(objectResult.errorLine())...)
+		return
+	}
+
+	line := objectResult.String()
+	if dryRun {
+		line = fmt.Sprintf("%s (server dry run)", line)
+	}
+	r.Stdout = append(r.Stdout, []byte(line+"\n")...)
+}
+
+func newObjectResult(obj *unstructured.Unstructured) ObjectResult {
+	gvk := obj.GroupVersionKind()
+	return ObjectResult{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+func (o ObjectResult) failed(err error) ObjectResult {
+	o.Action = ActionFailed
+	o.Error = &ObjectError{
+		Reason:  string(kuberneteserrors.ReasonForError(errors.Cause(err))),
+		Message: err.Error(),
+	}
+	return o
+}
+
+// String formats the result the same way as kubectl, e.g. "deployment.apps/web created"
+func (o ObjectResult) String() string {
+	return fmt.Sprintf("%s/%s %s", o.resourceString(), o.Name, o.Action)
+}
+
+func (o ObjectResult) errorLine() string {
+	if o.Error.Reason != "" && o.Error.Reason != string(metav1.StatusReasonUnknown) {
+		return fmt.Sprintf("Error from server (%s): %s: %s\n", o.Error.Reason, o.resourceString(), o.Error.Message)
+	}
+	return fmt.Sprintf("error: %s/%s: %s\n", o.resourceString(), o.Name, o.Error.Message)
+}
+
+func (o ObjectResult) resourceString() string {
+	kind := strings.ToLower(o.Kind)
+	if o.Group == "" {
+		return kind
+	}
+	return fmt.Sprintf("%s.%s", kind, o.Group)
+}
+
+// objectChanged compares the object before and after apply, ignoring fields that are
+// updated on every write
+func objectChanged(before *unstructured.Unstructured, after *unstructured.Unstructured) bool {
+	return !equality.Semantic.DeepEqual(comparableContent(before), comparableContent(after))
+}
+
+func comparableContent(obj *unstructured.Unstructured) map[string]interface{} {
+	content := runtime.DeepCopyJSON(obj.Object)
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(content, "metadata", "generation")
+	unstructured.RemoveNestedField(content, "status")
+	return content
+}
+
+// decodeObjects splits a multi-doc yaml into objects, expanding lists
+func decodeObjects(yamlDoc []byte) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(yamlDoc), 4096)
+	for {
+		content := map[string]interface{}{}
+		if err := decoder.Decode(&content); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to decode yaml")
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetKind() == "" {
+			return nil, errors.Errorf("object %q is missing kind", obj.GetName())
+		}
+
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+
+		list, err := obj.ToList()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert list")
+		}
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+	}
+
+	return objs, nil
+}
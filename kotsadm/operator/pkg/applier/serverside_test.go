@@ -0,0 +1,209 @@
+package applier
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_decodeObjects(t *testing.T) {
+	tests := []struct {
+		name    string
+		yamlDoc string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "multi doc",
+			yamlDoc: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+`,
+			want: []string{"ConfigMap/a", "Deployment/b"},
+		},
+		{
+			name: "empty docs are skipped",
+			yamlDoc: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+---
+`,
+			want: []string{"ConfigMap/a"},
+		},
+		{
+			name: "list is expanded",
+			yamlDoc: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: a
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: b
+`,
+			want: []string{"Secret/a", "Service/b"},
+		},
+		{
+			name: "missing kind",
+			yamlDoc: `apiVersion: v1
+metadata:
+  name: a
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, err := decodeObjects([]byte(tt.yamlDoc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []string{}
+			for _, obj := range objs {
+				got = append(got, obj.GetKind()+"/"+obj.GetName())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeObjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_objectChanged(t *testing.T) {
+	before := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "a",
+			"resourceVersion": "1",
+			"managedFields":   []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"data": map[string]interface{}{"key": "value"},
+	}}
+
+	tests := []struct {
+		name  string
+		after func() *unstructured.Unstructured
+		want  bool
+	}{
+		{
+			name: "only metadata bookkeeping changed",
+			after: func() *unstructured.Unstructured {
+				after := before.DeepCopy()
+				after.SetResourceVersion("2")
+				after.SetManagedFields(nil)
+				return after
+			},
+			want: false,
+		},
+		{
+			name: "data changed",
+			after: func() *unstructured.Unstructured {
+				after := before.DeepCopy()
+				unstructured.SetNestedField(after.Object, "other", "data", "key")
+				return after
+			},
+			want: true,
+		},
+		{
+			name: "annotation added",
+			after: func() *unstructured.Unstructured {
+				after := before.DeepCopy()
+				after.SetAnnotations(map[string]string{"kots.io/app-slug": "my-app"})
+				return after
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectChanged(before, tt.after()); got != tt.want {
+				t.Errorf("objectChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isObserved(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   bool
+	}{
+		{
+			name: "no status",
+			want: true,
+		},
+		{
+			name:   "generation not observed yet",
+			status: map[string]interface{}{"observedGeneration": int64(1)},
+			want:   false,
+		},
+		{
+			name:   "generation observed",
+			status: map[string]interface{}{"observedGeneration": int64(2)},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":       "web",
+					"generation": int64(2),
+				},
+			}}
+			if tt.status != nil {
+				obj.Object["status"] = tt.status
+			}
+			if got := isObserved(obj); got != tt.want {
+				t.Errorf("isObserved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectResult_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ObjectResult
+		want   string
+	}{
+		{
+			name:   "core group",
+			result: ObjectResult{Version: "v1", Kind: "Service", Name: "web", Action: ActionUnchanged},
+			want:   "service/web unchanged",
+		},
+		{
+			name:   "named group",
+			result: ObjectResult{Group: "apps", Version: "v1", Kind: "Deployment", Name: "web", Action: ActionCreated},
+			want:   "deployment.apps/web created",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,13 +8,20 @@ import (
 
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 )
 
 type Monitor struct {
 	clientset       kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	mapper          meta.ResettableRESTMapper
 	targetNamespace string
 	appInformersCh  chan appInformer
 	appStatusCh     chan types.AppStatus
@@ -33,13 +40,15 @@ type appInformer struct {
 	informers []types.StatusInformer
 }
 
-func NewMonitor(clientset kubernetes.Interface, targetNamespace string) *Monitor {
+func NewMonitor(clientset kubernetes.Interface, dynamicClient dynamic.Interface, targetNamespace string) *Monitor {
 	if targetNamespace == "" {
 		targetNamespace = corev1.NamespaceDefault
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &Monitor{
 		clientset:       clientset,
+		dynamicClient:   dynamicClient,
+		mapper:          restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery())),
 		targetNamespace: targetNamespace,
 		appInformersCh:  make(chan appInformer),
 		appStatusCh:     make(chan types.AppStatus),
@@ -92,7 +101,7 @@ func (m *Monitor) run(ctx context.Context) {
 				if appMonitor != nil {
 					appMonitor.Shutdown()
 				}
-				appMonitor = NewAppMonitor(m.clientset, m.targetNamespace, appInformer.appID, appInformer.sequence)
+				appMonitor = NewAppMonitor(m.clientset, m.dynamicClient, m.mapper, m.targetNamespace, appInformer.appID, appInformer.sequence)
 				go func() {
 					for appStatus := range appMonitor.AppStatusChan() {
 						m.appStatusCh <- appStatus
@@ -107,6 +116,8 @@ func (m *Monitor) run(ctx context.Context) {
 
 type AppMonitor struct {
 	clientset       kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	mapper          meta.ResettableRESTMapper
 	targetNamespace string
 	appID           string
 	informersCh     chan []types.StatusInformer
@@ -115,11 +126,13 @@ type AppMonitor struct {
 	sequence        int64
 }
 
-func NewAppMonitor(clientset kubernetes.Interface, targetNamespace, appID string, sequence int64) *AppMonitor {
+func NewAppMonitor(clientset kubernetes.Interface, dynamicClient dynamic.Interface, mapper meta.ResettableRESTMapper, targetNamespace, appID string, sequence int64) *AppMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &AppMonitor{
 		appID:           appID,
 		clientset:       clientset,
+		dynamicClient:   dynamicClient,
+		mapper:          mapper,
 		targetNamespace: targetNamespace,
 		informersCh:     make(chan []types.StatusInformer),
 		appStatusCh:     make(chan types.AppStatus),
@@ -195,7 +208,18 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 
 	// Collect namespace/kind pairs
 	namespaceKinds := make(map[string]map[string][]types.StatusInformer)
+	namespaceCustomResources := make(map[string]map[schema.GroupVersionResource][]types.StatusInformer)
 	for _, informer := range informers {
+		if informer.IsCustomResource() {
+			gvrsInNs, ok := namespaceCustomResources[informer.Namespace]
+			if !ok {
+				gvrsInNs = make(map[schema.GroupVersionResource][]types.StatusInformer)
+			}
+			gvr := customResourceGroupVersionResource(informer)
+			gvrsInNs[gvr] = append(gvrsInNs[gvr], informer)
+			namespaceCustomResources[informer.Namespace] = gvrsInNs
+			continue
+		}
 		kindsInNs, ok := namespaceKinds[informer.Namespace]
 		if !ok {
 			kindsInNs = make(map[string][]types.StatusInformer)
@@ -213,8 +237,11 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 	}
 
 	kindImpls := map[string]runControllerFunc{
+		CronJobResourceKind:               runCronJobController,
+		DaemonSetResourceKind:             runDaemonSetController,
 		DeploymentResourceKind:            runDeploymentController,
 		IngressResourceKind:               runIngressController,
+		JobResourceKind:                   runJobController,
 		PersistentVolumeClaimResourceKind: runPersistentVolumeClaimController,
 		ServiceResourceKind:               runServiceController,
 		StatefulSetResourceKind:           runStatefulSetController,
@@ -228,6 +255,16 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 			}
 		}
 	}
+	for namespace, gvrs := range namespaceCustomResources {
+		for gvr, informers := range gvrs {
+			namespace, gvr, informers := namespace, gvr, informers
+			shutdown.Add(1)
+			go func() {
+				runCustomResourceController(ctx, m.dynamicClient, m.mapper, namespace, gvr, informers, resourceStateCh)
+				shutdown.Done()
+			}()
+		}
+	}
 
 	for {
 		select {
@@ -0,0 +1,226 @@
+package appstate
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CustomResourceStateCalculator calculates the state of a custom resource
+type CustomResourceStateCalculator func(r *unstructured.Unstructured) types.State
+
+var (
+	customResourceStateCalculators   = map[schema.GroupKind]CustomResourceStateCalculator{}
+	customResourceStateCalculatorsMu sync.RWMutex
+)
+
+// RegisterCustomResourceStateCalculator overrides the default state calculation for a custom resource group/kind.
+// Custom resources without a registered calculator use the Ready condition in .status.conditions.
+func RegisterCustomResourceStateCalculator(gk schema.GroupKind, calculator CustomResourceStateCalculator) {
+	customResourceStateCalculatorsMu.Lock()
+	defer customResourceStateCalculatorsMu.Unlock()
+	customResourceStateCalculators[gk] = calculator
+}
+
+func getCustomResourceStateCalculator(gk schema.GroupKind) CustomResourceStateCalculator {
+	customResourceStateCalculatorsMu.RLock()
+	defer customResourceStateCalculatorsMu.RUnlock()
+	if calculator, ok := customResourceStateCalculators[gk]; ok {
+		return calculator
+	}
+	return calculateCustomResourceReadyConditionState
+}
+
+func runCustomResourceController(
+	ctx context.Context, dynamicClient dynamic.Interface, mapper meta.ResettableRESTMapper, targetNamespace string,
+	gvr schema.GroupVersionResource, informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
+) {
+	var mapping *meta.RESTMapping
+	for {
+		m, err := getCustomResourceRESTMapping(mapper, gvr)
+		if err == nil {
+			mapping = m
+			break
+		}
+		log.Printf("Failed to get rest mapping for custom resource %s: %v", gvr.String(), err)
+
+		// the custom resource definition may not have been created yet
+		mapper.Reset()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+
+	var resourceClient dynamic.ResourceInterface
+	if namespaced {
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(targetNamespace)
+	} else {
+		resourceClient = dynamicClient.Resource(mapping.Resource)
+	}
+
+	listwatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return resourceClient.List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return resourceClient.Watch(context.TODO(), options)
+		},
+	}
+	informer := cache.NewSharedInformer(
+		listwatch,
+		&unstructured.Unstructured{},
+		time.Minute,
+	)
+
+	eventHandler := NewCustomResourceEventHandler(
+		informers,
+		namespaced,
+		getCustomResourceStateCalculator(mapping.GroupVersionKind.GroupKind()),
+		resourceStateCh,
+	)
+
+	runInformer(ctx, informer, eventHandler)
+	return
+}
+
+func getCustomResourceRESTMapping(mapper meta.RESTMapper, gvr schema.GroupVersionResource) (*meta.RESTMapping, error) {
+	fullySpecifiedGVR, err := mapper.ResourceFor(gvr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get resource")
+	}
+	gvk, err := mapper.KindFor(fullySpecifiedGVR)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kind")
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get rest mapping")
+	}
+	return mapping, nil
+}
+
+type customResourceEventHandler struct {
+	informers       []types.StatusInformer
+	namespaced      bool
+	calculator      CustomResourceStateCalculator
+	resourceStateCh chan<- types.ResourceState
+}
+
+func NewCustomResourceEventHandler(informers []types.StatusInformer, namespaced bool, calculator CustomResourceStateCalculator, resourceStateCh chan<- types.ResourceState) *customResourceEventHandler {
+	return &customResourceEventHandler{
+		informers:       informers,
+		namespaced:      namespaced,
+		calculator:      calculator,
+		resourceStateCh: resourceStateCh,
+	}
+}
+
+func (h *customResourceEventHandler) ObjectCreated(obj interface{}) {
+	r := h.cast(obj)
+	informer, ok := h.getInformer(r)
+	if !ok {
+		return
+	}
+	h.resourceStateCh <- makeCustomResourceResourceState(informer, h.calculator(r))
+}
+
+func (h *customResourceEventHandler) ObjectUpdated(obj interface{}) {
+	r := h.cast(obj)
+	informer, ok := h.getInformer(r)
+	if !ok {
+		return
+	}
+	h.resourceStateCh <- makeCustomResourceResourceState(informer, h.calculator(r))
+}
+
+func (h *customResourceEventHandler) ObjectDeleted(obj interface{}) {
+	r := h.cast(obj)
+	informer, ok := h.getInformer(r)
+	if !ok {
+		return
+	}
+	h.resourceStateCh <- makeCustomResourceResourceState(informer, types.StateMissing)
+}
+
+func (h *customResourceEventHandler) cast(obj interface{}) *unstructured.Unstructured {
+	r, _ := obj.(*unstructured.Unstructured)
+	return r
+}
+
+func (h *customResourceEventHandler) getInformer(r *unstructured.Unstructured) (types.StatusInformer, bool) {
+	if r != nil {
+		for _, informer := range h.informers {
+			if h.namespaced && r.GetNamespace() != informer.Namespace {
+				continue
+			}
+			if r.GetName() == informer.Name {
+				return informer, true
+			}
+		}
+	}
+	return types.StatusInformer{}, false
+}
+
+// makeCustomResourceResourceState uses the informer rather than the object so that the resource state
+// matches the one built from the status informers, even for cluster scoped resources.
+func makeCustomResourceResourceState(informer types.StatusInformer, state types.State) types.ResourceState {
+	return types.ResourceState{
+		Kind:      informer.Kind,
+		Name:      informer.Name,
+		Namespace: informer.Namespace,
+		State:     state,
+	}
+}
+
+func calculateCustomResourceReadyConditionState(r *unstructured.Unstructured) types.State {
+	conditions, found, err := unstructured.NestedSlice(r.Object, "status", "conditions")
+	if err != nil || !found {
+		// without a ready condition the only thing we know is that the resource exists
+		return types.StateReady
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType != "Ready" {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		switch strings.ToLower(status) {
+		case "true":
+			return types.StateReady
+		case "false":
+			return types.StateUnavailable
+		default:
+			return types.StateDegraded
+		}
+	}
+	return types.StateReady
+}
+
+func customResourceGroupVersionResource(informer types.StatusInformer) schema.GroupVersionResource {
+	// the rest mapper will match the lowercase kind to the plural resource name
+	return schema.GroupVersionResource{
+		Group:    informer.Group,
+		Version:  informer.Version,
+		Resource: strings.ToLower(informer.Kind),
+	}
+}
@@ -0,0 +1,60 @@
+package appstate
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_calculateCustomResourceReadyConditionState(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   types.State
+	}{
+		{
+			name: "no status",
+			want: types.StateReady,
+		},
+		{
+			name: "ready",
+			status: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Synced", "status": "False"},
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			},
+			want: types.StateReady,
+		},
+		{
+			name: "not ready",
+			status: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False"},
+				},
+			},
+			want: types.StateUnavailable,
+		},
+		{
+			name: "unknown",
+			status: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "Unknown"},
+				},
+			},
+			want: types.StateDegraded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.status != nil {
+				r.Object["status"] = tt.status
+			}
+			if got := calculateCustomResourceReadyConditionState(r); got != tt.want {
+				t.Errorf("calculateCustomResourceReadyConditionState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,6 +23,16 @@ func Test_getResourceKindCommonName(t *testing.T) {
 			args: args{a: "sts"},
 			want: "statefulset",
 		},
+		{
+			name: "daemonset",
+			args: args{a: "ds"},
+			want: "daemonset",
+		},
+		{
+			name: "cronjob",
+			args: args{a: "cronjobs"},
+			want: "cronjob",
+		},
 		{
 			name: "uppercase",
 			args: args{a: "StatefulSet"},
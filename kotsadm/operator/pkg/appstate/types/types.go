@@ -12,18 +12,38 @@ var (
 	StateUnavailable State = "unavailable"
 	StateMissing     State = "missing"
 
-	StatusInformerRegexp = regexp.MustCompile(`^(?:([^\/]+)\/)?([^\/]+)\/([^\/]+)$`)
+	StatusInformerRegexp               = regexp.MustCompile(`^(?:([^\/]+)\/)?([^\/]+)\/([^\/]+)$`)
+	CustomResourceStatusInformerRegexp = regexp.MustCompile(`^(?:([^\/]+)\/)?([^\/]+\.[^\/]+)\/([^\/]+)\/([^\/]+)\/([^\/]+)$`)
 )
 
 type StatusInformerString string
 
 type StatusInformer struct {
+	Group     string
+	Version   string
 	Kind      string
 	Name      string
 	Namespace string
 }
 
+// IsCustomResource returns true if the informer references a resource by group/version/kind
+// rather than one of the built-in kinds.
+func (i StatusInformer) IsCustomResource() bool {
+	return i.Group != "" && i.Version != ""
+}
+
+// Parse parses status informer strings in the format [namespace/]kind/name for built-in kinds and
+// [namespace/]group/version/kind/name for custom resources.
 func (s StatusInformerString) Parse() (i StatusInformer, err error) {
+	if matches := CustomResourceStatusInformerRegexp.FindStringSubmatch(string(s)); len(matches) == 6 {
+		i.Namespace = matches[1]
+		i.Group = matches[2]
+		i.Version = matches[3]
+		i.Kind = matches[4]
+		i.Name = matches[5]
+		return
+	}
+
 	matches := StatusInformerRegexp.FindStringSubmatch(string(s))
 	if len(matches) != 4 {
 		err = errors.New("status informer format string incorrect")
@@ -29,6 +29,40 @@ func TestStatusInformerString_Parse(t *testing.T) {
 				Name:      "sentry-web",
 			},
 		},
+		{
+			name: "kind/name daemonset",
+			str:  "ds/node-agent",
+			want: StatusInformer{
+				Kind: "ds",
+				Name: "node-agent",
+			},
+		},
+		{
+			name: "group/version/kind/name",
+			str:  "example.com/v1/Widget/my-widget",
+			want: StatusInformer{
+				Group:   "example.com",
+				Version: "v1",
+				Kind:    "Widget",
+				Name:    "my-widget",
+			},
+		},
+		{
+			name: "namespace/group/version/kind/name",
+			str:  "default/example.com/v1/Widget/my-widget",
+			want: StatusInformer{
+				Namespace: "default",
+				Group:     "example.com",
+				Version:   "v1",
+				Kind:      "Widget",
+				Name:      "my-widget",
+			},
+		},
+		{
+			name:    "group without dot",
+			str:     "example/v1/Widget/my-widget",
+			wantErr: true,
+		},
 		{
 			name:    "no match",
 			str:     "sentry-web",
@@ -8,7 +8,9 @@ import (
 
 func normalizeStatusInformers(informers []types.StatusInformer, targetNamespace string) (next []types.StatusInformer) {
 	for _, informer := range informers {
-		informer.Kind = getResourceKindCommonName(informer.Kind)
+		if !informer.IsCustomResource() {
+			informer.Kind = getResourceKindCommonName(informer.Kind)
+		}
 		if informer.Namespace == "" {
 			informer.Namespace = targetNamespace
 		}
@@ -25,6 +25,7 @@ import (
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/supportbundle"
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -37,6 +38,7 @@ type ApplicationManifests struct {
 	AppID                string                `json:"app_id"`
 	AppSlug              string                `json:"app_slug"`
 	KubectlVersion       string                `json:"kubectl_version"`
+	Applier              string                `json:"applier"`
 	AdditionalNamespaces []string              `json:"additional_namespaces"`
 	ImagePullSecret      string                `json:"image_pull_secret"`
 	Namespace            string                `json:"namespace"`
@@ -195,7 +197,12 @@ func (c *Client) connect() error {
 		return errors.Wrap(err, "failed to get new kubernetes client")
 	}
 
-	c.appStateMonitor = appstate.NewMonitor(clientset, c.TargetNamespace)
+	dynamicClient, err := dynamic.NewForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get new dynamic client")
+	}
+
+	c.appStateMonitor = appstate.NewMonitor(clientset, dynamicClient, c.TargetNamespace)
 	defer c.appStateMonitor.Shutdown()
 
 	go c.runAppStateMonitor()
@@ -406,16 +413,18 @@ func (c *Client) sendResult(applicationManifests ApplicationManifests, dryRunRes
 	log.Printf("Reporting results to %q", uri)
 
 	result := struct {
-		AppID        string `json:"appId"`
-		IsError      bool   `json:"isError"`
-		DryrunStdout []byte `json:"dryrunStdout"`
-		DryrunStderr []byte `json:"dryrunStderr"`
-		ApplyStdout  []byte `json:"applyStdout"`
-		ApplyStderr  []byte `json:"applyStderr"`
-		HelmStdout   []byte `json:"helmStdout"`
-		HelmStderr   []byte `json:"helmStderr"`
+		AppID        string             `json:"appId"`
+		IsError      bool               `json:"isError"`
+		DryrunStdout []byte             `json:"dryrunStdout"`
+		DryrunStderr []byte             `json:"dryrunStderr"`
+		ApplyStdout  []byte             `json:"applyStdout"`
+		ApplyStderr  []byte             `json:"applyStderr"`
+		HelmStdout   []byte             `json:"helmStdout"`
+		HelmStderr   []byte             `json:"helmStderr"`
+		Resources    []deployedResource `json:"resources"`
 	}{
-		AppID: applicationManifests.AppID,
+		AppID:     applicationManifests.AppID,
+		Resources: []deployedResource{},
 	}
 
 	isError := false
@@ -423,12 +432,14 @@ func (c *Client) sendResult(applicationManifests ApplicationManifests, dryRunRes
 		isError = isError || dryRunResult.hasErr
 		result.DryrunStdout = bytes.Join(dryRunResult.multiStdout, []byte("\n"))
 		result.DryrunStderr = bytes.Join(dryRunResult.multiStderr, []byte("\n"))
+		result.Resources = append(result.Resources, dryRunResult.deployedResources(deployPhaseDryrun)...)
 	}
 
 	if applyResult != nil {
 		isError = isError || applyResult.hasErr
 		result.ApplyStdout = bytes.Join(applyResult.multiStdout, []byte("\n"))
 		result.ApplyStderr = bytes.Join(applyResult.multiStderr, []byte("\n"))
+		result.Resources = append(result.Resources, applyResult.deployedResources(deployPhaseApply)...)
 	}
 
 	if helmResult != nil {
@@ -505,16 +516,26 @@ func (c *Client) sendAppStatus(appStatus types.AppStatus) error {
 	return nil
 }
 
-func (c *Client) getApplier(kubectlVersion string) (*applier.Kubectl, error) {
-	kubectl, err := util.FindKubectlVersion(kubectlVersion)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to find kubectl")
-	}
-
+func (c *Client) getApplier(applierName string, kubectlVersion string) (applier.Applier, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get in cluster config")
 	}
 
-	return applier.NewKubectl(kubectl, config), nil
+	switch applierName {
+	case "", applier.KubectlApplier:
+		kubectl, err := util.FindKubectlVersion(kubectlVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find kubectl")
+		}
+		return applier.NewKubectl(kubectl, config), nil
+	case applier.ServerSideApplier:
+		serverSide, err := applier.NewServerSide(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create server-side applier")
+		}
+		return serverSide, nil
+	default:
+		return nil, errors.Errorf("unknown applier %q", applierName)
+	}
 }
@@ -13,7 +13,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/applier"
-	"github.com/replicatedhq/kots/kotsadm/operator/pkg/util"
 	"github.com/replicatedhq/yaml/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -41,6 +40,61 @@ type commandResult struct {
 	hasErr      bool
 	multiStdout [][]byte
 	multiStderr [][]byte
+	objects     []applier.ObjectResult
+}
+
+// add appends the output of an applier call to the command result
+func (r *commandResult) add(result *applier.Result) {
+	if result == nil {
+		return
+	}
+	if len(result.Stdout) > 0 {
+		r.multiStdout = append(r.multiStdout, result.Stdout)
+	}
+	if len(result.Stderr) > 0 {
+		r.multiStderr = append(r.multiStderr, result.Stderr)
+	}
+	r.objects = append(r.objects, result.Objects...)
+}
+
+func (r *commandResult) deployedResources(phase string) []deployedResource {
+	resources := []deployedResource{}
+	for _, o := range r.objects {
+		resources = append(resources, deployedResource{
+			Phase:        phase,
+			ObjectResult: o,
+		})
+	}
+	return resources
+}
+
+// summary counts the objects in the result by action, e.g. "2 created, 1 unchanged"
+func (r *commandResult) summary() string {
+	counts := map[applier.Action]int{}
+	actions := []applier.Action{}
+	for _, o := range r.objects {
+		if _, ok := counts[o.Action]; !ok {
+			actions = append(actions, o.Action)
+		}
+		counts[o.Action]++
+	}
+
+	parts := []string{}
+	for _, action := range actions {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[action], action))
+	}
+	return strings.Join(parts, ", ")
+}
+
+const (
+	deployPhaseDryrun = "dryrun"
+	deployPhaseApply  = "apply"
+)
+
+// deployedResource is the per-object result reported to the kotsadm api
+type deployedResource struct {
+	Phase string `json:"phase"`
+	applier.ObjectResult
 }
 
 type deployResult struct {
@@ -114,18 +168,10 @@ func (c *Client) diffAndRemovePreviousManifests(applicationManifests Application
 	}
 
 	// now remove anything that's in previous but not in current
-	kubectl, err := util.FindKubectlVersion(applicationManifests.KubectlVersion)
+	kubernetesApplier, err := c.getApplier(applicationManifests.Applier, applicationManifests.KubectlVersion)
 	if err != nil {
-		return errors.Wrap(err, "failed to find kubectl")
+		return errors.Wrap(err, "failed to get applier")
 	}
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "failed to get in cluster config")
-	}
-
-	// this is pretty raw, and required kubectl...  we should
-	// consider some other options here?
-	kubernetesApplier := applier.NewKubectl(kubectl, config)
 
 	allPVCs := make([]string, 0)
 	for k, previous := range decodedPreviousMap {
@@ -179,10 +225,12 @@ func (c *Client) diffAndRemovePreviousManifests(applicationManifests Application
 			wait = false
 		}
 
-		stdout, stderr, err := kubernetesApplier.Remove(namespace, []byte(previous.spec), wait)
+		removeResult, err := kubernetesApplier.Remove(namespace, []byte(previous.spec), wait)
 		if err != nil {
-			log.Printf("stdout (delete) = %s", stdout)
-			log.Printf("stderr (delete) = %s", stderr)
+			if removeResult != nil {
+				log.Printf("stdout (delete) = %s", removeResult.Stdout)
+				log.Printf("stderr (delete) = %s", removeResult.Stderr)
+			}
 			log.Printf("error: %s", err.Error())
 		} else {
 			log.Printf("manifest(s) deleted: %s/%s/%s", group, kind, name)
@@ -263,7 +311,7 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 		targetNamespace = applicationManifests.Namespace
 	}
 
-	kubernetesApplier, err := c.getApplier(applicationManifests.KubectlVersion)
+	kubernetesApplier, err := c.getApplier(applicationManifests.Applier, applicationManifests.KubectlVersion)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get applier")
 	}
@@ -294,18 +342,14 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 			}
 
 			log.Printf("dry run applying manifests(s) in requested namespace: %s", requestedNamespace)
-			dryrunStdout, dryrunStderr, dryRunErr := kubernetesApplier.Apply(requestedNamespace, applicationManifests.AppSlug, docs, true, applicationManifests.Wait, applicationManifests.AnnotateSlug)
-
-			if len(dryrunStdout) > 0 {
-				deployRes.dryRunResult.multiStdout = append(deployRes.dryRunResult.multiStdout, dryrunStdout)
-			}
-			if len(dryrunStderr) > 0 {
-				deployRes.dryRunResult.multiStderr = append(deployRes.dryRunResult.multiStderr, dryrunStderr)
-			}
+			dryRunResult, dryRunErr := kubernetesApplier.Apply(requestedNamespace, applicationManifests.AppSlug, docs, true, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+			deployRes.dryRunResult.add(dryRunResult)
 
 			if dryRunErr != nil {
-				log.Printf("stdout (dryrun) = %s", dryrunStdout)
-				log.Printf("stderr (dryrun) = %s", dryrunStderr)
+				if dryRunResult != nil {
+					log.Printf("stdout (dryrun) = %s", dryRunResult.Stdout)
+					log.Printf("stderr (dryrun) = %s", dryRunResult.Stderr)
+				}
 				log.Printf("error: %s", dryRunErr.Error())
 
 				deployRes.dryRunResult.hasErr = true
@@ -322,18 +366,14 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 
 		// CRDs don't have namespaces, so we can skip splitting
 
-		applyStdout, applyStderr, applyErr := kubernetesApplier.Apply("", applicationManifests.AppSlug, firstApplyDocs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
-
-		if len(applyStdout) > 0 {
-			deployRes.applyResult.multiStdout = append(deployRes.applyResult.multiStdout, applyStdout)
-		}
-		if len(applyStderr) > 0 {
-			deployRes.applyResult.multiStderr = append(deployRes.applyResult.multiStderr, applyStderr)
-		}
+		applyResult, applyErr := kubernetesApplier.Apply("", applicationManifests.AppSlug, firstApplyDocs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+		deployRes.applyResult.add(applyResult)
 
 		if applyErr != nil {
-			log.Printf("stdout (first apply) = %s", applyStdout)
-			log.Printf("stderr (first apply) = %s", applyStderr)
+			if applyResult != nil {
+				log.Printf("stdout (first apply) = %s", applyResult.Stdout)
+				log.Printf("stderr (first apply) = %s", applyResult.Stderr)
+			}
 			log.Printf("error (CRDS): %s", applyErr.Error())
 
 			deployRes.applyResult.hasErr = true
@@ -351,34 +391,29 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 		return nil, errors.Wrap(err, "failed to get docs by requested namespace")
 	}
 
-	var hasErr bool
-	var multiStdout, multiStderr [][]byte
 	for requestedNamespace, docs := range byNamespace {
 		if len(docs) == 0 {
 			continue
 		}
 
 		log.Printf("applying manifest(s) in namespace %s", requestedNamespace)
-		applyStdout, applyStderr, applyErr := kubernetesApplier.Apply(requestedNamespace, applicationManifests.AppSlug, docs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+		applyResult, applyErr := kubernetesApplier.Apply(requestedNamespace, applicationManifests.AppSlug, docs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
 		if applyErr != nil {
-			log.Printf("stdout (apply) = %s", applyStdout)
-			log.Printf("stderr (apply) = %s", applyStderr)
+			if applyResult != nil {
+				log.Printf("stdout (apply) = %s", applyResult.Stdout)
+				log.Printf("stderr (apply) = %s", applyResult.Stderr)
+			}
 			log.Printf("error: %s", applyErr.Error())
-			hasErr = true
+			deployRes.applyResult.hasErr = true
 		} else {
 			log.Printf("manifest(s) applied in namespace %s", requestedNamespace)
 		}
-		if len(applyStdout) > 0 {
-			multiStdout = append(multiStdout, applyStdout)
-		}
-		if len(applyStderr) > 0 {
-			multiStderr = append(multiStderr, applyStderr)
-		}
+		deployRes.applyResult.add(applyResult)
 	}
 
-	deployRes.applyResult.hasErr = hasErr
-	deployRes.applyResult.multiStdout = multiStdout
-	deployRes.applyResult.multiStderr = multiStderr
+	if len(deployRes.applyResult.objects) > 0 {
+		log.Printf("applied object(s): %s", deployRes.applyResult.summary())
+	}
 
 	return &deployRes, nil
 }
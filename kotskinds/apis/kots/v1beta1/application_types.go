@@ -44,19 +44,60 @@ type ApplicationList struct {
 
 // ApplicationSpec defines the desired state of ApplicationSpec
 type ApplicationSpec struct {
-	Title                        string            `json:"title"`
-	Icon                         string            `json:"icon,omitempty"`
-	ApplicationPorts             []ApplicationPort `json:"ports,omitempty"`
-	ReleaseNotes                 string            `json:"releaseNotes,omitempty"`
-	AllowRollback                bool              `json:"allowRollback,omitempty"`
-	StatusInformers              []string          `json:"statusInformers,omitempty"`
-	Graphs                       []MetricGraph     `json:"graphs,omitempty"`
-	KubectlVersion               string            `json:"kubectlVersion,omitempty"`
-	KustomizeVersion             string            `json:"kustomizeVersion,omitempty"`
-	AdditionalImages             []string          `json:"additionalImages,omitempty"`
-	AdditionalNamespaces         []string          `json:"additionalNamespaces,omitempty"`
-	RequireMinimalRBACPrivileges bool              `json:"requireMinimalRBACPrivileges,omitempty"`
-	ProxyPublicImages            bool              `json:"proxyPublicImages,omitempty"`
+	Title                        string             `json:"title"`
+	Icon                         string             `json:"icon,omitempty"`
+	ApplicationPorts             []ApplicationPort  `json:"ports,omitempty"`
+	ReleaseNotes                 string             `json:"releaseNotes,omitempty"`
+	AllowRollback                bool               `json:"allowRollback,omitempty"`
+	AutomaticRollback            *AutomaticRollback `json:"automaticRollback,omitempty"`
+	RolloutGates                 *RolloutGates      `json:"rolloutGates,omitempty"`
+	StatusInformers              []string           `json:"statusInformers,omitempty"`
+	Graphs                       []MetricGraph      `json:"graphs,omitempty"`
+	KubectlVersion               string             `json:"kubectlVersion,omitempty"`
+	Applier                      string             `json:"applier,omitempty"`
+	KustomizeVersion             string             `json:"kustomizeVersion,omitempty"`
+	AdditionalImages             []string           `json:"additionalImages,omitempty"`
+	AdditionalNamespaces         []string           `json:"additionalNamespaces,omitempty"`
+	RequireMinimalRBACPrivileges bool               `json:"requireMinimalRBACPrivileges,omitempty"`
+	ProxyPublicImages            bool               `json:"proxyPublicImages,omitempty"`
+	// TemplateLookupNamespaces are the namespaces, other than the one the app is deployed to,
+	// that the SecretKey and ConfigMapKey template functions may read from
+	TemplateLookupNamespaces []string `json:"templateLookupNamespaces,omitempty"`
+}
+
+// AutomaticRollback redeploys the previously deployed version when a deploy fails to apply,
+// when the application stays in one of the failure states for the unhealthy grace period during the health window,
+// or when it is in one of the failure states once the health window has passed
+type AutomaticRollback struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// HealthWindow is a duration such as "5m", measured from the time the version was deployed
+	HealthWindow string `json:"healthWindow,omitempty"`
+	// UnhealthyGracePeriod is a duration such as "2m", and defaults to 2m. Set it to the health window
+	// to only roll back versions that are in a failure state at the end of the health window.
+	UnhealthyGracePeriod string `json:"unhealthyGracePeriod,omitempty"`
+	// FailureStates defaults to degraded and unavailable
+	FailureStates []string `json:"failureStates,omitempty"`
+}
+
+// RolloutGates must all pass before a deployed version is marked as deployed
+type RolloutGates struct {
+	// Timeout is a duration such as "10m". The version is marked as failed if the gates have not passed by then.
+	Timeout string `json:"timeout,omitempty"`
+	// StatusInformers waits for all status informers to report ready
+	StatusInformers bool            `json:"statusInformers,omitempty"`
+	Prometheus      *PrometheusGate `json:"prometheus,omitempty"`
+	HTTP            *HTTPGate       `json:"http,omitempty"`
+}
+
+type PrometheusGate struct {
+	// Query passes when it returns at least one sample and every sample value is non-zero
+	Query string `json:"query"`
+}
+
+type HTTPGate struct {
+	URL string `json:"url"`
+	// ExpectedStatusCode defaults to any 2xx status code
+	ExpectedStatusCode int `json:"expectedStatusCode,omitempty"`
 }
 
 type ApplicationPort struct {
@@ -56,12 +56,27 @@ type ConfigItem struct {
 	CountByGroup  map[string]int         `json:"countByGroup,omitempty"`
 	Templates     []RepeatTemplate       `json:"templates,omitempty"`
 	ValuesByGroup ValuesByGroup          `json:"valuesByGroup,omitempty"`
+	Validation    *ConfigItemValidation  `json:"validation,omitempty"`
 	// Props       map[string]interface{} `json:"props,omitempty"`
 	// DefaultCmd  *ConfigItemCmd         `json:"default_cmd,omitempty"`
 	// ValueCmd    *ConfigItemCmd         `json:"value_cmd,omitempty"`
 	// DataCmd     *ConfigItemCmd         `json:"data_cmd,omitempty"`
 }
 
+// ConfigItemValidation describes the rules a config item value must satisfy.
+// Empty values are not validated, use Required for that.
+type ConfigItemValidation struct {
+	Regex     string   `json:"regex,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Min       string   `json:"min,omitempty"`
+	Max       string   `json:"max,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+	// Type is one of url, hostname, email, cidr or pem
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
 type RepeatTemplate struct {
 	APIVersion string `json:"apiVersion"`
 	Kind       string `json:"kind"`
@@ -31,6 +31,7 @@ type IdentityConfigSpec struct {
 	Enabled                bool                    `json:"enabled" yaml:"enabled"`
 	DisablePasswordAuth    bool                    `json:"disablePasswordAuth,omitempty" yaml:"disablePasswordAuth,omitempty"`
 	Groups                 []IdentityConfigGroup   `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Roles                  []IdentityConfigRole    `json:"roles,omitempty" yaml:"roles,omitempty"`
 	IngressConfig          IngressConfigSpec       `json:"ingressConfig,omitempty" yaml:"ingressConfig,omitempty"`
 	AdminConsoleAddress    string                  `json:"adminConsoleAddress,omitempty" yaml:"adminConsoleAddress,omitempty"` // TODO (ethan): this does not belong here
 	IdentityServiceAddress string                  `json:"identityServiceAddress,omitempty" yaml:"identityServiceAddress,omitempty"`
@@ -93,6 +94,23 @@ type IdentityConfigGroup struct {
 	RoleIDs []string `json:"roleIds" yaml:"roleIds"`
 }
 
+// IdentityConfigRole is a custom role that can be mapped to groups in addition to the built in roles.
+// Policies match the action and the dot separated resource names used by the admin console api with globs.
+type IdentityConfigRole struct {
+	ID          string                 `json:"id" yaml:"id"`
+	Name        string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Allow       []IdentityConfigPolicy `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny        []IdentityConfigPolicy `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+type IdentityConfigPolicy struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Action      string `json:"action" yaml:"action"`
+	Resource    string `json:"resource" yaml:"resource"`
+}
+
 type DexConnectors struct {
 	Value          []DexConnector       `json:"value,omitempty" yaml:"value,omitempty"`
 	ValueEncrypted string               `json:"valueEncrypted,omitempty" yaml:"valueEncrypted,omitempty"`
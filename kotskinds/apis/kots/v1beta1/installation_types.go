@@ -29,6 +29,7 @@ type InstallationSpec struct {
 	ReleaseNotes  string                  `json:"releaseNotes,omitempty"`
 	ReleasedAt    *metav1.Time            `json:"releasedAt,omitempty"`
 	EncryptionKey string                  `json:"encryptionKey,omitempty"`
+	CommitSHA     string                  `json:"commitSHA,omitempty"`
 	KnownImages   []InstallationImage     `json:"knownImages,omitempty"`
 	YAMLErrors    []InstallationYAMLError `json:"yamlErrors,omitempty"`
 }
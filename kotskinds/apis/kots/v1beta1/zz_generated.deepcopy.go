@@ -201,6 +201,16 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		*out = make([]ApplicationPort, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutomaticRollback != nil {
+		in, out := &in.AutomaticRollback, &out.AutomaticRollback
+		*out = new(AutomaticRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutGates != nil {
+		in, out := &in.RolloutGates, &out.RolloutGates
+		*out = new(RolloutGates)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StatusInformers != nil {
 		in, out := &in.StatusInformers, &out.StatusInformers
 		*out = make([]string, len(*in))
@@ -223,6 +233,11 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TemplateLookupNamespaces != nil {
+		in, out := &in.TemplateLookupNamespaces, &out.TemplateLookupNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -250,6 +265,26 @@ func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticRollback) DeepCopyInto(out *AutomaticRollback) {
+	*out = *in
+	if in.FailureStates != nil {
+		in, out := &in.FailureStates, &out.FailureStates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticRollback.
+func (in *AutomaticRollback) DeepCopy() *AutomaticRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartIdentifier) DeepCopyInto(out *ChartIdentifier) {
 	*out = *in
@@ -375,6 +410,11 @@ func (in *ConfigItem) DeepCopyInto(out *ConfigItem) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ConfigItemValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigItem.
@@ -387,6 +427,36 @@ func (in *ConfigItem) DeepCopy() *ConfigItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigItemValidation) DeepCopyInto(out *ConfigItemValidation) {
+	*out = *in
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int)
+		**out = **in
+	}
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigItemValidation.
+func (in *ConfigItemValidation) DeepCopy() *ConfigItemValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigItemValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigList) DeepCopyInto(out *ConfigList) {
 	*out = *in
@@ -682,6 +752,21 @@ func (in GroupValues) DeepCopy() GroupValues {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGate) DeepCopyInto(out *HTTPGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGate.
+func (in *HTTPGate) DeepCopy() *HTTPGate {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmChart) DeepCopyInto(out *HelmChart) {
 	*out = *in
@@ -904,6 +989,46 @@ func (in *IdentityConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityConfigPolicy) DeepCopyInto(out *IdentityConfigPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityConfigPolicy.
+func (in *IdentityConfigPolicy) DeepCopy() *IdentityConfigPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityConfigPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityConfigRole) DeepCopyInto(out *IdentityConfigRole) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]IdentityConfigPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]IdentityConfigPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityConfigRole.
+func (in *IdentityConfigRole) DeepCopy() *IdentityConfigRole {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityConfigRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IdentityConfigSpec) DeepCopyInto(out *IdentityConfigSpec) {
 	*out = *in
@@ -914,6 +1039,13 @@ func (in *IdentityConfigSpec) DeepCopyInto(out *IdentityConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]IdentityConfigRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.IngressConfig.DeepCopyInto(&out.IngressConfig)
 	in.Storage.DeepCopyInto(&out.Storage)
 	if in.ClientSecret != nil {
@@ -1570,6 +1702,21 @@ func (in *OptionalValue) DeepCopy() *OptionalValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusGate) DeepCopyInto(out *PrometheusGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusGate.
+func (in *PrometheusGate) DeepCopy() *PrometheusGate {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepeatTemplate) DeepCopyInto(out *RepeatTemplate) {
 	*out = *in
@@ -1585,6 +1732,31 @@ func (in *RepeatTemplate) DeepCopy() *RepeatTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutGates) DeepCopyInto(out *RolloutGates) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusGate)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPGate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutGates.
+func (in *RolloutGates) DeepCopy() *RolloutGates {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutGates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -4,16 +4,20 @@ import (
 	"time"
 
 	v1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	storetypes "github.com/replicatedhq/kots/pkg/store/types"
 )
 
 type Downstream struct {
-	ClusterID        string `json:"id"`
-	ClusterSlug      string `json:"slug"`
-	Name             string `json:"name"`
-	CurrentSequence  int64  `json:"currentSequence"`
-	SnapshotSchedule string `json:"snapshotSchedule,omitempty"`
-	SnapshotTTL      string `json:"snapshotTtl,omitempty"`
+	ClusterID         string                         `json:"id"`
+	ClusterSlug       string                         `json:"slug"`
+	Name              string                         `json:"name"`
+	CurrentSequence   int64                          `json:"currentSequence"`
+	SnapshotSchedule  string                         `json:"snapshotSchedule,omitempty"`
+	SnapshotTTL       string                         `json:"snapshotTtl,omitempty"`
+	SnapshotRetention *snapshottypes.RetentionPolicy `json:"snapshotRetention,omitempty"`
+	// SnapshotVerification schedules verification of the newest instance backup
+	SnapshotVerification *snapshottypes.VerificationPolicy `json:"snapshotVerification,omitempty"`
 }
 
 type DownstreamVersion struct {
@@ -37,11 +41,34 @@ type DownstreamVersion struct {
 }
 
 type DownstreamOutput struct {
-	DryrunStdout string `json:"dryrunStdout"`
-	DryrunStderr string `json:"dryrunStderr"`
-	ApplyStdout  string `json:"applyStdout"`
-	ApplyStderr  string `json:"applyStderr"`
-	HelmStdout   string `json:"helmStdout"`
-	HelmStderr   string `json:"helmStderr"`
-	RenderError  string `json:"renderError"`
+	DryrunStdout string             `json:"dryrunStdout"`
+	DryrunStderr string             `json:"dryrunStderr"`
+	ApplyStdout  string             `json:"applyStdout"`
+	ApplyStderr  string             `json:"applyStderr"`
+	HelmStdout   string             `json:"helmStdout"`
+	HelmStderr   string             `json:"helmStderr"`
+	RenderError  string             `json:"renderError"`
+	Resources    []DeployedResource `json:"resources,omitempty"`
+}
+
+const (
+	DeployPhaseDryrun = "dryrun"
+	DeployPhaseApply  = "apply"
+)
+
+// DeployedResource is the result of applying a single resource, as reported by the operator
+type DeployedResource struct {
+	Phase     string                 `json:"phase"`
+	Group     string                 `json:"group"`
+	Version   string                 `json:"version"`
+	Kind      string                 `json:"kind"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Name      string                 `json:"name"`
+	Action    string                 `json:"action"`
+	Error     *DeployedResourceError `json:"error,omitempty"`
+}
+
+type DeployedResourceError struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
 }
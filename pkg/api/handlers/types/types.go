@@ -6,6 +6,7 @@ import (
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 )
 
 type ListAppsResponse struct {
@@ -31,6 +32,8 @@ type ResponseApp struct {
 	IsConfigurable    bool       `json:"isConfigurable"`
 	UpdateCheckerSpec string     `json:"updateCheckerSpec"`
 
+	AutoDeployPolicy *apptypes.AutoDeployPolicy `json:"autoDeployPolicy"`
+
 	IsGitOpsSupported             bool                     `json:"isGitOpsSupported"`
 	IsIdentityServiceSupported    bool                     `json:"isIdentityServiceSupported"`
 	IsAppIdentityServiceSupported bool                     `json:"isAppIdentityServiceSupported"`
@@ -64,6 +67,7 @@ type ResponseGitOps struct {
 	Branch      string `json:"branch"`
 	Format      string `json:"format"`
 	Action      string `json:"action"`
+	Username    string `json:"username"`
 	DeployKey   string `json:"deployKey"`
 	IsConnected bool   `json:"isConnected"`
 }
@@ -84,3 +88,27 @@ type ResponsePendingApp struct {
 	LicenseData   string `json:"licenseData"`
 	NeedsRegistry bool   `json:"needsRegistry"`
 }
+
+type PreviewAppConfigValuesResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+
+	// DeployedSequence is the sequence the rendered output is compared against, or -1 if nothing is deployed
+	DeployedSequence  int64                    `json:"deployedSequence"`
+	TemplateErrors    []string                 `json:"templateErrors"`
+	VisibilityChanges []ConfigVisibilityChange `json:"visibilityChanges"`
+	Diffs             []RenderedFileDiff       `json:"diffs"`
+}
+
+// ConfigVisibilityChange is a config group or item whose "when" condition evaluates differently
+// with the proposed values. Item is empty when the whole group changes visibility.
+type ConfigVisibilityChange struct {
+	Group   string `json:"group"`
+	Item    string `json:"item,omitempty"`
+	Visible bool   `json:"visible"`
+}
+
+type RenderedFileDiff struct {
+	Filename string `json:"filename"`
+	Diff     string `json:"diff"`
+}
@@ -13,18 +13,22 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/automation"
+	"github.com/replicatedhq/kots/pkg/gitopsmonitor"
 	"github.com/replicatedhq/kots/pkg/handlers"
+	"github.com/replicatedhq/kots/pkg/identity"
 	"github.com/replicatedhq/kots/pkg/informers"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
 	"github.com/replicatedhq/kots/pkg/policy"
 	"github.com/replicatedhq/kots/pkg/rbac"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
 	"github.com/replicatedhq/kots/pkg/snapshotscheduler"
 	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
 	"github.com/replicatedhq/kots/pkg/updatechecker"
+	"github.com/replicatedhq/kots/pkg/util"
 	"github.com/segmentio/ksuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -98,6 +102,10 @@ func Start(params *APIServerParams) {
 		log.Println("Failed to start snapshot scheduler", err)
 	}
 
+	if err := gitopsmonitor.Start(); err != nil {
+		log.Println("Failed to start gitops pull request monitor", err)
+	}
+
 	waitForAirgap, err := automation.NeedToWaitForAirgapApp()
 	if err != nil {
 		log.Println("Failed to check if airgap install is in progress", err)
@@ -121,6 +129,7 @@ func Start(params *APIServerParams) {
 	r.HandleFunc("/healthz", handler.Healthz)
 	r.HandleFunc("/api/v1/login", handler.Login)
 	r.HandleFunc("/api/v1/login/info", handler.GetLoginInfo)
+	r.Path("/api/v1/login/password").Methods("POST").HandlerFunc(handler.ChangeLocalUserPassword)
 	r.HandleFunc("/api/v1/logout", handler.Logout) // this route uses its own auth
 	r.Path("/api/v1/metadata").Methods("GET").HandlerFunc(handler.Metadata)
 
@@ -157,6 +166,9 @@ func Start(params *APIServerParams) {
 
 	kotsStore := store.GetStore()
 	policyMiddleware := policy.NewMiddleware(kotsStore, rbac.DefaultRoles())
+	policyMiddleware.RolesGetter = func(ctx context.Context) ([]rbactypes.Role, error) {
+		return identity.GetRoles(ctx, util.PodNamespace)
+	}
 
 	sessionAuthQuietRouter := r.PathPrefix("").Subrouter()
 	sessionAuthQuietRouter.Use(handlers.RequireValidSessionQuietMiddleware(kotsStore))
@@ -0,0 +1,136 @@
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/apitoken/types"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+)
+
+// TokenPrefix distinguishes API tokens from session JWTs in a Bearer authorization header.
+// Tokens have the form kotsat_<id>_<secret>.
+const TokenPrefix = "kotsat_"
+
+// SessionIDPrefix is prepended to the token id to build the session id, and is used as the
+// audit actor so that token activity can't be confused with an interactive session
+const SessionIDPrefix = "apitoken:"
+
+// IsAPIToken returns true if the bearer value looks like an API token rather than a JWT
+func IsAPIToken(bearer string) bool {
+	return strings.HasPrefix(bearer, TokenPrefix)
+}
+
+// Create generates a new token, stores the hash of its secret and returns the token along with the
+// plaintext value. The plaintext is never stored and can't be retrieved again.
+func Create(kotsStore store.Store, name string, roleIDs []string, expiresAt time.Time, createdBy string) (*types.APIToken, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", errors.Wrap(err, "failed to generate secret")
+	}
+	encodedSecret := hex.EncodeToString(secret)
+
+	token := &types.APIToken{
+		ID:           ksuid.New().String(),
+		Name:         name,
+		SecretSHA256: hashSecret(encodedSecret),
+		RoleIDs:      roleIDs,
+		CreatedBy:    createdBy,
+		CreatedAt:    time.Now(),
+		ExpiresAt:    expiresAt,
+	}
+
+	if err := kotsStore.CreateAPIToken(token); err != nil {
+		return nil, "", errors.Wrap(err, "failed to create token")
+	}
+
+	return token, TokenPrefix + token.ID + "_" + encodedSecret, nil
+}
+
+// Authenticate looks up the token and returns a session carrying the token's roles.
+// An error is returned if the token is unknown, revoked or expired.
+func Authenticate(kotsStore store.Store, bearer string) (*sessiontypes.Session, error) {
+	tokenID, secret, err := parse(bearer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse token")
+	}
+
+	token, err := kotsStore.GetAPIToken(tokenID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(token.SecretSHA256)) != 1 {
+		return nil, errors.New("invalid token")
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return nil, errors.New("token has been revoked")
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, errors.New("token has expired")
+	}
+
+	return &sessiontypes.Session{
+		ID:        SessionIDPrefix + token.ID,
+		UserID:    SessionIDPrefix + token.Name,
+		IssuedAt:  token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		Roles:     token.RoleIDs,
+		HasRBAC:   true,
+	}, nil
+}
+
+// IsAPITokenSession returns true if the session was created by Authenticate
+func IsAPITokenSession(sess *sessiontypes.Session) bool {
+	return strings.HasPrefix(sess.ID, SessionIDPrefix)
+}
+
+// CheckCanGrant returns an error if the session would grant a role it does not hold itself.
+// Sessions created before rbac have full access and can grant any role.
+func CheckCanGrant(sess *sessiontypes.Session, roleIDs []string) error {
+	if !sess.HasRBAC {
+		return nil
+	}
+
+	for _, roleID := range roleIDs {
+		found := false
+		for _, sessionRoleID := range sess.Roles {
+			if sessionRoleID == roleID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("cannot grant role %q that the current session does not have", roleID)
+		}
+	}
+
+	return nil
+}
+
+func parse(bearer string) (string, string, error) {
+	if !IsAPIToken(bearer) {
+		return "", "", errors.New("missing token prefix")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(bearer, TokenPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("malformed token")
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,134 @@
+package apitoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/replicatedhq/kots/pkg/apitoken/types"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	mock_store "github.com/replicatedhq/kots/pkg/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		bearer    func(plaintext string) string
+		update    func(token *types.APIToken)
+		wantError bool
+	}{
+		{
+			name:   "valid token",
+			bearer: func(plaintext string) string { return plaintext },
+		},
+		{
+			name:      "wrong secret",
+			bearer:    func(plaintext string) string { return plaintext + "0" },
+			wantError: true,
+		},
+		{
+			name:      "revoked token",
+			bearer:    func(plaintext string) string { return plaintext },
+			update:    func(token *types.APIToken) { token.RevokedAt = &revokedAt },
+			wantError: true,
+		},
+		{
+			name:      "expired token",
+			bearer:    func(plaintext string) string { return plaintext },
+			update:    func(token *types.APIToken) { token.ExpiresAt = time.Now().Add(-time.Second) },
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var stored *types.APIToken
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockStore.EXPECT().CreateAPIToken(gomock.Any()).DoAndReturn(func(token *types.APIToken) error {
+				copied := *token
+				stored = &copied
+				return nil
+			})
+			mockStore.EXPECT().GetAPIToken(gomock.Any()).DoAndReturn(func(tokenID string) (*types.APIToken, error) {
+				assert.Equal(t, stored.ID, tokenID)
+				return stored, nil
+			})
+
+			token, plaintext, err := Create(mockStore, "ci", []string{"cluster-admin"}, time.Now().Add(time.Hour), "alice")
+			require.NoError(t, err)
+			require.True(t, IsAPIToken(plaintext))
+			assert.NotContains(t, stored.SecretSHA256, plaintext)
+
+			if test.update != nil {
+				test.update(stored)
+			}
+
+			sess, err := Authenticate(mockStore, test.bearer(plaintext))
+			if test.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, SessionIDPrefix+token.ID, sess.ID)
+			assert.Equal(t, SessionIDPrefix+"ci", sess.UserID)
+			assert.Equal(t, []string{"cluster-admin"}, sess.Roles)
+			assert.True(t, sess.HasRBAC)
+		})
+	}
+}
+
+func TestAuthenticateMalformed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+
+	for _, bearer := range []string{"kotsat_", "kotsat_abc", "kotsat__secret", "eyJhbGciOiJIUzI1NiJ9"} {
+		_, err := Authenticate(mockStore, bearer)
+		assert.Error(t, err, bearer)
+	}
+}
+
+func TestCheckCanGrant(t *testing.T) {
+	tests := []struct {
+		name      string
+		sess      *sessiontypes.Session
+		roleIDs   []string
+		wantError bool
+	}{
+		{
+			name:    "session without rbac can grant any role",
+			sess:    &sessiontypes.Session{HasRBAC: false},
+			roleIDs: []string{"cluster-admin"},
+		},
+		{
+			name:    "session holds the role",
+			sess:    &sessiontypes.Session{HasRBAC: true, Roles: []string{"cluster-admin", "support"}},
+			roleIDs: []string{"support"},
+		},
+		{
+			name:      "session does not hold the role",
+			sess:      &sessiontypes.Session{HasRBAC: true, Roles: []string{"support"}},
+			roleIDs:   []string{"support", "cluster-admin"},
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckCanGrant(test.sess, test.roleIDs)
+			if test.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+import "time"
+
+// APIToken is a long-lived credential for automation. Only the sha256 of the token secret is stored.
+type APIToken struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	SecretSHA256 string     `json:"secretSha256,omitempty"`
+	RoleIDs      []string   `json:"roleIds"`
+	CreatedBy    string     `json:"createdBy"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IsActive returns true if the token has not been revoked and has not expired
+func (t *APIToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+)
 
 type UndeployStatus string
 
@@ -12,24 +16,47 @@ const (
 )
 
 type App struct {
-	ID                    string         `json:"id"`
-	Slug                  string         `json:"slug"`
-	Name                  string         `json:"name"`
-	License               string         `json:"license"`
-	IsAirgap              bool           `json:"isAirgap"`
-	CurrentSequence       int64          `json:"currentSequence"`
-	UpstreamURI           string         `json:"upstreamUri"`
-	IconURI               string         `json:"iconUri"`
-	UpdatedAt             *time.Time     `json:"createdAt"`
-	CreatedAt             time.Time      `json:"updatedAt"`
-	LastUpdateCheckAt     string         `json:"lastUpdateCheckAt"`
-	HasPreflight          bool           `json:"hasPreflight"`
-	IsConfigurable        bool           `json:"isConfigurable"`
-	SnapshotTTL           string         `json:"snapshotTtl"`
-	SnapshotSchedule      string         `json:"snapshotSchedule"`
-	RestoreInProgressName string         `json:"restoreInProgressName"`
-	RestoreUndeployStatus UndeployStatus `json:"restoreUndeloyStatus"`
-	UpdateCheckerSpec     string         `json:"updateCheckerSpec"`
-	IsGitOps              bool           `json:"isGitOps"`
-	InstallState          string         `json:"installState"`
+	ID                    string                         `json:"id"`
+	Slug                  string                         `json:"slug"`
+	Name                  string                         `json:"name"`
+	License               string                         `json:"license"`
+	IsAirgap              bool                           `json:"isAirgap"`
+	CurrentSequence       int64                          `json:"currentSequence"`
+	UpstreamURI           string                         `json:"upstreamUri"`
+	IconURI               string                         `json:"iconUri"`
+	UpdatedAt             *time.Time                     `json:"createdAt"`
+	CreatedAt             time.Time                      `json:"updatedAt"`
+	LastUpdateCheckAt     string                         `json:"lastUpdateCheckAt"`
+	HasPreflight          bool                           `json:"hasPreflight"`
+	IsConfigurable        bool                           `json:"isConfigurable"`
+	SnapshotTTL           string                         `json:"snapshotTtl"`
+	SnapshotSchedule      string                         `json:"snapshotSchedule"`
+	SnapshotRetention     *snapshottypes.RetentionPolicy `json:"snapshotRetention"`
+	RestoreInProgressName string                         `json:"restoreInProgressName"`
+	RestoreUndeployStatus UndeployStatus                 `json:"restoreUndeloyStatus"`
+	UpdateCheckerSpec     string                         `json:"updateCheckerSpec"`
+	AutoDeployPolicy      *AutoDeployPolicy              `json:"autoDeployPolicy"`
+	IsGitOps              bool                           `json:"isGitOps"`
+	InstallState          string                         `json:"installState"`
+}
+
+type AutoDeployMode string
+
+const (
+	AutoDeployNever AutoDeployMode = "never"
+	AutoDeployPatch AutoDeployMode = "patch"
+	AutoDeployAny   AutoDeployMode = "any"
+)
+
+// AutoDeployPolicy controls which downloaded versions are deployed without user interaction
+type AutoDeployPolicy struct {
+	Mode AutoDeployMode `json:"mode"`
+	// MaintenanceWindows restrict when versions can be deployed. Versions can be deployed at any time if empty.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow opens at every time matched by the cron Schedule and stays open for Duration
+type MaintenanceWindow struct {
+	Schedule string `json:"schedule"`
+	Duration string `json:"duration"`
 }
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// ServeAndRecord calls handler and records an audit event with the outcome of the request.
+// The request body is hashed as the handler reads it, so large uploads are not buffered.
+// The app slug and sequence of the event are read from vars, which may have more vars than the route.
+// Failing to record the event is logged but does not fail the request.
+func ServeAndRecord(kotsStore store.Store, sess *sessiontypes.Session, action string, resource string, vars map[string]string, handler http.HandlerFunc, w http.ResponseWriter, r *http.Request) {
+	var body *hashingReadCloser
+	if r.Body != nil {
+		body = &hashingReadCloser{ReadCloser: r.Body, hash: sha256.New()}
+		r.Body = body
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+	handler(recorder, r)
+
+	event := NewEvent(sess, action, resource, vars, r)
+	event.StatusCode = recorder.statusCode
+	if body != nil && body.size > 0 {
+		event.RequestBodySHA256 = hex.EncodeToString(body.hash.Sum(nil))
+	}
+
+	if err := kotsStore.CreateAuditEvent(event); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to record audit event for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// NewEvent returns an event for the request without a status code or body hash
+func NewEvent(sess *sessiontypes.Session, action string, resource string, vars map[string]string, r *http.Request) *audittypes.Event {
+	event := &audittypes.Event{
+		CreatedAt: time.Now(),
+		Action:    action,
+		Resource:  resource,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+	}
+
+	if sess != nil {
+		event.SessionID = sess.ID
+		event.Actor = sess.UserID
+		event.Roles = sess.Roles
+	}
+
+	event.AppSlug = vars["appSlug"]
+	if sequence, err := strconv.ParseInt(vars["sequence"], 10, 64); err == nil {
+		event.Sequence = &sequence
+	}
+
+	return event
+}
+
+type hashingReadCloser struct {
+	io.ReadCloser
+	hash hash.Hash
+	size int64
+}
+
+func (h *hashingReadCloser) Read(p []byte) (int, error) {
+	n, err := h.ReadCloser.Read(p)
+	if n > 0 {
+		h.hash.Write(p[:n])
+		h.size += int64(n)
+	}
+	return n, err
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if !s.wroteHeader {
+		s.statusCode = statusCode
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush supports handlers that stream their response
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	mock_store "github.com/replicatedhq/kots/pkg/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeAndRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantHash   string
+		wantSeq    *int64
+	}{
+		{
+			name: "body is hashed as the handler reads it",
+			path: "/api/v1/app/my-app/sequence/3/deploy",
+			body: "hello",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				ioutil.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+			wantHash:   "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			wantSeq:    int64Ptr(3),
+		},
+		{
+			name: "implicit status and unread body",
+			path: "/api/v1/app/my-app/sequence/latest/deploy",
+			body: "ignored",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("{}"))
+			},
+			wantStatus: http.StatusOK,
+			wantHash:   "",
+			wantSeq:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var recorded *audittypes.Event
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockStore.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(event *audittypes.Event) error {
+				recorded = event
+				return nil
+			})
+
+			sess := &sessiontypes.Session{ID: "session-id", UserID: "alice", Roles: []string{"cluster-admin"}}
+
+			r := mux.NewRouter()
+			r.Path("/api/v1/app/{appSlug}/sequence/{sequence}/deploy").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ServeAndRecord(mockStore, sess, "write", "app.my-app.downstream.", mux.Vars(r), test.handler, w, r)
+			})
+
+			req := httptest.NewRequest("POST", test.path, strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.NotNil(t, recorded)
+			assert.Equal(t, test.wantStatus, w.Code)
+			assert.Equal(t, test.wantStatus, recorded.StatusCode)
+			assert.Equal(t, test.wantHash, recorded.RequestBodySHA256)
+			assert.Equal(t, test.wantSeq, recorded.Sequence)
+			assert.Equal(t, "session-id", recorded.SessionID)
+			assert.Equal(t, "alice", recorded.Actor)
+			assert.Equal(t, "my-app", recorded.AppSlug)
+			assert.Equal(t, "app.my-app.downstream.", recorded.Resource)
+			assert.Equal(t, test.path, recorded.Path)
+		})
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// Event is a record of a single mutating request to the admin console api.
+// Events are append only and are never updated or deleted.
+type Event struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	SessionID string   `json:"sessionId"`
+	Actor     string   `json:"actor"`
+	Roles     []string `json:"roles,omitempty"`
+
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	AppSlug  string `json:"appSlug,omitempty"`
+	Sequence *int64 `json:"sequence,omitempty"`
+
+	// RequestBodySHA256 is the hex encoded sha256 of the request body, empty if there was no body
+	RequestBodySHA256 string `json:"requestBodySha256,omitempty"`
+	StatusCode        int    `json:"statusCode"`
+}
+
+type ListEventsOptions struct {
+	AppSlug string
+	Offset  int
+	Limit   int
+}
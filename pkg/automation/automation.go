@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -17,6 +19,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	upstream "github.com/replicatedhq/kots/pkg/kotsadmupstream"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	kotslicense "github.com/replicatedhq/kots/pkg/license"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -24,6 +27,7 @@ import (
 	onlinetypes "github.com/replicatedhq/kots/pkg/online/types"
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/store"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/replicatedhq/kots/pkg/util"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -209,9 +213,119 @@ LICENSE_LOOP:
 		cleanup(&licenseSecret, verifiedLicense.Spec.AppSlug)
 	}
 
+	if err := automateUpstreamInstall(clientset); err != nil {
+		return errors.Wrap(err, "failed to install from upstream")
+	}
+
+	return nil
+}
+
+// automateUpstreamInstall installs the apps that kots install left in an upstream secret.
+// These apps come from a git or http upstream and don't have a license.
+func automateUpstreamInstall(clientset kubernetes.Interface) error {
+	upstreamSecrets, err := clientset.CoreV1().Secrets(util.PodNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: "kots.io/automation=upstream",
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list upstream secrets")
+	}
+
+	for _, upstreamSecret := range upstreamSecrets.Items {
+		err := installFromUpstreamSecret(upstreamSecret)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to install app from upstream secret %s", upstreamSecret.Name))
+		}
+
+		// the upstream secret is deleted even if the install failed, otherwise a new app would be created on each start
+		err = clientset.CoreV1().Secrets(upstreamSecret.Namespace).Delete(context.TODO(), upstreamSecret.Name, metav1.DeleteOptions{})
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to delete upstream secret %s", upstreamSecret.Name))
+		}
+	}
+
+	return nil
+}
+
+func installFromUpstreamSecret(upstreamSecret corev1.Secret) error {
+	upstreamURI := string(upstreamSecret.Data["upstreamURI"])
+	if !upstream.IsGitUpstream(upstreamURI) && !upstream.IsHTTPUpstream(upstreamURI) {
+		return errors.Errorf("unsupported upstream %q", upstreamURI)
+	}
+
+	logger.Debug("automated upstream install found",
+		zap.String("upstreamURI", upstreamURI))
+
+	instParams, err := kotsutil.GetInstallationParams(kotsadmtypes.KotsadmConfigMap)
+	if err != nil {
+		return errors.Wrap(err, "failed to get existing kotsadm config map")
+	}
+
+	desiredAppName, err := appNameFromUpstreamURI(upstreamURI)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app name")
+	}
+
+	a, err := store.GetStore().CreateApp(desiredAppName, upstreamURI, "", false, instParams.SkipImagePush, instParams.RegistryIsReadOnly)
+	if err != nil {
+		return errors.Wrap(err, "failed to create app record")
+	}
+
+	// the credentials are kept in the app's own secret so that update checks can fetch the upstream too
+	gitAuth := upstreamtypes.GitAuth{
+		Username:      string(upstreamSecret.Data["username"]),
+		Token:         string(upstreamSecret.Data["token"]),
+		SSHPrivateKey: string(upstreamSecret.Data["sshPrivateKey"]),
+	}
+	if upstream.IsGitUpstream(upstreamURI) && gitAuth != (upstreamtypes.GitAuth{}) {
+		if err := upstream.SetGitAuth(a.Slug, gitAuth); err != nil {
+			return errors.Wrap(err, "failed to set git auth")
+		}
+	}
+
+	releasePublicKey := string(upstreamSecret.Data["publicKey"])
+	if upstream.IsHTTPUpstream(upstreamURI) && releasePublicKey != "" {
+		if err := upstream.SetReleasePublicKey(a.Slug, releasePublicKey); err != nil {
+			return errors.Wrap(err, "failed to set release public key")
+		}
+	}
+
+	pendingApp := onlinetypes.PendingApp{
+		ID:   a.ID,
+		Slug: a.Slug,
+		Name: a.Name,
+	}
+
+	if _, err := online.CreateAppFromOnline(&pendingApp, upstreamURI, true, instParams.SkipPreflights); err != nil {
+		return errors.Wrap(err, "failed to create online app")
+	}
+
 	return nil
 }
 
+// appNameFromUpstreamURI returns the name of the repository of a git upstream,
+// e.g. "my-app" for git://github.com/org/my-app.git//manifests, or the name of
+// the release tarball of an http upstream, e.g. "my-app" for https://example.com/my-app.tar.gz.
+func appNameFromUpstreamURI(upstreamURI string) (string, error) {
+	u, err := url.ParseRequestURI(upstreamURI)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse upstream uri")
+	}
+
+	p := strings.TrimPrefix(u.Path, "/")
+	if idx := strings.Index(p, "//"); u.Scheme == "git" && idx != -1 {
+		p = p[:idx]
+	}
+	name := path.Base(strings.TrimSuffix(p, "/"))
+	for _, ext := range []string{".git", ".tar.gz", ".tgz"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if name == "" || name == "." || name == "/" {
+		return "", errors.Errorf("upstream uri %q does not include an app name", upstreamURI)
+	}
+
+	return name, nil
+}
+
 func AirgapInstall(appSlug string, additionalFiles map[string][]byte) error {
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
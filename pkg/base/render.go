@@ -32,7 +32,8 @@ func RenderUpstream(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (ba
 		return
 	}
 
-	if u.Type == "replicated" {
+	// git and http upstreams contain the same kots manifests as a replicated release
+	if u.Type == "replicated" || u.Type == "git" || u.Type == "http" {
 		base, helmBases, err = renderReplicated(u, renderOptions)
 		return
 	}
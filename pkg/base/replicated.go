@@ -46,7 +46,7 @@ func renderReplicated(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (
 		return nil, nil, errors.Wrap(err, "failed to find config file")
 	}
 
-	renderedConfig, err := kotsconfig.TemplateConfigObjects(config, itemValues, license, template.LocalRegistry{}, nil, idConfig, util.PodNamespace)
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(config, itemValues, license, template.LocalRegistry{}, nil, idConfig, util.PodNamespace, findTemplateLookupNamespaces(u))
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to template config objects")
 	}
@@ -384,6 +384,23 @@ func findConfigAndLicense(u *upstreamtypes.Upstream, log *logger.CLILogger) (*ko
 	return config, values, identityConfig, license, nil
 }
 
+// findTemplateLookupNamespaces returns the namespaces the Application in the release allows templates to read from
+func findTemplateLookupNamespaces(u *upstreamtypes.Upstream) []string {
+	for _, file := range u.Files {
+		decode := scheme.Codecs.UniversalDeserializer().Decode
+		obj, gvk, err := decode(file.Content, nil, nil)
+		if err != nil {
+			continue
+		}
+
+		if gvk.Group == "kots.io" && gvk.Version == "v1beta1" && gvk.Kind == "Application" {
+			return obj.(*kotsv1beta1.Application).Spec.TemplateLookupNamespaces
+		}
+	}
+
+	return nil
+}
+
 // findHelmChartArchiveInRelease iterates through all files in the release (upstreamFiles), looking for a helm chart archive
 // that matches the chart name and version specified in the kotsHelmChart parameter
 func findHelmChartArchiveInRelease(upstreamFiles []upstreamtypes.UpstreamFile, kotsHelmChart *kotsv1beta1.HelmChart) ([]byte, error) {
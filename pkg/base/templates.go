@@ -66,15 +66,16 @@ func NewConfigContextTemplateBuilder(u *upstreamtypes.Upstream, renderOptions *R
 	}
 
 	builderOptions := template.BuilderOptions{
-		ConfigGroups:    configGroups,
-		ExistingValues:  templateContext,
-		LocalRegistry:   localRegistry,
-		Cipher:          cipher,
-		License:         license,
-		VersionInfo:     &versionInfo,
-		ApplicationInfo: &appInfo,
-		IdentityConfig:  identityConfig,
-		Namespace:       renderOptions.Namespace,
+		ConfigGroups:     configGroups,
+		ExistingValues:   templateContext,
+		LocalRegistry:    localRegistry,
+		Cipher:           cipher,
+		License:          license,
+		VersionInfo:      &versionInfo,
+		ApplicationInfo:  &appInfo,
+		IdentityConfig:   identityConfig,
+		Namespace:        renderOptions.Namespace,
+		LookupNamespaces: findTemplateLookupNamespaces(u),
 	}
 	builder, itemValues, err := template.NewBuilder(builderOptions)
 	if err != nil {
@@ -0,0 +1,41 @@
+package cursor
+
+import (
+	semver "github.com/Masterminds/semver/v3"
+)
+
+// VersionBump describes how a version label changed relative to another
+type VersionBump string
+
+const (
+	VersionBumpNone    VersionBump = "none"
+	VersionBumpPatch   VersionBump = "patch"
+	VersionBumpMinor   VersionBump = "minor"
+	VersionBumpMajor   VersionBump = "major"
+	VersionBumpUnknown VersionBump = "unknown"
+)
+
+// GetVersionBump compares two version labels as semver. The result is VersionBumpUnknown if either
+// label is not a valid semver, and VersionBumpNone if the new label is not greater than the current one.
+func GetVersionBump(currentLabel string, newLabel string) VersionBump {
+	current, err := semver.NewVersion(currentLabel)
+	if err != nil {
+		return VersionBumpUnknown
+	}
+	next, err := semver.NewVersion(newLabel)
+	if err != nil {
+		return VersionBumpUnknown
+	}
+
+	if !next.GreaterThan(current) {
+		return VersionBumpNone
+	}
+
+	if next.Major() != current.Major() {
+		return VersionBumpMajor
+	}
+	if next.Minor() != current.Minor() {
+		return VersionBumpMinor
+	}
+	return VersionBumpPatch
+}
@@ -0,0 +1,71 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetVersionBump(t *testing.T) {
+	tests := []struct {
+		name         string
+		currentLabel string
+		newLabel     string
+		want         VersionBump
+	}{
+		{
+			name:         "patch",
+			currentLabel: "1.2.3",
+			newLabel:     "1.2.4",
+			want:         VersionBumpPatch,
+		},
+		{
+			name:         "patch with v prefix",
+			currentLabel: "v1.2.3",
+			newLabel:     "v1.2.10",
+			want:         VersionBumpPatch,
+		},
+		{
+			name:         "prerelease to release",
+			currentLabel: "1.2.3-beta.1",
+			newLabel:     "1.2.3",
+			want:         VersionBumpPatch,
+		},
+		{
+			name:         "minor",
+			currentLabel: "1.2.3",
+			newLabel:     "1.3.0",
+			want:         VersionBumpMinor,
+		},
+		{
+			name:         "major",
+			currentLabel: "1.2.3",
+			newLabel:     "2.0.0",
+			want:         VersionBumpMajor,
+		},
+		{
+			name:         "same version",
+			currentLabel: "1.2.3",
+			newLabel:     "1.2.3",
+			want:         VersionBumpNone,
+		},
+		{
+			name:         "downgrade",
+			currentLabel: "1.2.3",
+			newLabel:     "1.2.2",
+			want:         VersionBumpNone,
+		},
+		{
+			name:         "not semver",
+			currentLabel: "nightly-123",
+			newLabel:     "1.2.3",
+			want:         VersionBumpUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, GetVersionBump(test.currentLabel, test.newLabel))
+		})
+	}
+}
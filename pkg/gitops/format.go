@@ -0,0 +1,232 @@
+package gitops
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FormatSingle writes the rendered application to <path>/<appSlug>.yaml.
+// FormatKustomize writes the base and overlays kustomize tree to <path>/<appSlug>/.
+// FormatMultiple writes each rendered resource to <path>/<appSlug>/<kind>-<name>.yaml.
+const (
+	FormatSingle    = "single"
+	FormatKustomize = "kustomize"
+	FormatMultiple  = "multiple"
+)
+
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatSingle, FormatKustomize, FormatMultiple:
+		return true
+	}
+	return false
+}
+
+type resourceDoc struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+// singleFiles returns the files of the single format, relative to the gitops path.
+func singleFiles(appSlug string, rendered []byte) map[string][]byte {
+	return map[string][]byte{
+		fmt.Sprintf("%s.yaml", appSlug): rendered,
+	}
+}
+
+// resourceFiles splits the rendered application into one file per resource, relative to the gitops path.
+// Files are named <kind>-<name>.yaml, and the namespace is added to the names of resources that would collide.
+func resourceFiles(appSlug string, rendered []byte) (map[string][]byte, error) {
+	type resource struct {
+		doc     resourceDoc
+		content []byte
+	}
+
+	resources := []resource{}
+	for _, content := range bytes.Split(rendered, []byte("\n---\n")) {
+		content = bytes.TrimPrefix(content, []byte("---\n"))
+		if len(bytes.TrimSpace(content)) == 0 {
+			continue
+		}
+
+		doc := resourceDoc{}
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal resource")
+		}
+		if doc.Kind == "" || doc.Metadata.Name == "" {
+			return nil, errors.Errorf("resource is missing a kind or name: %s", string(content))
+		}
+
+		if !bytes.HasSuffix(content, []byte("\n")) {
+			content = append(content, '\n')
+		}
+		resources = append(resources, resource{doc: doc, content: content})
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		a, b := resources[i].doc, resources[j].doc
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Metadata.Name != b.Metadata.Name {
+			return a.Metadata.Name < b.Metadata.Name
+		}
+		return a.Metadata.Namespace < b.Metadata.Namespace
+	})
+
+	nameCounts := map[string]int{}
+	for _, r := range resources {
+		nameCounts[resourceFileName(r.doc.Kind, r.doc.Metadata.Name)]++
+	}
+
+	files := map[string][]byte{}
+	for _, r := range resources {
+		fileName := resourceFileName(r.doc.Kind, r.doc.Metadata.Name)
+		if nameCounts[fileName] > 1 && r.doc.Metadata.Namespace != "" {
+			fileName = resourceFileName(r.doc.Kind, fmt.Sprintf("%s-%s", r.doc.Metadata.Namespace, r.doc.Metadata.Name))
+		}
+
+		filePath := filepath.Join(appSlug, fileName)
+		if _, ok := files[filePath]; ok {
+			return nil, errors.Errorf("more than one %s named %s", r.doc.Kind, r.doc.Metadata.Name)
+		}
+		files[filePath] = r.content
+	}
+
+	return files, nil
+}
+
+func resourceFileName(kind string, name string) string {
+	fileName := strings.ToLower(fmt.Sprintf("%s-%s", kind, name))
+	fileName = strings.NewReplacer("/", "-", ":", "-").Replace(fileName)
+	return fmt.Sprintf("%s.yaml", fileName)
+}
+
+// kustomizeFiles returns the kustomize tree of a downstream in the archive, relative to the gitops path.
+// The tree can be built with kustomize build <appSlug>/overlays/downstreams/<downstreamName>.
+func kustomizeFiles(appSlug string, archiveDir string, downstreamName string) (map[string][]byte, error) {
+	dirs := []string{
+		"base",
+		filepath.Join("overlays", "midstream"),
+		filepath.Join("overlays", "downstreams", downstreamName),
+	}
+
+	files := map[string][]byte{}
+	for _, dir := range dirs {
+		root := filepath.Join(archiveDir, dir)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %s", path)
+			}
+
+			relPath, err := filepath.Rel(archiveDir, path)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get relative path of %s", path)
+			}
+			files[filepath.Join(appSlug, relPath)] = content
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to walk %s", dir)
+		}
+	}
+
+	return files, nil
+}
+
+// syncGitOpsFiles makes the app's files in dir match files, and stages the changes.
+// The app's files are <appSlug>.yaml and everything in <appSlug>/, so files from another format are pruned.
+// It returns false when nothing changed.
+func syncGitOpsFiles(workTree *git.Worktree, workDir string, dir string, appSlug string, files map[string][]byte) (bool, error) {
+	existingFiles := []string{}
+	if _, err := os.Stat(filepath.Join(workDir, dir, fmt.Sprintf("%s.yaml", appSlug))); err == nil {
+		existingFiles = append(existingFiles, fmt.Sprintf("%s.yaml", appSlug))
+	}
+
+	appDir := filepath.Join(workDir, dir, appSlug)
+	if _, err := os.Stat(appDir); err == nil {
+		err := filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			relPath, err := filepath.Rel(filepath.Join(workDir, dir), path)
+			if err != nil {
+				return err
+			}
+			existingFiles = append(existingFiles, relPath)
+			return nil
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to list current app files")
+		}
+	}
+
+	changed := false
+
+	for _, existingFile := range existingFiles {
+		if _, ok := files[existingFile]; ok {
+			continue
+		}
+		if _, err := workTree.Remove(gitPath(dir, existingFile)); err != nil {
+			return false, errors.Wrapf(err, "failed to remove %s", existingFile)
+		}
+		changed = true
+	}
+
+	filePaths := []string{}
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		fullPath := filepath.Join(workDir, dir, filePath)
+
+		currentContent, err := ioutil.ReadFile(fullPath)
+		if err == nil && bytes.Equal(currentContent, files[filePath]) {
+			continue
+		} else if err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrapf(err, "failed to read %s", filePath)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return false, errors.Wrapf(err, "failed to mkdir for %s", filePath)
+		}
+		if err := ioutil.WriteFile(fullPath, files[filePath], 0644); err != nil {
+			return false, errors.Wrapf(err, "failed to write %s", filePath)
+		}
+		if _, err := workTree.Add(gitPath(dir, filePath)); err != nil {
+			return false, errors.Wrapf(err, "failed to add %s to worktree", filePath)
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func gitPath(dir string, filePath string) string {
+	return filepath.ToSlash(strings.TrimPrefix(filepath.Join(dir, filePath), "/"))
+}
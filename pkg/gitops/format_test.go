@@ -0,0 +1,134 @@
+package gitops
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resourceFiles(t *testing.T) {
+	req := require.New(t)
+
+	rendered := `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: monitoring
+`
+
+	files, err := resourceFiles("my-app", []byte(rendered))
+	req.NoError(err)
+
+	assert.Equal(t, map[string][]byte{
+		"my-app/service-web.yaml":                   []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"),
+		"my-app/deployment-web.yaml":                []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"),
+		"my-app/configmap-default-settings.yaml":    []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\n"),
+		"my-app/configmap-monitoring-settings.yaml": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: monitoring\n"),
+	}, files)
+
+	_, err = resourceFiles("my-app", []byte("apiVersion: v1\nkind: Service\n"))
+	req.Error(err)
+}
+
+func Test_kustomizeFiles(t *testing.T) {
+	req := require.New(t)
+
+	archiveDir, err := ioutil.TempDir("", "kots-gitops")
+	req.NoError(err)
+	defer os.RemoveAll(archiveDir)
+
+	archiveFiles := map[string]string{
+		"upstream/deployment.yaml":                              "upstream",
+		"base/kustomization.yaml":                               "base",
+		"base/charts/postgres/deployment.yaml":                  "chart",
+		"overlays/midstream/kustomization.yaml":                 "midstream",
+		"overlays/downstreams/this-cluster/kustomization.yaml":  "downstream",
+		"overlays/downstreams/other-cluster/kustomization.yaml": "other downstream",
+	}
+	for filePath, content := range archiveFiles {
+		fullPath := filepath.Join(archiveDir, filePath)
+		req.NoError(os.MkdirAll(filepath.Dir(fullPath), 0755))
+		req.NoError(ioutil.WriteFile(fullPath, []byte(content), 0644))
+	}
+
+	files, err := kustomizeFiles("my-app", archiveDir, "this-cluster")
+	req.NoError(err)
+
+	assert.Equal(t, map[string][]byte{
+		"my-app/base/kustomization.yaml":                              []byte("base"),
+		"my-app/base/charts/postgres/deployment.yaml":                 []byte("chart"),
+		"my-app/overlays/midstream/kustomization.yaml":                []byte("midstream"),
+		"my-app/overlays/downstreams/this-cluster/kustomization.yaml": []byte("downstream"),
+	}, files)
+}
+
+func Test_syncGitOpsFiles(t *testing.T) {
+	req := require.New(t)
+
+	workDir, err := ioutil.TempDir("", "kots-gitops")
+	req.NoError(err)
+	defer os.RemoveAll(workDir)
+
+	r, err := git.PlainInit(workDir, false)
+	req.NoError(err)
+	workTree, err := r.Worktree()
+	req.NoError(err)
+
+	// the single format file is pruned when switching to one file per resource
+	changed, err := syncGitOpsFiles(workTree, workDir, "/apps", "my-app", map[string][]byte{
+		"my-app.yaml":    []byte("single"),
+		"other-app.yaml": []byte("other"),
+	})
+	req.NoError(err)
+	req.True(changed)
+
+	changed, err = syncGitOpsFiles(workTree, workDir, "/apps", "my-app", map[string][]byte{
+		"my-app/service-web.yaml":    []byte("service"),
+		"my-app/deployment-web.yaml": []byte("deployment"),
+	})
+	req.NoError(err)
+	req.True(changed)
+
+	changed, err = syncGitOpsFiles(workTree, workDir, "/apps", "my-app", map[string][]byte{
+		"my-app/service-web.yaml": []byte("service"),
+	})
+	req.NoError(err)
+	req.True(changed)
+
+	changed, err = syncGitOpsFiles(workTree, workDir, "/apps", "my-app", map[string][]byte{
+		"my-app/service-web.yaml": []byte("service"),
+	})
+	req.NoError(err)
+	req.False(changed)
+
+	status, err := workTree.Status()
+	req.NoError(err)
+	assert.Equal(t, git.Added, status.File("apps/my-app/service-web.yaml").Staging)
+	assert.Equal(t, git.Added, status.File("apps/other-app.yaml").Staging)
+	assert.Equal(t, git.Untracked, status.File("apps/my-app.yaml").Staging)
+	assert.Equal(t, git.Untracked, status.File("apps/my-app/deployment-web.yaml").Staging)
+
+	_, err = os.Stat(filepath.Join(workDir, "apps", "my-app.yaml"))
+	req.True(os.IsNotExist(err))
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,8 +18,11 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
+	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
 	go_git_ssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/crypto"
@@ -44,6 +48,9 @@ type GitOpsConfig struct {
 	Action      string `json:"action"`
 	PublicKey   string `json:"publicKey"`
 	PrivateKey  string `json:"-"`
+	Username    string `json:"username"`
+	Token       string `json:"-"`
+	SigningKey  string `json:"-"`
 	IsConnected bool   `json:"isConnected"`
 }
 
@@ -54,6 +61,7 @@ type GlobalGitOpsConfig struct {
 	SSHPort  string `json:"sshPort"`
 	Provider string `json:"provider"`
 	URI      string `json:"uri"`
+	Username string `json:"username"`
 }
 
 type KeyPair struct {
@@ -72,17 +80,45 @@ func (g *GitOpsConfig) CommitURL(hash string) string {
 	case "bitbucket", "bitbucket_server":
 		return fmt.Sprintf("%s/commits/%s", g.RepoURI, hash)
 
+	case "generic":
+		// most git servers, including gitea and azure devops, serve commits at <repo>/commit/<hash>
+		return fmt.Sprintf("%s/commit/%s", genericWebURL(g.RepoURI), hash)
+
 	default:
 		return fmt.Sprintf("%s/commit/%s", g.RepoURI, hash)
 	}
 }
 
-func (g *GitOpsConfig) CloneURL() (string, error) {
+// genericWebURL returns the web url of a repository from its ssh or https clone url.
+func genericWebURL(cloneURL string) string {
+	webURL := strings.TrimSuffix(cloneURL, ".git")
+
+	if strings.HasPrefix(webURL, "ssh://") {
+		// ssh://git@host:22/org/repo
+		u, err := url.Parse(webURL)
+		if err != nil {
+			return webURL
+		}
+		return fmt.Sprintf("https://%s%s", u.Hostname(), u.Path)
+	}
+
+	if !strings.Contains(webURL, "://") {
+		// scp-like syntax, git@host:org/repo
+		if idx := strings.Index(webURL, "@"); idx != -1 {
+			webURL = webURL[idx+1:]
+		}
+		return "https://" + strings.Replace(webURL, ":", "/", 1)
+	}
+
+	return webURL
+}
+
+func (g *GitOpsConfig) ownerAndRepo() (string, string, error) {
 	// copied this logic from node js api
 	uriParts := strings.Split(g.RepoURI, "/")
 
 	if len(uriParts) < 5 {
-		return "", errors.Errorf("unexpected url format: %s", g.RepoURI)
+		return "", "", errors.Errorf("unexpected url format: %s", g.RepoURI)
 	}
 
 	owner := uriParts[3]
@@ -90,12 +126,29 @@ func (g *GitOpsConfig) CloneURL() (string, error) {
 
 	if g.Provider == "bitbucket_server" {
 		if len(uriParts) < 7 {
-			return "", errors.Errorf("unexpected bitbucket server url format: %s", g.RepoURI)
+			return "", "", errors.Errorf("unexpected bitbucket server url format: %s", g.RepoURI)
 		}
 		owner = uriParts[4]
 		repo = uriParts[6]
 	}
 
+	return owner, repo, nil
+}
+
+func (g *GitOpsConfig) CloneURL() (string, error) {
+	if g.Provider == "generic" {
+		// the repo uri of a generic provider is the ssh or https clone url
+		if g.RepoURI == "" {
+			return "", errors.New("clone url is required")
+		}
+		return g.RepoURI, nil
+	}
+
+	owner, repo, err := g.ownerAndRepo()
+	if err != nil {
+		return "", err
+	}
+
 	switch g.Provider {
 	case "github":
 		return fmt.Sprintf("git@github.com:%s/%s.git", owner, repo), nil
@@ -160,36 +213,31 @@ func GetDownstreamGitOps(appID string, clusterID string) (*GitOpsConfig, error)
 				if err != nil {
 					return nil, errors.Wrap(err, "failed to parse index")
 				}
-				provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort := gitOpsConfigFromSecretData(idx, secret.Data)
+				gitOpsConfig := gitOpsConfigFromSecretData(idx, secret.Data)
 
 				cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
 				if err != nil {
 					return nil, errors.Wrap(err, "failed to create aes cipher")
 				}
-				decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
+
+				gitOpsConfig.PrivateKey, err = decryptSecretValue(cipher, gitOpsConfig.PrivateKey)
 				if err != nil {
-					return nil, errors.Wrap(err, "failed to decode")
+					return nil, errors.Wrap(err, "failed to decrypt private key")
 				}
-
-				decryptedPrivateKey, err := cipher.Decrypt([]byte(decodedPrivateKey))
+				gitOpsConfig.Token, err = decryptSecretValue(cipher, gitOpsConfig.Token)
 				if err != nil {
-					return nil, errors.Wrap(err, "failed to decrypt")
+					return nil, errors.Wrap(err, "failed to decrypt token")
 				}
-
-				gitOpsConfig := GitOpsConfig{
-					Provider:   provider,
-					PublicKey:  publicKey,
-					PrivateKey: string(decryptedPrivateKey),
-					RepoURI:    repoURI,
-					Hostname:   hostname,
-					HTTPPort:   httpPort,
-					SSHPort:    sshPort,
-					Branch:     configMapData["branch"],
-					Path:       configMapData["path"],
-					Format:     configMapData["format"],
-					Action:     configMapData["action"],
+				gitOpsConfig.SigningKey, err = decryptSecretValue(cipher, gitOpsConfig.SigningKey)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to decrypt signing key")
 				}
 
+				gitOpsConfig.Branch = configMapData["branch"]
+				gitOpsConfig.Path = configMapData["path"]
+				gitOpsConfig.Format = configMapData["format"]
+				gitOpsConfig.Action = configMapData["action"]
+
 				if lastError, ok := configMapData["lastError"]; ok && lastError == "" {
 					gitOpsConfig.IsConnected = true
 				}
@@ -358,7 +406,7 @@ func SetGitOpsError(appID string, clusterID string, errMsg string) error {
 // TestGitOpsConnection will attempt a clone of the target gitops repo.
 // It returns the default branch name from the clone.
 func TestGitOpsConnection(gitOpsConfig *GitOpsConfig) (string, error) {
-	auth, err := getAuth(gitOpsConfig.PrivateKey)
+	auth, err := gitOpsConfig.auth()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get auth")
 	}
@@ -379,7 +427,13 @@ func TestGitOpsConnection(gitOpsConfig *GitOpsConfig) (string, error) {
 		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
 		Auth:              auth,
 	})
-	if err != nil && errors.Cause(err) != transport.ErrEmptyRemoteRepository {
+	if errors.Cause(err) == transport.ErrEmptyRemoteRepository {
+		// an empty repository is reachable, but has no default branch yet
+		if gitOpsConfig.Branch == "" {
+			return "", errors.New("repository is empty, a branch is required")
+		}
+		return gitOpsConfig.Branch, nil
+	} else if err != nil {
 		return "", errors.Wrap(err, "failed to clone repo")
 	}
 
@@ -391,7 +445,17 @@ func TestGitOpsConnection(gitOpsConfig *GitOpsConfig) (string, error) {
 	return ref.Name().Short(), nil
 }
 
-func CreateGitOps(provider string, repoURI string, hostname string, httpPort string, sshPort string) error {
+// CreateGitOps creates or updates the provider for repoURI. The token is used to open pull requests and,
+// with the username, to authenticate https clone urls. The signing key is an armored GPG private key
+// used to sign commits. An empty username, token or signing key keeps the value that is already stored,
+// unless clearCredentials is set, which removes the stored credentials first.
+func CreateGitOps(provider string, repoURI string, hostname string, httpPort string, sshPort string, username string, token string, signingKey string, clearCredentials bool) error {
+	if signingKey != "" {
+		if _, err := getSigningEntity(signingKey); err != nil {
+			return errors.Wrap(err, "invalid signing key")
+		}
+	}
+
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
 		return errors.Wrap(err, "failed to get k8s client set")
@@ -448,16 +512,17 @@ func CreateGitOps(provider string, repoURI string, hostname string, httpPort str
 	secretData[fmt.Sprintf("provider.%d.type", repoIdx)] = []byte(provider)
 	secretData[fmt.Sprintf("provider.%d.repoUri", repoIdx)] = []byte(repoURI)
 
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return errors.Wrap(err, "failed to create aes cipher")
+	}
+
 	if !repoExists {
 		keyPair, err := generateKeyPair()
 		if err != nil {
 			return errors.Wrap(err, "failed to generate key pair")
 		}
 
-		cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
-		if err != nil {
-			return errors.Wrap(err, "failed to create aes cipher")
-		}
 		encryptedPrivateKey := cipher.Encrypt([]byte(keyPair.PrivateKeyPEM))
 		encodedPrivateKey := base64.StdEncoding.EncodeToString(encryptedPrivateKey) // encoding here shouldn't be needed. moved logic from TS where ffi EncryptString function base64 encodes the value as well
 
@@ -465,6 +530,29 @@ func CreateGitOps(provider string, repoURI string, hostname string, httpPort str
 		secretData[fmt.Sprintf("provider.%d.publicKey", repoIdx)] = []byte(keyPair.PublicKeySSH)
 	}
 
+	usernameKey := fmt.Sprintf("provider.%d.username", repoIdx)
+	tokenKey := fmt.Sprintf("provider.%d.token", repoIdx)
+	signingKeyKey := fmt.Sprintf("provider.%d.signingKey", repoIdx)
+	if clearCredentials {
+		delete(secretData, usernameKey)
+		delete(secretData, tokenKey)
+		delete(secretData, signingKeyKey)
+	}
+
+	if username != "" {
+		secretData[usernameKey] = []byte(username)
+	}
+
+	if token != "" {
+		encryptedToken := cipher.Encrypt([]byte(token))
+		secretData[tokenKey] = []byte(base64.StdEncoding.EncodeToString(encryptedToken))
+	}
+
+	if signingKey != "" {
+		encryptedSigningKey := cipher.Encrypt([]byte(signingKey))
+		secretData[signingKeyKey] = []byte(base64.StdEncoding.EncodeToString(encryptedSigningKey))
+	}
+
 	hostnameKey := fmt.Sprintf("provider.%d.hostname", repoIdx)
 	_, ok := secretData[hostnameKey]
 	if ok {
@@ -555,56 +643,49 @@ func GetGitOps() (GlobalGitOpsConfig, error) {
 		Hostname: string(secret.Data["provider.0.hostname"]),
 		HTTPPort: string(secret.Data["provider.0.httpPort"]),
 		SSHPort:  string(secret.Data["provider.0.sshPort"]),
+		Username: string(secret.Data["provider.0.username"]),
 	}
 
 	return parsedConfig, nil
 }
 
-func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) (string, string, string, string, string, string, string) {
-	provider := ""
-	publicKey := ""
-	privateKey := ""
-	repoURI := ""
-	hostname := ""
-	httpPort := ""
-	sshPort := ""
-
-	providerDecoded, ok := secretData[fmt.Sprintf("provider.%d.type", idx)]
-	if ok {
-		provider = string(providerDecoded)
-	}
-
-	publicKeyDecoded, ok := secretData[fmt.Sprintf("provider.%d.publicKey", idx)]
-	if ok {
-		publicKey = string(publicKeyDecoded)
-	}
-
-	privateKeyDecoded, ok := secretData[fmt.Sprintf("provider.%d.privateKey", idx)]
-	if ok {
-		privateKey = string(privateKeyDecoded)
+// gitOpsConfigFromSecretData returns the provider at idx. Private keys and tokens are returned as stored, encrypted and base64 encoded.
+func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) GitOpsConfig {
+	value := func(key string) string {
+		return string(secretData[fmt.Sprintf("provider.%d.%s", idx, key)])
 	}
 
-	repoURIDecoded, ok := secretData[fmt.Sprintf("provider.%d.repoUri", idx)]
-	if ok {
-		repoURI = string(repoURIDecoded)
+	return GitOpsConfig{
+		Provider:   value("type"),
+		PublicKey:  value("publicKey"),
+		PrivateKey: value("privateKey"),
+		Username:   value("username"),
+		Token:      value("token"),
+		SigningKey: value("signingKey"),
+		RepoURI:    value("repoUri"),
+		Hostname:   value("hostname"),
+		HTTPPort:   value("httpPort"),
+		SSHPort:    value("sshPort"),
 	}
+}
 
-	hostnameDecoded, ok := secretData[fmt.Sprintf("provider.%d.hostname", idx)]
-	if ok {
-		hostname = string(hostnameDecoded)
+// decryptSecretValue decrypts a base64 encoded, encrypted value from the gitops secret.
+func decryptSecretValue(cipher *crypto.AESCipher, value string) (string, error) {
+	if value == "" {
+		return "", nil
 	}
 
-	httpPortDecoded, ok := secretData[fmt.Sprintf("provider.%d.httpPort", idx)]
-	if ok {
-		httpPort = string(httpPortDecoded)
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode")
 	}
 
-	sshPortDecoded, ok := secretData[fmt.Sprintf("provider.%d.sshPort", idx)]
-	if ok {
-		sshPort = string(sshPortDecoded)
+	decrypted, err := cipher.Decrypt(decoded)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt")
 	}
 
-	return provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort
+	return string(decrypted), nil
 }
 
 func getAuth(privateKey string) (transport.AuthMethod, error) {
@@ -618,24 +699,76 @@ func getAuth(privateKey string) (transport.AuthMethod, error) {
 	return auth, nil
 }
 
+// auth returns the auth method for the clone url. https clone urls use the username and token,
+// all others use the deploy key.
+func (g *GitOpsConfig) auth() (transport.AuthMethod, error) {
+	cloneURL, err := g.CloneURL()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clone url")
+	}
+
+	if strings.HasPrefix(cloneURL, "https://") || strings.HasPrefix(cloneURL, "http://") {
+		return GetAuth("", g.Username, g.Token)
+	}
+
+	return getAuth(g.PrivateKey)
+}
+
+// GetAuth returns the auth method for a private SSH key or, if no key is provided, for an HTTPS token.
+// A nil auth method is returned when neither is set.
+func GetAuth(privateKey string, username string, token string) (transport.AuthMethod, error) {
+	if privateKey != "" {
+		return getAuth(privateKey)
+	}
+	if token != "" {
+		if username == "" {
+			username = "git"
+		}
+		return &go_git_http.BasicAuth{Username: username, Password: token}, nil
+	}
+	return nil, nil
+}
+
 func CreateGitOpsCommit(gitOpsConfig *GitOpsConfig, appSlug string, appName string, newSequence int, archiveDir string, downstreamName string) (string, error) {
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to load kots kinds")
 	}
 
-	// we use the kustomize binary here...
-	cmd := exec.Command(fmt.Sprintf("kustomize%s", kotsKinds.KustomizeVersion()), "build", filepath.Join(archiveDir, "overlays", "downstreams", downstreamName))
-	out, err := cmd.Output()
-	if err != nil {
-		if ee, ok := err.(*exec.ExitError); ok {
-			err = fmt.Errorf("kustomize stderr: %q", string(ee.Stderr))
+	var files map[string][]byte
+	switch gitOpsConfig.Format {
+	case FormatKustomize:
+		files, err = kustomizeFiles(appSlug, archiveDir, downstreamName)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get kustomize files")
+		}
+
+	case FormatSingle, FormatMultiple, "":
+		// we use the kustomize binary here...
+		cmd := exec.Command(fmt.Sprintf("kustomize%s", kotsKinds.KustomizeVersion()), "build", filepath.Join(archiveDir, "overlays", "downstreams", downstreamName))
+		out, err := cmd.Output()
+		if err != nil {
+			if ee, ok := err.(*exec.ExitError); ok {
+				err = fmt.Errorf("kustomize stderr: %q", string(ee.Stderr))
+			}
+			return "", errors.Wrap(err, "failed to run kustomize")
 		}
-		return "", errors.Wrap(err, "failed to run kustomize")
+
+		if gitOpsConfig.Format == FormatMultiple {
+			files, err = resourceFiles(appSlug, out)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to split resources")
+			}
+		} else {
+			files = singleFiles(appSlug, out)
+		}
+
+	default:
+		return "", errors.Errorf("unsupported gitops format: %s", gitOpsConfig.Format)
 	}
 
-	// using the deploy key, create the commit in a new branch
-	auth, err := getAuth(gitOpsConfig.PrivateKey)
+	// using the deploy key or token, create the commit in a new branch
+	auth, err := gitOpsConfig.auth()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get auth")
 	}
@@ -662,61 +795,101 @@ func CreateGitOpsCommit(gitOpsConfig *GitOpsConfig, appSlug string, appName stri
 		return "", err
 	}
 
-	dirPath := filepath.Join(workDir, gitOpsConfig.Path)
-	_, err = os.Stat(dirPath)
-	if os.IsNotExist(err) {
-		// create subdirectory if not exist
-		err := os.MkdirAll(dirPath, 0755)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to mkdir")
+	// in pull request mode, the commit is made to a new branch and a pull request is opened against the configured branch
+	prBranch := ""
+	baseBranch := gitOpsConfig.Branch
+	if gitOpsConfig.Action == ActionPullRequest {
+		if baseBranch == "" {
+			baseBranch, err = remoteDefaultBranch(cloned, auth)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to get default branch")
+			}
 		}
-	} // ignore error here and let the stat of the file below handle any errors
 
-	filePath := filepath.Join(dirPath, fmt.Sprintf("%s.yaml", appSlug))
-	_, err = os.Stat(filePath)
-	if err == nil { // if the file has not changed, end now
-		currentRevision, err := ioutil.ReadFile(filePath)
+		prBranch = fmt.Sprintf("kots/%s/%d", appSlug, newSequence)
+		err = workTree.Checkout(&git.CheckoutOptions{
+			Create: true,
+			Branch: plumbing.NewBranchReferenceName(prBranch),
+		})
 		if err != nil {
-			return "", errors.Wrap(err, "failed to read current app yaml")
+			return "", errors.Wrapf(err, "failed to create branch %s", prBranch)
 		}
-		if string(currentRevision) == string(out) {
-			return "", nil
-		}
-	} else if !os.IsNotExist(err) {
-		return "", errors.Wrap(err, "failed to stat current app yaml")
 	}
 
-	err = ioutil.WriteFile(filePath, out, 0644)
+	changed, err := syncGitOpsFiles(workTree, workDir, gitOpsConfig.Path, appSlug, files)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to write updated app yaml")
+		return "", errors.Wrap(err, "failed to write app files")
 	}
-
-	_, err = workTree.Add(strings.TrimPrefix(filepath.Join(gitOpsConfig.Path, fmt.Sprintf("%s.yaml", appSlug)), "/"))
-	if err != nil {
-		return "", errors.Wrap(err, "failed to add to worktree")
+	if !changed { // if the files have not changed, end now
+		return "", nil
 	}
 
 	// commit it
-	updatedHash, err := workTree.Commit(fmt.Sprintf("Updating %s to version %d", appName, newSequence), &git.CommitOptions{
+	commitMessage := fmt.Sprintf("Updating %s to version %d", appName, newSequence)
+	commitOptions := &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  "KOTS Admin Console",
 			Email: "help@replicated.com",
 			When:  time.Now(),
 		},
-	})
+	}
+	if gitOpsConfig.SigningKey != "" {
+		commitOptions.SignKey, err = getSigningEntity(gitOpsConfig.SigningKey)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get signing key")
+		}
+	}
+	updatedHash, err := workTree.Commit(commitMessage, commitOptions)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to commit")
 	}
 
-	err = cloned.Push(&git.PushOptions{
+	pushOptions := &git.PushOptions{
 		RemoteName: cloneOptions.RemoteName,
 		Auth:       auth,
-	})
+	}
+	if prBranch != "" {
+		pushOptions.RefSpecs = []config.RefSpec{
+			config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", prBranch, prBranch)),
+		}
+	}
+	err = cloned.Push(pushOptions)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to push")
 	}
 
-	return gitOpsConfig.CommitURL(updatedHash.String()), nil
+	if prBranch == "" {
+		return gitOpsConfig.CommitURL(updatedHash.String()), nil
+	}
+
+	description := fmt.Sprintf("This update to %s was created by the KOTS Admin Console. It will be deployed when this is merged.", appName)
+	prURL, err := CreatePullRequest(gitOpsConfig, commitMessage, description, prBranch, baseBranch)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create pull request")
+	}
+
+	return prURL, nil
+}
+
+// remoteDefaultBranch returns the branch that HEAD points to in the remote repository.
+func remoteDefaultBranch(r *git.Repository, auth transport.AuthMethod) (string, error) {
+	remote, err := r.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get remote")
+	}
+
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list remote refs")
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+			return ref.Target().Short(), nil
+		}
+	}
+
+	return "", errors.New("remote has no default branch")
 }
 
 func generateKeyPair() (*KeyPair, error) {
@@ -0,0 +1,102 @@
+package gitops
+
+import (
+	"bytes"
+	"testing"
+
+	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func Test_genericProvider(t *testing.T) {
+	tests := []struct {
+		name              string
+		repoURI           string
+		expectedCommitURL string
+	}{
+		{
+			name:              "https",
+			repoURI:           "https://gitea.example.com/org/app.git",
+			expectedCommitURL: "https://gitea.example.com/org/app/commit/abc123",
+		},
+		{
+			name:              "azure devops",
+			repoURI:           "https://dev.azure.com/org/project/_git/app",
+			expectedCommitURL: "https://dev.azure.com/org/project/_git/app/commit/abc123",
+		},
+		{
+			name:              "ssh",
+			repoURI:           "ssh://git@gitea.example.com:2222/org/app.git",
+			expectedCommitURL: "https://gitea.example.com/org/app/commit/abc123",
+		},
+		{
+			name:              "scp-like",
+			repoURI:           "git@gitea.example.com:org/app.git",
+			expectedCommitURL: "https://gitea.example.com/org/app/commit/abc123",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			gitOpsConfig := &GitOpsConfig{
+				Provider: "generic",
+				RepoURI:  test.repoURI,
+			}
+
+			cloneURL, err := gitOpsConfig.CloneURL()
+			req.NoError(err)
+			assert.Equal(t, test.repoURI, cloneURL)
+			assert.Equal(t, test.expectedCommitURL, gitOpsConfig.CommitURL("abc123"))
+		})
+	}
+}
+
+func Test_auth(t *testing.T) {
+	req := require.New(t)
+
+	gitOpsConfig := &GitOpsConfig{
+		Provider: "generic",
+		RepoURI:  "https://gitea.example.com/org/app.git",
+		Username: "kots",
+		Token:    "secret",
+	}
+
+	auth, err := gitOpsConfig.auth()
+	req.NoError(err)
+	assert.Equal(t, &go_git_http.BasicAuth{Username: "kots", Password: "secret"}, auth)
+
+	gitOpsConfig.RepoURI = "git@gitea.example.com:org/app.git"
+	_, err = gitOpsConfig.auth()
+	req.Error(err) // the deploy key is required for ssh
+}
+
+func Test_getSigningEntity(t *testing.T) {
+	req := require.New(t)
+
+	entity, err := openpgp.NewEntity("KOTS Admin Console", "", "help@replicated.com", nil)
+	req.NoError(err)
+
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PrivateKeyType, nil)
+	req.NoError(err)
+	req.NoError(entity.SerializePrivate(w, nil))
+	req.NoError(w.Close())
+
+	signingEntity, err := getSigningEntity(buf.String())
+	req.NoError(err)
+	assert.Equal(t, entity.PrimaryKey.Fingerprint, signingEntity.PrimaryKey.Fingerprint)
+
+	buf.Reset()
+	w, err = armor.Encode(buf, openpgp.PublicKeyType, nil)
+	req.NoError(err)
+	req.NoError(entity.Serialize(w))
+	req.NoError(w.Close())
+
+	_, err = getSigningEntity(buf.String())
+	req.Error(err)
+}
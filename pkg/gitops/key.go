@@ -3,8 +3,12 @@ package gitops
 import (
 	"crypto/rsa"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
 )
 
 var (
@@ -41,3 +45,24 @@ func generatePreseedPrivateKey() {
 	defer preseedPrivateKeyMu.Unlock()
 	preseedPrivateKey, _ = rsa.GenerateKey(r, 4096)
 }
+
+// getSigningEntity returns the GPG entity that commits are signed with. The armored private key
+// must not be protected by a passphrase.
+func getSigningEntity(armoredPrivateKey string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPrivateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read armored key")
+	}
+
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			return nil, errors.New("signing key must not be protected by a passphrase")
+		}
+		return entity, nil
+	}
+
+	return nil, errors.New("no private key found")
+}
@@ -0,0 +1,291 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ActionCommit      = "commit"
+	ActionPullRequest = "pull_request"
+)
+
+// pull request states match the downstream version statuses they are tracked with
+const (
+	PullRequestOpened = "opened"
+	PullRequestMerged = "merged"
+	PullRequestClosed = "closed"
+)
+
+type pullRequestClient struct {
+	provider   string
+	apiURL     string
+	token      string
+	owner      string
+	repo       string
+	httpClient *http.Client
+}
+
+func newPullRequestClient(gitOpsConfig *GitOpsConfig) (*pullRequestClient, error) {
+	if gitOpsConfig.Token == "" {
+		return nil, errors.New("an api token is required to open pull requests")
+	}
+
+	apiURL, err := gitOpsConfig.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repo, err := gitOpsConfig.ownerAndRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pullRequestClient{
+		provider:   gitOpsConfig.Provider,
+		apiURL:     apiURL,
+		token:      gitOpsConfig.Token,
+		owner:      owner,
+		repo:       repo,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// apiURL returns the base url of the provider's REST API. Self-hosted providers are served from the host of the repo uri.
+func (g *GitOpsConfig) apiURL() (string, error) {
+	switch g.Provider {
+	case "github":
+		return "https://api.github.com", nil
+	case "gitlab":
+		return "https://gitlab.com/api/v4", nil
+	}
+
+	u, err := url.Parse(g.RepoURI)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse repo uri")
+	}
+
+	switch g.Provider {
+	case "github_enterprise":
+		return fmt.Sprintf("%s://%s/api/v3", u.Scheme, u.Host), nil
+	case "gitlab_enterprise":
+		return fmt.Sprintf("%s://%s/api/v4", u.Scheme, u.Host), nil
+	case "bitbucket_server":
+		return fmt.Sprintf("%s://%s/rest/api/1.0", u.Scheme, u.Host), nil
+	}
+
+	return "", errors.Errorf("pull requests are not supported for provider type: %s", g.Provider)
+}
+
+// CreatePullRequest opens a pull request (a merge request in GitLab) from head into base and returns its url.
+func CreatePullRequest(gitOpsConfig *GitOpsConfig, title string, description string, head string, base string) (string, error) {
+	client, err := newPullRequestClient(gitOpsConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create pull request client")
+	}
+	return client.create(title, description, head, base)
+}
+
+// GetPullRequestState returns the state of the pull request at prURL: opened, merged or closed.
+func GetPullRequestState(gitOpsConfig *GitOpsConfig, prURL string) (string, error) {
+	client, err := newPullRequestClient(gitOpsConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create pull request client")
+	}
+	return client.state(prURL)
+}
+
+func (c *pullRequestClient) create(title string, description string, head string, base string) (string, error) {
+	switch c.provider {
+	case "github", "github_enterprise":
+		body := map[string]string{
+			"title": title,
+			"body":  description,
+			"head":  head,
+			"base":  base,
+		}
+		response := struct {
+			HTMLURL string `json:"html_url"`
+		}{}
+		if err := c.do("POST", fmt.Sprintf("/repos/%s/%s/pulls", c.owner, c.repo), body, &response); err != nil {
+			return "", err
+		}
+		return response.HTMLURL, nil
+
+	case "gitlab", "gitlab_enterprise":
+		body := map[string]string{
+			"title":         title,
+			"description":   description,
+			"source_branch": head,
+			"target_branch": base,
+		}
+		response := struct {
+			WebURL string `json:"web_url"`
+		}{}
+		if err := c.do("POST", fmt.Sprintf("/projects/%s/merge_requests", c.gitlabProjectID()), body, &response); err != nil {
+			return "", err
+		}
+		return response.WebURL, nil
+
+	case "bitbucket_server":
+		body := map[string]interface{}{
+			"title":       title,
+			"description": description,
+			"fromRef":     map[string]string{"id": fmt.Sprintf("refs/heads/%s", head)},
+			"toRef":       map[string]string{"id": fmt.Sprintf("refs/heads/%s", base)},
+		}
+		response := struct {
+			Links struct {
+				Self []struct {
+					Href string `json:"href"`
+				} `json:"self"`
+			} `json:"links"`
+		}{}
+		if err := c.do("POST", fmt.Sprintf("/projects/%s/repos/%s/pull-requests", c.owner, c.repo), body, &response); err != nil {
+			return "", err
+		}
+		if len(response.Links.Self) == 0 {
+			return "", errors.New("pull request response has no link")
+		}
+		return response.Links.Self[0].Href, nil
+	}
+
+	return "", errors.Errorf("pull requests are not supported for provider type: %s", c.provider)
+}
+
+func (c *pullRequestClient) state(prURL string) (string, error) {
+	number, err := pullRequestNumber(prURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch c.provider {
+	case "github", "github_enterprise":
+		response := struct {
+			State  string `json:"state"`
+			Merged bool   `json:"merged"`
+		}{}
+		if err := c.do("GET", fmt.Sprintf("/repos/%s/%s/pulls/%d", c.owner, c.repo, number), nil, &response); err != nil {
+			return "", err
+		}
+		if response.Merged {
+			return PullRequestMerged, nil
+		}
+		if response.State == "closed" {
+			return PullRequestClosed, nil
+		}
+		return PullRequestOpened, nil
+
+	case "gitlab", "gitlab_enterprise":
+		response := struct {
+			State string `json:"state"`
+		}{}
+		if err := c.do("GET", fmt.Sprintf("/projects/%s/merge_requests/%d", c.gitlabProjectID(), number), nil, &response); err != nil {
+			return "", err
+		}
+		switch response.State {
+		case "merged":
+			return PullRequestMerged, nil
+		case "closed":
+			return PullRequestClosed, nil
+		}
+		return PullRequestOpened, nil
+
+	case "bitbucket_server":
+		response := struct {
+			State string `json:"state"`
+		}{}
+		if err := c.do("GET", fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d", c.owner, c.repo, number), nil, &response); err != nil {
+			return "", err
+		}
+		switch response.State {
+		case "MERGED":
+			return PullRequestMerged, nil
+		case "DECLINED":
+			return PullRequestClosed, nil
+		}
+		return PullRequestOpened, nil
+	}
+
+	return "", errors.Errorf("pull requests are not supported for provider type: %s", c.provider)
+}
+
+func (c *pullRequestClient) gitlabProjectID() string {
+	return url.PathEscape(fmt.Sprintf("%s/%s", c.owner, c.repo))
+}
+
+func (c *pullRequestClient) do(method string, path string, body interface{}, response interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	switch c.provider {
+	case "github", "github_enterprise":
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", c.token))
+	case "gitlab", "gitlab_enterprise":
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	default:
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute %s request", method)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d from %s %s: %s", resp.StatusCode, method, path, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return nil
+}
+
+// pullRequestNumber returns the number of a pull request from its url, which is the last numeric path segment
+// for all providers, for example https://github.com/owner/repo/pull/12 or
+// https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7/overview.
+func pullRequestNumber(prURL string) (int64, error) {
+	u, err := url.Parse(prURL)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse pull request url")
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if number, err := strconv.ParseInt(segments[i], 10, 64); err == nil {
+			return number, nil
+		}
+	}
+
+	return 0, errors.Errorf("failed to find pull request number in %s", prURL)
+}
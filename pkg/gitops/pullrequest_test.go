@@ -0,0 +1,181 @@
+package gitops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResponse struct {
+	authHeader string
+	authValue  string
+	body       string
+}
+
+func newPullRequestStub(t *testing.T, responses map[string]stubResponse, requests map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.EscapedPath()
+		response, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get(response.authHeader) != response.authValue {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == "POST" {
+			body := map[string]interface{}{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			requests[key] = body
+		}
+		w.Write([]byte(response.body))
+	}))
+}
+
+func Test_pullRequestClient(t *testing.T) {
+	tests := []struct {
+		name            string
+		provider        string
+		repoPath        string
+		responses       map[string]stubResponse
+		createPath      string
+		expectedRequest map[string]interface{}
+		expectedURL     string
+		expectedState   string
+	}{
+		{
+			name:     "github enterprise",
+			provider: "github_enterprise",
+			repoPath: "/owner/repo",
+			responses: map[string]stubResponse{
+				"POST /api/v3/repos/owner/repo/pulls": {
+					authHeader: "Authorization",
+					authValue:  "token secret",
+					body:       `{"number": 12, "html_url": "https://github.example.com/owner/repo/pull/12"}`,
+				},
+				"GET /api/v3/repos/owner/repo/pulls/12": {
+					authHeader: "Authorization",
+					authValue:  "token secret",
+					body:       `{"number": 12, "state": "closed", "merged": true}`,
+				},
+			},
+			createPath: "POST /api/v3/repos/owner/repo/pulls",
+			expectedRequest: map[string]interface{}{
+				"title": "Updating app to version 3",
+				"body":  "description",
+				"head":  "kots/app/3",
+				"base":  "main",
+			},
+			expectedURL:   "https://github.example.com/owner/repo/pull/12",
+			expectedState: PullRequestMerged,
+		},
+		{
+			name:     "gitlab enterprise",
+			provider: "gitlab_enterprise",
+			repoPath: "/owner/repo",
+			responses: map[string]stubResponse{
+				"POST /api/v4/projects/owner%2Frepo/merge_requests": {
+					authHeader: "PRIVATE-TOKEN",
+					authValue:  "secret",
+					body:       `{"iid": 5, "web_url": "https://gitlab.example.com/owner/repo/-/merge_requests/5"}`,
+				},
+				"GET /api/v4/projects/owner%2Frepo/merge_requests/5": {
+					authHeader: "PRIVATE-TOKEN",
+					authValue:  "secret",
+					body:       `{"iid": 5, "state": "opened"}`,
+				},
+			},
+			createPath: "POST /api/v4/projects/owner%2Frepo/merge_requests",
+			expectedRequest: map[string]interface{}{
+				"title":         "Updating app to version 3",
+				"description":   "description",
+				"source_branch": "kots/app/3",
+				"target_branch": "main",
+			},
+			expectedURL:   "https://gitlab.example.com/owner/repo/-/merge_requests/5",
+			expectedState: PullRequestOpened,
+		},
+		{
+			name:     "bitbucket server",
+			provider: "bitbucket_server",
+			repoPath: "/projects/PROJ/repos/repo",
+			responses: map[string]stubResponse{
+				"POST /rest/api/1.0/projects/PROJ/repos/repo/pull-requests": {
+					authHeader: "Authorization",
+					authValue:  "Bearer secret",
+					body:       `{"id": 7, "links": {"self": [{"href": "https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7"}]}}`,
+				},
+				"GET /rest/api/1.0/projects/PROJ/repos/repo/pull-requests/7": {
+					authHeader: "Authorization",
+					authValue:  "Bearer secret",
+					body:       `{"id": 7, "state": "DECLINED"}`,
+				},
+			},
+			createPath: "POST /rest/api/1.0/projects/PROJ/repos/repo/pull-requests",
+			expectedRequest: map[string]interface{}{
+				"title":       "Updating app to version 3",
+				"description": "description",
+				"fromRef":     map[string]interface{}{"id": "refs/heads/kots/app/3"},
+				"toRef":       map[string]interface{}{"id": "refs/heads/main"},
+			},
+			expectedURL:   "https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7",
+			expectedState: PullRequestClosed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			requests := map[string]map[string]interface{}{}
+			server := newPullRequestStub(t, test.responses, requests)
+			defer server.Close()
+
+			gitOpsConfig := &GitOpsConfig{
+				Provider: test.provider,
+				RepoURI:  server.URL + test.repoPath,
+				Token:    "secret",
+			}
+
+			prURL, err := CreatePullRequest(gitOpsConfig, "Updating app to version 3", "description", "kots/app/3", "main")
+			req.NoError(err)
+			assert.Equal(t, test.expectedURL, prURL)
+			assert.Equal(t, test.expectedRequest, requests[test.createPath])
+
+			state, err := GetPullRequestState(gitOpsConfig, prURL)
+			req.NoError(err)
+			assert.Equal(t, test.expectedState, state)
+
+			gitOpsConfig.Token = "wrong"
+			_, err = GetPullRequestState(gitOpsConfig, prURL)
+			req.Error(err)
+		})
+	}
+}
+
+func Test_pullRequestNumber(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected int64
+	}{
+		{url: "https://github.com/owner/repo/pull/12", expected: 12},
+		{url: "https://gitlab.com/owner/repo/-/merge_requests/5", expected: 5},
+		{url: "https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7/overview", expected: 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			number, err := pullRequestNumber(test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, number)
+		})
+	}
+
+	_, err := pullRequestNumber("https://github.com/owner/repo")
+	require.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package types
 
 type DownstreamGitOps interface {
-	CreateGitOpsDownstreamCommit(appID string, clusterID string, newSequence int, archiveDir string, downstreamName string) (string, error)
+	// CreateGitOpsDownstreamCommit returns the url of the commit, or of the pull request when the downstream
+	// is configured to open pull requests. The url is empty when gitops is not enabled or nothing changed.
+	CreateGitOpsDownstreamCommit(appID string, clusterID string, newSequence int, archiveDir string, downstreamName string) (url string, isPullRequest bool, err error)
 }
@@ -0,0 +1,84 @@
+package gitopsmonitor
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/gitops"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+)
+
+// Start polls the state of gitops pull requests and updates the status of the versions they were opened for.
+func Start() error {
+	logger.Debug("starting gitops pull request monitor")
+
+	go func() {
+		for {
+			pullRequestLoop()
+			time.Sleep(time.Second * 60)
+		}
+	}()
+
+	return nil
+}
+
+func pullRequestLoop() {
+	appsList, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list installed apps for gitops pull requests"))
+		return
+	}
+
+	for _, a := range appsList {
+		if err := handleApp(a); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to check gitops pull requests for app %s", a.ID))
+		}
+	}
+}
+
+func handleApp(a *apptypes.App) error {
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams for app")
+	}
+
+	for _, d := range downstreams {
+		gitOpsConfig, err := gitops.GetDownstreamGitOps(a.ID, d.ClusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get downstream gitops")
+		}
+		if gitOpsConfig == nil || gitOpsConfig.Action != gitops.ActionPullRequest {
+			continue
+		}
+
+		versions, err := store.GetStore().GetPendingVersions(a.ID, d.ClusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get pending versions")
+		}
+
+		for _, v := range versions {
+			if v.Status != storetypes.VersionPullRequestOpened || v.CommitURL == "" {
+				continue
+			}
+
+			state, err := gitops.GetPullRequestState(gitOpsConfig, v.CommitURL)
+			if err != nil {
+				logger.Error(errors.Wrapf(err, "failed to get state of pull request %s", v.CommitURL))
+				continue
+			}
+			if state == gitops.PullRequestOpened {
+				continue
+			}
+
+			if err := store.GetStore().UpdateDownstreamVersionStatus(a.ID, v.Sequence, state, ""); err != nil {
+				return errors.Wrapf(err, "failed to update status of version %d", v.Sequence)
+			}
+			logger.Debugf("gitops pull request for version %d of app %s was %s", v.Sequence, a.Slug, state)
+		}
+	}
+
+	return nil
+}
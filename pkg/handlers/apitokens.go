@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/apitoken"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/user"
+	"github.com/replicatedhq/kots/pkg/util"
+)
+
+const (
+	maxAPITokenNameLength = 64
+	maxAPITokenLifetime   = 365 * 24 * time.Hour
+)
+
+type ListAPITokensResponse struct {
+	Success bool                      `json:"success"`
+	Error   string                    `json:"error,omitempty"`
+	Tokens  []*apitokentypes.APIToken `json:"tokens"`
+}
+
+type CreateAPITokenRequest struct {
+	Name      string    `json:"name"`
+	RoleIDs   []string  `json:"roleIds"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type CreateAPITokenResponse struct {
+	Success  bool                    `json:"success"`
+	Error    string                  `json:"error,omitempty"`
+	APIToken *apitokentypes.APIToken `json:"apiToken,omitempty"`
+	// Token is the plaintext token. It is only returned when the token is created.
+	Token string `json:"token,omitempty"`
+}
+
+type RevokeAPITokenResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (h *Handler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	response := ListAPITokensResponse{}
+
+	tokens, err := store.GetStore().ListAPITokens()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list api tokens"))
+		response.Error = "failed to list api tokens"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	for _, t := range tokens {
+		t.SecretSHA256 = ""
+	}
+
+	response.Success = true
+	response.Tokens = tokens
+
+	JSON(w, http.StatusOK, response)
+}
+
+// CreateAPIToken creates a token with the requested roles. A session can only grant roles it holds itself.
+// Tokens can't be created with another token, so that a leaked token can't be used to outlive its own expiry.
+func (h *Handler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	response := CreateAPITokenResponse{}
+
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		response.Error = "invalid session"
+		JSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	if apitoken.IsAPITokenSession(sess) {
+		response.Error = "api tokens cannot be used to create api tokens"
+		JSON(w, http.StatusForbidden, response)
+		return
+	}
+
+	request := CreateAPITokenRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if request.Name == "" || len(request.Name) > maxAPITokenNameLength {
+		response.Error = "name must be between 1 and 64 characters"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+	if !request.ExpiresAt.After(time.Now()) {
+		response.Error = "expiresAt must be in the future"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+	if request.ExpiresAt.After(time.Now().Add(maxAPITokenLifetime)) {
+		response.Error = "expiresAt must be within one year"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		response.Error = "failed to get roles"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if err := user.ValidateRoleIDs(request.RoleIDs, roles); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := apitoken.CheckCanGrant(sess, request.RoleIDs); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusForbidden, response)
+		return
+	}
+
+	token, plaintext, err := apitoken.Create(store.GetStore(), request.Name, request.RoleIDs, request.ExpiresAt, sess.UserID)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create api token"))
+		response.Error = "failed to create api token"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	token.SecretSHA256 = ""
+
+	response.Success = true
+	response.APIToken = token
+	response.Token = plaintext
+
+	JSON(w, http.StatusCreated, response)
+}
+
+func (h *Handler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	response := RevokeAPITokenResponse{}
+
+	tokenID := mux.Vars(r)["tokenId"]
+
+	if err := store.GetStore().RevokeAPIToken(tokenID); err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "api token not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to revoke api token"))
+		response.Error = "failed to revoke api token"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+
+	JSON(w, http.StatusOK, response)
+}
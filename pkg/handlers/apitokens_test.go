@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/apitoken"
+	"github.com/replicatedhq/kots/pkg/session"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIToken_FromAPITokenSession(t *testing.T) {
+	sess := &sessiontypes.Session{
+		ID:        apitoken.SessionIDPrefix + "token-id",
+		UserID:    apitoken.SessionIDPrefix + "ci",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Roles:     []string{"cluster-admin"},
+		HasRBAC:   true,
+	}
+
+	body, err := json.Marshal(CreateAPITokenRequest{
+		Name:      "child",
+		RoleIDs:   []string{"cluster-admin"},
+		ExpiresAt: time.Now().Add(300 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/tokens", bytes.NewReader(body))
+	req = session.ContextSetSession(req, sess)
+	w := httptest.NewRecorder()
+
+	(&Handler{}).CreateAPIToken(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	response := CreateAPITokenResponse{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.False(t, response.Success)
+	assert.Empty(t, response.Token)
+}
@@ -11,12 +11,14 @@ import (
 	"github.com/replicatedhq/kots/pkg/api/handlers/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	"github.com/replicatedhq/kots/pkg/gitops"
+	"github.com/replicatedhq/kots/pkg/identity"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/rbac"
 	"github.com/replicatedhq/kots/pkg/registry"
 	"github.com/replicatedhq/kots/pkg/render"
 	"github.com/replicatedhq/kots/pkg/session"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/util"
 	"github.com/replicatedhq/kots/pkg/version"
 )
 
@@ -39,10 +41,15 @@ func (h *Handler) GetPendingApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	defaultRoles := rbac.DefaultRoles() // TODO (ethan): this should be set in the handler
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	if sess.HasRBAC { // handle pre-rbac sessions
-		allow, err := rbac.CheckAccess(r.Context(), defaultRoles, "read", fmt.Sprintf("app.%s", papp.Slug), sess.Roles)
+		allow, err := rbac.CheckAccess(r.Context(), roles, "read", fmt.Sprintf("app.%s", papp.Slug), sess.Roles)
 		if err != nil {
 			logger.Error(errors.Wrapf(err, "failed to check access for pending app %s", papp.Slug))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -89,12 +96,17 @@ func (h *Handler) ListApps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	defaultRoles := rbac.DefaultRoles() // TODO (ethan): this should be set in the handler
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	responseApps := []types.ResponseApp{}
 	for _, a := range apps {
 		if sess.HasRBAC { // handle pre-rbac sessions
-			allow, err := rbac.CheckAccess(r.Context(), defaultRoles, "read", fmt.Sprintf("app.%s", a.Slug), sess.Roles)
+			allow, err := rbac.CheckAccess(r.Context(), roles, "read", fmt.Sprintf("app.%s", a.Slug), sess.Roles)
 			if err != nil {
 				logger.Error(errors.Wrapf(err, "failed to check access for app %s", a.Slug))
 				w.WriteHeader(http.StatusInternalServerError)
@@ -232,6 +244,7 @@ func responseAppFromApp(a *apptypes.App) (*types.ResponseApp, error) {
 				Branch:      downstreamGitOps.Branch,
 				Format:      downstreamGitOps.Format,
 				Action:      downstreamGitOps.Action,
+				Username:    downstreamGitOps.Username,
 				DeployKey:   downstreamGitOps.PublicKey,
 				IsConnected: downstreamGitOps.IsConnected,
 			}
@@ -284,6 +297,7 @@ func responseAppFromApp(a *apptypes.App) (*types.ResponseApp, error) {
 		HasPreflight:                  a.HasPreflight,
 		IsConfigurable:                a.IsConfigurable,
 		UpdateCheckerSpec:             a.UpdateCheckerSpec,
+		AutoDeployPolicy:              a.AutoDeployPolicy,
 		IsGitOpsSupported:             license.Spec.IsGitOpsSupported,
 		IsIdentityServiceSupported:    license.Spec.IsIdentityServiceSupported,
 		IsAppIdentityServiceSupported: isAppIdentityServiceSupported,
@@ -8,6 +8,8 @@ import (
 	"github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	"github.com/replicatedhq/kots/pkg/appstatus"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
 	"github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/store"
 )
@@ -62,5 +64,11 @@ func (h *Handler) SetAppStatus(w http.ResponseWriter, r *http.Request) {
 		go reporting.SendAppInfo(newAppStatus.AppID)
 	}
 
+	if newAppState == types.StateDegraded && (currentAppStatus == nil || currentAppStatus.State != types.StateDegraded) {
+		notifications.Emit(notificationtypes.EventAppStatusDegraded, newAppStatus.AppID, map[string]interface{}{
+			"sequence": newAppStatus.Sequence,
+		})
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	defaultAuditEventsPageSize = 50
+	maxAuditEventsPageSize     = 500
+)
+
+type ListAuditEventsResponse struct {
+	Success     bool                `json:"success"`
+	Error       string              `json:"error,omitempty"`
+	Events      []*audittypes.Event `json:"events"`
+	TotalCount  int64               `json:"totalCount"`
+	CurrentPage int                 `json:"currentPage"`
+	PageSize    int                 `json:"pageSize"`
+}
+
+// ListAuditEvents returns a page of audit events, newest first.
+// Supported query params are appSlug, currentPage (starting at 0) and pageSize.
+func (h *Handler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	response := ListAuditEventsResponse{}
+
+	currentPage := 0
+	if s := r.URL.Query().Get("currentPage"); s != "" {
+		p, err := strconv.Atoi(s)
+		if err != nil || p < 0 {
+			response.Error = "currentPage must be a non-negative integer"
+			JSON(w, http.StatusBadRequest, response)
+			return
+		}
+		currentPage = p
+	}
+
+	pageSize := defaultAuditEventsPageSize
+	if s := r.URL.Query().Get("pageSize"); s != "" {
+		p, err := strconv.Atoi(s)
+		if err != nil || p <= 0 || p > maxAuditEventsPageSize {
+			response.Error = "pageSize must be between 1 and 500"
+			JSON(w, http.StatusBadRequest, response)
+			return
+		}
+		pageSize = p
+	}
+
+	opts := audittypes.ListEventsOptions{
+		AppSlug: r.URL.Query().Get("appSlug"),
+		Offset:  currentPage * pageSize,
+		Limit:   pageSize,
+	}
+	events, totalCount, err := store.GetStore().ListAuditEvents(opts)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list audit events"))
+		response.Error = "failed to list audit events"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+	response.Events = events
+	response.TotalCount = totalCount
+	response.CurrentPage = currentPage
+	response.PageSize = pageSize
+
+	JSON(w, http.StatusOK, response)
+}
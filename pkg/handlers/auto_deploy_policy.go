@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+type AutoDeployPolicyRequest struct {
+	AutoDeployPolicy *apptypes.AutoDeployPolicy `json:"autoDeployPolicy"`
+}
+
+type AutoDeployPolicyResponse struct {
+	Success          bool                       `json:"success"`
+	Error            string                     `json:"error,omitempty"`
+	AutoDeployPolicy *apptypes.AutoDeployPolicy `json:"autoDeployPolicy"`
+}
+
+func (h *Handler) GetAutoDeployPolicy(w http.ResponseWriter, r *http.Request) {
+	response := AutoDeployPolicyResponse{}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "app not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to get app from slug"))
+		response.Error = "failed to get app from slug"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+	response.AutoDeployPolicy = foundApp.AutoDeployPolicy
+	if response.AutoDeployPolicy == nil {
+		response.AutoDeployPolicy = &apptypes.AutoDeployPolicy{Mode: apptypes.AutoDeployNever}
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) SetAutoDeployPolicy(w http.ResponseWriter, r *http.Request) {
+	response := AutoDeployPolicyResponse{}
+
+	request := AutoDeployPolicyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := updatechecker.ValidateAutoDeployPolicy(request.AutoDeployPolicy); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "app not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to get app from slug"))
+		response.Error = "failed to get app from slug"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := store.GetStore().SetAutoDeployPolicy(foundApp.ID, request.AutoDeployPolicy); err != nil {
+		logger.Error(errors.Wrap(err, "failed to set auto deploy policy"))
+		response.Error = "failed to set auto deploy policy"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+	response.AutoDeployPolicy = request.AutoDeployPolicy
+
+	JSON(w, http.StatusOK, response)
+}
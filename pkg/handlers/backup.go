@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -12,6 +13,7 @@ import (
 	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/util"
+	"github.com/segmentio/ksuid"
 )
 
 type CreateApplicationBackupRequest struct {
@@ -166,6 +168,67 @@ func (h *Handler) DeleteBackup(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, deleteBackupResponse)
 }
 
+type VerifyBackupRequest struct {
+	RunPreflights bool `json:"runPreflights"`
+}
+
+type VerifyBackupResponse struct {
+	Success        bool   `json:"success"`
+	VerificationID string `json:"verificationId,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// VerifyBackup starts test-restoring the backup into a scratch namespace.
+// The result is recorded on the backup with the returned verification id and returned by GetBackup.
+func (h *Handler) VerifyBackup(w http.ResponseWriter, r *http.Request) {
+	verifyBackupResponse := VerifyBackupResponse{}
+
+	// check minimal rbac
+	if err := requiresKotsadmVeleroAccess(w, r); err != nil {
+		return
+	}
+
+	verifyBackupRequest := VerifyBackupRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&verifyBackupRequest); err != nil {
+			logger.Error(err)
+			verifyBackupResponse.Error = "failed to decode request body"
+			JSON(w, http.StatusBadRequest, verifyBackupResponse)
+			return
+		}
+	}
+
+	if snapshot.IsVerifyingBackup() {
+		verifyBackupResponse.Error = snapshot.ErrVerificationInProgress.Error()
+		JSON(w, http.StatusConflict, verifyBackupResponse)
+		return
+	}
+
+	backupName := mux.Vars(r)["snapshotName"]
+	if _, err := snapshot.GetBackup(r.Context(), util.PodNamespace, backupName); err != nil {
+		logger.Error(err)
+		verifyBackupResponse.Error = "failed to get backup"
+		JSON(w, http.StatusNotFound, verifyBackupResponse)
+		return
+	}
+
+	verificationID := ksuid.New().String()
+	go func() {
+		opts := snapshot.VerifyBackupOptions{
+			RunPreflights: verifyBackupRequest.RunPreflights,
+			ID:            verificationID,
+		}
+		if _, err := snapshot.VerifyBackup(context.Background(), util.PodNamespace, backupName, opts); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to verify backup %s", backupName))
+		}
+	}()
+
+	verifyBackupResponse.Success = true
+	verifyBackupResponse.VerificationID = verificationID
+
+	JSON(w, http.StatusOK, verifyBackupResponse)
+}
+
 type CreateInstanceBackupRequest struct {
 }
 
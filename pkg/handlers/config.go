@@ -50,6 +50,7 @@ type UpdateAppConfigResponse struct {
 	Success       bool     `json:"success"`
 	Error         string   `json:"error,omitempty"`
 	RequiredItems []string `json:"requiredItems,omitempty"`
+	InvalidItems  []string `json:"invalidItems,omitempty"`
 }
 
 type LiveAppConfigResponse struct {
@@ -118,7 +119,7 @@ func (h *Handler) UpdateAppConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(resp.RequiredItems) > 0 {
+	if len(resp.RequiredItems) > 0 || len(resp.InvalidItems) > 0 {
 		JSON(w, http.StatusBadRequest, resp)
 		return
 	}
@@ -241,7 +242,7 @@ func (h *Handler) LiveAppConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	versionInfo := template.VersionInfoFromInstallation(liveAppConfigRequest.Sequence+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save (and we want the preview to be accurate)
-	renderedConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValues, appLicense, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace)
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValues, appLicense, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
 	if err != nil {
 		logger.Error(err)
 		liveAppConfigResponse.Error = "failed to render templates"
@@ -254,6 +255,9 @@ func (h *Handler) LiveAppConfig(w http.ResponseWriter, r *http.Request) {
 		configGroups = renderedConfig.Spec.Groups
 	}
 
+	// set per item errors for values that don't satisfy the validation rules
+	kotsadmconfig.ValidateConfigGroups(configGroups, nil)
+
 	JSON(w, http.StatusOK, LiveAppConfigResponse{Success: true, ConfigGroups: configGroups})
 }
 
@@ -341,7 +345,7 @@ func (h *Handler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	versionInfo := template.VersionInfoFromInstallation(int64(sequence)+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save (and we want the preview to be accurate)
-	renderedConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValues, appLicense, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace)
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValues, appLicense, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
 	if err != nil {
 		logger.Error(err)
 		currentAppConfigResponse.Error = "failed to render templates"
@@ -354,6 +358,9 @@ func (h *Handler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 		configGroups = renderedConfig.Spec.Groups
 	}
 
+	// set per item errors for values that don't satisfy the validation rules
+	kotsadmconfig.ValidateConfigGroups(configGroups, nil)
+
 	JSON(w, http.StatusOK, CurrentAppConfigResponse{Success: true, ConfigGroups: configGroups})
 }
 
@@ -458,6 +465,28 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 		return updateAppConfigResponse, nil
 	}
 
+	// check for values that don't satisfy the validation rules, which are taken from the
+	// archive so that they can't be removed from the submitted config groups
+	invalidItems := kotsadmconfig.ValidateConfigGroups(configGroups, kotsKinds.Config)
+	if len(invalidItems) > 0 && isPrimaryVersion {
+		invalidItemsErrors := make([]string, 0, 0)
+		for _, group := range configGroups {
+			for _, item := range group.Items {
+				if item.Error == "" || !isInvalidItem(item.Name, invalidItems) {
+					continue
+				}
+				title := item.Title
+				if title == "" {
+					title = item.Name
+				}
+				invalidItemsErrors = append(invalidItemsErrors, fmt.Sprintf("%s (%s)", title, item.Error))
+			}
+		}
+		updateAppConfigResponse.InvalidItems = invalidItems
+		updateAppConfigResponse.Error = fmt.Sprintf("The following fields are invalid: %s", strings.Join(invalidItemsErrors, ", "))
+		return updateAppConfigResponse, nil
+	}
+
 	// we don't merge, this is a wholesale replacement of the config values
 	// so we don't need the complex logic in kots, we can just write
 	values := kotsKinds.ConfigValues.Spec.Values
@@ -531,7 +560,7 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 		}
 		sequence = newSequence
 	} else {
-		if err := kotsadmconfig.UpdateConfigValuesInDB(archiveDir, updateApp.ID, int64(sequence)); err != nil {
+		if err := store.GetStore().UpdateAppVersionConfigValues(updateApp.ID, int64(sequence), archiveDir); err != nil {
 			updateAppConfigResponse.Error = "failed to update config values in db"
 			return updateAppConfigResponse, err
 		}
@@ -566,6 +595,15 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 	return updateAppConfigResponse, nil
 }
 
+func isInvalidItem(name string, invalidItems []string) bool {
+	for _, invalidItem := range invalidItems {
+		if invalidItem == name {
+			return true
+		}
+	}
+	return false
+}
+
 func updateAppConfigValues(values map[string]kotsv1beta1.ConfigValue, configGroups []kotsv1beta1.ConfigGroup, encryptionKey string) (map[string]kotsv1beta1.ConfigValue, error) {
 	for _, group := range configGroups {
 		for _, item := range group.Items {
@@ -770,7 +808,7 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 	}
 
 	versionInfo := template.VersionInfoFromInstallation(foundApp.CurrentSequence+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save (and we want the preview to be accurate)
-	renderedConfig, err := kotsconfig.TemplateConfigObjects(newConfig, configValueMap, kotsKinds.License, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace)
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(newConfig, configValueMap, kotsKinds.License, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
 	if err != nil {
 		setAppConfigValuesResponse.Error = "failed to render templates"
 		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
@@ -800,6 +838,12 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(resp.InvalidItems) > 0 {
+		logger.Errorf("invalid config values: %s", resp.Error)
+		JSON(w, http.StatusBadRequest, resp)
+		return
+	}
+
 	setAppConfigValuesResponse.Success = true
 	JSON(w, http.StatusOK, setAppConfigValuesResponse)
 }
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/marccampbell/yaml-toolbox/pkg/splitter"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	kotsconfig "github.com/replicatedhq/kots/pkg/config"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/render"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/replicatedhq/kots/pkg/util"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+type PreviewAppConfigValuesRequest struct {
+	ConfigValues []byte `json:"configValues"`
+	Merge        bool   `json:"merge"`
+}
+
+// PreviewAppConfigValues renders the latest version with the proposed config values and returns
+// the diff against the deployed version. No version is created.
+func (h *Handler) PreviewAppConfigValues(w http.ResponseWriter, r *http.Request) {
+	previewResponse := handlertypes.PreviewAppConfigValuesResponse{
+		Success:           false,
+		DeployedSequence:  -1,
+		TemplateErrors:    []string{},
+		VisibilityChanges: []handlertypes.ConfigVisibilityChange{},
+		Diffs:             []handlertypes.RenderedFileDiff{},
+	}
+
+	previewRequest := PreviewAppConfigValuesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&previewRequest); err != nil {
+		previewResponse.Error = "failed to decode request body"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusBadRequest, previewResponse)
+		return
+	}
+
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	decoded, gvk, err := decode(previewRequest.ConfigValues, nil, nil)
+	if err != nil {
+		previewResponse.Error = "failed to decode config values"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusBadRequest, previewResponse)
+		return
+	}
+
+	if gvk.String() != "kots.io/v1beta1, Kind=ConfigValues" {
+		previewResponse.Error = fmt.Sprintf("%q is not a valid ConfigValues GVK", gvk.String())
+		logger.Errorf(previewResponse.Error)
+		JSON(w, http.StatusBadRequest, previewResponse)
+		return
+	}
+	newConfigValues := decoded.(*kotsv1beta1.ConfigValues)
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			previewResponse.Error = "app not found"
+			JSON(w, http.StatusNotFound, previewResponse)
+			return
+		}
+		previewResponse.Error = "failed to get app from app slug"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		previewResponse.Error = "failed to create temp dir"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	err = store.GetStore().GetAppVersionArchive(foundApp.ID, foundApp.CurrentSequence, archiveDir)
+	if err != nil {
+		previewResponse.Error = "failed to get app version archive"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		previewResponse.Error = "failed to load kots kinds from path"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	if kotsKinds.Config == nil || kotsKinds.ConfigValues == nil {
+		previewResponse.Error = fmt.Sprintf("app %s does not have a config", foundApp.Slug)
+		logger.Errorf(previewResponse.Error)
+		JSON(w, http.StatusBadRequest, previewResponse)
+		return
+	}
+
+	if previewRequest.Merge {
+		if err := kotsKinds.DecryptConfigValues(); err != nil {
+			previewResponse.Error = "failed to decrypt existing values"
+			logger.Error(errors.Wrap(err, previewResponse.Error))
+			JSON(w, http.StatusInternalServerError, previewResponse)
+			return
+		}
+
+		newConfigValues, err = mergeConfigValues(kotsKinds.Config, kotsKinds.ConfigValues, newConfigValues)
+		if err != nil {
+			previewResponse.Error = errors.Cause(err).Error()
+			JSON(w, http.StatusBadRequest, previewResponse)
+			return
+		}
+	}
+
+	newConfig, err := updateConfigObject(kotsKinds.Config, newConfigValues, previewRequest.Merge)
+	if err != nil {
+		previewResponse.Error = "failed to create new config object"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	registryInfo, err := store.GetStore().GetRegistryDetailsForApp(foundApp.ID)
+	if err != nil {
+		previewResponse.Error = "failed to get app registry info"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	localRegistry := template.LocalRegistry{
+		Host:      registryInfo.Hostname,
+		Namespace: registryInfo.Namespace,
+		Username:  registryInfo.Username,
+		Password:  registryInfo.Password,
+		ReadOnly:  registryInfo.IsReadOnly,
+	}
+
+	versionInfo := template.VersionInfoFromInstallation(foundApp.CurrentSequence+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save (and we want the preview to be accurate)
+
+	existingConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValuesToTemplateValues(kotsKinds.ConfigValues), kotsKinds.License, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
+	if err != nil {
+		// visibility changes cannot be computed, but the new values may still render
+		logger.Error(errors.Wrap(err, "failed to render existing config templates"))
+	}
+
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(newConfig, configValuesToTemplateValues(newConfigValues), kotsKinds.License, localRegistry, &versionInfo, kotsKinds.IdentityConfig, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
+	if err != nil {
+		previewResponse.Success = true
+		previewResponse.TemplateErrors = append(previewResponse.TemplateErrors, errors.Cause(err).Error())
+		JSON(w, http.StatusOK, previewResponse)
+		return
+	}
+
+	if existingConfig != nil && renderedConfig != nil {
+		previewResponse.VisibilityChanges = getConfigVisibilityChanges(existingConfig, renderedConfig)
+	}
+
+	updatedValues, err := updateAppConfigValues(kotsKinds.ConfigValues.Spec.Values, renderedConfig.Spec.Groups, kotsKinds.Installation.Spec.EncryptionKey)
+	if err != nil {
+		previewResponse.Error = "failed to update config values"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+	kotsKinds.ConfigValues.Spec.Values = updatedValues
+
+	configValuesSpec, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
+	if err != nil {
+		previewResponse.Error = "failed to marshal config values spec"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(archiveDir, "upstream", "userdata", "config.yaml"), []byte(configValuesSpec), 0644); err != nil {
+		previewResponse.Error = "failed to write config.yaml to upstream/userdata"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(foundApp.ID)
+	if err != nil {
+		previewResponse.Error = "failed to list downstreams for app"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	if err := render.RenderDir(archiveDir, foundApp, downstreams, registryInfo, true); err != nil {
+		previewResponse.Success = true
+		previewResponse.TemplateErrors = append(previewResponse.TemplateErrors, errors.Cause(err).Error())
+		JSON(w, http.StatusOK, previewResponse)
+		return
+	}
+
+	newFiles, err := getRenderedArchiveFiles(archiveDir)
+	if err != nil {
+		previewResponse.Success = true
+		previewResponse.TemplateErrors = append(previewResponse.TemplateErrors, errors.Cause(err).Error())
+		JSON(w, http.StatusOK, previewResponse)
+		return
+	}
+
+	deployedFiles := map[string]string{}
+	if len(downstreams) > 0 {
+		deployedSequence, err := store.GetStore().GetCurrentParentSequence(foundApp.ID, downstreams[0].ClusterID)
+		if err != nil {
+			previewResponse.Error = "failed to get deployed sequence"
+			logger.Error(errors.Wrap(err, previewResponse.Error))
+			JSON(w, http.StatusInternalServerError, previewResponse)
+			return
+		}
+		previewResponse.DeployedSequence = deployedSequence
+	}
+
+	if previewResponse.DeployedSequence != -1 {
+		deployedArchiveDir, err := ioutil.TempDir("", "kotsadm")
+		if err != nil {
+			previewResponse.Error = "failed to create temp dir"
+			logger.Error(errors.Wrap(err, previewResponse.Error))
+			JSON(w, http.StatusInternalServerError, previewResponse)
+			return
+		}
+		defer os.RemoveAll(deployedArchiveDir)
+
+		err = store.GetStore().GetAppVersionArchive(foundApp.ID, previewResponse.DeployedSequence, deployedArchiveDir)
+		if err != nil {
+			previewResponse.Error = "failed to get deployed app version archive"
+			logger.Error(errors.Wrap(err, previewResponse.Error))
+			JSON(w, http.StatusInternalServerError, previewResponse)
+			return
+		}
+
+		deployedFiles, err = getRenderedArchiveFiles(deployedArchiveDir)
+		if err != nil {
+			previewResponse.Error = "failed to render deployed app version"
+			logger.Error(errors.Wrap(err, previewResponse.Error))
+			JSON(w, http.StatusInternalServerError, previewResponse)
+			return
+		}
+	}
+
+	previewResponse.Diffs, err = diffRenderedFiles(deployedFiles, newFiles)
+	if err != nil {
+		previewResponse.Error = "failed to diff rendered files"
+		logger.Error(errors.Wrap(err, previewResponse.Error))
+		JSON(w, http.StatusInternalServerError, previewResponse)
+		return
+	}
+
+	previewResponse.Success = true
+	JSON(w, http.StatusOK, previewResponse)
+}
+
+func configValuesToTemplateValues(configValues *kotsv1beta1.ConfigValues) map[string]template.ItemValue {
+	configValueMap := map[string]template.ItemValue{}
+	for key, value := range configValues.Spec.Values {
+		configValueMap[key] = template.ItemValue{
+			Default:        value.Default,
+			Value:          value.Value,
+			Filename:       value.Filename,
+			RepeatableItem: value.RepeatableItem,
+		}
+	}
+	return configValueMap
+}
+
+// getRenderedArchiveFiles runs kustomize on the first downstream of the archive, the same way GetAppRenderedContents does
+func getRenderedArchiveFiles(archiveDir string) (map[string]string, error) {
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kots kinds from path")
+	}
+
+	children, err := ioutil.ReadDir(filepath.Join(archiveDir, "overlays", "downstreams"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read downstreams dir")
+	}
+	kustomizeBuildTarget := filepath.Join(archiveDir, "overlays", "midstream")
+	for _, child := range children {
+		if child.IsDir() {
+			kustomizeBuildTarget = filepath.Join(archiveDir, "overlays", "downstreams", child.Name())
+		}
+	}
+
+	archiveOutput, err := exec.Command(fmt.Sprintf("kustomize%s", kotsKinds.KustomizeVersion()), "build", kustomizeBuildTarget).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			err = fmt.Errorf("kustomize stderr: %q", string(ee.Stderr))
+		}
+		return nil, errors.Wrap(err, "failed to run kustomize")
+	}
+
+	archiveFiles, err := splitter.SplitYAML(archiveOutput)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split yaml")
+	}
+
+	renderedFiles := map[string]string{}
+	for filename, b := range archiveFiles {
+		renderedFiles[filename] = string(b)
+	}
+
+	kustomizedFiles, err := getKustomizedFiles(kustomizeBuildTarget, kotsKinds.KustomizeVersion())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to kustomize charts")
+	}
+	for filename, content := range kustomizedFiles {
+		renderedFiles[filename] = content
+	}
+
+	return renderedFiles, nil
+}
+
+// diffRenderedFiles returns a unified diff for every file that was added, removed or changed, sorted by filename
+func diffRenderedFiles(before map[string]string, after map[string]string) ([]handlertypes.RenderedFileDiff, error) {
+	filenames := map[string]struct{}{}
+	for filename := range before {
+		filenames[filename] = struct{}{}
+	}
+	for filename := range after {
+		filenames[filename] = struct{}{}
+	}
+
+	sortedFilenames := make([]string, 0, len(filenames))
+	for filename := range filenames {
+		sortedFilenames = append(sortedFilenames, filename)
+	}
+	sort.Strings(sortedFilenames)
+
+	diffs := []handlertypes.RenderedFileDiff{}
+	for _, filename := range sortedFilenames {
+		if before[filename] == after[filename] {
+			continue
+		}
+
+		fromFile, toFile := "a/"+filename, "b/"+filename
+		if _, ok := before[filename]; !ok {
+			fromFile = "/dev/null"
+		}
+		if _, ok := after[filename]; !ok {
+			toFile = "/dev/null"
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitDiffLines(before[filename]),
+			B:        splitDiffLines(after[filename]),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to diff %s", filename)
+		}
+
+		diffs = append(diffs, handlertypes.RenderedFileDiff{
+			Filename: filename,
+			Diff:     diff,
+		})
+	}
+
+	return diffs, nil
+}
+
+// splitDiffLines is like difflib.SplitLines, but does not append an extra empty line
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// getConfigVisibilityChanges compares the rendered "when" conditions of two versions of the same config.
+// Items are only reported when their group is visible in both.
+func getConfigVisibilityChanges(before *kotsv1beta1.Config, after *kotsv1beta1.Config) []handlertypes.ConfigVisibilityChange {
+	beforeGroups := map[string]kotsv1beta1.ConfigGroup{}
+	for _, group := range before.Spec.Groups {
+		beforeGroups[group.Name] = group
+	}
+
+	changes := []handlertypes.ConfigVisibilityChange{}
+	for _, afterGroup := range after.Spec.Groups {
+		beforeGroup, ok := beforeGroups[afterGroup.Name]
+		if !ok {
+			continue
+		}
+
+		beforeVisible, afterVisible := beforeGroup.When != "false", afterGroup.When != "false"
+		if beforeVisible != afterVisible {
+			changes = append(changes, handlertypes.ConfigVisibilityChange{
+				Group:   afterGroup.Name,
+				Visible: afterVisible,
+			})
+			continue
+		}
+		if !afterVisible {
+			continue
+		}
+
+		beforeItems := map[string]kotsv1beta1.ConfigItem{}
+		for _, item := range beforeGroup.Items {
+			beforeItems[item.Name] = item
+		}
+		for _, afterItem := range afterGroup.Items {
+			beforeItem, ok := beforeItems[afterItem.Name]
+			if !ok {
+				continue
+			}
+			if (beforeItem.When != "false") != (afterItem.When != "false") {
+				changes = append(changes, handlertypes.ConfigVisibilityChange{
+					Group:   afterGroup.Name,
+					Item:    afterItem.Name,
+					Visible: afterItem.When != "false",
+				})
+			}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getConfigVisibilityChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []kotsv1beta1.ConfigGroup
+		after  []kotsv1beta1.ConfigGroup
+		want   []handlertypes.ConfigVisibilityChange
+	}{
+		{
+			name: "no changes",
+			before: []kotsv1beta1.ConfigGroup{
+				{Name: "db", Items: []kotsv1beta1.ConfigItem{{Name: "host", When: "true"}}},
+			},
+			after: []kotsv1beta1.ConfigGroup{
+				{Name: "db", Items: []kotsv1beta1.ConfigItem{{Name: "host"}}},
+			},
+			want: []handlertypes.ConfigVisibilityChange{},
+		},
+		{
+			name: "item shown and hidden",
+			before: []kotsv1beta1.ConfigGroup{
+				{Name: "db", Items: []kotsv1beta1.ConfigItem{
+					{Name: "embedded", When: "false"},
+					{Name: "external_host"},
+				}},
+			},
+			after: []kotsv1beta1.ConfigGroup{
+				{Name: "db", Items: []kotsv1beta1.ConfigItem{
+					{Name: "embedded"},
+					{Name: "external_host", When: "false"},
+				}},
+			},
+			want: []handlertypes.ConfigVisibilityChange{
+				{Group: "db", Item: "embedded", Visible: true},
+				{Group: "db", Item: "external_host", Visible: false},
+			},
+		},
+		{
+			name: "group hidden hides items implicitly",
+			before: []kotsv1beta1.ConfigGroup{
+				{Name: "tls", Items: []kotsv1beta1.ConfigItem{{Name: "cert"}}},
+			},
+			after: []kotsv1beta1.ConfigGroup{
+				{Name: "tls", When: "false", Items: []kotsv1beta1.ConfigItem{{Name: "cert", When: "false"}}},
+			},
+			want: []handlertypes.ConfigVisibilityChange{
+				{Group: "tls", Visible: false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := &kotsv1beta1.Config{Spec: kotsv1beta1.ConfigSpec{Groups: test.before}}
+			after := &kotsv1beta1.Config{Spec: kotsv1beta1.ConfigSpec{Groups: test.after}}
+			assert.Equal(t, test.want, getConfigVisibilityChanges(before, after))
+		})
+	}
+}
+
+func Test_diffRenderedFiles(t *testing.T) {
+	before := map[string]string{
+		"deployment.yaml": "kind: Deployment\nreplicas: 1\n",
+		"removed.yaml":    "kind: Service\n",
+		"same.yaml":       "kind: ConfigMap\n",
+	}
+	after := map[string]string{
+		"added.yaml":      "kind: Secret\n",
+		"deployment.yaml": "kind: Deployment\nreplicas: 2\n",
+		"same.yaml":       "kind: ConfigMap\n",
+	}
+
+	diffs, err := diffRenderedFiles(before, after)
+	require.NoError(t, err)
+
+	want := []handlertypes.RenderedFileDiff{
+		{
+			Filename: "added.yaml",
+			Diff:     "--- /dev/null\n+++ b/added.yaml\n@@ -0,0 +1 @@\n+kind: Secret\n",
+		},
+		{
+			Filename: "deployment.yaml",
+			Diff:     "--- a/deployment.yaml\n+++ b/deployment.yaml\n@@ -1,2 +1,2 @@\n kind: Deployment\n-replicas: 1\n+replicas: 2\n",
+		},
+		{
+			Filename: "removed.yaml",
+			Diff:     "--- a/removed.yaml\n+++ /dev/null\n@@ -1 +0,0 @@\n-kind: Service\n",
+		},
+	}
+	assert.Equal(t, want, diffs)
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
-	"github.com/replicatedhq/kots/pkg/app"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
@@ -24,6 +23,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/redact"
 	"github.com/replicatedhq/kots/pkg/registry"
 	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
 	storetypes "github.com/replicatedhq/kots/pkg/store/types"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
@@ -32,15 +32,16 @@ import (
 )
 
 type UpdateDeployResultRequest struct {
-	AppID        string `json:"appId"`
-	IsError      bool   `json:"isError"`
-	DryrunStdout string `json:"dryrunStdout"`
-	DryrunStderr string `json:"dryrunStderr"`
-	ApplyStdout  string `json:"applyStdout"`
-	ApplyStderr  string `json:"applyStderr"`
-	HelmStdout   string `json:"helmStdout"`
-	HelmStderr   string `json:"helmStderr"`
-	RenderError  string `json:"renderError"`
+	AppID        string                             `json:"appId"`
+	IsError      bool                               `json:"isError"`
+	DryrunStdout string                             `json:"dryrunStdout"`
+	DryrunStderr string                             `json:"dryrunStderr"`
+	ApplyStdout  string                             `json:"applyStdout"`
+	ApplyStderr  string                             `json:"applyStderr"`
+	HelmStdout   string                             `json:"helmStdout"`
+	HelmStderr   string                             `json:"helmStderr"`
+	RenderError  string                             `json:"renderError"`
+	Resources    []downstreamtypes.DeployedResource `json:"resources"`
 }
 
 type UpdateUndeployResultRequest struct {
@@ -184,6 +185,7 @@ func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 		HelmStdout:   updateDeployResultRequest.HelmStdout,
 		HelmStderr:   updateDeployResultRequest.HelmStderr,
 		RenderError:  updateDeployResultRequest.RenderError,
+		Resources:    updateDeployResultRequest.Resources,
 	}
 	err = store.GetStore().UpdateDownstreamDeployStatus(updateDeployResultRequest.AppID, clusterID, currentSequence, updateDeployResultRequest.IsError, downstreamOutput)
 	if err != nil {
@@ -192,6 +194,8 @@ func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	go socketservice.HandleDeployResult(updateDeployResultRequest.AppID, clusterID, currentSequence, updateDeployResultRequest.IsError)
+
 	if !updateDeployResultRequest.IsError {
 		go func() {
 			err := deleteUnusedImages(updateDeployResultRequest.AppID)
@@ -405,7 +409,7 @@ func (h *Handler) UpdateUndeployResult(w http.ResponseWriter, r *http.Request) {
 	if foundApp.RestoreInProgressName != "" {
 		go func() {
 			<-time.After(20 * time.Second)
-			err = app.SetRestoreUndeployStatus(updateUndeployResultRequest.AppID, status)
+			err = store.GetStore().SetAppRestoreUndeployStatus(updateUndeployResultRequest.AppID, status)
 			if err != nil {
 				err = errors.Wrap(err, "failed to set app undeploy status")
 				logger.Error(err)
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/store"
 )
@@ -13,13 +14,14 @@ type GetDownstreamOutputResponse struct {
 	Logs DownstreamLogs `json:"logs"`
 }
 type DownstreamLogs struct {
-	DryrunStdout string `json:"dryrunStdout"`
-	DryrunStderr string `json:"dryrunStderr"`
-	ApplyStdout  string `json:"applyStdout"`
-	ApplyStderr  string `json:"applyStderr"`
-	HelmStdout   string `json:"helmStdout"`
-	HelmStderr   string `json:"helmStderr"`
-	RenderError  string `json:"renderError"`
+	DryrunStdout string                             `json:"dryrunStdout"`
+	DryrunStderr string                             `json:"dryrunStderr"`
+	ApplyStdout  string                             `json:"applyStdout"`
+	ApplyStderr  string                             `json:"applyStderr"`
+	HelmStdout   string                             `json:"helmStdout"`
+	HelmStderr   string                             `json:"helmStderr"`
+	RenderError  string                             `json:"renderError"`
+	Resources    []downstreamtypes.DeployedResource `json:"resources"`
 }
 
 func (h *Handler) GetDownstreamOutput(w http.ResponseWriter, r *http.Request) {
@@ -54,6 +56,7 @@ func (h *Handler) GetDownstreamOutput(w http.ResponseWriter, r *http.Request) {
 		HelmStdout:   output.HelmStdout,
 		HelmStderr:   output.HelmStderr,
 		RenderError:  output.RenderError,
+		Resources:    output.Resources,
 	}
 	getDownstreamOutputResponse := GetDownstreamOutputResponse{
 		Logs: downstreamLogs,
@@ -29,11 +29,17 @@ type CreateGitOpsRequest struct {
 	GitOpsInput CreateGitOpsInput `json:"gitOpsInput"`
 }
 type CreateGitOpsInput struct {
-	Provider string `json:"provider"`
-	URI      string `json:"uri"`
-	Hostname string `json:"hostname"`
-	HTTPPort string `json:"httpPort"`
-	SSHPort  string `json:"sshPort"`
+	Provider   string `json:"provider"`
+	URI        string `json:"uri"`
+	Hostname   string `json:"hostname"`
+	HTTPPort   string `json:"httpPort"`
+	SSHPort    string `json:"sshPort"`
+	Username   string `json:"username"`
+	Token      string `json:"token"`
+	SigningKey string `json:"signingKey"`
+	// ClearCredentials removes the stored username, token and signing key before the ones in this request are saved.
+	// Without it, credentials that are left empty keep their stored value.
+	ClearCredentials bool `json:"clearCredentials"`
 }
 
 func (h *Handler) UpdateAppGitOps(w http.ResponseWriter, r *http.Request) {
@@ -138,8 +144,8 @@ func (h *Handler) InitGitOpsConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if downstreamGitOps.Format != "single" {
-		logger.Error(errors.New("unsupported gitops format"))
+	if !gitops.IsSupportedFormat(downstreamGitOps.Format) {
+		logger.Error(errors.Errorf("unsupported gitops format %q", downstreamGitOps.Format))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -307,7 +313,7 @@ func (h *Handler) CreateGitOps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gitOpsInput := createGitOpsRequest.GitOpsInput
-	if err := gitops.CreateGitOps(gitOpsInput.Provider, gitOpsInput.URI, gitOpsInput.Hostname, gitOpsInput.HTTPPort, gitOpsInput.SSHPort); err != nil {
+	if err := gitops.CreateGitOps(gitOpsInput.Provider, gitOpsInput.URI, gitOpsInput.Hostname, gitOpsInput.HTTPPort, gitOpsInput.SSHPort, gitOpsInput.Username, gitOpsInput.Token, gitOpsInput.SigningKey, gitOpsInput.ClearCredentials); err != nil {
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
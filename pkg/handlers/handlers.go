@@ -169,6 +169,8 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.LiveAppConfig))
 	r.Name("SetAppConfigValues").Path("/api/v1/app/{appSlug}/config/values").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.SetAppConfigValues))
+	r.Name("PreviewAppConfigValues").Path("/api/v1/app/{appSlug}/config/values/preview").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.PreviewAppConfigValues))
 
 	r.Name("SyncLicense").Path("/api/v1/app/{appSlug}/license").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppLicenseWrite, handler.SyncLicense))
@@ -179,6 +181,10 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.AppUpdateCheck))
 	r.Name("UpdateCheckerSpec").Path("/api/v1/app/{appSlug}/updatecheckerspec").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.UpdateCheckerSpec))
+	r.Name("GetAutoDeployPolicy").Path("/api/v1/app/{appSlug}/autodeploy").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetAutoDeployPolicy))
+	r.Name("SetAutoDeployPolicy").Path("/api/v1/app/{appSlug}/autodeploy").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetAutoDeployPolicy))
 	r.Name("RemoveApp").Path("/api/v1/app/{appSlug}/remove").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.RemoveApp))
 
@@ -219,6 +225,8 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.BackupRead, handler.GetBackup))
 	r.Name("DeleteBackup").Path("/api/v1/snapshot/{snapshotName}/delete").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.BackupWrite, handler.DeleteBackup))
+	r.Name("VerifyBackup").Path("/api/v1/snapshot/{snapshotName}/verify").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.BackupWrite, handler.VerifyBackup))
 	r.Name("RestoreApps").Path("/api/v1/snapshot/{snapshotName}/restore-apps").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.RestoreWrite, handler.RestoreApps))
 	r.Name("GetRestoreAppsStatus").Path("/api/v1/snapshot/{snapshotName}/apps-restore-status").Methods("POST").
@@ -262,6 +270,49 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.GitopsWrite, handler.ResetGitOps))
 	r.Name("GetGitOpsRepo").Path("/api/v1/gitops/get").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.GitopsRead, handler.GetGitOpsRepo))
+
+	// Notifications
+	r.Name("ListNotificationEndpoints").Path("/api/v1/notifications").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsRead, handler.ListNotificationEndpoints))
+	r.Name("CreateNotificationEndpoint").Path("/api/v1/notifications").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsWrite, handler.CreateNotificationEndpoint))
+	r.Name("UpdateNotificationEndpoint").Path("/api/v1/notifications/{endpointId}").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsWrite, handler.UpdateNotificationEndpoint))
+	r.Name("DeleteNotificationEndpoint").Path("/api/v1/notifications/{endpointId}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsWrite, handler.DeleteNotificationEndpoint))
+	r.Name("ListNotificationDeliveries").Path("/api/v1/notifications/{endpointId}/deliveries").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsRead, handler.ListNotificationDeliveries))
+	r.Name("TestNotificationEndpoint").Path("/api/v1/notifications/{endpointId}/test").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.NotificationsWrite, handler.TestNotificationEndpoint))
+
+	// Local users
+	r.Name("ListLocalUsers").Path("/api/v1/users").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.UsersRead, handler.ListLocalUsers))
+	r.Name("CreateLocalUser").Path("/api/v1/users").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.UsersWrite, handler.CreateLocalUser))
+	r.Name("DeleteLocalUser").Path("/api/v1/users/{username}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.UsersWrite, handler.DeleteLocalUser))
+	r.Name("SetLocalUserRoles").Path("/api/v1/users/{username}/roles").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.UsersWrite, handler.SetLocalUserRoles))
+	r.Name("ResetLocalUserPassword").Path("/api/v1/users/{username}/password").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.UsersWrite, handler.ResetLocalUserPassword))
+
+	// API tokens
+	r.Name("ListAPITokens").Path("/api/v1/tokens").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.TokensRead, handler.ListAPITokens))
+	r.Name("CreateAPIToken").Path("/api/v1/tokens").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.TokensWrite, handler.CreateAPIToken))
+	r.Name("RevokeAPIToken").Path("/api/v1/tokens/{tokenId}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.TokensWrite, handler.RevokeAPIToken))
+
+	// Permissions
+	// every session can list its own permissions, so there is no policy to enforce
+	r.Name("GetSessionPermissions").Path("/api/v1/permissions").Methods("GET").
+		HandlerFunc(handler.GetSessionPermissions)
+
+	// Audit
+	r.Name("ListAuditEvents").Path("/api/v1/audit").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AuditRead, handler.ListAuditEvents))
 }
 
 func JSON(w http.ResponseWriter, code int, payload interface{}) {
@@ -43,6 +43,7 @@ type ConfigureIdentityServiceRequest struct {
 	IdentityServiceAddress  string                            `json:"identityServiceAddress,omitempty"`
 	UseAdminConsoleSettings bool                              `json:"useAdminConsoleSettings,omitempty"`
 	Groups                  []kotsv1beta1.IdentityConfigGroup `json:"groups,omitempty"`
+	Roles                   []kotsv1beta1.IdentityConfigRole  `json:"roles,omitempty"`
 
 	IDPConfig `json:",inline"`
 }
@@ -152,6 +153,13 @@ func (h *Handler) ConfigureIdentityService(w http.ResponseWriter, r *http.Reques
 		identityConfig.Spec.Groups = request.Groups
 	}
 
+	// keep the existing custom roles if the request does not include any, groups may reference them
+	if request.Roles != nil {
+		identityConfig.Spec.Roles = request.Roles
+	} else {
+		identityConfig.Spec.Roles = previousConfig.Spec.Roles
+	}
+
 	ingressConfig, err := ingress.GetConfig(r.Context(), namespace)
 	if err != nil {
 		err = errors.Wrap(err, "failed to get ingress config")
@@ -572,6 +580,7 @@ type GetIdentityServiceConfigResponse struct {
 	IdentityServiceAddress string                            `json:"identityServiceAddress,omitempty"`
 	Groups                 []kotsv1beta1.IdentityConfigGroup `json:"groups,omitempty"`
 	Roles                  []kotsv1beta1.IdentityRole        `json:"roles,omitempty"`
+	CustomRoles            []kotsv1beta1.IdentityConfigRole  `json:"customRoles,omitempty"`
 
 	IDPConfig `json:",inline"`
 }
@@ -593,15 +602,16 @@ func (h *Handler) GetIdentityServiceConfig(w http.ResponseWriter, r *http.Reques
 		AdminConsoleAddress:    identityConfig.Spec.AdminConsoleAddress,
 		IdentityServiceAddress: identityConfig.Spec.IdentityServiceAddress,
 		Groups:                 identityConfig.Spec.Groups,
+		CustomRoles:            identityConfig.Spec.Roles,
 	}
 
 	roles := []kotsv1beta1.IdentityRole{}
-	defaultRoles := rbac.DefaultRoles()
-	for _, defaultRole := range defaultRoles {
+	allRoles := append(rbac.DefaultRoles(), identity.RolesFromConfig(identityConfig.Spec)...)
+	for _, rbacRole := range allRoles {
 		role := kotsv1beta1.IdentityRole{
-			ID:          defaultRole.ID,
-			Name:        defaultRole.Name,
-			Description: defaultRole.Description,
+			ID:          rbacRole.ID,
+			Name:        rbacRole.Name,
+			Description: rbacRole.Description,
 		}
 		roles = append(roles, role)
 	}
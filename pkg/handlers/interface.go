@@ -81,12 +81,15 @@ type KOTSHandler interface {
 	CurrentAppConfig(w http.ResponseWriter, r *http.Request)
 	LiveAppConfig(w http.ResponseWriter, r *http.Request)
 	SetAppConfigValues(w http.ResponseWriter, r *http.Request)
+	PreviewAppConfigValues(w http.ResponseWriter, r *http.Request)
 
 	SyncLicense(w http.ResponseWriter, r *http.Request)
 	GetLicense(w http.ResponseWriter, r *http.Request)
 
 	AppUpdateCheck(w http.ResponseWriter, r *http.Request)
 	UpdateCheckerSpec(w http.ResponseWriter, r *http.Request)
+	GetAutoDeployPolicy(w http.ResponseWriter, r *http.Request)
+	SetAutoDeployPolicy(w http.ResponseWriter, r *http.Request)
 	RemoveApp(w http.ResponseWriter, r *http.Request)
 
 	// App snapshot routes
@@ -109,6 +112,7 @@ type KOTSHandler interface {
 	ConfigureFileSystemSnapshotProvider(w http.ResponseWriter, r *http.Request)
 	GetBackup(w http.ResponseWriter, r *http.Request)
 	DeleteBackup(w http.ResponseWriter, r *http.Request)
+	VerifyBackup(w http.ResponseWriter, r *http.Request)
 	RestoreApps(w http.ResponseWriter, r *http.Request)
 	GetRestoreAppsStatus(w http.ResponseWriter, r *http.Request)
 	DownloadSnapshotLogs(w http.ResponseWriter, r *http.Request)
@@ -133,4 +137,30 @@ type KOTSHandler interface {
 	CreateGitOps(w http.ResponseWriter, r *http.Request)
 	ResetGitOps(w http.ResponseWriter, r *http.Request)
 	GetGitOpsRepo(w http.ResponseWriter, r *http.Request)
+
+	// Notifications
+	ListNotificationEndpoints(w http.ResponseWriter, r *http.Request)
+	CreateNotificationEndpoint(w http.ResponseWriter, r *http.Request)
+	UpdateNotificationEndpoint(w http.ResponseWriter, r *http.Request)
+	DeleteNotificationEndpoint(w http.ResponseWriter, r *http.Request)
+	ListNotificationDeliveries(w http.ResponseWriter, r *http.Request)
+	TestNotificationEndpoint(w http.ResponseWriter, r *http.Request)
+
+	// Local users
+	ListLocalUsers(w http.ResponseWriter, r *http.Request)
+	CreateLocalUser(w http.ResponseWriter, r *http.Request)
+	DeleteLocalUser(w http.ResponseWriter, r *http.Request)
+	SetLocalUserRoles(w http.ResponseWriter, r *http.Request)
+	ResetLocalUserPassword(w http.ResponseWriter, r *http.Request)
+
+	// API tokens
+	ListAPITokens(w http.ResponseWriter, r *http.Request)
+	CreateAPIToken(w http.ResponseWriter, r *http.Request)
+	RevokeAPIToken(w http.ResponseWriter, r *http.Request)
+
+	// Permissions
+	GetSessionPermissions(w http.ResponseWriter, r *http.Request)
+
+	// Audit
+	ListAuditEvents(w http.ResponseWriter, r *http.Request)
 }
@@ -26,12 +26,15 @@ import (
 )
 
 type LoginRequest struct {
+	// Username is empty when logging in with the shared password
+	Username string `json:"username,omitempty"`
 	Password string `json:"password"`
 }
 
 type LoginResponse struct {
-	Error string `json:"error,omitempty"`
-	Token string `json:"token,omitempty"`
+	Error              string `json:"error,omitempty"`
+	Token              string `json:"token,omitempty"`
+	MustChangePassword bool   `json:"mustChangePassword,omitempty"`
 }
 
 type LoginMethod string
@@ -74,6 +77,11 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if loginRequest.Username != "" {
+		h.loginLocalUser(w, loginRequest)
+		return
+	}
+
 	foundUser, err := user.LogIn(loginRequest.Password)
 	if err == user.ErrInvalidPassword {
 		loginResponse.Error = "Invalid password. Please try again."
@@ -92,6 +100,44 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	// TODO: super user permissions
 	roles := session.GetSessionRolesFromRBAC(nil, identity.DefaultGroups)
 
+	createSessionAndRespond(w, foundUser, roles)
+}
+
+func (h *Handler) loginLocalUser(w http.ResponseWriter, loginRequest LoginRequest) {
+	loginResponse := LoginResponse{}
+
+	foundUser, roles, err := user.LogInLocalUser(loginRequest.Username, loginRequest.Password)
+	if err == user.ErrInvalidPassword {
+		loginResponse.Error = "Invalid username or password. Please try again."
+		JSON(w, http.StatusUnauthorized, loginResponse)
+		return
+	} else if err == user.ErrTooManyAttempts {
+		loginResponse.Error = "This account has been locked. Please ask an administrator to reset your password using the \"kubectl kots admin-console user reset-password\" command."
+		JSON(w, http.StatusUnauthorized, loginResponse)
+		return
+	} else if err == user.ErrPasswordChangeRequired {
+		loginResponse.Error = "Your password must be changed before you can log in."
+		loginResponse.MustChangePassword = true
+		JSON(w, http.StatusForbidden, loginResponse)
+		return
+	} else if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(roles) == 0 {
+		loginResponse.Error = "This account does not have any roles."
+		JSON(w, http.StatusForbidden, loginResponse)
+		return
+	}
+
+	createSessionAndRespond(w, foundUser, roles)
+}
+
+func createSessionAndRespond(w http.ResponseWriter, foundUser *usertypes.User, roles []string) {
+	loginResponse := LoginResponse{}
+
 	issuedAt, expiresAt := time.Now(), time.Now().AddDate(0, 0, 14)
 	createdSession, err := store.GetStore().CreateSession(foundUser, issuedAt, expiresAt, roles)
 	if err != nil {
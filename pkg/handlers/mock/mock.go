@@ -142,6 +142,18 @@ func (mr *MockKOTSHandlerMockRecorder) ConfigureIdentityService(w, r interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureIdentityService", reflect.TypeOf((*MockKOTSHandler)(nil).ConfigureIdentityService), w, r)
 }
 
+// CreateAPIToken mocks base method.
+func (m *MockKOTSHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateAPIToken", w, r)
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockKOTSHandlerMockRecorder) CreateAPIToken(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockKOTSHandler)(nil).CreateAPIToken), w, r)
+}
+
 // CreateAppFromAirgap mocks base method.
 func (m *MockKOTSHandler) CreateAppFromAirgap(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -202,6 +214,30 @@ func (mr *MockKOTSHandlerMockRecorder) CreateInstanceBackup(w, r interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstanceBackup", reflect.TypeOf((*MockKOTSHandler)(nil).CreateInstanceBackup), w, r)
 }
 
+// CreateLocalUser mocks base method.
+func (m *MockKOTSHandler) CreateLocalUser(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateLocalUser", w, r)
+}
+
+// CreateLocalUser indicates an expected call of CreateLocalUser.
+func (mr *MockKOTSHandlerMockRecorder) CreateLocalUser(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLocalUser", reflect.TypeOf((*MockKOTSHandler)(nil).CreateLocalUser), w, r)
+}
+
+// CreateNotificationEndpoint mocks base method.
+func (m *MockKOTSHandler) CreateNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateNotificationEndpoint", w, r)
+}
+
+// CreateNotificationEndpoint indicates an expected call of CreateNotificationEndpoint.
+func (mr *MockKOTSHandlerMockRecorder) CreateNotificationEndpoint(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationEndpoint", reflect.TypeOf((*MockKOTSHandler)(nil).CreateNotificationEndpoint), w, r)
+}
+
 // CurrentAppConfig mocks base method.
 func (m *MockKOTSHandler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -226,6 +262,18 @@ func (mr *MockKOTSHandlerMockRecorder) DeleteBackup(w, r interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBackup", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteBackup), w, r)
 }
 
+// DeleteLocalUser mocks base method.
+func (m *MockKOTSHandler) DeleteLocalUser(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLocalUser", w, r)
+}
+
+// DeleteLocalUser indicates an expected call of DeleteLocalUser.
+func (mr *MockKOTSHandlerMockRecorder) DeleteLocalUser(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocalUser", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteLocalUser), w, r)
+}
+
 // DeleteNode mocks base method.
 func (m *MockKOTSHandler) DeleteNode(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -238,6 +286,18 @@ func (mr *MockKOTSHandlerMockRecorder) DeleteNode(w, r interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteNode), w, r)
 }
 
+// DeleteNotificationEndpoint mocks base method.
+func (m *MockKOTSHandler) DeleteNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteNotificationEndpoint", w, r)
+}
+
+// DeleteNotificationEndpoint indicates an expected call of DeleteNotificationEndpoint.
+func (mr *MockKOTSHandlerMockRecorder) DeleteNotificationEndpoint(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationEndpoint", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteNotificationEndpoint), w, r)
+}
+
 // DeleteRedact mocks base method.
 func (m *MockKOTSHandler) DeleteRedact(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -502,6 +562,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetAppVersionHistory(w, r interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionHistory", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppVersionHistory), w, r)
 }
 
+// GetAutoDeployPolicy mocks base method.
+func (m *MockKOTSHandler) GetAutoDeployPolicy(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAutoDeployPolicy", w, r)
+}
+
+// GetAutoDeployPolicy indicates an expected call of GetAutoDeployPolicy.
+func (mr *MockKOTSHandlerMockRecorder) GetAutoDeployPolicy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoDeployPolicy", reflect.TypeOf((*MockKOTSHandler)(nil).GetAutoDeployPolicy), w, r)
+}
+
 // GetBackup mocks base method.
 func (m *MockKOTSHandler) GetBackup(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -742,6 +814,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetRestoreStatus(w, r interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestoreStatus", reflect.TypeOf((*MockKOTSHandler)(nil).GetRestoreStatus), w, r)
 }
 
+// GetSessionPermissions mocks base method.
+func (m *MockKOTSHandler) GetSessionPermissions(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSessionPermissions", w, r)
+}
+
+// GetSessionPermissions indicates an expected call of GetSessionPermissions.
+func (mr *MockKOTSHandlerMockRecorder) GetSessionPermissions(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionPermissions", reflect.TypeOf((*MockKOTSHandler)(nil).GetSessionPermissions), w, r)
+}
+
 // GetSnapshotConfig mocks base method.
 func (m *MockKOTSHandler) GetSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -850,6 +934,18 @@ func (mr *MockKOTSHandlerMockRecorder) InitGitOpsConnection(w, r interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitGitOpsConnection", reflect.TypeOf((*MockKOTSHandler)(nil).InitGitOpsConnection), w, r)
 }
 
+// ListAPITokens mocks base method.
+func (m *MockKOTSHandler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListAPITokens", w, r)
+}
+
+// ListAPITokens indicates an expected call of ListAPITokens.
+func (mr *MockKOTSHandlerMockRecorder) ListAPITokens(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPITokens", reflect.TypeOf((*MockKOTSHandler)(nil).ListAPITokens), w, r)
+}
+
 // ListApps mocks base method.
 func (m *MockKOTSHandler) ListApps(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -862,6 +958,18 @@ func (mr *MockKOTSHandlerMockRecorder) ListApps(w, r interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApps", reflect.TypeOf((*MockKOTSHandler)(nil).ListApps), w, r)
 }
 
+// ListAuditEvents mocks base method.
+func (m *MockKOTSHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListAuditEvents", w, r)
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockKOTSHandlerMockRecorder) ListAuditEvents(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockKOTSHandler)(nil).ListAuditEvents), w, r)
+}
+
 // ListBackups mocks base method.
 func (m *MockKOTSHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -886,6 +994,42 @@ func (mr *MockKOTSHandlerMockRecorder) ListInstanceBackups(w, r interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceBackups", reflect.TypeOf((*MockKOTSHandler)(nil).ListInstanceBackups), w, r)
 }
 
+// ListLocalUsers mocks base method.
+func (m *MockKOTSHandler) ListLocalUsers(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListLocalUsers", w, r)
+}
+
+// ListLocalUsers indicates an expected call of ListLocalUsers.
+func (mr *MockKOTSHandlerMockRecorder) ListLocalUsers(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocalUsers", reflect.TypeOf((*MockKOTSHandler)(nil).ListLocalUsers), w, r)
+}
+
+// ListNotificationDeliveries mocks base method.
+func (m *MockKOTSHandler) ListNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListNotificationDeliveries", w, r)
+}
+
+// ListNotificationDeliveries indicates an expected call of ListNotificationDeliveries.
+func (mr *MockKOTSHandlerMockRecorder) ListNotificationDeliveries(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationDeliveries", reflect.TypeOf((*MockKOTSHandler)(nil).ListNotificationDeliveries), w, r)
+}
+
+// ListNotificationEndpoints mocks base method.
+func (m *MockKOTSHandler) ListNotificationEndpoints(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListNotificationEndpoints", w, r)
+}
+
+// ListNotificationEndpoints indicates an expected call of ListNotificationEndpoints.
+func (mr *MockKOTSHandlerMockRecorder) ListNotificationEndpoints(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationEndpoints", reflect.TypeOf((*MockKOTSHandler)(nil).ListNotificationEndpoints), w, r)
+}
+
 // ListRedactors mocks base method.
 func (m *MockKOTSHandler) ListRedactors(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -946,6 +1090,18 @@ func (mr *MockKOTSHandlerMockRecorder) PreflightsReports(w, r interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreflightsReports", reflect.TypeOf((*MockKOTSHandler)(nil).PreflightsReports), w, r)
 }
 
+// PreviewAppConfigValues mocks base method.
+func (m *MockKOTSHandler) PreviewAppConfigValues(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PreviewAppConfigValues", w, r)
+}
+
+// PreviewAppConfigValues indicates an expected call of PreviewAppConfigValues.
+func (mr *MockKOTSHandlerMockRecorder) PreviewAppConfigValues(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewAppConfigValues", reflect.TypeOf((*MockKOTSHandler)(nil).PreviewAppConfigValues), w, r)
+}
+
 // RedeployAppVersion mocks base method.
 func (m *MockKOTSHandler) RedeployAppVersion(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -994,6 +1150,18 @@ func (mr *MockKOTSHandlerMockRecorder) ResetGitOps(w, r interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetGitOps", reflect.TypeOf((*MockKOTSHandler)(nil).ResetGitOps), w, r)
 }
 
+// ResetLocalUserPassword mocks base method.
+func (m *MockKOTSHandler) ResetLocalUserPassword(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetLocalUserPassword", w, r)
+}
+
+// ResetLocalUserPassword indicates an expected call of ResetLocalUserPassword.
+func (mr *MockKOTSHandlerMockRecorder) ResetLocalUserPassword(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLocalUserPassword", reflect.TypeOf((*MockKOTSHandler)(nil).ResetLocalUserPassword), w, r)
+}
+
 // RestoreApps mocks base method.
 func (m *MockKOTSHandler) RestoreApps(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1018,6 +1186,18 @@ func (mr *MockKOTSHandlerMockRecorder) ResumeInstallOnline(w, r interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeInstallOnline", reflect.TypeOf((*MockKOTSHandler)(nil).ResumeInstallOnline), w, r)
 }
 
+// RevokeAPIToken mocks base method.
+func (m *MockKOTSHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeAPIToken", w, r)
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockKOTSHandlerMockRecorder) RevokeAPIToken(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockKOTSHandler)(nil).RevokeAPIToken), w, r)
+}
+
 // SaveInstanceSnapshotConfig mocks base method.
 func (m *MockKOTSHandler) SaveInstanceSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1054,6 +1234,30 @@ func (mr *MockKOTSHandlerMockRecorder) SetAppConfigValues(w, r interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppConfigValues", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppConfigValues), w, r)
 }
 
+// SetAutoDeployPolicy mocks base method.
+func (m *MockKOTSHandler) SetAutoDeployPolicy(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAutoDeployPolicy", w, r)
+}
+
+// SetAutoDeployPolicy indicates an expected call of SetAutoDeployPolicy.
+func (mr *MockKOTSHandlerMockRecorder) SetAutoDeployPolicy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAutoDeployPolicy", reflect.TypeOf((*MockKOTSHandler)(nil).SetAutoDeployPolicy), w, r)
+}
+
+// SetLocalUserRoles mocks base method.
+func (m *MockKOTSHandler) SetLocalUserRoles(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLocalUserRoles", w, r)
+}
+
+// SetLocalUserRoles indicates an expected call of SetLocalUserRoles.
+func (mr *MockKOTSHandlerMockRecorder) SetLocalUserRoles(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalUserRoles", reflect.TypeOf((*MockKOTSHandler)(nil).SetLocalUserRoles), w, r)
+}
+
 // SetPrometheusAddress mocks base method.
 func (m *MockKOTSHandler) SetPrometheusAddress(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1114,6 +1318,18 @@ func (mr *MockKOTSHandlerMockRecorder) SyncLicense(w, r interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLicense", reflect.TypeOf((*MockKOTSHandler)(nil).SyncLicense), w, r)
 }
 
+// TestNotificationEndpoint mocks base method.
+func (m *MockKOTSHandler) TestNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TestNotificationEndpoint", w, r)
+}
+
+// TestNotificationEndpoint indicates an expected call of TestNotificationEndpoint.
+func (mr *MockKOTSHandlerMockRecorder) TestNotificationEndpoint(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestNotificationEndpoint", reflect.TypeOf((*MockKOTSHandler)(nil).TestNotificationEndpoint), w, r)
+}
+
 // UpdateAppConfig mocks base method.
 func (m *MockKOTSHandler) UpdateAppConfig(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1186,6 +1402,18 @@ func (mr *MockKOTSHandlerMockRecorder) UpdateGlobalSnapshotSettings(w, r interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGlobalSnapshotSettings", reflect.TypeOf((*MockKOTSHandler)(nil).UpdateGlobalSnapshotSettings), w, r)
 }
 
+// UpdateNotificationEndpoint mocks base method.
+func (m *MockKOTSHandler) UpdateNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateNotificationEndpoint", w, r)
+}
+
+// UpdateNotificationEndpoint indicates an expected call of UpdateNotificationEndpoint.
+func (mr *MockKOTSHandlerMockRecorder) UpdateNotificationEndpoint(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationEndpoint", reflect.TypeOf((*MockKOTSHandler)(nil).UpdateNotificationEndpoint), w, r)
+}
+
 // UpdateRedact mocks base method.
 func (m *MockKOTSHandler) UpdateRedact(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1233,3 +1461,15 @@ func (mr *MockKOTSHandlerMockRecorder) ValidateAppRegistry(w, r interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAppRegistry", reflect.TypeOf((*MockKOTSHandler)(nil).ValidateAppRegistry), w, r)
 }
+
+// VerifyBackup mocks base method.
+func (m *MockKOTSHandler) VerifyBackup(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "VerifyBackup", w, r)
+}
+
+// VerifyBackup indicates an expected call of VerifyBackup.
+func (mr *MockKOTSHandlerMockRecorder) VerifyBackup(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBackup", reflect.TypeOf((*MockKOTSHandler)(nil).VerifyBackup), w, r)
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const notificationDeliveriesLimit = 50
+
+type ListNotificationEndpointsResponse struct {
+	Success    bool                          `json:"success"`
+	Error      string                        `json:"error,omitempty"`
+	Endpoints  []*notificationtypes.Endpoint `json:"endpoints"`
+	EventTypes []notificationtypes.EventType `json:"eventTypes"`
+}
+
+type NotificationEndpointRequest struct {
+	URL       string                        `json:"url"`
+	Secret    string                        `json:"secret"`
+	Events    []notificationtypes.EventType `json:"events"`
+	IsEnabled *bool                         `json:"isEnabled"`
+}
+
+type NotificationEndpointResponse struct {
+	Success  bool                        `json:"success"`
+	Error    string                      `json:"error,omitempty"`
+	Endpoint *notificationtypes.Endpoint `json:"endpoint,omitempty"`
+}
+
+type ListNotificationDeliveriesResponse struct {
+	Success    bool                          `json:"success"`
+	Error      string                        `json:"error,omitempty"`
+	Deliveries []*notificationtypes.Delivery `json:"deliveries"`
+}
+
+type TestNotificationEndpointResponse struct {
+	Success  bool                        `json:"success"`
+	Error    string                      `json:"error,omitempty"`
+	Delivery *notificationtypes.Delivery `json:"delivery,omitempty"`
+}
+
+func (h *Handler) ListNotificationEndpoints(w http.ResponseWriter, r *http.Request) {
+	response := ListNotificationEndpointsResponse{
+		EventTypes: notificationtypes.EventTypes,
+	}
+
+	endpoints, err := store.GetStore().ListNotificationEndpoints()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list notification endpoints"))
+		response.Error = "failed to list notification endpoints"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	response.Success = true
+	response.Endpoints = endpoints
+
+	JSON(w, http.StatusOK, response)
+}
+
+// CreateNotificationEndpoint returns the signing secret. This is the only time the secret is returned.
+func (h *Handler) CreateNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	response := NotificationEndpointResponse{}
+
+	request := NotificationEndpointRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := validateNotificationEndpointRequest(request); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		generated, err := generateNotificationSecret()
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to generate secret"))
+			response.Error = "failed to generate secret"
+			JSON(w, http.StatusInternalServerError, response)
+			return
+		}
+		secret = generated
+	}
+
+	endpoint, err := store.GetStore().CreateNotificationEndpoint(request.URL, secret, request.Events)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create notification endpoint"))
+		response.Error = "failed to create notification endpoint"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if request.IsEnabled != nil && !*request.IsEnabled {
+		endpoint.IsEnabled = false
+		if err := store.GetStore().UpdateNotificationEndpoint(endpoint); err != nil {
+			logger.Error(errors.Wrap(err, "failed to disable notification endpoint"))
+			response.Error = "failed to disable notification endpoint"
+			JSON(w, http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	response.Success = true
+	response.Endpoint = endpoint
+
+	JSON(w, http.StatusCreated, response)
+}
+
+// UpdateNotificationEndpoint keeps the existing secret unless a new one is provided
+func (h *Handler) UpdateNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	response := NotificationEndpointResponse{}
+
+	request := NotificationEndpointRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := validateNotificationEndpointRequest(request); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	endpoint, err := store.GetStore().GetNotificationEndpoint(mux.Vars(r)["endpointId"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "notification endpoint not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to get notification endpoint"))
+		response.Error = "failed to get notification endpoint"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	endpoint.URL = request.URL
+	endpoint.Events = request.Events
+	if request.Secret != "" {
+		endpoint.Secret = request.Secret
+	}
+	if request.IsEnabled != nil {
+		endpoint.IsEnabled = *request.IsEnabled
+	}
+
+	if err := store.GetStore().UpdateNotificationEndpoint(endpoint); err != nil {
+		logger.Error(errors.Wrap(err, "failed to update notification endpoint"))
+		response.Error = "failed to update notification endpoint"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	endpoint.Secret = ""
+
+	response.Success = true
+	response.Endpoint = endpoint
+
+	JSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) DeleteNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	if err := store.GetStore().DeleteNotificationEndpoint(mux.Vars(r)["endpointId"]); err != nil {
+		logger.Error(errors.Wrap(err, "failed to delete notification endpoint"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	response := ListNotificationDeliveriesResponse{}
+
+	deliveries, err := store.GetStore().ListNotificationDeliveries(mux.Vars(r)["endpointId"], notificationDeliveriesLimit)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list notification deliveries"))
+		response.Error = "failed to list notification deliveries"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+	response.Deliveries = deliveries
+
+	JSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) TestNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	response := TestNotificationEndpointResponse{}
+
+	endpoint, err := store.GetStore().GetNotificationEndpoint(mux.Vars(r)["endpointId"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "notification endpoint not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to get notification endpoint"))
+		response.Error = "failed to get notification endpoint"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	delivery, err := notifications.SendTest(endpoint)
+	response.Delivery = delivery
+	if err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusOK, response)
+		return
+	}
+
+	response.Success = true
+
+	JSON(w, http.StatusOK, response)
+}
+
+func validateNotificationEndpointRequest(request NotificationEndpointRequest) error {
+	u, err := url.Parse(request.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be a valid http or https url")
+	}
+
+	for _, event := range request.Events {
+		if !isNotificationEventType(event) {
+			return errors.Errorf("unknown event type %q", event)
+		}
+	}
+
+	return nil
+}
+
+func isNotificationEventType(eventType notificationtypes.EventType) bool {
+	for _, t := range notificationtypes.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateNotificationSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to read random bytes")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/policy"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/util"
+)
+
+type GetSessionPermissionsResponse struct {
+	Success     bool                `json:"success"`
+	Error       string              `json:"error,omitempty"`
+	UserID      string              `json:"userId"`
+	Roles       []rbactypes.Role    `json:"roles"`
+	Permissions []policy.Permission `json:"permissions"`
+}
+
+// GetSessionPermissions lists the roles of the current session and which actions they allow on each resource.
+// Sessions created before rbac have full access.
+func (h *Handler) GetSessionPermissions(w http.ResponseWriter, r *http.Request) {
+	response := GetSessionPermissionsResponse{}
+
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		response.Error = "invalid session"
+		JSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		response.Error = "failed to get roles"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list apps"))
+		response.Error = "failed to list apps"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	appSlugs := []string{}
+	for _, a := range apps {
+		appSlugs = append(appSlugs, a.Slug)
+	}
+
+	permissions, err := policy.EffectivePermissions(r.Context(), roles, sess.Roles, appSlugs)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get effective permissions"))
+		response.Error = "failed to get effective permissions"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if !sess.HasRBAC {
+		for i := range permissions {
+			permissions[i].Allowed = true
+		}
+	}
+
+	sessionRoles := []rbactypes.Role{}
+	for _, role := range roles {
+		for _, roleID := range sess.Roles {
+			if role.ID == roleID {
+				sessionRoles = append(sessionRoles, role)
+				break
+			}
+		}
+	}
+
+	response.Success = true
+	response.UserID = sess.UserID
+	response.Roles = sessionRoles
+	response.Permissions = permissions
+
+	JSON(w, http.StatusOK, response)
+}
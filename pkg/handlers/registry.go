@@ -13,7 +13,6 @@ import (
 	"github.com/docker/distribution/registry/api/errcode"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
-	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	dockerregistry "github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/preflight"
@@ -31,6 +30,11 @@ type UpdateAppRegistryRequest struct {
 	Password   string `json:"password"`
 	Namespace  string `json:"namespace"`
 	IsReadOnly bool   `json:"isReadOnly"`
+	// Sequence is the version to rewrite with the new registry settings. Defaults to the current version.
+	Sequence *int64 `json:"sequence,omitempty"`
+	// Deploy deploys the rewritten version once its preflight checks pass.
+	// If the registry settings did not change, the version is deployed as is.
+	Deploy bool `json:"deploy"`
 }
 
 type UpdateAppRegistryResponse struct {
@@ -146,7 +150,12 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 	updateAppRegistryResponse.Username = updateAppRegistryRequest.Username
 	updateAppRegistryResponse.Namespace = updateAppRegistryRequest.Namespace
 
-	registryChanged, err := registrySettingsChanged(foundApp, updateAppRegistryRequest, registrySettings)
+	baseSequence := foundApp.CurrentSequence
+	if updateAppRegistryRequest.Sequence != nil {
+		baseSequence = *updateAppRegistryRequest.Sequence
+	}
+
+	registryChanged, err := registrySettingsChanged(foundApp.ID, baseSequence, updateAppRegistryRequest, registrySettings)
 	if err != nil {
 		logger.Error(errors.Wrap(err, "failed to check registry settings"))
 		updateAppRegistryResponse.Error = err.Error()
@@ -155,11 +164,28 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !registryChanged {
+		if updateAppRegistryRequest.Deploy {
+			if err := version.DeployVersion(foundApp.ID, baseSequence); err != nil {
+				logger.Error(errors.Wrap(err, "failed to deploy version"))
+				updateAppRegistryResponse.Error = err.Error()
+				JSON(w, http.StatusInternalServerError, updateAppRegistryResponse)
+				return
+			}
+		}
+
 		updateAppRegistryResponse.Success = true
 		JSON(w, http.StatusOK, updateAppRegistryResponse)
 		return
 	}
 
+	// mark the task as running before returning so that clients polling the status don't miss it
+	if err := store.GetStore().SetTaskStatus("image-rewrite", "Updating registry settings", "running"); err != nil {
+		logger.Error(errors.Wrap(err, "failed to set image-rewrite task status"))
+		updateAppRegistryResponse.Error = err.Error()
+		JSON(w, http.StatusInternalServerError, updateAppRegistryResponse)
+		return
+	}
+
 	// in a goroutine, start pushing the images to the remote registry
 	// we will let this function return while this happens
 	go func() {
@@ -170,7 +196,7 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 		}
 
 		appDir, err := registry.RewriteImages(
-			foundApp.ID, foundApp.CurrentSequence, updateAppRegistryRequest.Hostname,
+			foundApp.ID, baseSequence, updateAppRegistryRequest.Hostname,
 			updateAppRegistryRequest.Username, registryPassword,
 			updateAppRegistryRequest.Namespace, skipImagePush, nil)
 		if err != nil {
@@ -191,7 +217,7 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 		}
 		defer os.RemoveAll(appDir)
 
-		newSequence, err := store.GetStore().CreateAppVersion(foundApp.ID, &foundApp.CurrentSequence, appDir, "Registry Change", false, &version.DownstreamGitOps{})
+		newSequence, err := store.GetStore().CreateAppVersion(foundApp.ID, &baseSequence, appDir, "Registry Change", false, &version.DownstreamGitOps{})
 		if err != nil {
 			logger.Error(errors.Wrap(err, "failed to create app version"))
 			return
@@ -203,7 +229,13 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := preflight.Run(foundApp.ID, foundApp.Slug, newSequence, foundApp.IsAirgap, appDir); err != nil {
+		// the new version is only deployed once its preflights pass, like any other version
+		if updateAppRegistryRequest.Deploy {
+			err = preflight.RunAndDeploy(foundApp.ID, foundApp.Slug, newSequence, foundApp.IsAirgap, appDir)
+		} else {
+			err = preflight.Run(foundApp.ID, foundApp.Slug, newSequence, foundApp.IsAirgap, appDir)
+		}
+		if err != nil {
 			logger.Error(errors.Wrap(err, "failed to run preflights"))
 			return
 		}
@@ -213,7 +245,7 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, updateAppRegistryResponse)
 }
 
-func registrySettingsChanged(app *apptypes.App, new UpdateAppRegistryRequest, current registrytypes.RegistrySettings) (bool, error) {
+func registrySettingsChanged(appID string, sequence int64, new UpdateAppRegistryRequest, current registrytypes.RegistrySettings) (bool, error) {
 	if new.Hostname != current.Hostname {
 		return true, nil
 	}
@@ -238,7 +270,7 @@ func registrySettingsChanged(app *apptypes.App, new UpdateAppRegistryRequest, cu
 	}
 	defer os.RemoveAll(archiveDir)
 
-	err = store.GetStore().GetAppVersionArchive(app.ID, app.CurrentSequence, archiveDir)
+	err = store.GetStore().GetAppVersionArchive(appID, sequence, archiveDir)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to get version archive")
 	}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
-	"github.com/replicatedhq/kots/pkg/app"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
 	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
@@ -103,7 +102,7 @@ func (h *Handler) CreateApplicationRestore(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = app.InitiateRestore(snapshotName, kotsApp.ID)
+	err = store.GetStore().SetAppRestoreInProgress(kotsApp.ID, snapshotName)
 	if err != nil {
 		logger.Error(err)
 		createRestoreResponse.Error = "failed to initiate restore"
@@ -180,7 +179,7 @@ func (h *Handler) RestoreApps(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		if err := app.ResetRestore(a.ID); err != nil {
+		if err := store.GetStore().ResetAppRestore(a.ID); err != nil {
 			logger.Error(err)
 			restoreResponse.Error = fmt.Sprintf("failed to reset restore for app %s", a.Slug)
 			JSON(w, http.StatusInternalServerError, restoreResponse)
@@ -195,7 +194,7 @@ func (h *Handler) RestoreApps(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := app.InitiateRestore(snapshotName, a.ID); err != nil {
+		if err := store.GetStore().SetAppRestoreInProgress(a.ID, snapshotName); err != nil {
 			logger.Error(err)
 			restoreResponse.Error = fmt.Sprintf("failed to initiate restore for app %s", a.Slug)
 			JSON(w, http.StatusInternalServerError, restoreResponse)
@@ -339,7 +338,7 @@ func (h *Handler) CancelRestore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := app.ResetRestore(foundApp.ID); err != nil {
+	if err := store.GetStore().ResetAppRestore(foundApp.ID); err != nil {
 		err = errors.Wrap(err, "failed to reset app restore in progress name")
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -375,7 +374,7 @@ func (h *Handler) GetRestoreDetails(w http.ResponseWriter, r *http.Request) {
 		if foundApp.RestoreUndeployStatus == apptypes.UndeployFailed {
 			// HACK: once the user has see the error, clear it out.
 			// Otherwise there is no way to get back to snapshot list.
-			if err := app.ResetRestore(foundApp.ID); err != nil {
+			if err := store.GetStore().ResetAppRestore(foundApp.ID); err != nil {
 				err = errors.Wrap(err, "failed to reset app restore in progress name")
 				logger.Error(err)
 				w.WriteHeader(http.StatusInternalServerError)
@@ -29,6 +29,8 @@ import (
 )
 
 const (
+	storeMigrationTaskID = "snapshot-store-migration"
+
 	urlPattern = `\b(https?):\/\/[\-A-Za-z0-9+&@#\/%?=~_|!:,.;]*[\-A-Za-z0-9+&@#\/%=~_|]`
 )
 
@@ -49,6 +51,9 @@ type GlobalSnapshotSettingsResponse struct {
 	Store            *kotssnapshottypes.Store            `json:"store,omitempty"`
 	FileSystemConfig *kotssnapshottypes.FileSystemConfig `json:"fileSystemConfig,omitempty"`
 
+	StoreMigrationStatus  string `json:"storeMigrationStatus,omitempty"`
+	StoreMigrationMessage string `json:"storeMigrationMessage,omitempty"`
+
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
 }
@@ -64,6 +69,12 @@ type UpdateGlobalSnapshotSettingsRequest struct {
 	Other      *kotssnapshottypes.StoreOther  `json:"other"`
 	Internal   bool                           `json:"internal"`
 	FileSystem *FileSystemOptions             `json:"fileSystem"`
+
+	// MigrateBackups copies existing backups to the new store before switching to it. The request returns 202 and
+	// the migration runs in the background, reporting progress in storeMigrationStatus and storeMigrationMessage.
+	// Copied objects are verified by checksum. Objects already in the new store are compared by md5 when both stores
+	// report one, and by size otherwise.
+	MigrateBackups bool `json:"migrateBackups"`
 }
 
 type ConfigureFileSystemSnapshotProviderResponse struct {
@@ -82,9 +93,10 @@ type FileSystemOptions struct {
 }
 
 type SnapshotConfig struct {
-	AutoEnabled  bool                            `json:"autoEnabled"`
-	AutoSchedule *snapshottypes.SnapshotSchedule `json:"autoSchedule"`
-	TTl          *snapshottypes.SnapshotTTL      `json:"ttl"`
+	AutoEnabled     bool                            `json:"autoEnabled"`
+	AutoSchedule    *snapshottypes.SnapshotSchedule `json:"autoSchedule"`
+	TTl             *snapshottypes.SnapshotTTL      `json:"ttl"`
+	RetentionPolicy *snapshottypes.RetentionPolicy  `json:"retentionPolicy"`
 }
 
 type VeleroStatus struct {
@@ -191,7 +203,40 @@ func (h *Handler) UpdateGlobalSnapshotSettings(w http.ResponseWriter, r *http.Re
 
 		KotsadmNamespace: kotsadmNamespace,
 		RegistryOptions:  &registryOptions,
+
+		MigrateBackups: updateGlobalSnapshotSettingsRequest.MigrateBackups,
 	}
+	if options.MigrateBackups {
+		migrationStatus, _, err := store.GetStore().GetTaskStatus(storeMigrationTaskID)
+		if err != nil {
+			logger.Error(err)
+			globalSnapshotSettingsResponse.Error = "failed to get store migration status"
+			JSON(w, http.StatusInternalServerError, globalSnapshotSettingsResponse)
+			return
+		}
+		if migrationStatus == "running" {
+			globalSnapshotSettingsResponse.Error = "a store migration is already running"
+			JSON(w, http.StatusConflict, globalSnapshotSettingsResponse)
+			return
+		}
+
+		// this is to avoid a race condition where the UI polls the task status before it is set by the goroutine
+		if err := store.GetStore().SetTaskStatus(storeMigrationTaskID, "Migrating backups...", "running"); err != nil {
+			logger.Error(err)
+			globalSnapshotSettingsResponse.Error = "failed to set store migration status"
+			JSON(w, http.StatusInternalServerError, globalSnapshotSettingsResponse)
+			return
+		}
+
+		// copying backups can take much longer than a request, so the store is switched over in the background
+		// once the migration finishes. progress and failures are reported through the task status.
+		go configureStoreWithMigration(options)
+
+		globalSnapshotSettingsResponse.Success = true
+		JSON(w, http.StatusAccepted, globalSnapshotSettingsResponse)
+		return
+	}
+
 	updatedStore, err := kotssnapshot.ConfigureStore(r.Context(), options)
 	if err != nil {
 		if _, ok := errors.Cause(err).(*kotssnapshot.InvalidStoreDataError); ok {
@@ -223,6 +268,41 @@ func (h *Handler) UpdateGlobalSnapshotSettings(w http.ResponseWriter, r *http.Re
 	JSON(w, http.StatusOK, globalSnapshotSettingsResponse)
 }
 
+func configureStoreWithMigration(options kotssnapshot.ConfigureStoreOptions) {
+	finishedCh := make(chan struct{})
+	defer close(finishedCh)
+	go func() {
+		for {
+			select {
+			case <-time.After(time.Second):
+				if err := store.GetStore().UpdateTaskStatusTimestamp(storeMigrationTaskID); err != nil {
+					logger.Error(err)
+				}
+			case <-finishedCh:
+				return
+			}
+		}
+	}()
+
+	options.MigrationProgressFn = func(progress kotssnapshot.StoreMigrationProgress) {
+		if err := store.GetStore().SetTaskStatus(storeMigrationTaskID, progress.String(), "running"); err != nil {
+			logger.Error(errors.Wrap(err, "failed to set store migration task status"))
+		}
+	}
+
+	if _, err := kotssnapshot.ConfigureStore(context.Background(), options); err != nil {
+		logger.Error(errors.Wrap(err, "failed to configure store with migration"))
+		if err := store.GetStore().SetTaskStatus(storeMigrationTaskID, errors.Cause(err).Error(), "failed"); err != nil {
+			logger.Error(errors.Wrap(err, "failed to set store migration task status"))
+		}
+		return
+	}
+
+	if err := store.GetStore().ClearTaskStatus(storeMigrationTaskID); err != nil {
+		logger.Error(errors.Wrap(err, "failed to clear store migration task status"))
+	}
+}
+
 func (h *Handler) GetGlobalSnapshotSettings(w http.ResponseWriter, r *http.Request) {
 	globalSnapshotSettingsResponse := GlobalSnapshotSettingsResponse{
 		Success: false,
@@ -267,6 +347,16 @@ func (h *Handler) GetGlobalSnapshotSettings(w http.ResponseWriter, r *http.Reque
 	globalSnapshotSettingsResponse.IsKurl = kurl.IsKurl()
 	globalSnapshotSettingsResponse.IsMinimalRBACEnabled = !k8sutil.IsKotsadmClusterScoped(r.Context(), clientset, kotsadmNamespace)
 
+	migrationStatus, migrationMessage, err := store.GetStore().GetTaskStatus(storeMigrationTaskID)
+	if err != nil {
+		logger.Error(err)
+		globalSnapshotSettingsResponse.Error = "failed to get store migration status"
+		JSON(w, http.StatusInternalServerError, globalSnapshotSettingsResponse)
+		return
+	}
+	globalSnapshotSettingsResponse.StoreMigrationStatus = migrationStatus
+	globalSnapshotSettingsResponse.StoreMigrationMessage = migrationMessage
+
 	store, err := kotssnapshot.GetGlobalStore(r.Context(), kotsadmNamespace, nil)
 	if err != nil {
 		logger.Error(err)
@@ -423,6 +513,7 @@ func (h *Handler) GetSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 	getSnapshotConfigResponse.AutoEnabled = foundApp.SnapshotSchedule != ""
 	getSnapshotConfigResponse.AutoSchedule = snapshotSchedule
 	getSnapshotConfigResponse.TTl = ttl
+	getSnapshotConfigResponse.RetentionPolicy = foundApp.SnapshotRetention
 
 	JSON(w, http.StatusOK, getSnapshotConfigResponse)
 }
@@ -454,11 +545,16 @@ type SaveSnapshotConfigRequest struct {
 	InputTimeUnit string `json:"inputTimeUnit"`
 	Schedule      string `json:"schedule"`
 	AutoEnabled   bool   `json:"autoEnabled"`
+	// RetentionPolicy is left unchanged if nil, and cleared if all counts are zero
+	RetentionPolicy *snapshottypes.RetentionPolicy `json:"retentionPolicy"`
+	// DryRun lists the backups the retention policy would prune without saving the config
+	DryRun bool `json:"dryRun"`
 }
 
 type SaveSnapshotConfigResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Success    bool                         `json:"success"`
+	Error      string                       `json:"error,omitempty"`
+	WouldPrune []snapshottypes.PrunedBackup `json:"wouldPrune,omitempty"`
 }
 
 func (h *Handler) SaveSnapshotConfig(w http.ResponseWriter, r *http.Request) {
@@ -493,6 +589,46 @@ func (h *Handler) SaveSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := snapshot.ValidateRetentionPolicy(requestBody.RetentionPolicy); err != nil {
+		responseBody.Error = fmt.Sprintf("Invalid snapshot retention policy: %s", err.Error())
+		JSON(w, http.StatusBadRequest, responseBody)
+		return
+	}
+
+	retentionPolicy := app.SnapshotRetention
+	if requestBody.RetentionPolicy != nil {
+		retentionPolicy = requestBody.RetentionPolicy
+		if snapshot.IsRetentionPolicyEmpty(retentionPolicy) {
+			retentionPolicy = nil
+		}
+	}
+
+	if requestBody.DryRun {
+		responseBody.WouldPrune = []snapshottypes.PrunedBackup{}
+		if !snapshot.IsRetentionPolicyEmpty(retentionPolicy) {
+			wouldPrune, err := snapshot.PruneApplicationBackups(r.Context(), util.PodNamespace, app.ID, *retentionPolicy, true)
+			if err != nil {
+				logger.Error(err)
+				responseBody.Error = "Failed to list backups that would be pruned"
+				JSON(w, http.StatusInternalServerError, responseBody)
+				return
+			}
+			responseBody.WouldPrune = wouldPrune
+		}
+		responseBody.Success = true
+		JSON(w, http.StatusOK, responseBody)
+		return
+	}
+
+	if requestBody.RetentionPolicy != nil {
+		if err := store.GetStore().SetSnapshotRetentionPolicy(app.ID, retentionPolicy); err != nil {
+			logger.Error(err)
+			responseBody.Error = "Failed to set snapshot retention policy"
+			JSON(w, http.StatusInternalServerError, responseBody)
+			return
+		}
+	}
+
 	if app.SnapshotTTL != retention {
 		app.SnapshotTTL = retention
 		if err := store.GetStore().SetSnapshotTTL(app.ID, retention); err != nil {
@@ -557,9 +693,11 @@ func (h *Handler) SaveSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 }
 
 type InstanceSnapshotConfig struct {
-	AutoEnabled  bool                            `json:"autoEnabled"`
-	AutoSchedule *snapshottypes.SnapshotSchedule `json:"autoSchedule"`
-	TTl          *snapshottypes.SnapshotTTL      `json:"ttl"`
+	AutoEnabled        bool                              `json:"autoEnabled"`
+	AutoSchedule       *snapshottypes.SnapshotSchedule   `json:"autoSchedule"`
+	TTl                *snapshottypes.SnapshotTTL        `json:"ttl"`
+	RetentionPolicy    *snapshottypes.RetentionPolicy    `json:"retentionPolicy"`
+	VerificationPolicy *snapshottypes.VerificationPolicy `json:"verificationPolicy"`
 }
 
 func (h *Handler) GetInstanceSnapshotConfig(w http.ResponseWriter, r *http.Request) {
@@ -605,6 +743,8 @@ func (h *Handler) GetInstanceSnapshotConfig(w http.ResponseWriter, r *http.Reque
 	getInstanceSnapshotConfigResponse.AutoEnabled = c.SnapshotSchedule != ""
 	getInstanceSnapshotConfigResponse.AutoSchedule = snapshotSchedule
 	getInstanceSnapshotConfigResponse.TTl = ttl
+	getInstanceSnapshotConfigResponse.RetentionPolicy = c.SnapshotRetention
+	getInstanceSnapshotConfigResponse.VerificationPolicy = c.SnapshotVerification
 
 	JSON(w, http.StatusOK, getInstanceSnapshotConfigResponse)
 }
@@ -614,11 +754,18 @@ type SaveInstanceSnapshotConfigRequest struct {
 	InputTimeUnit string `json:"inputTimeUnit"`
 	Schedule      string `json:"schedule"`
 	AutoEnabled   bool   `json:"autoEnabled"`
+	// RetentionPolicy is left unchanged if nil, and cleared if all counts are zero
+	RetentionPolicy *snapshottypes.RetentionPolicy `json:"retentionPolicy"`
+	// VerificationPolicy is left unchanged if nil, and cleared if the schedule is empty
+	VerificationPolicy *snapshottypes.VerificationPolicy `json:"verificationPolicy"`
+	// DryRun lists the backups the retention policy would prune without saving the config
+	DryRun bool `json:"dryRun"`
 }
 
 type SaveInstanceSnapshotConfigResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Success    bool                         `json:"success"`
+	Error      string                       `json:"error,omitempty"`
+	WouldPrune []snapshottypes.PrunedBackup `json:"wouldPrune,omitempty"`
 }
 
 func (h *Handler) SaveInstanceSnapshotConfig(w http.ResponseWriter, r *http.Request) {
@@ -661,6 +808,67 @@ func (h *Handler) SaveInstanceSnapshotConfig(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if err := snapshot.ValidateRetentionPolicy(requestBody.RetentionPolicy); err != nil {
+		responseBody.Error = fmt.Sprintf("Invalid instance snapshot retention policy: %s", err.Error())
+		JSON(w, http.StatusBadRequest, responseBody)
+		return
+	}
+
+	if requestBody.VerificationPolicy != nil && requestBody.VerificationPolicy.Schedule != "" {
+		if _, err := cron.ParseStandard(requestBody.VerificationPolicy.Schedule); err != nil {
+			responseBody.Error = fmt.Sprintf("Invalid backup verification cron schedule expression: %s", requestBody.VerificationPolicy.Schedule)
+			JSON(w, http.StatusBadRequest, responseBody)
+			return
+		}
+	}
+
+	retentionPolicy := c.SnapshotRetention
+	if requestBody.RetentionPolicy != nil {
+		retentionPolicy = requestBody.RetentionPolicy
+		if snapshot.IsRetentionPolicyEmpty(retentionPolicy) {
+			retentionPolicy = nil
+		}
+	}
+
+	if requestBody.DryRun {
+		responseBody.WouldPrune = []snapshottypes.PrunedBackup{}
+		if !snapshot.IsRetentionPolicyEmpty(retentionPolicy) {
+			wouldPrune, err := snapshot.PruneInstanceBackups(r.Context(), util.PodNamespace, *retentionPolicy, true)
+			if err != nil {
+				logger.Error(err)
+				responseBody.Error = "Failed to list instance backups that would be pruned"
+				JSON(w, http.StatusInternalServerError, responseBody)
+				return
+			}
+			responseBody.WouldPrune = wouldPrune
+		}
+		responseBody.Success = true
+		JSON(w, http.StatusOK, responseBody)
+		return
+	}
+
+	if requestBody.RetentionPolicy != nil {
+		if err := store.GetStore().SetInstanceSnapshotRetentionPolicy(c.ClusterID, retentionPolicy); err != nil {
+			logger.Error(err)
+			responseBody.Error = "Failed to set instance snapshot retention policy"
+			JSON(w, http.StatusInternalServerError, responseBody)
+			return
+		}
+	}
+
+	if requestBody.VerificationPolicy != nil {
+		verificationPolicy := requestBody.VerificationPolicy
+		if verificationPolicy.Schedule == "" {
+			verificationPolicy = nil
+		}
+		if err := store.GetStore().SetInstanceSnapshotVerificationPolicy(c.ClusterID, verificationPolicy); err != nil {
+			logger.Error(err)
+			responseBody.Error = "Failed to set instance snapshot verification policy"
+			JSON(w, http.StatusInternalServerError, responseBody)
+			return
+		}
+	}
+
 	if c.SnapshotTTL != retention {
 		c.SnapshotTTL = retention
 		if err := store.GetStore().SetInstanceSnapshotTTL(c.ClusterID, retention); err != nil {
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/user"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+	"github.com/replicatedhq/kots/pkg/util"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ListLocalUsersResponse struct {
+	Success bool                   `json:"success"`
+	Error   string                 `json:"error,omitempty"`
+	Users   []*usertypes.LocalUser `json:"users"`
+}
+
+type CreateLocalUserRequest struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	RoleIDs  []string `json:"roleIds"`
+}
+
+type SetLocalUserRolesRequest struct {
+	RoleIDs []string `json:"roleIds"`
+}
+
+type ResetLocalUserPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+type LocalUserResponse struct {
+	Success bool                 `json:"success"`
+	Error   string               `json:"error,omitempty"`
+	User    *usertypes.LocalUser `json:"user,omitempty"`
+}
+
+type ChangeLocalUserPasswordRequest struct {
+	Username        string `json:"username"`
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+type ChangeLocalUserPasswordResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (h *Handler) ListLocalUsers(w http.ResponseWriter, r *http.Request) {
+	response := ListLocalUsersResponse{}
+
+	users, err := store.GetStore().ListLocalUsers()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list users"))
+		response.Error = "failed to list users"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	for _, u := range users {
+		u.PasswordBcrypt = nil
+	}
+
+	response.Success = true
+	response.Users = users
+
+	JSON(w, http.StatusOK, response)
+}
+
+// CreateLocalUser creates a user that must change the given password on first login
+func (h *Handler) CreateLocalUser(w http.ResponseWriter, r *http.Request) {
+	response := LocalUserResponse{}
+
+	request := CreateLocalUserRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := user.ValidateUsername(request.Username); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+	if err := user.ValidatePassword(request.Password); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		response.Error = "failed to get roles"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if err := user.ValidateRoleIDs(request.RoleIDs, roles); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	_, err = store.GetStore().GetLocalUser(request.Username)
+	if err == nil {
+		response.Error = "user already exists"
+		JSON(w, http.StatusConflict, response)
+		return
+	} else if !store.GetStore().IsNotFound(err) {
+		logger.Error(errors.Wrap(err, "failed to get user"))
+		response.Error = "failed to get user"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	passwordBcrypt, err := bcrypt.GenerateFromPassword([]byte(request.Password), 10)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to generate password hash"))
+		response.Error = "failed to generate password hash"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	createdUser, err := store.GetStore().CreateLocalUser(request.Username, passwordBcrypt, request.RoleIDs)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create user"))
+		response.Error = "failed to create user"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	createdUser.PasswordBcrypt = nil
+
+	response.Success = true
+	response.User = createdUser
+
+	JSON(w, http.StatusCreated, response)
+}
+
+func (h *Handler) DeleteLocalUser(w http.ResponseWriter, r *http.Request) {
+	response := LocalUserResponse{}
+
+	username := mux.Vars(r)["username"]
+	if _, err := store.GetStore().GetLocalUser(username); err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "user not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to get user"))
+		response.Error = "failed to get user"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := store.GetStore().DeleteLocalUser(username); err != nil {
+		logger.Error(errors.Wrap(err, "failed to delete user"))
+		response.Error = "failed to delete user"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := store.GetStore().DeleteUserSessions(username); err != nil {
+		logger.Error(errors.Wrap(err, "failed to delete user sessions"))
+		response.Error = "failed to delete user sessions"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+
+	JSON(w, http.StatusOK, response)
+}
+
+// SetLocalUserRoles replaces the roles of a user. Existing sessions of the user are deleted
+// so that the new roles apply the next time the user logs in.
+func (h *Handler) SetLocalUserRoles(w http.ResponseWriter, r *http.Request) {
+	response := LocalUserResponse{}
+
+	request := SetLocalUserRolesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	roles, err := identity.GetRoles(r.Context(), util.PodNamespace)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get roles"))
+		response.Error = "failed to get roles"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if err := user.ValidateRoleIDs(request.RoleIDs, roles); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+	if err := store.GetStore().SetLocalUserRoles(username, request.RoleIDs); err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "user not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to set user roles"))
+		response.Error = "failed to set user roles"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := store.GetStore().DeleteUserSessions(username); err != nil {
+		logger.Error(errors.Wrap(err, "failed to delete user sessions"))
+		response.Error = "failed to delete user sessions"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	updatedUser, err := store.GetStore().GetLocalUser(username)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get user"))
+		response.Error = "failed to get user"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	updatedUser.PasswordBcrypt = nil
+
+	response.Success = true
+	response.User = updatedUser
+
+	JSON(w, http.StatusOK, response)
+}
+
+// ResetLocalUserPassword sets a new temporary password and unlocks the user.
+// The user is logged out and must change the password on the next login.
+func (h *Handler) ResetLocalUserPassword(w http.ResponseWriter, r *http.Request) {
+	response := LocalUserResponse{}
+
+	request := ResetLocalUserPasswordRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := user.ValidatePassword(request.Password); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	passwordBcrypt, err := bcrypt.GenerateFromPassword([]byte(request.Password), 10)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to generate password hash"))
+		response.Error = "failed to generate password hash"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+	if err := store.GetStore().SetLocalUserPassword(username, passwordBcrypt, true); err != nil {
+		if store.GetStore().IsNotFound(err) {
+			response.Error = "user not found"
+			JSON(w, http.StatusNotFound, response)
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to set user password"))
+		response.Error = "failed to set user password"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := store.GetStore().DeleteUserSessions(username); err != nil {
+		logger.Error(errors.Wrap(err, "failed to delete user sessions"))
+		response.Error = "failed to delete user sessions"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+
+	JSON(w, http.StatusOK, response)
+}
+
+// ChangeLocalUserPassword is unauthenticated so that users who must change their password can do so
+// before logging in. The current password is required instead of a session.
+func (h *Handler) ChangeLocalUserPassword(w http.ResponseWriter, r *http.Request) {
+	response := ChangeLocalUserPasswordResponse{}
+
+	request := ChangeLocalUserPasswordRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(errors.Wrap(err, "failed to decode request body"))
+		response.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := user.ValidatePassword(request.NewPassword); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+	if request.NewPassword == request.CurrentPassword {
+		response.Error = "new password must be different from the current password"
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	err := user.ChangeLocalUserPassword(request.Username, request.CurrentPassword, request.NewPassword)
+	if err == user.ErrInvalidPassword {
+		response.Error = "Invalid username or password. Please try again."
+		JSON(w, http.StatusUnauthorized, response)
+		return
+	} else if err == user.ErrTooManyAttempts {
+		response.Error = "This account has been locked. Please ask an administrator to reset your password."
+		JSON(w, http.StatusUnauthorized, response)
+		return
+	} else if err != nil {
+		logger.Error(errors.Wrap(err, "failed to change password"))
+		response.Error = "failed to change password"
+		JSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response.Success = true
+
+	JSON(w, http.StatusOK, response)
+}
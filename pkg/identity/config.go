@@ -120,6 +120,8 @@ func SetConfig(ctx context.Context, namespace string, identityConfig kotsv1beta1
 		return errors.Wrap(err, "failed to ensure config map")
 	}
 
+	invalidateRolesCache()
+
 	return nil
 }
 
@@ -247,6 +249,10 @@ func ValidateConfig(ctx context.Context, namespace string, identityConfig kotsv1
 		return errors.New("identityServiceAddress required or ingressConfig.ingress must be enabled")
 	}
 
+	if err := validateRoles(ctx, identityConfig.Spec); err != nil {
+		return errors.Wrap(err, "invalid roles")
+	}
+
 	return nil
 }
 
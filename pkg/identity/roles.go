@@ -0,0 +1,115 @@
+package identity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
+)
+
+const (
+	rolesCacheTTL = 30 * time.Second
+)
+
+var (
+	rolesCacheMutex sync.Mutex
+	rolesCache      []rbactypes.Role
+	rolesCachedAt   time.Time
+)
+
+// GetRoles returns the built in roles followed by the custom roles defined in the identity config.
+// The result is cached for a short time because it is used to check access on every request.
+func GetRoles(ctx context.Context, namespace string) ([]rbactypes.Role, error) {
+	rolesCacheMutex.Lock()
+	defer rolesCacheMutex.Unlock()
+
+	if rolesCache != nil && time.Since(rolesCachedAt) < rolesCacheTTL {
+		return rolesCache, nil
+	}
+
+	identityConfig, err := GetConfig(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identity config")
+	}
+
+	rolesCache = append(rbac.DefaultRoles(), RolesFromConfig(identityConfig.Spec)...)
+	rolesCachedAt = time.Now()
+
+	return rolesCache, nil
+}
+
+func invalidateRolesCache() {
+	rolesCacheMutex.Lock()
+	defer rolesCacheMutex.Unlock()
+
+	rolesCache = nil
+}
+
+// RolesFromConfig returns the custom roles defined in the identity config spec
+func RolesFromConfig(identityConfigSpec kotsv1beta1.IdentityConfigSpec) []rbactypes.Role {
+	roles := []rbactypes.Role{}
+	for _, configRole := range identityConfigSpec.Roles {
+		role := rbactypes.Role{
+			ID:          configRole.ID,
+			Name:        configRole.Name,
+			Description: configRole.Description,
+			Allow:       []rbactypes.Policy{},
+			Deny:        []rbactypes.Policy{},
+		}
+		for _, p := range configRole.Allow {
+			role.Allow = append(role.Allow, policyFromConfig(p))
+		}
+		for _, p := range configRole.Deny {
+			role.Deny = append(role.Deny, policyFromConfig(p))
+		}
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+func policyFromConfig(p kotsv1beta1.IdentityConfigPolicy) rbactypes.Policy {
+	return rbactypes.Policy{
+		Name:        p.Name,
+		Description: p.Description,
+		Action:      p.Action,
+		Resource:    p.Resource,
+	}
+}
+
+// validateRoles checks that custom roles have unique ids that do not replace the built in roles,
+// that their policies are valid and that every group only references roles that exist
+func validateRoles(ctx context.Context, identityConfigSpec kotsv1beta1.IdentityConfigSpec) error {
+	roleIDs := map[string]bool{}
+	for _, role := range rbac.DefaultRoles() {
+		roleIDs[role.ID] = true
+	}
+
+	customRoles := RolesFromConfig(identityConfigSpec)
+	for _, role := range customRoles {
+		if role.ID == "" {
+			return errors.New("role id is required")
+		}
+		if roleIDs[role.ID] {
+			return errors.Errorf("role %s is already defined", role.ID)
+		}
+		roleIDs[role.ID] = true
+	}
+
+	if err := rbac.ValidateRoles(ctx, customRoles); err != nil {
+		return errors.Wrap(err, "invalid role")
+	}
+
+	for _, group := range identityConfigSpec.Groups {
+		for _, roleID := range group.RoleIDs {
+			if !roleIDs[roleID] {
+				return errors.Errorf("group %s references unknown role %s", group.ID, roleID)
+			}
+		}
+	}
+
+	return nil
+}
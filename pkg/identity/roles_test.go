@@ -0,0 +1,71 @@
+package identity
+
+import (
+	"context"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+func Test_validateRoles(t *testing.T) {
+	deployer := kotsv1beta1.IdentityConfigRole{
+		ID: "deployer",
+		Allow: []kotsv1beta1.IdentityConfigPolicy{
+			{Action: "read", Resource: "**"},
+			{Action: "write", Resource: "app.*.downstream."},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    kotsv1beta1.IdentityConfigSpec
+		wantErr bool
+	}{
+		{
+			name: "custom role mapped to a group",
+			spec: kotsv1beta1.IdentityConfigSpec{
+				Roles:  []kotsv1beta1.IdentityConfigRole{deployer},
+				Groups: []kotsv1beta1.IdentityConfigGroup{{ID: "ops", RoleIDs: []string{"deployer", "support"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "group references unknown role",
+			spec: kotsv1beta1.IdentityConfigSpec{
+				Groups: []kotsv1beta1.IdentityConfigGroup{{ID: "ops", RoleIDs: []string{"deployer"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom role replaces built in role",
+			spec: kotsv1beta1.IdentityConfigSpec{
+				Roles: []kotsv1beta1.IdentityConfigRole{{ID: "cluster-admin"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate custom role",
+			spec: kotsv1beta1.IdentityConfigSpec{
+				Roles: []kotsv1beta1.IdentityConfigRole{deployer, deployer},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid policy",
+			spec: kotsv1beta1.IdentityConfigSpec{
+				Roles: []kotsv1beta1.IdentityConfigRole{
+					{ID: "broken", Deny: []kotsv1beta1.IdentityConfigPolicy{{Action: "write", Resource: "app.[x"}}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRoles(context.Background(), tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
 	"github.com/replicatedhq/kots/pkg/logger"
 	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
@@ -69,6 +70,10 @@ func Start() error {
 					logger.Errorf("failed to cast obj to backup")
 				}
 
+				if err := snapshot.NotifyBackupFinished(context.TODO(), veleroClient, backup); err != nil {
+					logger.Error(errors.Wrapf(err, "failed to notify backup %s finished", backup.Name))
+				}
+
 				if backup.Status.Phase == velerov1.BackupPhaseFailed || backup.Status.Phase == velerov1.BackupPhasePartiallyFailed {
 					if backup.Annotations == nil {
 						backup.Annotations = map[string]string{}
@@ -404,6 +404,19 @@ func ensureKotsadm(deployOptions types.DeployOptions, clientset *kubernetes.Clie
 		}
 	}
 
+	if deployOptions.License == nil && isUnlicensedUpstream(deployOptions.UpstreamURI) {
+		// apps from a git or http upstream don't have a license, so the upstream and its
+		// credentials are written as a secret for kotsadm to install on startup instead
+		updated, err := ensureUpstreamSecret(&deployOptions, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to ensure upstream secret")
+		}
+
+		if updated {
+			restartKotsadmAPI = true
+		}
+	}
+
 	if deployOptions.ConfigValues != nil {
 		// if there's a configvalues file, store it as a secret (they may contain
 		// sensitive information) and kotsadm will find it on startup and apply
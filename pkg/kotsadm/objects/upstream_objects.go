@@ -0,0 +1,33 @@
+package kotsadm
+
+import (
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func UpstreamSecret(namespace string, upstreamURI string, gitAuth upstreamtypes.GitAuth, releasePublicKey string) *corev1.Secret {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kotsadm-default-upstream",
+			Namespace: namespace,
+			Labels: types.GetKotsadmLabels(map[string]string{
+				"kots.io/automation": "upstream",
+			}),
+		},
+		Data: map[string][]byte{
+			"upstreamURI":   []byte(upstreamURI),
+			"username":      []byte(gitAuth.Username),
+			"token":         []byte(gitAuth.Token),
+			"sshPrivateKey": []byte(gitAuth.SSHPrivateKey),
+			"publicKey":     []byte(releasePublicKey),
+		},
+	}
+
+	return secret
+}
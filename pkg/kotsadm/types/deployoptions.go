@@ -5,6 +5,7 @@ import (
 	"time"
 
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -47,6 +48,8 @@ type DeployOptions struct {
 	SimultaneousUploads       int
 	DisableImagePush          bool
 	UpstreamURI               string
+	GitAuth                   upstreamtypes.GitAuth
+	ReleasePublicKey          string
 
 	IdentityConfig kotsv1beta1.IdentityConfig
 	IngressConfig  kotsv1beta1.IngressConfig
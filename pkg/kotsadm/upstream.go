@@ -0,0 +1,55 @@
+package kotsadm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ensureUpstreamSecret(deployOptions *types.DeployOptions, clientset *kubernetes.Clientset) (bool, error) {
+	existingSecret, err := getUpstreamSecret(deployOptions.Namespace, clientset)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check for existing upstream secret")
+	}
+
+	if existingSecret != nil {
+		return false, nil
+	}
+
+	_, err = clientset.CoreV1().Secrets(deployOptions.Namespace).Create(context.TODO(), kotsadmobjects.UpstreamSecret(deployOptions.Namespace, deployOptions.UpstreamURI, deployOptions.GitAuth, deployOptions.ReleasePublicKey), metav1.CreateOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create upstream secret")
+	}
+
+	return true, nil
+}
+
+func getUpstreamSecret(namespace string, clientset *kubernetes.Clientset) (*corev1.Secret, error) {
+	upstreamSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), "kotsadm-default-upstream", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to get upstream secret from cluster")
+	}
+
+	return upstreamSecret, nil
+}
+
+// isUnlicensedUpstream returns true for the git and http upstreams, which are installed without a license.
+func isUnlicensedUpstream(upstreamURI string) bool {
+	for _, prefix := range []string{"git://", "http://", "https://"} {
+		if strings.HasPrefix(upstreamURI, prefix) {
+			return true
+		}
+	}
+	return false
+}
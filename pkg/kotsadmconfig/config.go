@@ -9,7 +9,6 @@ import (
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
-	"github.com/replicatedhq/kots/pkg/persistence"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
 	"github.com/replicatedhq/kots/pkg/template"
 	"github.com/replicatedhq/kots/pkg/util"
@@ -77,7 +76,7 @@ func NeedsConfiguration(kotsKinds *kotsutil.KotsKinds, registrySettings registry
 		ReadOnly:  registrySettings.IsReadOnly,
 	}
 
-	rendered, err := kotsconfig.TemplateConfig(logger.NewCLILogger(), configSpec, configValuesSpec, licenseSpec, identityConfigSpec, localRegistry, util.PodNamespace)
+	rendered, err := kotsconfig.TemplateConfig(logger.NewCLILogger(), configSpec, configValuesSpec, licenseSpec, identityConfigSpec, localRegistry, util.PodNamespace, kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to template config")
 	}
@@ -110,29 +109,6 @@ func NeedsConfiguration(kotsKinds *kotsutil.KotsKinds, registrySettings registry
 	return false, nil
 }
 
-// UpdateConfigValuesInDB it gets the config values from filesInDir and
-// updates the app version config values in the db for the given sequence and app id
-func UpdateConfigValuesInDB(filesInDir string, appID string, sequence int64) error {
-	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(filesInDir)
-	if err != nil {
-		return errors.Wrap(err, "failed to read kots kinds")
-	}
-
-	configValues, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal configvalues spec")
-	}
-
-	db := persistence.MustGetDBSession()
-	query := `update app_version set config_values = $1 where app_id = $2 and sequence = $3`
-	_, err = db.Exec(query, configValues, appID, sequence)
-	if err != nil {
-		return errors.Wrap(err, "failed to update config values in db")
-	}
-
-	return nil
-}
-
 func ReadConfigValuesFromInClusterSecret() (string, error) {
 	log := logger.NewCLILogger()
 
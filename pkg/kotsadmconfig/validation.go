@@ -0,0 +1,210 @@
+package kotsadmconfig
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateConfigGroups checks item values in groups against their validation rules,
+// sets the Error field of every invalid item and returns the names of the invalid items.
+// When config is not nil, rules are taken from the items with the same name in config
+// instead of from groups, so that a client cannot bypass validation by editing the
+// rules it sends back.
+func ValidateConfigGroups(groups []kotsv1beta1.ConfigGroup, config *kotsv1beta1.Config) []string {
+	var rules map[string]*kotsv1beta1.ConfigItemValidation
+	if config != nil {
+		rules = map[string]*kotsv1beta1.ConfigItemValidation{}
+		for _, group := range config.Spec.Groups {
+			for _, item := range group.Items {
+				rules[item.Name] = item.Validation
+			}
+		}
+	}
+
+	invalidItems := []string{}
+	for i, group := range groups {
+		if group.When == "false" {
+			continue
+		}
+		for j, item := range group.Items {
+			if rules != nil {
+				item.Validation = rules[item.Name]
+			}
+			if item.Hidden || item.When == "false" {
+				continue
+			}
+			if message := ValidateConfigItem(item); message != "" {
+				groups[i].Items[j].Error = message
+				invalidItems = append(invalidItems, item.Name)
+			}
+		}
+	}
+
+	return invalidItems
+}
+
+// ValidateConfigItem returns a message describing why the value of item does not
+// satisfy its validation rules, or an empty string if it does. Unset values are
+// considered valid.
+func ValidateConfigItem(item kotsv1beta1.ConfigItem) string {
+	if item.Validation == nil {
+		return ""
+	}
+
+	switch item.Type {
+	case "text", "textarea", "password", "file":
+	default:
+		return ""
+	}
+
+	values := []string{}
+	if item.Repeatable {
+		for _, groupValues := range item.ValuesByGroup {
+			for _, value := range groupValues {
+				values = append(values, value)
+			}
+		}
+	} else {
+		values = append(values, item.Value.String())
+	}
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if item.Type == "file" {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return "file contents could not be decoded"
+			}
+			value = string(decoded)
+		}
+		if message := validateValue(value, item.Validation); message != "" {
+			if item.Validation.Message != "" {
+				return item.Validation.Message
+			}
+			return message
+		}
+	}
+
+	return ""
+}
+
+func validateValue(value string, rules *kotsv1beta1.ConfigItemValidation) string {
+	if rules.MinLength != nil && len(value) < *rules.MinLength {
+		return fmt.Sprintf("must be at least %d characters long", *rules.MinLength)
+	}
+	if rules.MaxLength != nil && len(value) > *rules.MaxLength {
+		return fmt.Sprintf("must be at most %d characters long", *rules.MaxLength)
+	}
+
+	if rules.Regex != "" {
+		re, err := regexp.Compile(rules.Regex)
+		if err != nil {
+			return fmt.Sprintf("invalid validation regex %q", rules.Regex)
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("must match %s", rules.Regex)
+		}
+	}
+
+	if len(rules.Enum) > 0 {
+		found := false
+		for _, allowed := range rules.Enum {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("must be one of %s", strings.Join(rules.Enum, ", "))
+		}
+	}
+
+	if rules.Min != "" || rules.Max != "" {
+		if message := validateRange(value, rules.Min, rules.Max); message != "" {
+			return message
+		}
+	}
+
+	if rules.Type != "" {
+		if message := validateType(value, rules.Type); message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
+func validateRange(value string, min string, max string) string {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return "must be a number"
+	}
+
+	if min != "" {
+		minNumber, err := strconv.ParseFloat(min, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid validation min %q", min)
+		}
+		if number < minNumber {
+			return fmt.Sprintf("must be greater than or equal to %s", min)
+		}
+	}
+
+	if max != "" {
+		maxNumber, err := strconv.ParseFloat(max, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid validation max %q", max)
+		}
+		if number > maxNumber {
+			return fmt.Sprintf("must be less than or equal to %s", max)
+		}
+	}
+
+	return ""
+}
+
+func validateType(value string, validationType string) string {
+	switch validationType {
+	case "url":
+		u, err := url.ParseRequestURI(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL"
+		}
+	case "hostname":
+		if net.ParseIP(value) != nil {
+			return ""
+		}
+		if errs := validation.IsDNS1123Subdomain(strings.ToLower(value)); len(errs) > 0 {
+			return "must be a valid hostname"
+		}
+	case "email":
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return "must be a valid email address"
+		}
+	case "cidr":
+		if _, _, err := net.ParseCIDR(value); err != nil {
+			return "must be a valid CIDR"
+		}
+	case "pem":
+		if block, _ := pem.Decode([]byte(value)); block == nil {
+			return "must be PEM encoded"
+		}
+	default:
+		return fmt.Sprintf("unknown validation type %q", validationType)
+	}
+
+	return ""
+}
@@ -0,0 +1,263 @@
+package kotsadmconfig
+
+import (
+	"encoding/base64"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/kotskinds/multitype"
+	"github.com/stretchr/testify/require"
+)
+
+const testCertificate = `-----BEGIN CERTIFICATE-----
+MIIBszCCAVmgAwIBAgIUQ2s=
+-----END CERTIFICATE-----
+`
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func Test_ValidateConfigItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		itemType   string
+		value      string
+		validation *kotsv1beta1.ConfigItemValidation
+		want       string
+	}{
+		{
+			name:       "no validation",
+			itemType:   "text",
+			value:      "anything",
+			validation: nil,
+			want:       "",
+		},
+		{
+			name:       "empty value is not validated",
+			itemType:   "text",
+			value:      "",
+			validation: &kotsv1beta1.ConfigItemValidation{MinLength: intPtr(3)},
+			want:       "",
+		},
+		{
+			name:       "bool items are not validated",
+			itemType:   "bool",
+			value:      "1",
+			validation: &kotsv1beta1.ConfigItemValidation{MinLength: intPtr(3)},
+			want:       "",
+		},
+		{
+			name:       "regex match",
+			itemType:   "text",
+			value:      "abc-123",
+			validation: &kotsv1beta1.ConfigItemValidation{Regex: `^[a-z]+-[0-9]+$`},
+			want:       "",
+		},
+		{
+			name:       "regex mismatch",
+			itemType:   "text",
+			value:      "abc",
+			validation: &kotsv1beta1.ConfigItemValidation{Regex: `^[a-z]+-[0-9]+$`},
+			want:       "must match ^[a-z]+-[0-9]+$",
+		},
+		{
+			name:       "too short",
+			itemType:   "password",
+			value:      "abc",
+			validation: &kotsv1beta1.ConfigItemValidation{MinLength: intPtr(8)},
+			want:       "must be at least 8 characters long",
+		},
+		{
+			name:       "too long",
+			itemType:   "textarea",
+			value:      "abcdef",
+			validation: &kotsv1beta1.ConfigItemValidation{MaxLength: intPtr(5)},
+			want:       "must be at most 5 characters long",
+		},
+		{
+			name:       "port in range",
+			itemType:   "text",
+			value:      "8080",
+			validation: &kotsv1beta1.ConfigItemValidation{Min: "1", Max: "65535"},
+			want:       "",
+		},
+		{
+			name:       "port out of range",
+			itemType:   "text",
+			value:      "70000",
+			validation: &kotsv1beta1.ConfigItemValidation{Min: "1", Max: "65535"},
+			want:       "must be less than or equal to 65535",
+		},
+		{
+			name:       "not a number",
+			itemType:   "text",
+			value:      "http",
+			validation: &kotsv1beta1.ConfigItemValidation{Min: "1"},
+			want:       "must be a number",
+		},
+		{
+			name:       "enum",
+			itemType:   "text",
+			value:      "debug",
+			validation: &kotsv1beta1.ConfigItemValidation{Enum: []string{"info", "warn", "error"}},
+			want:       "must be one of info, warn, error",
+		},
+		{
+			name:       "url",
+			itemType:   "text",
+			value:      "https://example.com/path",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "url"},
+			want:       "",
+		},
+		{
+			name:       "invalid url",
+			itemType:   "text",
+			value:      "example.com",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "url"},
+			want:       "must be a valid URL",
+		},
+		{
+			name:       "hostname",
+			itemType:   "text",
+			value:      "DB.example.com",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "hostname"},
+			want:       "",
+		},
+		{
+			name:       "ip address as hostname",
+			itemType:   "text",
+			value:      "10.0.0.1",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "hostname"},
+			want:       "",
+		},
+		{
+			name:       "invalid hostname",
+			itemType:   "text",
+			value:      "db_host:5432",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "hostname"},
+			want:       "must be a valid hostname",
+		},
+		{
+			name:       "email",
+			itemType:   "text",
+			value:      "admin@example.com",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "email"},
+			want:       "",
+		},
+		{
+			name:       "invalid email",
+			itemType:   "text",
+			value:      "admin",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "email"},
+			want:       "must be a valid email address",
+		},
+		{
+			name:       "cidr",
+			itemType:   "text",
+			value:      "10.96.0.0/12",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "cidr"},
+			want:       "",
+		},
+		{
+			name:       "invalid cidr",
+			itemType:   "text",
+			value:      "10.96.0.0",
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "cidr"},
+			want:       "must be a valid CIDR",
+		},
+		{
+			name:       "pem file",
+			itemType:   "file",
+			value:      base64.StdEncoding.EncodeToString([]byte(testCertificate)),
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "pem"},
+			want:       "",
+		},
+		{
+			name:       "invalid pem file",
+			itemType:   "file",
+			value:      base64.StdEncoding.EncodeToString([]byte("not a certificate")),
+			validation: &kotsv1beta1.ConfigItemValidation{Type: "pem"},
+			want:       "must be PEM encoded",
+		},
+		{
+			name:       "custom message",
+			itemType:   "text",
+			value:      "abc",
+			validation: &kotsv1beta1.ConfigItemValidation{Regex: `^[0-9]+$`, Message: "Only digits are allowed"},
+			want:       "Only digits are allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := kotsv1beta1.ConfigItem{
+				Name:       "item",
+				Type:       test.itemType,
+				Value:      multitype.FromString(test.value),
+				Validation: test.validation,
+			}
+			require.Equal(t, test.want, ValidateConfigItem(item))
+		})
+	}
+}
+
+func Test_ValidateConfigGroups(t *testing.T) {
+	req := require.New(t)
+
+	config := &kotsv1beta1.Config{
+		Spec: kotsv1beta1.ConfigSpec{
+			Groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "db_host", Type: "text", Validation: &kotsv1beta1.ConfigItemValidation{Type: "hostname"}},
+						{Name: "db_port", Type: "text", Validation: &kotsv1beta1.ConfigItemValidation{Min: "1", Max: "65535"}},
+						{Name: "db_hidden", Type: "text", Validation: &kotsv1beta1.ConfigItemValidation{Type: "url"}},
+					},
+				},
+				{
+					Name: "workers",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "worker_host", Type: "text", Repeatable: true, Validation: &kotsv1beta1.ConfigItemValidation{Type: "hostname"}},
+					},
+				},
+			},
+		},
+	}
+
+	// the submitted groups have had their validation rules removed, rules from config must still apply
+	groups := []kotsv1beta1.ConfigGroup{
+		{
+			Name: "database",
+			Items: []kotsv1beta1.ConfigItem{
+				{Name: "db_host", Type: "text", Value: multitype.FromString("db.example.com")},
+				{Name: "db_port", Type: "text", Value: multitype.FromString("0")},
+				{Name: "db_hidden", Type: "text", Value: multitype.FromString("not a url"), Hidden: true},
+			},
+		},
+		{
+			Name: "workers",
+			Items: []kotsv1beta1.ConfigItem{
+				{
+					Name:       "worker_host",
+					Type:       "text",
+					Repeatable: true,
+					ValuesByGroup: kotsv1beta1.ValuesByGroup{
+						"workers": {
+							"worker_host-1": "worker-1.example.com",
+							"worker_host-2": "worker 2",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	invalidItems := ValidateConfigGroups(groups, config)
+	req.Equal([]string{"db_port", "worker_host"}, invalidItems)
+	req.Equal("", groups[0].Items[0].Error)
+	req.Equal("must be greater than or equal to 1", groups[0].Items[1].Error)
+	req.Equal("", groups[0].Items[2].Error)
+	req.Equal("must be a valid hostname", groups[1].Items[0].Error)
+}
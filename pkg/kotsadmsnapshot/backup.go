@@ -150,6 +150,15 @@ func CreateApplicationBackup(ctx context.Context, a *apptypes.App, isScheduled b
 		}
 	}
 
+	// scheduled backups are pruned by the retention policy, and must not expire before the policy would delete them
+	if isScheduled && !IsRetentionPolicyEmpty(a.SnapshotRetention) {
+		if ttl := RetentionTTL(*a.SnapshotRetention); veleroBackup.Spec.TTL.Duration < ttl {
+			veleroBackup.Spec.TTL = metav1.Duration{
+				Duration: ttl,
+			}
+		}
+	}
+
 	cfg, err := k8sutil.GetClusterConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get cluster config")
@@ -366,6 +375,15 @@ func CreateInstanceBackup(ctx context.Context, cluster *downstreamtypes.Downstre
 		}
 	}
 
+	// scheduled backups are pruned by the retention policy, and must not expire before the policy would delete them
+	if isScheduled && !IsRetentionPolicyEmpty(cluster.SnapshotRetention) {
+		if ttl := RetentionTTL(*cluster.SnapshotRetention); veleroBackup.Spec.TTL.Duration < ttl {
+			veleroBackup.Spec.TTL = metav1.Duration{
+				Duration: ttl,
+			}
+		}
+	}
+
 	cfg, err := k8sutil.GetClusterConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get cluster config")
@@ -450,6 +468,8 @@ func ListBackupsForApp(ctx context.Context, kotsadmNamespace string, appID strin
 			backup.SupportBundleID = supportBundleID
 		}
 
+		backup.Verification = getBackupVerification(&veleroBackup)
+
 		volumeCount, volumeCountOk := veleroBackup.Annotations["kots.io/snapshot-volume-count"]
 		if volumeCountOk {
 			i, err := strconv.Atoi(volumeCount)
@@ -589,6 +609,8 @@ func ListInstanceBackups(ctx context.Context, kotsadmNamespace string) ([]*types
 			backup.VolumeSizeHuman = units.HumanSize(float64(i))
 		}
 
+		backup.Verification = getBackupVerification(&veleroBackup)
+
 		appAnnotationStr, _ := veleroBackup.Annotations["kots.io/apps-sequences"]
 		if len(appAnnotationStr) > 0 {
 			var apps map[string]int64
@@ -799,10 +821,11 @@ func GetBackupDetail(ctx context.Context, kotsadmNamespace string, backupName st
 	}
 
 	result := &types.BackupDetail{
-		Name:       backup.Name,
-		Status:     string(backup.Status.Phase),
-		Namespaces: backup.Spec.IncludedNamespaces,
-		Volumes:    listBackupVolumes(backupVolumes.Items),
+		Name:         backup.Name,
+		Status:       string(backup.Status.Phase),
+		Namespaces:   backup.Spec.IncludedNamespaces,
+		Volumes:      listBackupVolumes(backupVolumes.Items),
+		Verification: getBackupVerification(backup),
 	}
 
 	totalBytesDone := int64(0)
@@ -0,0 +1,98 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// notifiedBackups tracks the backups that a snapshot.finished event has already been emitted for,
+// since the backup informer sees every modification of a backup. the kots.io/notified annotation
+// on the backup is what keeps the event from being emitted again after a restart.
+var notifiedBackups = map[string]bool{}
+var notifiedBackupsMtx sync.Mutex
+
+// NotifyBackupFinished emits a snapshot.finished event once the backup reaches a terminal phase.
+// Instance backups are not tied to an app and are emitted without an app id.
+// The backup is annotated before the event is emitted, and the annotation and resource version
+// of the backup that was passed in are updated to match.
+func NotifyBackupFinished(ctx context.Context, veleroClient veleroclientv1.VeleroV1Interface, backup *velerov1.Backup) error {
+	if !isBackupFinished(backup.Status.Phase) {
+		return nil
+	}
+	if _, ok := backup.Annotations[types.NotifiedAnnotation]; ok {
+		return nil
+	}
+
+	notifiedBackupsMtx.Lock()
+	defer notifiedBackupsMtx.Unlock()
+
+	key := string(backup.UID)
+	if key == "" {
+		key = backup.Name
+	}
+	if notifiedBackups[key] {
+		return nil
+	}
+
+	notifiedAt := time.Now().UTC().Format(time.RFC3339)
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				types.NotifiedAnnotation: notifiedAt,
+			},
+		},
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+
+	updated, err := veleroClient.Backups(backup.Namespace).Patch(ctx, backup.Name, k8stypes.MergePatchType, b, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to patch backup")
+	}
+	if backup.Annotations == nil {
+		backup.Annotations = map[string]string{}
+	}
+	backup.Annotations[types.NotifiedAnnotation] = notifiedAt
+	backup.ResourceVersion = updated.ResourceVersion
+
+	notifiedBackups[key] = true
+
+	data := map[string]interface{}{
+		"name":  backup.Name,
+		"phase": string(backup.Status.Phase),
+	}
+	if backup.Annotations["kots.io/instance"] == "true" {
+		data["instance"] = true
+	}
+	if backup.Status.Errors > 0 {
+		data["errors"] = backup.Status.Errors
+	}
+	if backup.Status.Warnings > 0 {
+		data["warnings"] = backup.Status.Warnings
+	}
+
+	notifications.Emit(notificationtypes.EventSnapshotFinished, backup.Annotations["kots.io/app-id"], data)
+
+	return nil
+}
+
+func isBackupFinished(phase velerov1.BackupPhase) bool {
+	switch phase {
+	case velerov1.BackupPhaseCompleted, velerov1.BackupPhaseFailed, velerov1.BackupPhasePartiallyFailed:
+		return true
+	}
+	return false
+}
@@ -103,6 +103,12 @@ func CreateApplicationRestore(ctx context.Context, kotsadmNamespace string, snap
 				"kots.io/app-slug": appSlug,
 			},
 		}
+
+		namespaceMapping, err := getRestoreNamespaceMapping(ctx, veleroClient, backup, kotsadmNamespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get restore namespace mapping")
+		}
+		restore.Spec.NamespaceMapping = namespaceMapping
 	}
 
 	_, err = veleroClient.Restores(veleroNamespace).Create(ctx, restore, metav1.CreateOptions{})
@@ -113,6 +119,57 @@ func CreateApplicationRestore(ctx context.Context, kotsadmNamespace string, snap
 	return nil
 }
 
+// GetRestoreNamespaceMapping returns the namespace mapping to use when restoring applications from an instance backup.
+// when the admin console was restored to a different cluster or namespace, applications follow the same mapping.
+func GetRestoreNamespaceMapping(ctx context.Context, kotsadmNamespace string, backup *velerov1.Backup) (map[string]string, error) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create clientset")
+	}
+
+	return getRestoreNamespaceMapping(ctx, veleroClient, backup, kotsadmNamespace)
+}
+
+func getRestoreNamespaceMapping(ctx context.Context, veleroClient veleroclientv1.VeleroV1Interface, backup *velerov1.Backup, kotsadmNamespace string) (map[string]string, error) {
+	if backup.Annotations["kots.io/instance"] != "true" {
+		return nil, nil
+	}
+
+	kotsadmRestore, err := veleroClient.Restores(backup.Namespace).Get(ctx, fmt.Sprintf("%s.kotsadm", backup.Name), metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to get kotsadm restore")
+		}
+		kotsadmRestore = nil
+	}
+
+	return restoreNamespaceMapping(backup, kotsadmRestore, kotsadmNamespace), nil
+}
+
+func restoreNamespaceMapping(backup *velerov1.Backup, kotsadmRestore *velerov1.Restore, kotsadmNamespace string) map[string]string {
+	mapping := map[string]string{}
+	if kotsadmRestore != nil {
+		for source, target := range kotsadmRestore.Spec.NamespaceMapping {
+			mapping[source] = target
+		}
+	}
+
+	sourceKotsadmNamespace := backup.Annotations["kots.io/kotsadm-deploy-namespace"]
+	if _, ok := mapping[sourceKotsadmNamespace]; !ok && sourceKotsadmNamespace != "" && sourceKotsadmNamespace != kotsadmNamespace {
+		mapping[sourceKotsadmNamespace] = kotsadmNamespace
+	}
+
+	if len(mapping) == 0 {
+		return nil
+	}
+	return mapping
+}
+
 func DeleteRestore(ctx context.Context, kotsadmNamespace string, snapshotName string) error {
 	bsl, err := kotssnapshot.FindBackupStoreLocation(ctx, kotsadmNamespace)
 	if err != nil {
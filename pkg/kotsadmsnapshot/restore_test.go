@@ -0,0 +1,58 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestoreNamespaceMapping(t *testing.T) {
+	backup := &velerov1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "instance-abcd",
+			Annotations: map[string]string{
+				"kots.io/instance":                 "true",
+				"kots.io/kotsadm-deploy-namespace": "default",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		kotsadmRestore   *velerov1.Restore
+		kotsadmNamespace string
+		want             map[string]string
+	}{
+		{
+			name:             "same namespace",
+			kotsadmNamespace: "default",
+			want:             nil,
+		},
+		{
+			name:             "kotsadm moved without a restore mapping",
+			kotsadmNamespace: "kots",
+			want:             map[string]string{"default": "kots"},
+		},
+		{
+			name: "mapping from the kotsadm restore",
+			kotsadmRestore: &velerov1.Restore{
+				Spec: velerov1.RestoreSpec{
+					NamespaceMapping: map[string]string{"default": "kots", "monitoring": "metrics"},
+				},
+			},
+			kotsadmNamespace: "kots",
+			want:             map[string]string{"default": "kots", "monitoring": "metrics"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := restoreNamespaceMapping(backup, test.kotsadmRestore, test.kotsadmNamespace)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("restoreNamespaceMapping() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
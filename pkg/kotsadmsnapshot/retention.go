@@ -0,0 +1,176 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+)
+
+const maxRetentionCount = 1000
+
+type retentionPeriod struct {
+	count    int
+	duration time.Duration
+	key      func(t time.Time) string
+}
+
+func retentionPeriods(policy types.RetentionPolicy) []retentionPeriod {
+	return []retentionPeriod{
+		{
+			count:    policy.Hourly,
+			duration: time.Hour,
+			key:      func(t time.Time) string { return t.Format("2006-01-02T15") },
+		},
+		{
+			count:    policy.Daily,
+			duration: 24 * time.Hour,
+			key:      func(t time.Time) string { return t.Format("2006-01-02") },
+		},
+		{
+			count:    policy.Weekly,
+			duration: 7 * 24 * time.Hour,
+			key: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", year, week)
+			},
+		},
+		{
+			count:    policy.Monthly,
+			duration: 31 * 24 * time.Hour,
+			key:      func(t time.Time) string { return t.Format("2006-01") },
+		},
+	}
+}
+
+// IsRetentionPolicyEmpty returns true if the policy keeps no periods, in which case only the ttl applies
+func IsRetentionPolicyEmpty(policy *types.RetentionPolicy) bool {
+	return policy == nil || (policy.Hourly == 0 && policy.Daily == 0 && policy.Weekly == 0 && policy.Monthly == 0)
+}
+
+func ValidateRetentionPolicy(policy *types.RetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	for _, period := range []struct {
+		name  string
+		count int
+	}{
+		{"hourly", policy.Hourly},
+		{"daily", policy.Daily},
+		{"weekly", policy.Weekly},
+		{"monthly", policy.Monthly},
+	} {
+		if period.count < 0 || period.count > maxRetentionCount {
+			return errors.Errorf("%s retention must be between 0 and %d", period.name, maxRetentionCount)
+		}
+	}
+
+	return nil
+}
+
+// RetentionTTL is how long velero must keep scheduled backups so that the oldest period of the policy can be filled.
+// The newest backup of a period can be up to one period old, so one extra period is added.
+func RetentionTTL(policy types.RetentionPolicy) time.Duration {
+	ttl := time.Duration(0)
+	for _, period := range retentionPeriods(policy) {
+		if period.count == 0 {
+			continue
+		}
+		if d := time.Duration(period.count+1) * period.duration; d > ttl {
+			ttl = d
+		}
+	}
+	return ttl
+}
+
+// SelectBackupsToPrune returns the completed scheduled backups that are not kept by the policy, oldest first.
+// Manual backups and backups that have not completed are never pruned, and the last successful backup is always kept.
+func SelectBackupsToPrune(backups []*types.Backup, policy types.RetentionPolicy) []*types.Backup {
+	candidates := []*types.Backup{}
+	for _, backup := range backups {
+		if backup.Trigger != "schedule" || backup.Status != "Completed" || backup.StartedAt == nil {
+			continue
+		}
+		candidates = append(candidates, backup)
+	}
+
+	// newest first, so the first backup seen in a period is the one that is kept
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].StartedAt.After(*candidates[j].StartedAt)
+	})
+
+	keep := map[string]bool{}
+	if len(candidates) > 0 {
+		keep[candidates[0].Name] = true
+	}
+
+	for _, period := range retentionPeriods(policy) {
+		seen := map[string]bool{}
+		for _, backup := range candidates {
+			if len(seen) >= period.count {
+				break
+			}
+			key := period.key(backup.StartedAt.UTC())
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keep[backup.Name] = true
+		}
+	}
+
+	pruned := []*types.Backup{}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if !keep[candidates[i].Name] {
+			pruned = append(pruned, candidates[i])
+		}
+	}
+
+	return pruned
+}
+
+// PruneApplicationBackups deletes the scheduled backups of the app that are not kept by the policy.
+// If dryRun is true, the backups are only listed.
+func PruneApplicationBackups(ctx context.Context, kotsadmNamespace string, appID string, policy types.RetentionPolicy, dryRun bool) ([]types.PrunedBackup, error) {
+	backups, err := ListBackupsForApp(ctx, kotsadmNamespace, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list backups")
+	}
+
+	return pruneBackups(ctx, kotsadmNamespace, backups, policy, dryRun)
+}
+
+// PruneInstanceBackups deletes the scheduled instance backups that are not kept by the policy.
+// If dryRun is true, the backups are only listed.
+func PruneInstanceBackups(ctx context.Context, kotsadmNamespace string, policy types.RetentionPolicy, dryRun bool) ([]types.PrunedBackup, error) {
+	backups, err := ListInstanceBackups(ctx, kotsadmNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list instance backups")
+	}
+
+	return pruneBackups(ctx, kotsadmNamespace, backups, policy, dryRun)
+}
+
+func pruneBackups(ctx context.Context, kotsadmNamespace string, backups []*types.Backup, policy types.RetentionPolicy, dryRun bool) ([]types.PrunedBackup, error) {
+	pruned := []types.PrunedBackup{}
+	for _, backup := range SelectBackupsToPrune(backups, policy) {
+		if !dryRun {
+			if err := DeleteBackup(ctx, kotsadmNamespace, backup.Name); err != nil {
+				return pruned, errors.Wrapf(err, "failed to delete backup %s", backup.Name)
+			}
+			logger.Infof("Deleted backup %s because it is not kept by the retention policy", backup.Name)
+		}
+		pruned = append(pruned, types.PrunedBackup{
+			Name:      backup.Name,
+			StartedAt: backup.StartedAt,
+		})
+	}
+
+	return pruned, nil
+}
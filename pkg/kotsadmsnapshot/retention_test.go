@@ -0,0 +1,101 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+)
+
+func TestSelectBackupsToPrune(t *testing.T) {
+	backup := func(name string, trigger string, status string, startedAt string) *types.Backup {
+		ts, _ := time.Parse(time.RFC3339, startedAt)
+		return &types.Backup{Name: name, Trigger: trigger, Status: status, StartedAt: &ts}
+	}
+	scheduled := func(name string, startedAt string) *types.Backup {
+		return backup(name, "schedule", "Completed", startedAt)
+	}
+
+	tests := []struct {
+		name    string
+		backups []*types.Backup
+		policy  types.RetentionPolicy
+		want    []string
+	}{
+		{
+			name: "hourly",
+			backups: []*types.Backup{
+				scheduled("h3", "2021-01-10T03:00:00Z"),
+				scheduled("h0", "2021-01-10T00:00:00Z"),
+				scheduled("h5", "2021-01-10T05:00:00Z"),
+				scheduled("h1", "2021-01-10T01:00:00Z"),
+				scheduled("h4", "2021-01-10T04:00:00Z"),
+				scheduled("h2", "2021-01-10T02:00:00Z"),
+			},
+			policy: types.RetentionPolicy{Hourly: 2},
+			want:   []string{"h0", "h1", "h2", "h3"},
+		},
+		{
+			name: "newest backup of each day is kept",
+			backups: []*types.Backup{
+				scheduled("d1-10", "2021-01-01T10:00:00Z"),
+				scheduled("d2-08", "2021-01-02T08:00:00Z"),
+				scheduled("d2-20", "2021-01-02T20:00:00Z"),
+				scheduled("d3-06", "2021-01-03T06:00:00Z"),
+				scheduled("d3-18", "2021-01-03T18:00:00Z"),
+			},
+			policy: types.RetentionPolicy{Daily: 2},
+			want:   []string{"d1-10", "d2-08", "d3-06"},
+		},
+		{
+			name: "periods are combined",
+			backups: []*types.Backup{
+				scheduled("jan-05", "2021-01-05T00:00:00Z"),
+				scheduled("jan-20", "2021-01-20T00:00:00Z"),
+				scheduled("feb-01", "2021-02-01T00:00:00Z"),
+				scheduled("feb-02", "2021-02-02T00:00:00Z"),
+				scheduled("feb-03", "2021-02-03T00:00:00Z"),
+			},
+			policy: types.RetentionPolicy{Daily: 2, Monthly: 2},
+			want:   []string{"jan-05", "feb-01"},
+		},
+		{
+			name: "manual and unfinished backups are never pruned and the last successful backup is kept",
+			backups: []*types.Backup{
+				scheduled("s1", "2021-01-01T00:00:00Z"),
+				scheduled("s2", "2021-01-02T00:00:00Z"),
+				backup("m1", "manual", "Completed", "2021-01-01T12:00:00Z"),
+				backup("f1", "schedule", "Failed", "2021-01-03T00:00:00Z"),
+				backup("p1", "schedule", "InProgress", "2021-01-04T00:00:00Z"),
+			},
+			policy: types.RetentionPolicy{},
+			want:   []string{"s1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pruned := SelectBackupsToPrune(test.backups, test.policy)
+
+			got := []string{}
+			for _, b := range pruned {
+				got = append(got, b.Name)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRetentionTTL(t *testing.T) {
+	ttl := RetentionTTL(types.RetentionPolicy{Daily: 7, Weekly: 4})
+	if ttl != 840*time.Hour {
+		t.Errorf("Expected %s, got %s", 840*time.Hour, ttl)
+	}
+
+	if err := ValidateRetentionPolicy(&types.RetentionPolicy{Daily: -1}); err == nil {
+		t.Error("Expected error")
+	}
+}
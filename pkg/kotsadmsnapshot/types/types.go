@@ -14,31 +14,33 @@ type App struct {
 }
 
 type Backup struct {
-	Name               string     `json:"name"`
-	Status             string     `json:"status"`
-	Trigger            string     `json:"trigger"`
-	AppID              string     `json:"appID"`    // TODO: remove with app backups
-	Sequence           int64      `json:"sequence"` // TODO: remove with app backups
-	StartedAt          *time.Time `json:"startedAt,omitempty"`
-	FinishedAt         *time.Time `json:"finishedAt,omitempty"`
-	ExpiresAt          *time.Time `json:"expiresAt,omitempty"`
-	VolumeCount        int        `json:"volumeCount"`
-	VolumeSuccessCount int        `json:"volumeSuccessCount"`
-	VolumeBytes        int64      `json:"volumeBytes"`
-	VolumeSizeHuman    string     `json:"volumeSizeHuman"`
-	SupportBundleID    string     `json:"supportBundleId,omitempty"`
-	IncludedApps       []App      `json:"includedApps,omitempty"`
+	Name               string              `json:"name"`
+	Status             string              `json:"status"`
+	Trigger            string              `json:"trigger"`
+	AppID              string              `json:"appID"`    // TODO: remove with app backups
+	Sequence           int64               `json:"sequence"` // TODO: remove with app backups
+	StartedAt          *time.Time          `json:"startedAt,omitempty"`
+	FinishedAt         *time.Time          `json:"finishedAt,omitempty"`
+	ExpiresAt          *time.Time          `json:"expiresAt,omitempty"`
+	VolumeCount        int                 `json:"volumeCount"`
+	VolumeSuccessCount int                 `json:"volumeSuccessCount"`
+	VolumeBytes        int64               `json:"volumeBytes"`
+	VolumeSizeHuman    string              `json:"volumeSizeHuman"`
+	SupportBundleID    string              `json:"supportBundleId,omitempty"`
+	IncludedApps       []App               `json:"includedApps,omitempty"`
+	Verification       *BackupVerification `json:"verification,omitempty"`
 }
 
 type BackupDetail struct {
-	Name            string           `json:"name"`
-	Status          string           `json:"status"`
-	VolumeSizeHuman string           `json:"volumeSizeHuman"`
-	Namespaces      []string         `json:"namespaces"`
-	Hooks           []*SnapshotHook  `json:"hooks"`
-	Volumes         []SnapshotVolume `json:"volumes"`
-	Errors          []SnapshotError  `json:"errors"`
-	Warnings        []SnapshotError  `json:"warnings"`
+	Name            string              `json:"name"`
+	Status          string              `json:"status"`
+	VolumeSizeHuman string              `json:"volumeSizeHuman"`
+	Namespaces      []string            `json:"namespaces"`
+	Hooks           []*SnapshotHook     `json:"hooks"`
+	Volumes         []SnapshotVolume    `json:"volumes"`
+	Errors          []SnapshotError     `json:"errors"`
+	Warnings        []SnapshotError     `json:"warnings"`
+	Verification    *BackupVerification `json:"verification,omitempty"`
 }
 
 type RestoreDetail struct {
@@ -112,6 +114,60 @@ type SnapshotTTL struct {
 	Converted     string `json:"converted"`
 }
 
+// RetentionPolicy prunes scheduled snapshots grandfather-father-son style.
+// The newest snapshot in each of the last N hours, days, weeks and months is kept,
+// along with the last successful snapshot. A zero count keeps no snapshots for that period.
+type RetentionPolicy struct {
+	Hourly  int `json:"hourly"`
+	Daily   int `json:"daily"`
+	Weekly  int `json:"weekly"`
+	Monthly int `json:"monthly"`
+}
+
+// PrunedBackup is a backup that is not kept by a retention policy
+type PrunedBackup struct {
+	Name      string     `json:"name"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+const (
+	VerificationStatusAnnotation         = "kots.io/verification-status"
+	VerificationMessageAnnotation        = "kots.io/verification-message"
+	VerificationPreflightStateAnnotation = "kots.io/verification-preflight-state"
+	VerificationTimeAnnotation           = "kots.io/verification-time"
+	VerificationIDAnnotation             = "kots.io/verification-id"
+)
+
+// NotifiedAnnotation is set on a backup once the snapshot.finished event has been emitted for it
+const NotifiedAnnotation = "kots.io/notified"
+
+type VerificationStatus string
+
+const (
+	VerificationRunning VerificationStatus = "Running"
+	VerificationPassed  VerificationStatus = "Passed"
+	VerificationFailed  VerificationStatus = "Failed"
+	// VerificationUnverified means the restore completed, but none of the status informers could be evaluated
+	VerificationUnverified VerificationStatus = "Unverified"
+)
+
+// BackupVerification is the result of test-restoring a backup into a scratch namespace.
+// It is recorded as annotations on the velero backup.
+type BackupVerification struct {
+	Status         VerificationStatus `json:"status"`
+	Message        string             `json:"message,omitempty"`
+	PreflightState string             `json:"preflightState,omitempty"`
+	VerifiedAt     *time.Time         `json:"verifiedAt,omitempty"`
+	// ID identifies the verification that recorded the result, so that a client can wait for the verification it started
+	ID string `json:"id,omitempty"`
+}
+
+// VerificationPolicy schedules verification of the newest completed backup
+type VerificationPolicy struct {
+	Schedule      string `json:"schedule"`
+	RunPreflights bool   `json:"runPreflights"`
+}
+
 type ParsedTTL struct {
 	Quantity int64  `json:"quantity"`
 	Unit     string `json:"unit"`
@@ -0,0 +1,738 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/appstatus"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/preflight"
+	"github.com/replicatedhq/kots/pkg/render"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
+	velerolabel "github.com/vmware-tanzu/velero/pkg/label"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultVerificationTimeout = 30 * time.Minute
+	verificationPollInterval   = 5 * time.Second
+)
+
+var ErrVerificationInProgress = errors.New("a backup verification is already in progress")
+
+var (
+	verificationMtx     sync.Mutex
+	verificationRunning bool
+)
+
+type VerifyBackupOptions struct {
+	RunPreflights bool
+	// Timeout bounds how long to wait for the restore and then for the status informers
+	Timeout time.Duration
+	// ID is recorded with the result of the verification. A new id is generated if it is empty.
+	ID string
+}
+
+type verifiedApp struct {
+	app      *apptypes.App
+	sequence int64
+}
+
+type statusInformer struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// IsVerifyingBackup returns true if a backup verification is running
+func IsVerifyingBackup() bool {
+	verificationMtx.Lock()
+	defer verificationMtx.Unlock()
+	return verificationRunning
+}
+
+// VerifyBackup test-restores a completed backup into scratch namespaces, waits for the status informers of the
+// apps in the backup to report ready and optionally runs their preflights against the scratch namespaces.
+// The result is recorded on the backup, and the scratch namespaces are deleted before returning.
+// Only one verification runs at a time.
+func VerifyBackup(ctx context.Context, kotsadmNamespace string, backupName string, opts VerifyBackupOptions) (*types.BackupVerification, error) {
+	verificationMtx.Lock()
+	if verificationRunning {
+		verificationMtx.Unlock()
+		return nil, ErrVerificationInProgress
+	}
+	verificationRunning = true
+	verificationMtx.Unlock()
+
+	defer func() {
+		verificationMtx.Lock()
+		verificationRunning = false
+		verificationMtx.Unlock()
+	}()
+
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultVerificationTimeout
+	}
+	if opts.ID == "" {
+		opts.ID = ksuid.New().String()
+	}
+
+	veleroBackup, err := GetBackup(ctx, kotsadmNamespace, backupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup")
+	}
+
+	if veleroBackup.Status.Phase != velerov1.BackupPhaseCompleted {
+		return nil, errors.Errorf("backup %s is %s, only completed backups can be verified", backupName, veleroBackup.Status.Phase)
+	}
+
+	apps, err := getBackupApps(veleroBackup)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get apps in backup")
+	}
+	if len(apps) == 0 {
+		return nil, errors.Errorf("backup %s does not include any installed apps", backupName)
+	}
+
+	if err := recordBackupVerification(ctx, veleroBackup, &types.BackupVerification{Status: types.VerificationRunning, ID: opts.ID}); err != nil {
+		return nil, errors.Wrap(err, "failed to record verification start")
+	}
+
+	logger.Infof("Verifying backup %s", backupName)
+
+	verification := verifyBackup(ctx, kotsadmNamespace, veleroBackup, apps, opts)
+	now := time.Now().UTC()
+	verification.VerifiedAt = &now
+	verification.ID = opts.ID
+
+	if err := recordBackupVerification(ctx, veleroBackup, verification); err != nil {
+		return verification, errors.Wrap(err, "failed to record verification result")
+	}
+
+	logger.Infof("Verification of backup %s %s: %s", backupName, strings.ToLower(string(verification.Status)), verification.Message)
+
+	return verification, nil
+}
+
+// verifyBackup reports problems with the backup as a failed verification rather than as an error
+func verifyBackup(ctx context.Context, kotsadmNamespace string, veleroBackup *velerov1.Backup, apps []verifiedApp, opts VerifyBackupOptions) *types.BackupVerification {
+	failed := func(format string, args ...interface{}) *types.BackupVerification {
+		return &types.BackupVerification{
+			Status:  types.VerificationFailed,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return failed("failed to get cluster config: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return failed("failed to create clientset: %v", err)
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return failed("failed to create velero clientset: %v", err)
+	}
+
+	suffix := rand.String(5)
+	namespaceMapping := scratchNamespaceMapping(veleroBackup.Spec.IncludedNamespaces, suffix)
+
+	// cleanup uses a new context so that the scratch namespaces are removed even if ctx is cancelled
+	defer func() {
+		for _, scratchNamespace := range namespaceMapping {
+			err := clientset.CoreV1().Namespaces().Delete(context.Background(), scratchNamespace, metav1.DeleteOptions{})
+			if err != nil && !kuberneteserrors.IsNotFound(err) {
+				logger.Error(errors.Wrapf(err, "failed to delete scratch namespace %s", scratchNamespace))
+			}
+		}
+	}()
+
+	for _, scratchNamespace := range namespaceMapping {
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: scratchNamespace,
+				Labels: map[string]string{
+					"kots.io/backup-verification": velerolabel.GetValidName(veleroBackup.Name),
+				},
+			},
+		}
+		if _, err := clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+			return failed("failed to create scratch namespace %s: %v", scratchNamespace, err)
+		}
+	}
+
+	restore := buildVerificationRestore(veleroBackup, apps, namespaceMapping, suffix)
+	if _, err := veleroClient.Restores(veleroBackup.Namespace).Create(ctx, restore, metav1.CreateOptions{}); err != nil {
+		return failed("failed to create restore: %v", err)
+	}
+	defer func() {
+		err := veleroClient.Restores(veleroBackup.Namespace).Delete(context.Background(), restore.Name, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			logger.Error(errors.Wrapf(err, "failed to delete restore %s", restore.Name))
+		}
+	}()
+
+	deadline := time.Now().Add(opts.Timeout)
+
+	restorePhase, err := waitForRestore(ctx, veleroClient, veleroBackup.Namespace, restore.Name, deadline)
+	if err != nil {
+		return failed("failed to wait for restore: %v", err)
+	}
+	if restorePhase != velerov1.RestorePhaseCompleted {
+		return failed("restore %s finished with phase %s", restore.Name, restorePhase)
+	}
+
+	informers := []statusInformer{}
+	archiveDirs := map[string]string{}
+	defer func() {
+		for _, archiveDir := range archiveDirs {
+			os.RemoveAll(archiveDir)
+		}
+	}()
+
+	for _, a := range apps {
+		archiveDir, err := ioutil.TempDir("", "kotsadm")
+		if err != nil {
+			return failed("failed to create temp dir: %v", err)
+		}
+		archiveDirs[a.app.ID] = archiveDir
+
+		appInformers, err := getAppStatusInformers(a, archiveDir, kotsadmNamespace)
+		if err != nil {
+			return failed("failed to get status informers for app %s: %v", a.app.Slug, err)
+		}
+		for _, informer := range appInformers {
+			scratchNamespace, ok := namespaceMapping[informer.Namespace]
+			if !ok {
+				return failed("status informer %s/%s of app %s is in namespace %s, which is not included in the backup", informer.Kind, informer.Name, a.app.Slug, informer.Namespace)
+			}
+			informer.Namespace = scratchNamespace
+			informers = append(informers, informer)
+		}
+	}
+
+	resourceStates, err := waitForStatusInformers(ctx, clientset, informers, deadline)
+	if err != nil {
+		return failed("failed to wait for status informers: %v", err)
+	}
+	verification := checkResourceStates(len(namespaceMapping), len(informers), resourceStates)
+	if verification.Status != types.VerificationPassed {
+		return verification
+	}
+
+	if opts.RunPreflights {
+		preflightState := "pass"
+		for _, a := range apps {
+			// preflights render the app namespace, which is mapped to a scratch namespace
+			appNamespace := namespaceMapping[getAppNamespace(kotsadmNamespace)]
+			state, err := preflight.RunInNamespace(a.app.ID, a.app.Slug, a.sequence, a.app.IsAirgap, archiveDirs[a.app.ID], appNamespace)
+			if err != nil {
+				return failed("failed to run preflights for app %s: %v", a.app.Slug, err)
+			}
+			if state == "fail" || (state == "warn" && preflightState == "pass") {
+				preflightState = state
+			}
+		}
+		verification.PreflightState = preflightState
+		if preflightState == "fail" {
+			verification.Status = types.VerificationFailed
+			verification.Message = "preflight checks failed against the restored app"
+		}
+	}
+
+	return verification
+}
+
+func getBackupApps(veleroBackup *velerov1.Backup) ([]verifiedApp, error) {
+	apps := []verifiedApp{}
+
+	if veleroBackup.Annotations["kots.io/instance"] == "true" {
+		appsSequences := map[string]int64{}
+		if s := veleroBackup.Annotations["kots.io/apps-sequences"]; s != "" {
+			if err := json.Unmarshal([]byte(s), &appsSequences); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal apps sequences")
+			}
+		}
+
+		for slug, sequence := range appsSequences {
+			a, err := store.GetStore().GetAppFromSlug(slug)
+			if err != nil {
+				if store.GetStore().IsNotFound(err) {
+					// app might not exist in current installation
+					continue
+				}
+				return nil, errors.Wrap(err, "failed to get app from slug")
+			}
+			apps = append(apps, verifiedApp{app: a, sequence: sequence})
+		}
+
+		sort.Slice(apps, func(i, j int) bool {
+			return apps[i].app.Slug < apps[j].app.Slug
+		})
+
+		return apps, nil
+	}
+
+	appID := veleroBackup.Annotations["kots.io/app-id"]
+	if appID == "" {
+		return apps, nil
+	}
+
+	sequence, err := strconv.ParseInt(veleroBackup.Annotations["kots.io/app-sequence"], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse app sequence")
+	}
+
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			return apps, nil
+		}
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	return append(apps, verifiedApp{app: a, sequence: sequence}), nil
+}
+
+// scratchNamespaceMapping maps each namespace in the backup to a new namespace that does not exist yet
+func scratchNamespaceMapping(includedNamespaces []string, suffix string) map[string]string {
+	mapping := map[string]string{}
+	for _, namespace := range includedNamespaces {
+		if namespace == "*" || namespace == "" {
+			continue
+		}
+		if _, ok := mapping[namespace]; ok {
+			continue
+		}
+		scratchNamespace := fmt.Sprintf("%s-verify-%s", namespace, suffix)
+		if len(scratchNamespace) > 63 {
+			scratchNamespace = fmt.Sprintf("kots-verify-%s-%d", suffix, len(mapping))
+		}
+		mapping[namespace] = scratchNamespace
+	}
+	return mapping
+}
+
+func buildVerificationRestore(veleroBackup *velerov1.Backup, apps []verifiedApp, namespaceMapping map[string]string, suffix string) *velerov1.Restore {
+	trueVal := true
+	falseVal := false
+
+	restore := &velerov1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: veleroBackup.Namespace,
+			Name:      fmt.Sprintf("%s.verify-%s", veleroBackup.Name, suffix),
+			Annotations: map[string]string{
+				"kots.io/backup-verification": "true",
+			},
+		},
+		Spec: velerov1.RestoreSpec{
+			BackupName:       veleroBackup.Name,
+			RestorePVs:       &trueVal,
+			NamespaceMapping: namespaceMapping,
+			// cluster scoped resources would be shared with the running app
+			IncludeClusterResources: &falseVal,
+		},
+	}
+
+	if veleroBackup.Annotations["kots.io/instance"] == "true" {
+		// only restore app-specific objects, never the admin console itself
+		slugs := []string{}
+		for _, a := range apps {
+			slugs = append(slugs, a.app.Slug)
+		}
+		restore.Spec.LabelSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "kots.io/app-slug",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   slugs,
+				},
+			},
+		}
+	}
+
+	return restore
+}
+
+func waitForRestore(ctx context.Context, veleroClient veleroclientv1.VeleroV1Interface, veleroNamespace string, restoreName string, deadline time.Time) (velerov1.RestorePhase, error) {
+	for {
+		restore, err := veleroClient.Restores(veleroNamespace).Get(ctx, restoreName, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get restore")
+		}
+
+		switch restore.Status.Phase {
+		case velerov1.RestorePhaseCompleted, velerov1.RestorePhasePartiallyFailed, velerov1.RestorePhaseFailed, velerov1.RestorePhaseFailedValidation:
+			return restore.Status.Phase, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", errors.Errorf("timed out with restore in phase %q", restore.Status.Phase)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(verificationPollInterval):
+		}
+	}
+}
+
+func getAppNamespace(kotsadmNamespace string) string {
+	if os.Getenv("KOTSADM_TARGET_NAMESPACE") != "" {
+		return os.Getenv("KOTSADM_TARGET_NAMESPACE")
+	}
+	return kotsadmNamespace
+}
+
+// getAppStatusInformers renders the status informers of the app version in the backup
+func getAppStatusInformers(a verifiedApp, archiveDir string, kotsadmNamespace string) ([]statusInformer, error) {
+	if err := store.GetStore().GetAppVersionArchive(a.app.ID, a.sequence, archiveDir); err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kotskinds")
+	}
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.app.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings for app")
+	}
+
+	builder, err := render.NewBuilder(kotsKinds, registrySettings, a.app.Slug, a.sequence, a.app.IsAirgap, kotsadmNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get template builder")
+	}
+
+	informers := []statusInformer{}
+	for _, informer := range kotsKinds.KotsApplication.Spec.StatusInformers {
+		renderedInformer, err := builder.String(informer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render status informer %s", informer)
+		}
+		if renderedInformer == "" {
+			continue
+		}
+		parsed, err := parseStatusInformer(renderedInformer, getAppNamespace(kotsadmNamespace))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse status informer")
+		}
+		informers = append(informers, parsed)
+	}
+
+	return informers, nil
+}
+
+// parseStatusInformer parses an informer in the [namespace/]kind/name format
+func parseStatusInformer(informer string, defaultNamespace string) (statusInformer, error) {
+	parts := strings.Split(informer, "/")
+	switch len(parts) {
+	case 2:
+		return statusInformer{
+			Kind:      statusInformerKind(parts[0]),
+			Name:      parts[1],
+			Namespace: defaultNamespace,
+		}, nil
+	case 3:
+		return statusInformer{
+			Kind:      statusInformerKind(parts[1]),
+			Name:      parts[2],
+			Namespace: parts[0],
+		}, nil
+	}
+	return statusInformer{}, errors.Errorf("status informer %q is not in the [namespace/]kind/name format", informer)
+}
+
+func statusInformerKind(kind string) string {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		return "deployment"
+	case "statefulset", "statefulsets", "sts":
+		return "statefulset"
+	case "daemonset", "daemonsets", "ds":
+		return "daemonset"
+	case "service", "services", "svc":
+		return "service"
+	case "persistentvolumeclaim", "persistentvolumeclaims", "pvc":
+		return "persistentvolumeclaim"
+	}
+	return strings.ToLower(kind)
+}
+
+// waitForStatusInformers polls the informers until they are all ready or the deadline passes.
+// Kinds that can't be evaluated outside of the operator are skipped.
+func waitForStatusInformers(ctx context.Context, clientset kubernetes.Interface, informers []statusInformer, deadline time.Time) ([]appstatustypes.ResourceState, error) {
+	for {
+		resourceStates := []appstatustypes.ResourceState{}
+		for _, informer := range informers {
+			state, ok, err := getStatusInformerState(ctx, clientset, informer)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get state of %s/%s", informer.Kind, informer.Name)
+			}
+			if !ok {
+				continue
+			}
+			resourceStates = append(resourceStates, appstatustypes.ResourceState{
+				Kind:      informer.Kind,
+				Name:      informer.Name,
+				Namespace: informer.Namespace,
+				State:     state,
+			})
+		}
+
+		if len(resourceStates) == 0 || appstatus.GetState(resourceStates) == appstatustypes.StateReady {
+			return resourceStates, nil
+		}
+
+		if time.Now().After(deadline) {
+			return resourceStates, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(verificationPollInterval):
+		}
+	}
+}
+
+func getStatusInformerState(ctx context.Context, clientset kubernetes.Interface, informer statusInformer) (appstatustypes.State, bool, error) {
+	var err error
+	state := appstatustypes.StateMissing
+
+	switch informer.Kind {
+	case "deployment":
+		var r *appsv1.Deployment
+		if r, err = clientset.AppsV1().Deployments(informer.Namespace).Get(ctx, informer.Name, metav1.GetOptions{}); err == nil {
+			state = calculateReplicasState(r.Spec.Replicas, r.Status.ReadyReplicas)
+		}
+	case "statefulset":
+		var r *appsv1.StatefulSet
+		if r, err = clientset.AppsV1().StatefulSets(informer.Namespace).Get(ctx, informer.Name, metav1.GetOptions{}); err == nil {
+			state = calculateReplicasState(r.Spec.Replicas, r.Status.ReadyReplicas)
+		}
+	case "daemonset":
+		var r *appsv1.DaemonSet
+		if r, err = clientset.AppsV1().DaemonSets(informer.Namespace).Get(ctx, informer.Name, metav1.GetOptions{}); err == nil {
+			desired := r.Status.DesiredNumberScheduled
+			state = calculateReplicasState(&desired, r.Status.NumberReady)
+		}
+	case "persistentvolumeclaim":
+		var r *corev1.PersistentVolumeClaim
+		if r, err = clientset.CoreV1().PersistentVolumeClaims(informer.Namespace).Get(ctx, informer.Name, metav1.GetOptions{}); err == nil {
+			state = appstatustypes.StateUnavailable
+			if r.Status.Phase == corev1.ClaimBound {
+				state = appstatustypes.StateReady
+			}
+		}
+	case "service":
+		var r *corev1.Service
+		if r, err = clientset.CoreV1().Services(informer.Namespace).Get(ctx, informer.Name, metav1.GetOptions{}); err == nil {
+			state, err = calculateServiceState(ctx, clientset, r)
+		}
+	default:
+		logger.Debugf("Skipping status informer %s/%s during backup verification, kind is not supported", informer.Kind, informer.Name)
+		return "", false, nil
+	}
+
+	if kuberneteserrors.IsNotFound(err) {
+		return appstatustypes.StateMissing, true, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return state, true, nil
+}
+
+// calculateReplicasState mirrors how the operator's status informers evaluate workloads
+func calculateReplicasState(desiredReplicas *int32, readyReplicas int32) appstatustypes.State {
+	desired := int32(1)
+	if desiredReplicas != nil {
+		desired = *desiredReplicas
+	}
+	if readyReplicas >= desired {
+		return appstatustypes.StateReady
+	}
+	if readyReplicas > 0 {
+		return appstatustypes.StateDegraded
+	}
+	return appstatustypes.StateUnavailable
+}
+
+// calculateServiceState only considers endpoints, external ips are not expected for scratch namespaces
+func calculateServiceState(ctx context.Context, clientset kubernetes.Interface, r *corev1.Service) (appstatustypes.State, error) {
+	if r.Spec.Type == corev1.ServiceTypeExternalName || len(r.Spec.Selector) == 0 {
+		return appstatustypes.StateReady, nil
+	}
+
+	endpoints, err := clientset.CoreV1().Endpoints(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return appstatustypes.StateUnavailable, nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get endpoints")
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return appstatustypes.StateReady, nil
+		}
+	}
+	return appstatustypes.StateUnavailable, nil
+}
+
+// checkResourceStates passes the verification if all evaluated status informers are ready.
+// The backup is unverified if there was nothing to evaluate, since a restore that completed is not proof that the app works.
+func checkResourceStates(namespaceCount int, informerCount int, resourceStates []appstatustypes.ResourceState) *types.BackupVerification {
+	if len(resourceStates) == 0 {
+		message := "restore completed, but the apps do not have any status informers to evaluate"
+		if informerCount > 0 {
+			message = fmt.Sprintf("restore completed, but none of the %d status informers could be evaluated", informerCount)
+		}
+		return &types.BackupVerification{
+			Status:  types.VerificationUnverified,
+			Message: message,
+		}
+	}
+
+	if state := appstatus.GetState(resourceStates); state != appstatustypes.StateReady {
+		return &types.BackupVerification{
+			Status:  types.VerificationFailed,
+			Message: fmt.Sprintf("app is %s after restore: %s", state, describeNotReady(resourceStates)),
+		}
+	}
+
+	return &types.BackupVerification{
+		Status:  types.VerificationPassed,
+		Message: fmt.Sprintf("restored into %d scratch namespaces and %d status informers reported ready", namespaceCount, len(resourceStates)),
+	}
+}
+
+func describeNotReady(resourceStates []appstatustypes.ResourceState) string {
+	notReady := []string{}
+	for _, resourceState := range resourceStates {
+		if resourceState.State == appstatustypes.StateReady {
+			continue
+		}
+		notReady = append(notReady, fmt.Sprintf("%s/%s is %s", resourceState.Kind, resourceState.Name, resourceState.State))
+	}
+	return strings.Join(notReady, ", ")
+}
+
+func recordBackupVerification(ctx context.Context, veleroBackup *velerov1.Backup, verification *types.BackupVerification) error {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster config")
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create clientset")
+	}
+
+	annotations := map[string]string{
+		types.VerificationStatusAnnotation:         string(verification.Status),
+		types.VerificationMessageAnnotation:        verification.Message,
+		types.VerificationPreflightStateAnnotation: verification.PreflightState,
+		types.VerificationTimeAnnotation:           "",
+		types.VerificationIDAnnotation:             verification.ID,
+	}
+	if verification.VerifiedAt != nil {
+		annotations[types.VerificationTimeAnnotation] = verification.VerifiedAt.Format(time.RFC3339)
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+
+	_, err = veleroClient.Backups(veleroBackup.Namespace).Patch(ctx, veleroBackup.Name, k8stypes.MergePatchType, b, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to patch backup")
+	}
+
+	return nil
+}
+
+// getBackupVerification reads the verification recorded on the backup, if any
+func getBackupVerification(veleroBackup *velerov1.Backup) *types.BackupVerification {
+	status := veleroBackup.Annotations[types.VerificationStatusAnnotation]
+	if status == "" {
+		return nil
+	}
+
+	verification := &types.BackupVerification{
+		Status:         types.VerificationStatus(status),
+		Message:        veleroBackup.Annotations[types.VerificationMessageAnnotation],
+		PreflightState: veleroBackup.Annotations[types.VerificationPreflightStateAnnotation],
+		ID:             veleroBackup.Annotations[types.VerificationIDAnnotation],
+	}
+	if t, err := time.Parse(time.RFC3339, veleroBackup.Annotations[types.VerificationTimeAnnotation]); err == nil {
+		verification.VerifiedAt = &t
+	}
+
+	return verification
+}
+
+// GetLastVerifiedAt returns when the most recent verification of any of the backups finished
+func GetLastVerifiedAt(backups []*types.Backup) *time.Time {
+	var last *time.Time
+	for _, backup := range backups {
+		if backup.Verification == nil || backup.Verification.VerifiedAt == nil {
+			continue
+		}
+		if last == nil || backup.Verification.VerifiedAt.After(*last) {
+			last = backup.Verification.VerifiedAt
+		}
+	}
+	return last
+}
+
+// SelectBackupToVerify returns the newest completed backup, or nil if there is none
+func SelectBackupToVerify(backups []*types.Backup) *types.Backup {
+	var newest *types.Backup
+	for _, backup := range backups {
+		if backup.Status != string(velerov1.BackupPhaseCompleted) || backup.StartedAt == nil {
+			continue
+		}
+		if newest == nil || backup.StartedAt.After(*newest.StartedAt) {
+			newest = backup
+		}
+	}
+	return newest
+}
@@ -0,0 +1,166 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseStatusInformer(t *testing.T) {
+	tests := []struct {
+		informer string
+		want     statusInformer
+		wantErr  bool
+	}{
+		{
+			informer: "deployment/web",
+			want:     statusInformer{Kind: "deployment", Name: "web", Namespace: "default"},
+		},
+		{
+			informer: "other/sts/db",
+			want:     statusInformer{Kind: "statefulset", Name: "db", Namespace: "other"},
+		},
+		{
+			informer: "svc/web",
+			want:     statusInformer{Kind: "service", Name: "web", Namespace: "default"},
+		},
+		{
+			informer: "web",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.informer, func(t *testing.T) {
+			got, err := parseStatusInformer(test.informer, "default")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseStatusInformer() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && got != test.want {
+				t.Errorf("parseStatusInformer() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScratchNamespaceMapping(t *testing.T) {
+	got := scratchNamespaceMapping([]string{"default", "*", "default", "monitoring", "a-namespace-name-that-is-long-enough-to-overflow-the-limit"}, "x7b2q")
+	want := map[string]string{
+		"default":    "default-verify-x7b2q",
+		"monitoring": "monitoring-verify-x7b2q",
+		"a-namespace-name-that-is-long-enough-to-overflow-the-limit": "kots-verify-x7b2q-2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scratchNamespaceMapping() = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateReplicasState(t *testing.T) {
+	three := int32(3)
+	tests := []struct {
+		name    string
+		desired *int32
+		ready   int32
+		want    appstatustypes.State
+	}{
+		{name: "default replicas ready", desired: nil, ready: 1, want: appstatustypes.StateReady},
+		{name: "all ready", desired: &three, ready: 3, want: appstatustypes.StateReady},
+		{name: "some ready", desired: &three, ready: 1, want: appstatustypes.StateDegraded},
+		{name: "none ready", desired: &three, ready: 0, want: appstatustypes.StateUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := calculateReplicasState(test.desired, test.ready); got != test.want {
+				t.Errorf("calculateReplicasState() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckResourceStates(t *testing.T) {
+	ready := appstatustypes.ResourceState{Kind: "deployment", Name: "web", Namespace: "app-abcde", State: appstatustypes.StateReady}
+	unavailable := appstatustypes.ResourceState{Kind: "deployment", Name: "db", Namespace: "app-abcde", State: appstatustypes.StateUnavailable}
+
+	tests := []struct {
+		name           string
+		informerCount  int
+		resourceStates []appstatustypes.ResourceState
+		want           types.VerificationStatus
+	}{
+		{name: "no status informers", informerCount: 0, resourceStates: nil, want: types.VerificationUnverified},
+		{name: "no informers could be evaluated", informerCount: 2, resourceStates: nil, want: types.VerificationUnverified},
+		{name: "all ready", informerCount: 1, resourceStates: []appstatustypes.ResourceState{ready}, want: types.VerificationPassed},
+		{name: "not ready", informerCount: 2, resourceStates: []appstatustypes.ResourceState{ready, unavailable}, want: types.VerificationFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := checkResourceStates(1, test.informerCount, test.resourceStates); got.Status != test.want {
+				t.Errorf("checkResourceStates() = %s (%s), want %s", got.Status, got.Message, test.want)
+			}
+		})
+	}
+}
+
+func TestSelectBackupToVerify(t *testing.T) {
+	backup := func(name string, status string, startedAt string) *types.Backup {
+		ts, _ := time.Parse(time.RFC3339, startedAt)
+		return &types.Backup{Name: name, Status: status, StartedAt: &ts}
+	}
+
+	backups := []*types.Backup{
+		backup("old", "Completed", "2021-01-10T00:00:00Z"),
+		backup("in-progress", "InProgress", "2021-01-12T00:00:00Z"),
+		backup("newest", "Completed", "2021-01-11T00:00:00Z"),
+		backup("failed", "Failed", "2021-01-13T00:00:00Z"),
+	}
+	if got := SelectBackupToVerify(backups); got == nil || got.Name != "newest" {
+		t.Errorf("SelectBackupToVerify() = %v, want newest", got)
+	}
+
+	if got := SelectBackupToVerify([]*types.Backup{backup("failed", "Failed", "2021-01-13T00:00:00Z")}); got != nil {
+		t.Errorf("SelectBackupToVerify() = %v, want nil", got)
+	}
+}
+
+func TestBuildVerificationRestore(t *testing.T) {
+	veleroBackup := &velerov1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "instance-abcd",
+			Namespace: "velero",
+			Annotations: map[string]string{
+				"kots.io/instance": "true",
+			},
+		},
+	}
+	apps := []verifiedApp{
+		{app: &apptypes.App{Slug: "app-a"}},
+		{app: &apptypes.App{Slug: "app-b"}},
+	}
+	namespaceMapping := map[string]string{"default": "default-verify-x7b2q"}
+
+	restore := buildVerificationRestore(veleroBackup, apps, namespaceMapping, "x7b2q")
+
+	if restore.Name != "instance-abcd.verify-x7b2q" || restore.Namespace != "velero" {
+		t.Errorf("unexpected restore name %s/%s", restore.Namespace, restore.Name)
+	}
+	if restore.Spec.IncludeClusterResources == nil || *restore.Spec.IncludeClusterResources {
+		t.Errorf("expected cluster resources to be excluded")
+	}
+	if !reflect.DeepEqual(restore.Spec.NamespaceMapping, namespaceMapping) {
+		t.Errorf("NamespaceMapping = %v, want %v", restore.Spec.NamespaceMapping, namespaceMapping)
+	}
+	wantSelector := []metav1.LabelSelectorRequirement{
+		{Key: "kots.io/app-slug", Operator: metav1.LabelSelectorOpIn, Values: []string{"app-a", "app-b"}},
+	}
+	if restore.Spec.LabelSelector == nil || !reflect.DeepEqual(restore.Spec.LabelSelector.MatchExpressions, wantSelector) {
+		t.Errorf("LabelSelector = %v, want %v", restore.Spec.LabelSelector, wantSelector)
+	}
+}
@@ -0,0 +1,59 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/replicatedhq/kots/pkg/util"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsGitUpstream returns true if the upstream uri points to a git repository.
+func IsGitUpstream(upstreamURI string) bool {
+	return strings.HasPrefix(upstreamURI, "git://")
+}
+
+// GetGitAuth returns the credentials used to clone the git upstream of an app.
+// They are read from the kotsadm-<app slug>-upstream-git secret, with the keys username, token and sshPrivateKey.
+// Public repositories don't need the secret, and empty credentials are returned when it doesn't exist.
+func GetGitAuth(appSlug string) (upstreamtypes.GitAuth, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return upstreamtypes.GitAuth{}, errors.Wrap(err, "failed to get clientset")
+	}
+
+	secretName := fmt.Sprintf("kotsadm-%s-upstream-git", appSlug)
+	secret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return upstreamtypes.GitAuth{}, nil
+	} else if err != nil {
+		return upstreamtypes.GitAuth{}, errors.Wrap(err, "failed to get git auth secret")
+	}
+
+	return upstreamtypes.GitAuth{
+		Username:      string(secret.Data["username"]),
+		Token:         string(secret.Data["token"]),
+		SSHPrivateKey: string(secret.Data["sshPrivateKey"]),
+	}, nil
+}
+
+// SetGitAuth writes the credentials used to clone the git upstream of an app to the
+// kotsadm-<app slug>-upstream-git secret, replacing any credentials that are already stored.
+func SetGitAuth(appSlug string, gitAuth upstreamtypes.GitAuth) error {
+	secretName := fmt.Sprintf("kotsadm-%s-upstream-git", appSlug)
+	data := map[string][]byte{
+		"username":      []byte(gitAuth.Username),
+		"token":         []byte(gitAuth.Token),
+		"sshPrivateKey": []byte(gitAuth.SSHPrivateKey),
+	}
+	if err := writeUpstreamSecret(secretName, data); err != nil {
+		return errors.Wrap(err, "failed to write git auth secret")
+	}
+
+	return nil
+}
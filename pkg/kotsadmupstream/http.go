@@ -0,0 +1,52 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/util"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsHTTPUpstream returns true if the upstream uri points to a release tarball.
+func IsHTTPUpstream(upstreamURI string) bool {
+	return strings.HasPrefix(upstreamURI, "http://") || strings.HasPrefix(upstreamURI, "https://")
+}
+
+// GetReleasePublicKey returns the public key used to verify the releases of an http upstream.
+// It is read from the publicKey key of the kotsadm-<app slug>-upstream-http secret. When the secret
+// doesn't exist, an empty key is returned and releases are verified with their sha256 checksum instead.
+func GetReleasePublicKey(appSlug string) (string, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get clientset")
+	}
+
+	secretName := fmt.Sprintf("kotsadm-%s-upstream-http", appSlug)
+	secret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to get release public key secret")
+	}
+
+	return string(secret.Data["publicKey"]), nil
+}
+
+// SetReleasePublicKey writes the public key used to verify the releases of an http upstream
+// to the kotsadm-<app slug>-upstream-http secret, replacing any key that is already stored.
+func SetReleasePublicKey(appSlug string, publicKey string) error {
+	secretName := fmt.Sprintf("kotsadm-%s-upstream-http", appSlug)
+	data := map[string][]byte{
+		"publicKey": []byte(publicKey),
+	}
+	if err := writeUpstreamSecret(secretName, data); err != nil {
+		return errors.Wrap(err, "failed to write release public key secret")
+	}
+
+	return nil
+}
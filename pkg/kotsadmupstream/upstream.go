@@ -2,6 +2,7 @@ package upstream
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -18,8 +21,12 @@ import (
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/store"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/replicatedhq/kots/pkg/util"
 	"github.com/replicatedhq/kots/pkg/version"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPreflights bool) (sequence int64, finalError error) {
@@ -94,9 +101,32 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 		return 0, errors.Wrap(err, "failed to get new app sequence")
 	}
 
-	latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to get latest license")
+	// git and http upstreams are not licensed, they are fetched using the app's credentials or public key instead
+	var upstreamURI string
+	var latestLicense *kotsv1beta1.License
+	var gitAuth upstreamtypes.GitAuth
+	var releasePublicKey string
+	if IsGitUpstream(a.UpstreamURI) {
+		upstreamURI = a.UpstreamURI
+		gitAuth, err = GetGitAuth(a.Slug)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get git auth")
+		}
+	} else if IsHTTPUpstream(a.UpstreamURI) {
+		upstreamURI = a.UpstreamURI
+		releasePublicKey, err = GetReleasePublicKey(a.Slug)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get release public key")
+		}
+	} else {
+		if beforeKotsKinds.License == nil {
+			return 0, errors.Errorf("app %s does not have a license", a.Slug)
+		}
+		upstreamURI = fmt.Sprintf("replicated://%s", beforeKotsKinds.License.Spec.AppSlug)
+		latestLicense, err = store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get latest license")
+		}
 	}
 
 	identityConfigFile := filepath.Join(archiveDir, "upstream", "userdata", "identityconfig.yaml")
@@ -141,9 +171,11 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 			Password:   registrySettings.Password,
 			IsReadOnly: registrySettings.IsReadOnly,
 		},
+		GitAuth:          gitAuth,
+		ReleasePublicKey: releasePublicKey,
 	}
 
-	if _, err := kotspull.Pull(fmt.Sprintf("replicated://%s", beforeKotsKinds.License.Spec.AppSlug), pullOptions); err != nil {
+	if _, err := kotspull.Pull(upstreamURI, pullOptions); err != nil {
 		return 0, errors.Wrap(err, "failed to pull")
 	}
 
@@ -169,3 +201,40 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 
 	return newSequence, nil
 }
+
+// writeUpstreamSecret creates or replaces the data of a secret that holds upstream credentials.
+func writeUpstreamSecret(secretName string, data map[string][]byte) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	secret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: util.PodNamespace,
+				Labels:    kotsadmtypes.GetKotsadmLabels(),
+			},
+			Data: data,
+		}
+		if _, err := clientset.CoreV1().Secrets(util.PodNamespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to get secret")
+	}
+
+	secret.Data = data
+	if _, err := clientset.CoreV1().Secrets(util.PodNamespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+
+	return nil
+}
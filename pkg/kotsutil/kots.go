@@ -529,6 +529,15 @@ func LoadLicenseFromPath(licenseFilePath string) (*kotsv1beta1.License, error) {
 	return LoadLicenseFromBytes(licenseData)
 }
 
+// LoadLicenseFromPathIfExists is like LoadLicenseFromPath, but returns nil if there is no license file.
+// Apps installed from git or http upstreams do not have a license.
+func LoadLicenseFromPathIfExists(licenseFilePath string) (*kotsv1beta1.License, error) {
+	if _, err := os.Stat(licenseFilePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return LoadLicenseFromPath(licenseFilePath)
+}
+
 func LoadLicenseFromBytes(data []byte) (*kotsv1beta1.License, error) {
 	decode := scheme.Codecs.UniversalDeserializer().Decode
 	obj, gvk, err := decode([]byte(data), nil, nil)
@@ -0,0 +1,192 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	// SignatureHeader is the hex encoded HMAC-SHA256 of the request body, using the endpoint secret as the key
+	SignatureHeader = "X-Kots-Signature-256"
+	EventHeader     = "X-Kots-Event"
+	EventIDHeader   = "X-Kots-Event-Id"
+
+	maxAttempts = 5
+)
+
+var (
+	httpClient   = &http.Client{Timeout: 10 * time.Second}
+	retryBackoff = 2 * time.Second
+)
+
+// Emit sends the event to all endpoints subscribed to the event type.
+// Delivery happens in the background and failures are only recorded in the delivery log.
+func Emit(eventType types.EventType, appID string, data map[string]interface{}) {
+	go func() {
+		if err := emit(store.GetStore(), eventType, appID, data); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to emit %s notification", eventType))
+		}
+	}()
+}
+
+func emit(kotsStore store.Store, eventType types.EventType, appID string, data map[string]interface{}) error {
+	endpoints, err := kotsStore.ListNotificationEndpoints()
+	if err != nil {
+		return errors.Wrap(err, "failed to list notification endpoints")
+	}
+
+	subscribed := []*types.Endpoint{}
+	for _, endpoint := range endpoints {
+		if endpoint.Subscribes(eventType) {
+			subscribed = append(subscribed, endpoint)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	event, err := newEvent(kotsStore, eventType, appID, data)
+	if err != nil {
+		return errors.Wrap(err, "failed to create event")
+	}
+
+	for _, endpoint := range subscribed {
+		go func(endpoint *types.Endpoint) {
+			if _, err := deliver(kotsStore, endpoint, event, maxAttempts); err != nil {
+				logger.Error(errors.Wrapf(err, "failed to deliver %s notification to endpoint %s", eventType, endpoint.ID))
+			}
+		}(endpoint)
+	}
+
+	return nil
+}
+
+// SendTest sends a test event to the endpoint once, without retries, and returns the delivery
+func SendTest(endpoint *types.Endpoint) (*types.Delivery, error) {
+	kotsStore := store.GetStore()
+
+	event, err := newEvent(kotsStore, types.EventTest, "", map[string]interface{}{
+		"kotsVersion": buildversion.Version(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create event")
+	}
+
+	return deliver(kotsStore, endpoint, event, 1)
+}
+
+func newEvent(kotsStore store.Store, eventType types.EventType, appID string, data map[string]interface{}) (*types.Event, error) {
+	event := &types.Event{
+		ID:        ksuid.New().String(),
+		Type:      eventType,
+		AppID:     appID,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+
+	if appID != "" {
+		a, err := kotsStore.GetApp(appID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get app")
+		}
+		event.AppSlug = a.Slug
+	}
+
+	return event, nil
+}
+
+// deliver posts the event to the endpoint, retrying with exponential backoff until it succeeds or
+// maxAttempts is reached. Every attempt is recorded in the delivery log.
+func deliver(kotsStore store.Store, endpoint *types.Endpoint, event *types.Event, attempts int) (*types.Delivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+
+	backoff := retryBackoff
+
+	var delivery *types.Delivery
+	for attempt := 1; attempt <= attempts; attempt++ {
+		delivery = &types.Delivery{
+			ID:          ksuid.New().String(),
+			EndpointID:  endpoint.ID,
+			EventID:     event.ID,
+			EventType:   event.Type,
+			Payload:     string(payload),
+			Attempt:     attempt,
+			DeliveredAt: time.Now(),
+		}
+
+		statusCode, err := post(endpoint, event, payload)
+		delivery.StatusCode = statusCode
+		if err != nil {
+			delivery.Error = err.Error()
+		} else {
+			delivery.IsSuccess = true
+		}
+
+		if err := kotsStore.CreateNotificationDelivery(delivery); err != nil {
+			logger.Error(errors.Wrap(err, "failed to record notification delivery"))
+		}
+
+		if delivery.IsSuccess {
+			return delivery, nil
+		}
+
+		if attempt < attempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return delivery, errors.Errorf("giving up after %d attempts: %s", attempts, delivery.Error)
+}
+
+func post(endpoint *types.Endpoint, event *types.Event, payload []byte) (int, error) {
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(EventIDHeader, event.ID)
+	if endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(endpoint.Secret, payload))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header for the payload, e.g. "sha256=<hex digest>"
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/replicatedhq/kots/pkg/notifications/types"
+	mock_store "github.com/replicatedhq/kots/pkg/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '{"type":"test"}' | openssl dgst -sha256 -hmac secret
+	got := Sign("secret", []byte(`{"type":"test"}`))
+	assert.Equal(t, "sha256=e0c6dc0edbeee535e9560c6876404637e75d912703f2cf36863b2220daa18af8", got)
+}
+
+func TestEndpoint_Subscribes(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  types.Endpoint
+		eventType types.EventType
+		want      bool
+	}{
+		{
+			name:      "all events",
+			endpoint:  types.Endpoint{IsEnabled: true},
+			eventType: types.EventDeployFailed,
+			want:      true,
+		},
+		{
+			name:      "subscribed",
+			endpoint:  types.Endpoint{IsEnabled: true, Events: []types.EventType{types.EventDeployFailed}},
+			eventType: types.EventDeployFailed,
+			want:      true,
+		},
+		{
+			name:      "not subscribed",
+			endpoint:  types.Endpoint{IsEnabled: true, Events: []types.EventType{types.EventDeployFailed}},
+			eventType: types.EventSnapshotFinished,
+			want:      false,
+		},
+		{
+			name:      "disabled",
+			endpoint:  types.Endpoint{IsEnabled: false},
+			eventType: types.EventDeployFailed,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.endpoint.Subscribes(tt.eventType))
+		})
+	}
+}
+
+func Test_deliver(t *testing.T) {
+	retryBackoff = 0
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, Sign("secret", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, string(types.EventDeployFailed), r.Header.Get(EventHeader))
+
+		event := types.Event{}
+		require.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "my-app", event.AppSlug)
+
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+
+	var deliveries []*types.Delivery
+	mockStore.EXPECT().CreateNotificationDelivery(gomock.Any()).Times(2).DoAndReturn(func(delivery *types.Delivery) error {
+		deliveries = append(deliveries, delivery)
+		return nil
+	})
+
+	endpoint := &types.Endpoint{ID: "endpoint", URL: server.URL, Secret: "secret", IsEnabled: true}
+	event := &types.Event{ID: "event", Type: types.EventDeployFailed, AppID: "app", AppSlug: "my-app"}
+
+	delivery, err := deliver(mockStore, endpoint, event, maxAttempts)
+	require.NoError(t, err)
+	assert.True(t, delivery.IsSuccess)
+	assert.Equal(t, 2, delivery.Attempt)
+
+	require.Len(t, deliveries, 2)
+	assert.False(t, deliveries[0].IsSuccess)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	assert.True(t, deliveries[1].IsSuccess)
+}
@@ -0,0 +1,73 @@
+package types
+
+import "time"
+
+type EventType string
+
+const (
+	EventUpdateDownloaded  EventType = "update.downloaded"
+	EventPreflightFailed   EventType = "preflight.failed"
+	EventDeployFailed      EventType = "deploy.failed"
+	EventSnapshotFinished  EventType = "snapshot.finished"
+	EventAppStatusDegraded EventType = "appstatus.degraded"
+	EventTest              EventType = "test"
+)
+
+// EventTypes are the event types an endpoint can subscribe to
+var EventTypes = []EventType{
+	EventUpdateDownloaded,
+	EventPreflightFailed,
+	EventDeployFailed,
+	EventSnapshotFinished,
+	EventAppStatusDegraded,
+}
+
+// Endpoint is a webhook that receives events. An endpoint with no events receives all events.
+type Endpoint struct {
+	ID        string      `json:"id"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"secret,omitempty"`
+	Events    []EventType `json:"events"`
+	IsEnabled bool        `json:"isEnabled"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// Subscribes returns true if the endpoint should receive events of this type
+func (e Endpoint) Subscribes(eventType EventType) bool {
+	if !e.IsEnabled {
+		return false
+	}
+	if eventType == EventTest || len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON payload sent to endpoints
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	AppID     string                 `json:"appId,omitempty"`
+	AppSlug   string                 `json:"appSlug,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Delivery is a single attempt to deliver an event to an endpoint
+type Delivery struct {
+	ID          string    `json:"id"`
+	EndpointID  string    `json:"endpointId"`
+	EventID     string    `json:"eventId"`
+	EventType   EventType `json:"eventType"`
+	Payload     string    `json:"payload"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	IsSuccess   bool      `json:"isSuccess"`
+	DeliveredAt time.Time `json:"deliveredAt"`
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/crypto"
 	kotsadmconfig "github.com/replicatedhq/kots/pkg/kotsadmconfig"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
+	upstream "github.com/replicatedhq/kots/pkg/kotsadmupstream"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/online/types"
@@ -82,14 +83,18 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 		pipeReader.CloseWithError(scanner.Err())
 	}()
 
-	// put the license in a temp file
-	licenseFile, err := ioutil.TempFile("", "kotsadm")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create tmp file for license")
-	}
-	defer os.RemoveAll(licenseFile.Name())
-	if err := ioutil.WriteFile(licenseFile.Name(), []byte(pendingApp.LicenseData), 0644); err != nil {
-		return nil, errors.Wrap(err, "failed to write license tmp file")
+	// put the license in a temp file. apps installed from a git or http upstream don't have a license
+	licenseFilePath := ""
+	if pendingApp.LicenseData != "" {
+		licenseFile, err := ioutil.TempFile("", "kotsadm")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tmp file for license")
+		}
+		defer os.RemoveAll(licenseFile.Name())
+		if err := ioutil.WriteFile(licenseFile.Name(), []byte(pendingApp.LicenseData), 0644); err != nil {
+			return nil, errors.Wrap(err, "failed to write license tmp file")
+		}
+		licenseFilePath = licenseFile.Name()
 	}
 
 	// pull to a tmp dir
@@ -131,7 +136,7 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 	// for the application, and then delete it.
 	pullOptions := pull.PullOptions{
 		Downstreams:         []string{"this-cluster"},
-		LicenseFile:         licenseFile.Name(),
+		LicenseFile:         licenseFilePath,
 		Namespace:           appNamespace,
 		ExcludeKotsKinds:    true,
 		RootDir:             tmpRoot,
@@ -145,6 +150,20 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 		ReportingInfo:       reporting.GetReportingInfo(pendingApp.ID),
 	}
 
+	if upstream.IsGitUpstream(upstreamURI) {
+		gitAuth, err := upstream.GetGitAuth(pendingApp.Slug)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get git auth")
+		}
+		pullOptions.GitAuth = gitAuth
+	} else if upstream.IsHTTPUpstream(upstreamURI) {
+		releasePublicKey, err := upstream.GetReleasePublicKey(pendingApp.Slug)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get release public key")
+		}
+		pullOptions.ReleasePublicKey = releasePublicKey
+	}
+
 	if _, err := pull.Pull(upstreamURI, pullOptions); err != nil {
 		return nil, errors.Wrap(err, "failed to pull")
 	}
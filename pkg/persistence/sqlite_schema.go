@@ -28,6 +28,46 @@ spec:
 `,
 	`apiVersion: schemas.schemahero.io/v1alpha4
 kind: Table
+metadata:
+  name: api-token
+spec:
+  database: kotsadm
+  name: api_token
+  requires: []
+  schema:
+    sqlite:
+      primaryKey:
+      - id
+      columns:
+      - name: id
+        type: text
+        constraints:
+          notNull: true
+      - name: name
+        type: text
+        constraints:
+          notNull: true
+      - name: secret_sha256
+        type: text
+        constraints:
+          notNull: true
+      - name: role_ids
+        type: text
+      - name: created_by
+        type: text
+      - name: created_at
+        type: integer
+        constraints:
+          notNull: true
+      - name: expires_at
+        type: integer
+        constraints:
+          notNull: true
+      - name: revoked_at
+        type: integer
+`,
+	`apiVersion: schemas.schemahero.io/v1alpha4
+kind: Table
 metadata:
   name: app-downstream-output
 spec:
@@ -65,6 +105,8 @@ spec:
         type: text
       - name: helm_stderr
         type: text
+      - name: resources
+        type: text
       - name: is_error
         type: boolean`,
 	`apiVersion: schemas.schemahero.io/v1alpha4
@@ -310,6 +352,8 @@ spec:
           notNull: true
       - name: snapshot_schedule
         type: text
+      - name: snapshot_retention
+        type: text
       - name: restore_in_progress_name
         type: text
       - name: restore_undeploy_status
@@ -317,6 +361,62 @@ spec:
       - name: update_checker_spec
         type: text
         default: '@default'
+      - name: auto_deploy_policy
+        type: text
+`,
+	`apiVersion: schemas.schemahero.io/v1alpha4
+kind: Table
+metadata:
+  name: audit-event
+spec:
+  database: kotsadm
+  name: audit_event
+  requires: []
+  schema:
+    sqlite:
+      primaryKey:
+      - id
+      columns:
+      - name: id
+        type: text
+        constraints:
+          notNull: true
+      - name: created_at
+        type: integer
+        constraints:
+          notNull: true
+      - name: session_id
+        type: text
+      - name: actor
+        type: text
+      - name: roles
+        type: text
+      - name: action
+        type: text
+        constraints:
+          notNull: true
+      - name: resource
+        type: text
+        constraints:
+          notNull: true
+      - name: method
+        type: text
+        constraints:
+          notNull: true
+      - name: path
+        type: text
+        constraints:
+          notNull: true
+      - name: app_slug
+        type: text
+      - name: sequence
+        type: integer
+      - name: request_body_sha256
+        type: text
+      - name: status_code
+        type: integer
+        constraints:
+          notNull: true
 `,
 	`apiVersion: schemas.schemahero.io/v1alpha4
 kind: Table
@@ -372,6 +472,10 @@ spec:
         default: '720h'
         constraints:
           notNull: true
+      - name: snapshot_retention
+        type: text
+      - name: snapshot_verification
+        type: text
 `,
 	`apiVersion: schemas.schemahero.io/v1alpha4
 kind: Table
@@ -397,6 +501,127 @@ spec:
 `,
 	`apiVersion: schemas.schemahero.io/v1alpha4
 kind: Table
+metadata:
+  name: kotsadm-user
+spec:
+  database: kotsadm
+  name: kotsadm_user
+  requires: []
+  schema:
+    sqlite:
+      primaryKey:
+      - username
+      columns:
+      - name: username
+        type: text
+        constraints:
+          notNull: true
+      - name: password_bcrypt
+        type: text
+        constraints:
+          notNull: true
+      - name: role_ids
+        type: text
+      - name: must_change_password
+        type: boolean
+        constraints:
+          notNull: true
+      - name: failed_login_count
+        type: integer
+        default: "0"
+        constraints:
+          notNull: true
+      - name: created_at
+        type: integer
+        constraints:
+          notNull: true
+      - name: last_login_at
+        type: integer
+`,
+	`apiVersion: schemas.schemahero.io/v1alpha4
+kind: Table
+metadata:
+  name: notification-delivery
+spec:
+  database: kotsadm
+  name: notification_delivery
+  requires: []
+  schema:
+    sqlite:
+      primaryKey:
+      - id
+      columns:
+      - name: id
+        type: text
+        constraints:
+          notNull: true
+      - name: endpoint_id
+        type: text
+        constraints:
+          notNull: true
+      - name: event_id
+        type: text
+        constraints:
+          notNull: true
+      - name: event_type
+        type: text
+        constraints:
+          notNull: true
+      - name: payload
+        type: text
+      - name: attempt
+        type: integer
+        constraints:
+          notNull: true
+      - name: status_code
+        type: integer
+      - name: error
+        type: text
+      - name: is_success
+        type: boolean
+        constraints:
+          notNull: true
+      - name: delivered_at
+        type: integer
+        constraints:
+          notNull: true
+`,
+	`apiVersion: schemas.schemahero.io/v1alpha4
+kind: Table
+metadata:
+  name: notification-endpoint
+spec:
+  database: kotsadm
+  name: notification_endpoint
+  requires: []
+  schema:
+    sqlite:
+      primaryKey:
+      - id
+      columns:
+      - name: id
+        type: text
+        constraints:
+          notNull: true
+      - name: url
+        type: text
+        constraints:
+          notNull: true
+      - name: secret_enc
+        type: text
+      - name: events
+        type: text
+      - name: is_enabled
+        type: boolean
+        constraints:
+          notNull: true
+      - name: created_at
+        type: integer
+        constraints:
+          notNull: true
+`,
+	`apiVersion: schemas.schemahero.io/v1alpha4
+kind: Table
 metadata:
   labels:
     controller-tools.k8s.io: "1.0"
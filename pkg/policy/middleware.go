@@ -1,11 +1,14 @@
 package policy
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/audit"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/rbac"
 	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
@@ -31,6 +34,8 @@ func (e RBACError) Abort(w http.ResponseWriter) error {
 type Middleware struct {
 	KOTSStore store.Store
 	Roles     []rbactypes.Role
+	// RolesGetter is used instead of Roles when set so that roles can be changed without a restart
+	RolesGetter func(ctx context.Context) ([]rbactypes.Role, error)
 }
 
 func NewMiddleware(kotsStore store.Store, roles []rbactypes.Role) *Middleware {
@@ -49,17 +54,42 @@ func (m *Middleware) EnforceAccess(p *Policy, handler http.HandlerFunc) http.Han
 			return
 		}
 
-		if sess.HasRBAC { // handle pre-rbac sessions
-			action, resource, err := p.execute(r, m.KOTSStore)
+		// the vars getters add their vars to the request, so the audit event has the app slug even if the route only has the app id
+		action, resource, auditVars := p.action, p.resource, mux.Vars(r)
+		if sess.HasRBAC {
+			var err error
+			action, resource, err = p.execute(r, m.KOTSStore)
 			if err != nil {
 				logger.Error(errors.Wrapf(err, "failed to execute policy template %q", p.resource))
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
+		} else if p.action == ActionWrite {
+			// pre-rbac sessions only need the resource for the audit log. the template is rendered from a copy of
+			// the vars so the handler sees the same request as before, and a template that cannot be rendered
+			// is recorded as is instead of failing the request. the rendered vars are only used by the audit event.
+			auditVars = map[string]string{}
+			for key, val := range mux.Vars(r) {
+				auditVars[key] = val
+			}
+			if _, rendered, err := p.executeWithVars(auditVars, m.KOTSStore); err != nil {
+				logger.Debugf("failed to execute policy template %q for audit log: %v", p.resource, err)
+			} else {
+				resource = rendered
+			}
+		}
 
+		if sess.HasRBAC { // handle pre-rbac sessions
 			rbacErr := NewRBACError(resource)
 
-			allow, err := rbac.CheckAccess(r.Context(), m.Roles, action, resource, sess.Roles)
+			roles, err := m.getRoles(r.Context())
+			if err != nil {
+				logger.Error(errors.Wrap(err, "failed to get roles"))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			allow, err := rbac.CheckAccess(r.Context(), roles, action, resource, sess.Roles)
 			if err != nil {
 				logger.Error(errors.Wrapf(err, "failed to check access to resource %q", resource))
 				w.WriteHeader(http.StatusInternalServerError)
@@ -71,10 +101,22 @@ func (m *Middleware) EnforceAccess(p *Policy, handler http.HandlerFunc) http.Han
 			}
 		}
 
+		if action == ActionWrite {
+			audit.ServeAndRecord(m.KOTSStore, sess, action, resource, auditVars, handler, w, r)
+			return
+		}
+
 		handler(w, r)
 	}
 }
 
+func (m *Middleware) getRoles(ctx context.Context) ([]rbactypes.Role, error) {
+	if m.RolesGetter != nil {
+		return m.RolesGetter(ctx)
+	}
+	return m.Roles, nil
+}
+
 // TODO: move everything below here to a shared package
 
 type ErrorResponse struct {
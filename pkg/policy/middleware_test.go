@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	"github.com/replicatedhq/kots/pkg/session"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	mock_store "github.com/replicatedhq/kots/pkg/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforceAccess_PreRBACSession(t *testing.T) {
+	defer func(policies []*Policy) {
+		registeredPolicies = policies
+	}(registeredPolicies)
+
+	// sessions created with the shared password before rbac have no roles and are not checked
+	sess := &sessiontypes.Session{
+		ID:        "session",
+		UserID:    "kots",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		policy         *Policy
+		method         string
+		expectAudit    bool
+		getAppErr      error
+		expectResource string
+		expectAppSlug  string
+	}{
+		{
+			name:   "read",
+			policy: Must(NewPolicy(ActionRead, "app.{{.appSlug}}", appSlugFromAppIDGetter)),
+			method: "GET",
+		},
+		{
+			name:           "write",
+			policy:         Must(NewPolicy(ActionWrite, "app.{{.appSlug}}", appSlugFromAppIDGetter)),
+			method:         "POST",
+			expectAudit:    true,
+			expectResource: "app.my-app",
+			expectAppSlug:  "my-app",
+		},
+		{
+			name:           "write with a template that cannot be rendered",
+			policy:         Must(NewPolicy(ActionWrite, "app.{{.appSlug}}", appSlugFromAppIDGetter)),
+			method:         "POST",
+			expectAudit:    true,
+			getAppErr:      errors.New("app not found"),
+			expectResource: "app.{{.appSlug}}",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			if test.expectAudit {
+				if test.getAppErr != nil {
+					mockStore.EXPECT().GetApp("app-id").Return(nil, test.getAppErr)
+				} else {
+					mockStore.EXPECT().GetApp("app-id").Return(&apptypes.App{ID: "app-id", Slug: "my-app"}, nil)
+				}
+				mockStore.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(event *audittypes.Event) error {
+					assert.Equal(t, ActionWrite, event.Action)
+					assert.Equal(t, test.expectResource, event.Resource)
+					assert.Equal(t, test.expectAppSlug, event.AppSlug)
+					assert.Equal(t, http.StatusNotFound, event.StatusCode)
+					assert.Equal(t, "kots", event.Actor)
+					return nil
+				})
+			}
+
+			middleware := NewMiddleware(mockStore, rbac.DefaultRoles())
+
+			handlerCalled := false
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+				// the handler sees the same vars as before rbac
+				assert.Equal(t, map[string]string{"appId": "app-id"}, mux.Vars(r))
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			r := mux.NewRouter()
+			r.Path("/app/{appId}").Methods(test.method).HandlerFunc(middleware.EnforceAccess(test.policy, handler))
+
+			req := httptest.NewRequest(test.method, "/app/app-id", nil)
+			req = session.ContextSetSession(req, sess)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.True(t, handlerCalled)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
+)
+
+type Permission struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Allowed  bool   `json:"allowed"`
+}
+
+// EffectivePermissions checks every policy used by the api against the session roles.
+// Policies with the app slug in the resource are checked once for each app.
+func EffectivePermissions(ctx context.Context, roles []rbactypes.Role, sessionRoles []string, appSlugs []string) ([]Permission, error) {
+	permissions := []Permission{}
+	seen := map[string]bool{}
+
+	for _, p := range registeredPolicies {
+		varsList := []map[string]string{{}}
+		if strings.Contains(p.resource, "appSlug") {
+			varsList = []map[string]string{}
+			for _, appSlug := range appSlugs {
+				varsList = append(varsList, map[string]string{"appSlug": appSlug})
+			}
+		}
+
+		for _, vars := range varsList {
+			var buf bytes.Buffer
+			if err := p.resourceTemplate.Execute(&buf, vars); err != nil {
+				return nil, errors.Wrapf(err, "failed to execute policy template %q", p.resource)
+			}
+			resource := buf.String()
+
+			key := p.action + " " + resource
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			allowed, err := rbac.CheckAccess(ctx, roles, p.action, resource, sessionRoles)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to check access to resource %q", resource)
+			}
+
+			permissions = append(permissions, Permission{
+				Action:   p.action,
+				Resource: resource,
+				Allowed:  allowed,
+			})
+		}
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].Resource == permissions[j].Resource {
+			return permissions[i].Action < permissions[j].Action
+		}
+		return permissions[i].Resource < permissions[j].Resource
+	})
+
+	return permissions, nil
+}
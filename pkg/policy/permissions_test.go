@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/rbac"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectivePermissions(t *testing.T) {
+	deployerRole := rbactypes.Role{
+		ID: "deployer",
+		Allow: []rbactypes.Policy{
+			rbac.PolicyReadonly,
+			{Action: "write", Resource: "app.*.downstream."},
+		},
+		Deny: []rbactypes.Policy{
+			{Action: "**", Resource: "app.*.registry."},
+		},
+	}
+	roles := append(rbac.DefaultRoles(), deployerRole)
+
+	permissions, err := EffectivePermissions(context.Background(), roles, []string{"deployer"}, []string{"my-app"})
+	require.NoError(t, err)
+
+	allowed := map[string]bool{}
+	for _, permission := range permissions {
+		allowed[permission.Action+" "+permission.Resource] = permission.Allowed
+	}
+
+	assert.True(t, allowed["write app.my-app.downstream."])
+	assert.True(t, allowed["read app.my-app.downstream.config."])
+	assert.False(t, allowed["write app.my-app.downstream.config."])
+	assert.False(t, allowed["read app.my-app.registry."])
+	assert.False(t, allowed["write users."])
+	assert.True(t, allowed["read users."])
+
+	_, hasTemplate := allowed["read app.{{.appSlug}}"]
+	assert.False(t, hasTemplate)
+}
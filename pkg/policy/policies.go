@@ -51,6 +51,33 @@ var (
 	GitopsWrite = Must(NewPolicy(ActionWrite, "gitops."))
 )
 
+// Notifications
+
+var (
+	NotificationsRead  = Must(NewPolicy(ActionRead, "notifications."))
+	NotificationsWrite = Must(NewPolicy(ActionWrite, "notifications."))
+)
+
+// Users
+
+var (
+	UsersRead  = Must(NewPolicy(ActionRead, "users."))
+	UsersWrite = Must(NewPolicy(ActionWrite, "users."))
+)
+
+// API tokens
+
+var (
+	TokensRead  = Must(NewPolicy(ActionRead, "tokens."))
+	TokensWrite = Must(NewPolicy(ActionWrite, "tokens."))
+)
+
+// Audit
+
+var (
+	AuditRead = Must(NewPolicy(ActionRead, "audit."))
+)
+
 // Prometheus
 
 var (
@@ -18,9 +18,15 @@ type Policy struct {
 	varsGetterFns    []VarsGetter
 }
 
+// registeredPolicies are all policies created with NewPolicy, used to list the permissions of a session
+var registeredPolicies []*Policy
+
 func NewPolicy(action, resource string, fns ...VarsGetter) (policy *Policy, err error) {
 	policy = &Policy{action: action, resource: resource, varsGetterFns: fns}
 	policy.resourceTemplate, err = template.New(resource).Option("missingkey=error").Parse(resource)
+	if err == nil {
+		registeredPolicies = append(registeredPolicies, policy)
+	}
 	return
 }
 
@@ -31,8 +37,12 @@ func Must(p *Policy, err error) *Policy {
 	return p
 }
 
+// execute renders the resource from the request vars. the vars getters add their vars to the request.
 func (p *Policy) execute(r *http.Request, kotsStore store.Store) (action, resource string, err error) {
-	vars := mux.Vars(r)
+	return p.executeWithVars(mux.Vars(r), kotsStore)
+}
+
+func (p *Policy) executeWithVars(vars map[string]string, kotsStore store.Store) (action, resource string, err error) {
 	for _, fn := range p.varsGetterFns {
 		additionalVars, err := fn(kotsStore, vars)
 		if err != nil {
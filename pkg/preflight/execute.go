@@ -62,6 +62,31 @@ func execute(appID string, sequence int64, preflightSpec *troubleshootv1beta2.Pr
 		}
 	}()
 
+	uploadPreflightResults, err := collectAndAnalyze(preflightSpec, ignorePermissionErrors, progressChan)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("preflight marshalling")
+	b, err := json.Marshal(uploadPreflightResults)
+	if err != nil {
+		return uploadPreflightResults, errors.Wrap(err, "failed to marshal results")
+	}
+
+	completeMx.Lock()
+	defer completeMx.Unlock()
+
+	isComplete = true
+	if err := store.GetStore().SetPreflightResults(appID, sequence, b); err != nil {
+		return uploadPreflightResults, errors.Wrap(err, "failed to set preflight results")
+	}
+
+	return uploadPreflightResults, nil
+}
+
+// collectAndAnalyze runs the collectors in preflightSpec against the cluster and analyzes the results.
+// progressChan must be drained by the caller.
+func collectAndAnalyze(preflightSpec *troubleshootv1beta2.Preflight, ignorePermissionErrors bool, progressChan chan interface{}) (*troubleshootpreflight.UploadPreflightResults, error) {
 	restConfig, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read in cluster config")
@@ -120,20 +145,6 @@ func execute(appID string, sequence int64, preflightSpec *troubleshootv1beta2.Pr
 		uploadPreflightResults.Results = results
 	}
 
-	logger.Debug("preflight marshalling")
-	b, err := json.Marshal(uploadPreflightResults)
-	if err != nil {
-		return uploadPreflightResults, errors.Wrap(err, "failed to marshal results")
-	}
-
-	completeMx.Lock()
-	defer completeMx.Unlock()
-
-	isComplete = true
-	if err := store.GetStore().SetPreflightResults(appID, sequence, b); err != nil {
-		return uploadPreflightResults, errors.Wrap(err, "failed to set preflight results")
-	}
-
 	return uploadPreflightResults, nil
 }
 
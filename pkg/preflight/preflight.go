@@ -12,6 +12,8 @@ import (
 	kotstypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
 	"github.com/replicatedhq/kots/pkg/registry"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
 	"github.com/replicatedhq/kots/pkg/render"
@@ -36,6 +38,16 @@ const (
 )
 
 func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir string) error {
+	return run(appID, appSlug, sequence, isAirgap, archiveDir, false)
+}
+
+// RunAndDeploy runs the preflights like Run and deploys the version once they pass.
+// Versions without preflights are deployed right away.
+func RunAndDeploy(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir string) error {
+	return run(appID, appSlug, sequence, isAirgap, archiveDir, true)
+}
+
+func run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir string, deployOnPass bool) error {
 	renderedKotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
 	if err != nil {
 		return errors.Wrap(err, "failed to load rendered kots kinds")
@@ -71,34 +83,10 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			return errors.Wrap(err, "failed to get ignore rbac flag")
 		}
 
-		// render the preflight file
-		// we need to convert to bytes first, so that we can reuse the renderfile function
-		renderedMarshalledPreflights, err := renderedKotsKinds.Marshal("troubleshoot.replicated.com", "v1beta1", "Preflight")
-		if err != nil {
-			return errors.Wrap(err, "failed to marshal rendered preflight")
-		}
-
-		registrySettings, err := store.GetStore().GetRegistryDetailsForApp(appID)
-		if err != nil {
-			return errors.Wrap(err, "failed to get registry settings for app")
-		}
-
-		renderedPreflight, err := render.RenderFile(renderedKotsKinds, registrySettings, appSlug, sequence, isAirgap, util.PodNamespace, []byte(renderedMarshalledPreflights))
-		if err != nil {
-			return errors.Wrap(err, "failed to render preflights")
-		}
-		p, err := kotsutil.LoadPreflightFromContents(renderedPreflight)
-		if err != nil {
-			return errors.Wrap(err, "failed to load rendered preflight")
-		}
-
-		injectDefaultPreflights(p, renderedKotsKinds, registrySettings)
-
-		collectors, err := registry.UpdateCollectorSpecsWithRegistryData(p.Spec.Collectors, registrySettings, renderedKotsKinds.Installation.Spec.KnownImages, renderedKotsKinds.License)
+		p, err := renderPreflight(appID, appSlug, sequence, isAirgap, util.PodNamespace, renderedKotsKinds)
 		if err != nil {
-			return errors.Wrap(err, "failed to rewrite images in preflight")
+			return errors.Wrap(err, "failed to render preflight")
 		}
-		p.Spec.Collectors = collectors
 
 		go func() {
 			logger.Debug("preflight checks beginning")
@@ -110,6 +98,12 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			}
 			logger.Debug("preflight checks completed")
 
+			if getPreflightState(uploadPreflightResults) == "fail" {
+				notifications.Emit(notificationtypes.EventPreflightFailed, appID, map[string]interface{}{
+					"sequence": sequence,
+				})
+			}
+
 			isDeployed, err := maybeDeployFirstVersion(appID, sequence, uploadPreflightResults)
 			if err != nil {
 				err = errors.Wrap(err, "failed to deploy first version")
@@ -117,6 +111,20 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 				return
 			}
 
+			if deployOnPass && !isDeployed {
+				preflightState := getPreflightState(uploadPreflightResults)
+				if preflightState != "pass" {
+					logger.Infof("not deploying version %d of app %s, preflight checks state is %q", sequence, appSlug, preflightState)
+					return
+				}
+				if err := version.DeployVersion(appID, sequence); err != nil {
+					err = errors.Wrap(err, "failed to deploy version")
+					logger.Error(err)
+					return
+				}
+				isDeployed = true
+			}
+
 			// preflight reporting
 			if isDeployed {
 				if err := reporting.ReportAppInfo(appID, sequence, false, false); err != nil {
@@ -126,10 +134,19 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			}
 		}()
 	} else if sequence == 0 {
-		_, err := maybeDeployFirstVersion(appID, sequence, &troubleshootpreflight.UploadPreflightResults{})
+		isDeployed, err := maybeDeployFirstVersion(appID, sequence, &troubleshootpreflight.UploadPreflightResults{})
 		if err != nil {
 			return errors.Wrap(err, "failed to deploy first version")
 		}
+		if deployOnPass && !isDeployed {
+			if err := version.DeployVersion(appID, sequence); err != nil {
+				return errors.Wrap(err, "failed to deploy version")
+			}
+		}
+	} else if deployOnPass {
+		if err := version.DeployVersion(appID, sequence); err != nil {
+			return errors.Wrap(err, "failed to deploy version")
+		}
 	} else {
 		status, err := store.GetStore().GetDownstreamVersionStatus(appID, sequence)
 		if err != nil {
@@ -145,6 +162,77 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 	return nil
 }
 
+// RunInNamespace runs the app's preflights synchronously, rendered as if the app were installed in namespace,
+// and returns the resulting state. The results are not stored and no versions are deployed.
+func RunInNamespace(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir string, namespace string) (string, error) {
+	renderedKotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load rendered kots kinds")
+	}
+
+	if renderedKotsKinds.Preflight == nil {
+		return "pass", nil
+	}
+
+	ignoreRBAC, err := store.GetStore().GetIgnoreRBACErrors(appID, sequence)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get ignore rbac flag")
+	}
+
+	p, err := renderPreflight(appID, appSlug, sequence, isAirgap, namespace, renderedKotsKinds)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render preflight")
+	}
+
+	progressChan := make(chan interface{}, 0)
+	defer close(progressChan)
+	go func() {
+		for msg := range progressChan {
+			logger.Debugf("%v", msg)
+		}
+	}()
+
+	uploadPreflightResults, err := collectAndAnalyze(p, ignoreRBAC, progressChan)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to run preflight checks")
+	}
+
+	return getPreflightState(uploadPreflightResults), nil
+}
+
+func renderPreflight(appID string, appSlug string, sequence int64, isAirgap bool, namespace string, renderedKotsKinds *kotsutil.KotsKinds) (*troubleshootv1beta2.Preflight, error) {
+	// render the preflight file
+	// we need to convert to bytes first, so that we can reuse the renderfile function
+	renderedMarshalledPreflights, err := renderedKotsKinds.Marshal("troubleshoot.replicated.com", "v1beta1", "Preflight")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal rendered preflight")
+	}
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings for app")
+	}
+
+	renderedPreflight, err := render.RenderFile(renderedKotsKinds, registrySettings, appSlug, sequence, isAirgap, namespace, []byte(renderedMarshalledPreflights))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render preflights")
+	}
+	p, err := kotsutil.LoadPreflightFromContents(renderedPreflight)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load rendered preflight")
+	}
+
+	injectDefaultPreflights(p, renderedKotsKinds, registrySettings)
+
+	collectors, err := registry.UpdateCollectorSpecsWithRegistryData(p.Spec.Collectors, registrySettings, renderedKotsKinds.Installation.Spec.KnownImages, renderedKotsKinds.License)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rewrite images in preflight")
+	}
+	p.Spec.Collectors = collectors
+
+	return p, nil
+}
+
 // maybeDeployFirstVersion will deploy the first version if
 // 1. preflight checks pass
 // 2. we have not already deployed it
@@ -0,0 +1,43 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+)
+
+func APITokens(tokens []*apitokentypes.APIToken, format string) {
+	switch format {
+	case "json":
+		printAPITokensJSON(tokens)
+	default:
+		printAPITokensTable(tokens)
+	}
+}
+
+func printAPITokensJSON(tokens []*apitokentypes.APIToken) {
+	str, _ := json.MarshalIndent(tokens, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printAPITokensTable(tokens []*apitokentypes.APIToken) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	now := time.Now()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "ID", "NAME", "ROLES", "CREATED BY", "EXPIRES", "STATUS")
+	for _, t := range tokens {
+		status := "active"
+		if t.RevokedAt != nil {
+			status = "revoked"
+		} else if !t.IsActive(now) {
+			status = "expired"
+		}
+		fmt.Fprintf(w, fmtColumns, t.ID, t.Name, strings.Join(t.RoleIDs, ","), t.CreatedBy, t.ExpiresAt.Format("2006-01-02 15:04:05"), status)
+	}
+}
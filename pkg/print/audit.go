@@ -0,0 +1,38 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+)
+
+func AuditEvents(events []*audittypes.Event, format string) {
+	switch format {
+	case "json":
+		printAuditEventsJSON(events)
+	default:
+		printAuditEventsTable(events)
+	}
+}
+
+func printAuditEventsJSON(events []*audittypes.Event) {
+	str, _ := json.MarshalIndent(events, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printAuditEventsTable(events []*audittypes.Event) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%d\n"
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "TIME", "ACTOR", "METHOD", "PATH", "APP", "SEQUENCE", "STATUS")
+	for _, event := range events {
+		sequence := ""
+		if event.Sequence != nil {
+			sequence = strconv.FormatInt(*event.Sequence, 10)
+		}
+		fmt.Fprintf(w, fmtColumns, event.CreatedAt.Format("2006-01-02 15:04:05"), event.Actor, event.Method, event.Path, event.AppSlug, sequence, event.StatusCode)
+	}
+}
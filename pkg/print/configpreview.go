@@ -0,0 +1,64 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+)
+
+func ConfigPreview(preview handlertypes.PreviewAppConfigValuesResponse, format string) {
+	switch format {
+	case "json":
+		printConfigPreviewJSON(preview)
+	default:
+		printConfigPreviewText(preview)
+	}
+}
+
+func printConfigPreviewJSON(preview handlertypes.PreviewAppConfigValuesResponse) {
+	str, _ := json.MarshalIndent(preview, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printConfigPreviewText(preview handlertypes.PreviewAppConfigValuesResponse) {
+	if len(preview.TemplateErrors) > 0 {
+		fmt.Println("Template errors:")
+		for _, templateError := range preview.TemplateErrors {
+			fmt.Printf("  %s\n", templateError)
+		}
+		fmt.Println()
+	}
+
+	if len(preview.VisibilityChanges) > 0 {
+		w := NewTabWriter()
+		fmtColumns := "%s\t%s\t%s\n"
+		fmt.Fprintf(w, fmtColumns, "GROUP", "ITEM", "VISIBILITY")
+		for _, change := range preview.VisibilityChanges {
+			visibility := "hidden"
+			if change.Visible {
+				visibility = "shown"
+			}
+			fmt.Fprintf(w, fmtColumns, change.Group, change.Item, visibility)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	if len(preview.TemplateErrors) > 0 {
+		return
+	}
+
+	if len(preview.Diffs) == 0 {
+		if preview.DeployedSequence == -1 {
+			fmt.Println("No rendered files")
+		} else {
+			fmt.Printf("No changes to rendered files compared to deployed sequence %d\n", preview.DeployedSequence)
+		}
+		return
+	}
+
+	for _, diff := range preview.Diffs {
+		fmt.Print(diff.Diff)
+	}
+}
@@ -0,0 +1,49 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+)
+
+type DeployReport struct {
+	AppSlug   string                             `json:"appSlug"`
+	Sequence  int64                              `json:"sequence"`
+	Resources []downstreamtypes.DeployedResource `json:"resources"`
+}
+
+func DeployedResources(report DeployReport, format string) {
+	switch format {
+	case "json":
+		printDeployReportJSON(report)
+	default:
+		printDeployReportTable(report)
+	}
+}
+
+func printDeployReportJSON(report DeployReport) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printDeployReportTable(report DeployReport) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "PHASE", "KIND", "NAMESPACE", "NAME", "ACTION", "ERROR")
+	for _, r := range report.Resources {
+		kind := r.Kind
+		if r.Group != "" {
+			kind = fmt.Sprintf("%s.%s", r.Kind, r.Group)
+		}
+
+		errorMessage := ""
+		if r.Error != nil {
+			errorMessage = r.Error.Message
+		}
+
+		fmt.Fprintf(w, fmtColumns, r.Phase, kind, r.Namespace, r.Name, r.Action, errorMessage)
+	}
+}
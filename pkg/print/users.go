@@ -0,0 +1,38 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+)
+
+func LocalUsers(users []*usertypes.LocalUser, format string) {
+	switch format {
+	case "json":
+		printLocalUsersJSON(users)
+	default:
+		printLocalUsersTable(users)
+	}
+}
+
+func printLocalUsersJSON(users []*usertypes.LocalUser) {
+	str, _ := json.MarshalIndent(users, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printLocalUsersTable(users []*usertypes.LocalUser) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "USERNAME", "ROLES", "MUST CHANGE PASSWORD", "LAST LOGIN")
+	for _, u := range users {
+		lastLogin := "never"
+		if u.LastLoginAt != nil {
+			lastLogin = u.LastLoginAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, fmtColumns, u.Username, strings.Join(u.RoleIDs, ","), fmt.Sprintf("%t", u.MustChangePassword), lastLogin)
+	}
+}
@@ -0,0 +1,41 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+)
+
+func Versions(versions []downstreamtypes.DownstreamVersion, format string) {
+	switch format {
+	case "json":
+		printVersionsJSON(versions)
+	default:
+		printVersionsTable(versions)
+	}
+}
+
+func printVersionsJSON(versions []downstreamtypes.DownstreamVersion) {
+	str, _ := json.MarshalIndent(versions, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printVersionsTable(versions []downstreamtypes.DownstreamVersion) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%d\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "SEQUENCE", "VERSION", "STATUS", "SOURCE", "CREATED", "DEPLOYED")
+	for _, v := range versions {
+		fmt.Fprintf(w, fmtColumns, v.Sequence, v.VersionLabel, v.Status, v.Source, formatVersionTime(v.CreatedOn), formatVersionTime(v.DeployedAt))
+	}
+}
+
+func formatVersionTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
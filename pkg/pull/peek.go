@@ -19,6 +19,8 @@ type GetUpdatesOptions struct {
 	CurrentChannelName  string
 	CurrentVersionLabel string
 	ReportingInfo       *reportingtypes.ReportingInfo
+	GitAuth             upstreamtypes.GitAuth
+	ReleasePublicKey    string
 	Silent              bool
 }
 
@@ -41,6 +43,8 @@ func GetUpdates(upstreamURI string, getUpdatesOptions GetUpdatesOptions) ([]upst
 	fetchOptions.CurrentChannelName = getUpdatesOptions.CurrentChannelName
 	fetchOptions.CurrentVersionLabel = getUpdatesOptions.CurrentVersionLabel
 	fetchOptions.ReportingInfo = getUpdatesOptions.ReportingInfo
+	fetchOptions.GitAuth = getUpdatesOptions.GitAuth
+	fetchOptions.ReleasePublicKey = getUpdatesOptions.ReleasePublicKey
 
 	if getUpdatesOptions.License != nil {
 		fetchOptions.License = getUpdatesOptions.License
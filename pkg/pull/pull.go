@@ -67,6 +67,8 @@ type PullOptions struct {
 	NoProxyEnvValue        string
 	ReportingInfo          *reportingtypes.ReportingInfo
 	IdentityPostgresConfig *kotsv1beta1.IdentityPostgresConfig
+	GitAuth                upstreamtypes.GitAuth
+	ReleasePublicKey       string
 }
 
 type RewriteImageOptions struct {
@@ -134,7 +136,9 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 			Password:  pullOptions.RewriteImageOptions.Password,
 			ReadOnly:  pullOptions.RewriteImageOptions.IsReadOnly,
 		},
-		ReportingInfo: pullOptions.ReportingInfo,
+		ReportingInfo:    pullOptions.ReportingInfo,
+		GitAuth:          pullOptions.GitAuth,
+		ReleasePublicKey: pullOptions.ReleasePublicKey,
 	}
 
 	var installation *kotsv1beta1.Installation
@@ -85,6 +85,50 @@ func CheckAccess(ctx context.Context, roles []types.Role, action, resource strin
 	return false, nil
 }
 
+// ValidateRoles evaluates every policy of the roles with the rbac module so that invalid glob patterns
+// are rejected when the roles are saved rather than silently denying access when they are used
+func ValidateRoles(ctx context.Context, roles []types.Role) error {
+	for _, role := range roles {
+		policies := append(append([]types.Policy{}, role.Allow...), role.Deny...)
+		for _, policy := range policies {
+			if policy.Action == "" || policy.Resource == "" {
+				return errors.Errorf("role %s: policy action and resource are required", role.ID)
+			}
+
+			matchesAction := false
+			for _, action := range []string{"read", "write"} {
+				allow, err := evalPolicyStrict(ctx, types.Policy{Action: policy.Action, Resource: "**"}, action, "app")
+				if err != nil {
+					return errors.Wrapf(err, "role %s: invalid policy action %q", role.ID, policy.Action)
+				}
+				matchesAction = matchesAction || allow
+			}
+			if !matchesAction {
+				return errors.Errorf("role %s: policy action %q matches neither read nor write", role.ID, policy.Action)
+			}
+
+			if _, err := evalPolicyStrict(ctx, types.Policy{Action: "**", Resource: policy.Resource}, "read", "app"); err != nil {
+				return errors.Wrapf(err, "role %s: invalid policy resource %q", role.ID, policy.Resource)
+			}
+		}
+	}
+
+	return nil
+}
+
+// evalPolicyStrict evaluates a single allow policy and returns an error for invalid glob patterns,
+// which would otherwise just evaluate to undefined
+func evalPolicyStrict(ctx context.Context, policy types.Policy, action string, resource string) (bool, error) {
+	i := map[string]interface{}{
+		"action":            action,
+		"resource":          resource,
+		"roles":             []string{"validate"},
+		"allowRolePolicies": map[string][]types.Policy{"validate": {policy}},
+		"denyRolePolicies":  map[string][]types.Policy{},
+	}
+	return regoEval(ctx, i, rego.StrictBuiltinErrors(true))
+}
+
 func roleToAllowRolePolicies(role types.Role) map[string][]types.Policy {
 	return map[string][]types.Policy{
 		role.ID: role.Allow,
@@ -97,12 +141,13 @@ func roleToDenyRolePolicies(role types.Role) map[string][]types.Policy {
 	}
 }
 
-func regoEval(ctx context.Context, input map[string]interface{}) (bool, error) {
-	query := rego.New(
+func regoEval(ctx context.Context, input map[string]interface{}, opts ...func(*rego.Rego)) (bool, error) {
+	opts = append([]func(*rego.Rego){
 		rego.Query("data.rbac.allow"),
 		rego.Compiler(compiler),
 		rego.Input(input),
-	)
+	}, opts...)
+	query := rego.New(opts...)
 	results, err := query.Eval(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to evaluate query")
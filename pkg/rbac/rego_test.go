@@ -295,3 +295,72 @@ func TestCheckAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []types.Role
+		wantErr bool
+	}{
+		{
+			name:    "default roles",
+			roles:   DefaultRoles(),
+			wantErr: false,
+		},
+		{
+			name: "deployer",
+			roles: []types.Role{
+				{
+					ID: "deployer",
+					Allow: []types.Policy{
+						PolicyReadonly,
+						{Action: "write", Resource: "app.*.downstream."},
+					},
+					Deny: []types.Policy{
+						{Action: "write", Resource: "app.*.downstream.config."},
+						{Action: "**", Resource: "app.*.registry.**"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid resource glob",
+			roles: []types.Role{
+				{
+					ID:    "broken",
+					Allow: []types.Policy{{Action: "read", Resource: "app.[my-app"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown action",
+			roles: []types.Role{
+				{
+					ID:    "deploy-only",
+					Allow: []types.Policy{{Action: "deploy", Resource: "**"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing resource",
+			roles: []types.Role{
+				{
+					ID:   "empty",
+					Deny: []types.Policy{{Action: "read"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoles(context.Background(), tt.roles)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -81,7 +81,7 @@ func RewriteImages(appID string, sequence int64, hostname string, username strin
 		return "", errors.Wrap(err, "failed to load installation from path")
 	}
 
-	license, err := kotsutil.LoadLicenseFromPath(filepath.Join(appDir, "upstream", "userdata", "license.yaml"))
+	license, err := kotsutil.LoadLicenseFromPathIfExists(filepath.Join(appDir, "upstream", "userdata", "license.yaml"))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to load license from path")
 	}
@@ -128,9 +128,15 @@ func RewriteImages(appID string, sequence int64, hostname string, username strin
 		pipeReader.CloseWithError(scanner.Err())
 	}()
 
+	// the upstream is read from the archive, so the replicated scheme works for apps from any upstream
+	upstreamSlug := a.Slug
+	if license != nil {
+		upstreamSlug = license.Spec.AppSlug
+	}
+
 	options := rewrite.RewriteOptions{
 		RootDir:            appDir,
-		UpstreamURI:        fmt.Sprintf("replicated://%s", license.Spec.AppSlug),
+		UpstreamURI:        fmt.Sprintf("replicated://%s", upstreamSlug),
 		UpstreamPath:       filepath.Join(appDir, "upstream"),
 		Installation:       installation,
 		Downstreams:        downstreamNames,
@@ -88,15 +88,16 @@ func NewBuilder(kotsKinds *kotsutil.KotsKinds, registrySettings registrytypes.Re
 	versionInfo := template.VersionInfoFromInstallation(sequence, isAirgap, kotsKinds.Installation.Spec)
 
 	builderOptions := template.BuilderOptions{
-		ConfigGroups:    configGroups,
-		ExistingValues:  templateContextValues,
-		LocalRegistry:   localRegistry,
-		Cipher:          appCipher,
-		License:         kotsKinds.License,
-		ApplicationInfo: &appInfo,
-		VersionInfo:     &versionInfo,
-		IdentityConfig:  kotsKinds.IdentityConfig,
-		Namespace:       namespace,
+		ConfigGroups:     configGroups,
+		ExistingValues:   templateContextValues,
+		LocalRegistry:    localRegistry,
+		Cipher:           appCipher,
+		License:          kotsKinds.License,
+		ApplicationInfo:  &appInfo,
+		VersionInfo:      &versionInfo,
+		IdentityConfig:   kotsKinds.IdentityConfig,
+		Namespace:        namespace,
+		LookupNamespaces: kotsKinds.KotsApplication.Spec.TemplateLookupNamespaces,
 	}
 	builder, _, err := template.NewBuilder(builderOptions)
 	return &builder, errors.Wrap(err, "failed to create builder")
@@ -114,7 +115,7 @@ func RenderDir(archiveDir string, a *apptypes.App, downstreams []downstreamtypes
 		return errors.Wrap(err, "failed to load installation from path")
 	}
 
-	license, err := kotsutil.LoadLicenseFromPath(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"))
+	license, err := kotsutil.LoadLicenseFromPathIfExists(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"))
 	if err != nil {
 		return errors.Wrap(err, "failed to load license from path")
 	}
@@ -134,9 +135,15 @@ func RenderDir(archiveDir string, a *apptypes.App, downstreams []downstreamtypes
 		appNamespace = os.Getenv("KOTSADM_TARGET_NAMESPACE")
 	}
 
+	// the upstream is read from the archive, so the replicated scheme works for apps from any upstream
+	upstreamSlug := a.Slug
+	if license != nil {
+		upstreamSlug = license.Spec.AppSlug
+	}
+
 	reOptions := rewrite.RewriteOptions{
 		RootDir:            archiveDir,
-		UpstreamURI:        fmt.Sprintf("replicated://%s", license.Spec.AppSlug),
+		UpstreamURI:        fmt.Sprintf("replicated://%s", upstreamSlug),
 		UpstreamPath:       filepath.Join(archiveDir, "upstream"),
 		Installation:       installation,
 		Downstreams:        downstreamNames,
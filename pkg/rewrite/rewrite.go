@@ -372,7 +372,8 @@ func writeMidstream(writeMidstreamOptions midstream.WriteOptions, options Rewrit
 
 		images = copyResult.Images
 		objects = affectedObjects
-	} else {
+	} else if license != nil {
+		// apps without a license (from git or http upstreams) cannot pull from the replicated registry
 		application, err := upstream.LoadApplication(upstreamDir)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to load application")
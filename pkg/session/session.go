@@ -10,6 +10,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/apitoken"
 	"github.com/replicatedhq/kots/pkg/identity"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/session/types"
@@ -58,6 +59,7 @@ func Parse(kotsStore store.Store, signedToken string) (*types.Session, error) {
 
 		s := types.Session{
 			ID:        "kots-cli",
+			UserID:    "kots-cli",
 			IssuedAt:  time.Now(),
 			ExpiresAt: time.Now().Add(time.Minute),
 			// TODO: super user permissions
@@ -68,6 +70,10 @@ func Parse(kotsStore store.Store, signedToken string) (*types.Session, error) {
 		return &s, nil
 	}
 
+	if apitoken.IsAPIToken(tokenParts[1]) {
+		return apitoken.Authenticate(kotsStore, tokenParts[1])
+	}
+
 	token, err := jwt.Parse(tokenParts[1], func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
@@ -4,6 +4,7 @@ import "time"
 
 type Session struct {
 	ID        string
+	UserID    string
 	IssuedAt  time.Time
 	ExpiresAt time.Time
 	Roles     []string
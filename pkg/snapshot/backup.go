@@ -1,6 +1,7 @@
 package snapshot
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
@@ -18,11 +20,23 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// DefaultVerifyInstanceBackupTimeout leaves time for preflights after the 30 minute verification timeout of kotsadm
+const DefaultVerifyInstanceBackupTimeout = time.Hour
+
 type CreateInstanceBackupOptions struct {
 	Namespace string
 	Wait      bool
 }
 
+type VerifyInstanceBackupOptions struct {
+	Namespace     string
+	BackupName    string
+	RunPreflights bool
+	Wait          bool
+	// Timeout bounds how long to wait for the verification when Wait is set, DefaultVerifyInstanceBackupTimeout if zero
+	Timeout time.Duration
+}
+
 type ListInstanceBackupsOptions struct {
 	Namespace string
 }
@@ -158,6 +172,135 @@ func CreateInstanceBackup(ctx context.Context, options CreateInstanceBackupOptio
 	return nil
 }
 
+func VerifyInstanceBackup(ctx context.Context, options VerifyInstanceBackupOptions) error {
+	log := logger.NewCLILogger()
+	log.ActionWithSpinner("Connecting to cluster")
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	podName, err := k8sutil.FindKotsadm(clientset, options.Namespace)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to find kotsadm pod")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, errChan, err := k8sutil.PortForward(0, 3000, options.Namespace, podName, false, stopCh, log)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	log.FinishSpinner()
+	log.ActionWithSpinner("Verifying Backup")
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, options.Namespace)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to get kotsadm auth slug")
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"runPreflights": options.RunPreflights,
+	})
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/snapshot/%s/verify", localPort, options.BackupName)
+
+	newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to create verify backup request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(newRequest)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to post to kotsadm")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to read server response")
+	}
+
+	type VerifyBackupResponse struct {
+		Success        bool   `json:"success"`
+		VerificationID string `json:"verificationId,omitempty"`
+		Error          string `json:"error,omitempty"`
+	}
+	var verifyBackupResponse VerifyBackupResponse
+	if err := json.Unmarshal(respBody, &verifyBackupResponse); err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrapf(err, "unexpected status code from %s: %s", url, resp.Status)
+	}
+
+	if verifyBackupResponse.Error != "" {
+		log.FinishSpinnerWithError()
+		return errors.New(verifyBackupResponse.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.FinishSpinnerWithError()
+		return errors.Errorf("unexpected status code from %s: %s", url, resp.Status)
+	}
+
+	if !options.Wait {
+		log.FinishSpinner()
+		log.ActionWithoutSpinner(fmt.Sprintf("Verification of backup %s is in progress", options.BackupName))
+		return nil
+	}
+
+	if verifyBackupResponse.VerificationID == "" {
+		log.FinishSpinnerWithError()
+		return errors.New("kotsadm did not return a verification id, run with --wait=false and check the backup for the result")
+	}
+
+	backup, err := waitForBackupVerified(ctx, clientset, options.BackupName, options.Namespace, verifyBackupResponse.VerificationID, options.Timeout)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return errors.Wrap(err, "failed to wait for backup verification")
+	}
+
+	message := backup.Annotations[snapshottypes.VerificationMessageAnnotation]
+	switch backup.Annotations[snapshottypes.VerificationStatusAnnotation] {
+	case string(snapshottypes.VerificationPassed):
+	case string(snapshottypes.VerificationUnverified):
+		log.FinishSpinnerWithError()
+		return errors.Errorf("backup could not be verified: %s", message)
+	default:
+		log.FinishSpinnerWithError()
+		return errors.Errorf("backup verification failed: %s", message)
+	}
+
+	log.FinishSpinner()
+	log.ActionWithoutSpinner(fmt.Sprintf("Backup %s verified successfully: %s", options.BackupName, message))
+
+	return nil
+}
+
 func ListInstanceBackups(ctx context.Context, options ListInstanceBackupsOptions) ([]velerov1.Backup, error) {
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
@@ -242,3 +385,59 @@ func waitForVeleroBackupCompleted(ctx context.Context, clientset kubernetes.Inte
 		time.Sleep(time.Second)
 	}
 }
+
+// waitForBackupVerified waits for the result of the verification with the id to be recorded on the backup
+func waitForBackupVerified(ctx context.Context, clientset kubernetes.Interface, backupName string, namespace string, verificationID string, timeout time.Duration) (*velerov1.Backup, error) {
+	veleroNamespace, err := DetectVeleroNamespace(ctx, clientset, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detect velero namespace")
+	}
+	if veleroNamespace == "" {
+		return nil, errors.New("velero not found")
+	}
+
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create clientset")
+	}
+
+	if timeout == 0 {
+		timeout = DefaultVerifyInstanceBackupTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		backup, err := veleroClient.Backups(veleroNamespace).Get(ctx, backupName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get backup")
+		}
+
+		if isBackupVerificationFinished(backup, verificationID) {
+			return backup, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("timed out waiting for verification %s, check the kotsadm logs for errors", verificationID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// isBackupVerificationFinished returns true if the result of the verification with the id is recorded on the backup
+func isBackupVerificationFinished(backup *velerov1.Backup, verificationID string) bool {
+	if backup.Annotations[snapshottypes.VerificationIDAnnotation] != verificationID {
+		return false
+	}
+	status := backup.Annotations[snapshottypes.VerificationStatusAnnotation]
+	return status != "" && status != string(snapshottypes.VerificationRunning)
+}
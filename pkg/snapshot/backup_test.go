@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"testing"
+
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_isBackupVerificationFinished(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name:        "not verified",
+			annotations: nil,
+			want:        false,
+		},
+		{
+			name: "running",
+			annotations: map[string]string{
+				snapshottypes.VerificationIDAnnotation:     "verification",
+				snapshottypes.VerificationStatusAnnotation: string(snapshottypes.VerificationRunning),
+			},
+			want: false,
+		},
+		{
+			name: "result of another verification",
+			annotations: map[string]string{
+				snapshottypes.VerificationIDAnnotation:     "previous",
+				snapshottypes.VerificationStatusAnnotation: string(snapshottypes.VerificationPassed),
+			},
+			want: false,
+		},
+		{
+			name: "finished",
+			annotations: map[string]string{
+				snapshottypes.VerificationIDAnnotation:     "verification",
+				snapshottypes.VerificationStatusAnnotation: string(snapshottypes.VerificationFailed),
+			},
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backup := &velerov1.Backup{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			assert.Equal(t, test.want, isBackupVerificationFinished(backup, "verification"))
+		})
+	}
+}
@@ -0,0 +1,695 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gcpstorage "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/snapshot/types"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// velero keeps backup metadata under "backups/" and restic repositories under "restic/" in the store prefix
+var migratedStorePrefixes = []string{"backups/", "restic/"}
+
+const storeMigrationProgressInterval = 50
+
+type MigrateStoreOptions struct {
+	// If set to true, stores that are only reachable from inside the cluster will be accessed through a port forward
+	PortForward bool
+	ProgressFn  func(progress StoreMigrationProgress)
+}
+
+type StoreMigrationProgress struct {
+	TotalObjects   int
+	CopiedObjects  int
+	SkippedObjects int
+	CopiedBytes    int64
+}
+
+func (p StoreMigrationProgress) String() string {
+	return fmt.Sprintf("Copied %d of %d objects (%d already present)", p.CopiedObjects+p.SkippedObjects, p.TotalObjects, p.SkippedObjects)
+}
+
+// objectInfo is what a store reports about an object when listing
+type objectInfo struct {
+	Size int64
+	// MD5 is the hex encoded md5 of the object's content, or empty if the store does not report one
+	// (e.g. s3 multipart uploads or azure blobs uploaded without a content md5)
+	MD5 string
+}
+
+// objectStore is the minimal set of operations needed to copy velero data between backup storage locations
+type objectStore interface {
+	// ListObjects returns every object under prefix, keyed by object key
+	ListObjects(ctx context.Context, prefix string) (map[string]objectInfo, error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, body io.Reader, size int64) error
+}
+
+// MigrateStore copies velero backups and restic repositories from one store to another and verifies the copy.
+// Every copied object is read back from the destination and its sha256 compared with the source's.
+// Objects that already exist in the destination are not copied again, so a failed migration can be retried. These are
+// compared by md5 when both stores report one, and only by size otherwise.
+func MigrateStore(ctx context.Context, source *types.Store, destination *types.Store, options MigrateStoreOptions) error {
+	if source == nil || destination == nil {
+		return errors.New("source and destination stores are required")
+	}
+
+	if storeLocation(source) == storeLocation(destination) {
+		return nil
+	}
+
+	if source.FileSystem != nil && destination.FileSystem != nil {
+		return &InvalidStoreDataError{Message: "cannot migrate backups between file system configurations"}
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	sourceObjectStore, err := getObjectStore(ctx, source, options.PortForward, stopCh)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to source store")
+	}
+
+	destinationObjectStore, err := getObjectStore(ctx, destination, options.PortForward, stopCh)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to destination store")
+	}
+
+	return migrateObjects(ctx, sourceObjectStore, destinationObjectStore, storeKeyPrefix(source.Path), storeKeyPrefix(destination.Path), options)
+}
+
+func migrateObjects(ctx context.Context, sourceObjectStore objectStore, destinationObjectStore objectStore, sourceRoot string, destinationRoot string, options MigrateStoreOptions) error {
+	sourceObjects, destinationObjects, err := listMigratedObjects(ctx, sourceObjectStore, destinationObjectStore, sourceRoot, destinationRoot)
+	if err != nil {
+		return errors.Wrap(err, "failed to list objects")
+	}
+
+	toCopy := planStoreMigration(sourceObjects, destinationObjects, sourceRoot, destinationRoot)
+
+	progress := StoreMigrationProgress{
+		TotalObjects:   len(sourceObjects),
+		SkippedObjects: len(sourceObjects) - len(toCopy),
+	}
+	reportStoreMigrationProgress(options, progress)
+
+	for i, key := range toCopy {
+		destinationKey := destinationRoot + strings.TrimPrefix(key, sourceRoot)
+		if err := copyObject(ctx, sourceObjectStore, destinationObjectStore, key, destinationKey, sourceObjects[key].Size); err != nil {
+			return errors.Wrapf(err, "failed to copy %s", key)
+		}
+
+		progress.CopiedObjects++
+		progress.CopiedBytes += sourceObjects[key].Size
+		if (i+1)%storeMigrationProgressInterval == 0 {
+			reportStoreMigrationProgress(options, progress)
+		}
+	}
+	reportStoreMigrationProgress(options, progress)
+
+	// list the destination again rather than trusting the uploads so that the store is only switched over once everything is there
+	_, destinationObjects, err = listMigratedObjects(ctx, nil, destinationObjectStore, sourceRoot, destinationRoot)
+	if err != nil {
+		return errors.Wrap(err, "failed to list destination objects")
+	}
+
+	if err := verifyStoreMigration(sourceObjects, destinationObjects, sourceRoot, destinationRoot); err != nil {
+		return errors.Wrap(err, "failed to verify migrated objects")
+	}
+
+	return nil
+}
+
+func reportStoreMigrationProgress(options MigrateStoreOptions, progress StoreMigrationProgress) {
+	if options.ProgressFn != nil {
+		options.ProgressFn(progress)
+	}
+}
+
+func listMigratedObjects(ctx context.Context, source objectStore, destination objectStore, sourceRoot string, destinationRoot string) (map[string]objectInfo, map[string]objectInfo, error) {
+	sourceObjects := map[string]objectInfo{}
+	destinationObjects := map[string]objectInfo{}
+
+	for _, prefix := range migratedStorePrefixes {
+		if source != nil {
+			objects, err := source.ListObjects(ctx, sourceRoot+prefix)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to list source objects under %s", sourceRoot+prefix)
+			}
+			for key, info := range objects {
+				sourceObjects[key] = info
+			}
+		}
+
+		objects, err := destination.ListObjects(ctx, destinationRoot+prefix)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to list destination objects under %s", destinationRoot+prefix)
+		}
+		for key, info := range objects {
+			destinationObjects[key] = info
+		}
+	}
+
+	return sourceObjects, destinationObjects, nil
+}
+
+// planStoreMigration returns the source keys that are missing from the destination or differ in size or md5
+func planStoreMigration(sourceObjects map[string]objectInfo, destinationObjects map[string]objectInfo, sourceRoot string, destinationRoot string) []string {
+	toCopy := []string{}
+	for key, info := range sourceObjects {
+		destinationKey := destinationRoot + strings.TrimPrefix(key, sourceRoot)
+		if destinationInfo, ok := destinationObjects[destinationKey]; ok && sameObject(info, destinationInfo) {
+			continue
+		}
+		toCopy = append(toCopy, key)
+	}
+	sort.Strings(toCopy)
+	return toCopy
+}
+
+// sameObject compares objects by md5 when both stores report one, and by size otherwise
+func sameObject(a objectInfo, b objectInfo) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	if a.MD5 != "" && b.MD5 != "" {
+		return a.MD5 == b.MD5
+	}
+	return true
+}
+
+// verifyStoreMigration checks that every source object is in the destination with the same size.
+// the content of copied objects has already been compared by copyObject, and md5s are not compared here
+// because not every store reports an md5 of the content (see s3ETagMD5).
+func verifyStoreMigration(sourceObjects map[string]objectInfo, destinationObjects map[string]objectInfo, sourceRoot string, destinationRoot string) error {
+	missing := []string{}
+	for key, info := range sourceObjects {
+		destinationKey := destinationRoot + strings.TrimPrefix(key, sourceRoot)
+		if destinationInfo, ok := destinationObjects[destinationKey]; !ok || destinationInfo.Size != info.Size {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if len(missing) > 5 {
+		return errors.Errorf("%d objects are missing or incomplete in the destination store, including %s", len(missing), strings.Join(missing[:5], ", "))
+	}
+	return errors.Errorf("objects are missing or incomplete in the destination store: %s", strings.Join(missing, ", "))
+}
+
+// copyObject copies an object and reads it back from the destination to make sure the content matches
+func copyObject(ctx context.Context, source objectStore, destination objectStore, sourceKey string, destinationKey string, size int64) error {
+	body, err := source.GetObject(ctx, sourceKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to get object")
+	}
+	defer body.Close()
+
+	sourceHash := sha256.New()
+	if err := destination.PutObject(ctx, destinationKey, io.TeeReader(body, sourceHash), size); err != nil {
+		return errors.Wrap(err, "failed to put object")
+	}
+
+	destinationChecksum, err := objectChecksum(ctx, destination, destinationKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to read back copied object")
+	}
+	if !bytes.Equal(sourceHash.Sum(nil), destinationChecksum) {
+		return errors.Errorf("checksum of %s does not match the source", destinationKey)
+	}
+
+	return nil
+}
+
+func objectChecksum(ctx context.Context, store objectStore, key string) ([]byte, error) {
+	body, err := store.GetObject(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return nil, errors.Wrap(err, "failed to read object")
+	}
+	return hash.Sum(nil), nil
+}
+
+// storeKeyPrefix returns the object key prefix velero uses for a backup storage location prefix
+func storeKeyPrefix(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return ""
+	}
+	return path + "/"
+}
+
+// storeLocation uniquely identifies where a store keeps its data
+func storeLocation(store *types.Store) string {
+	endpoint := ""
+	switch {
+	case store.Other != nil:
+		endpoint = store.Other.Endpoint
+	case store.Internal != nil:
+		endpoint = store.Internal.Endpoint
+	case store.FileSystem != nil:
+		endpoint = store.FileSystem.Endpoint
+	case store.Azure != nil:
+		endpoint = store.Azure.StorageAccount
+	}
+	return strings.Join([]string{store.Provider, endpoint, store.Bucket, strings.Trim(store.Path, "/")}, "|")
+}
+
+func getObjectStore(ctx context.Context, store *types.Store, portForward bool, stopCh <-chan struct{}) (objectStore, error) {
+	switch {
+	case store.AWS != nil:
+		s3Config := &aws.Config{
+			Region:           aws.String(store.AWS.Region),
+			DisableSSL:       aws.Bool(false),
+			S3ForcePathStyle: aws.Bool(false),
+		}
+		if store.AWS.UseInstanceRole {
+			s3Config.Credentials = credentials.NewChainCredentials([]credentials.Provider{
+				&ec2rolecreds.EC2RoleProvider{
+					Client:       ec2metadata.New(session.New()),
+					ExpiryWindow: 5 * time.Minute,
+				},
+			})
+		} else {
+			s3Config.Credentials = credentials.NewStaticCredentials(store.AWS.AccessKeyID, store.AWS.SecretAccessKey, "")
+		}
+		return newS3ObjectStore(s3Config, store.Bucket, store.CACertData)
+
+	case store.Other != nil:
+		return newS3CompatibleObjectStore(store.Other.Endpoint, store.Other.Region, store.Other.AccessKeyID, store.Other.SecretAccessKey, store.Bucket, store.CACertData)
+
+	case store.Internal != nil:
+		endpoint := store.Internal.Endpoint
+		if portForward {
+			forwardedEndpoint, err := forwardStoreEndpoint(ctx, endpoint, store.Internal.ObjectStoreClusterIP, stopCh)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to forward internal store endpoint")
+			}
+			endpoint = forwardedEndpoint
+		}
+		return newS3CompatibleObjectStore(endpoint, store.Internal.Region, store.Internal.AccessKeyID, store.Internal.SecretAccessKey, store.Bucket, nil)
+
+	case store.FileSystem != nil:
+		endpoint := store.FileSystem.Endpoint
+		if portForward {
+			forwardedEndpoint, err := forwardStoreEndpoint(ctx, endpoint, store.FileSystem.ObjectStoreClusterIP, stopCh)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to forward file system store endpoint")
+			}
+			endpoint = forwardedEndpoint
+		}
+		return newS3CompatibleObjectStore(endpoint, store.FileSystem.Region, store.FileSystem.AccessKeyID, store.FileSystem.SecretAccessKey, store.Bucket, nil)
+
+	case store.Google != nil:
+		var client *gcpstorage.Client
+		var err error
+		if store.Google.UseInstanceRole {
+			client, err = gcpstorage.NewClient(ctx)
+		} else {
+			client, err = gcpstorage.NewClient(ctx, option.WithCredentialsJSON([]byte(store.Google.JSONFile)))
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create storage client")
+		}
+		return &gcsObjectStore{bucket: client.Bucket(store.Bucket)}, nil
+
+	case store.Azure != nil:
+		storageClient, err := getAzureStorageClient(ctx, store.Azure)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get storage client")
+		}
+		blobClient := storageClient.GetBlobService()
+		container := blobClient.GetContainerReference(store.Bucket)
+		if container == nil {
+			return nil, errors.Errorf("unable to get container reference for bucket %s", store.Bucket)
+		}
+		return &azureObjectStore{container: container}, nil
+	}
+
+	return nil, errors.New("no valid configuration found")
+}
+
+func newS3CompatibleObjectStore(endpoint string, region string, accessKeyID string, secretAccessKey string, bucket string, caCertData []byte) (objectStore, error) {
+	s3Config := &aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(endpoint),
+		DisableSSL:       aws.Bool(strings.HasPrefix(endpoint, "http://")),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+	if accessKeyID != "" && secretAccessKey != "" {
+		s3Config.Credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")
+	}
+	return newS3ObjectStore(s3Config, bucket, caCertData)
+}
+
+func newS3ObjectStore(s3Config *aws.Config, bucket string, caCertData []byte) (objectStore, error) {
+	if len(caCertData) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caCertData) {
+			return nil, errors.New("failed to parse ca certificate")
+		}
+		s3Config.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+			},
+		}
+	}
+
+	newSession, err := session.NewSession(s3Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+
+	s3Client := s3.New(newSession)
+	return &s3ObjectStore{
+		client:   s3Client,
+		uploader: s3manager.NewUploaderWithClient(s3Client),
+		bucket:   bucket,
+	}, nil
+}
+
+type s3ObjectStore struct {
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
+}
+
+func (s *s3ObjectStore) ListObjects(ctx context.Context, prefix string) (map[string]objectInfo, error) {
+	objects := map[string]objectInfo{}
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	err := s.client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects[aws.StringValue(object.Key)] = objectInfo{
+				Size: aws.Int64Value(object.Size),
+				MD5:  s3ETagMD5(aws.StringValue(object.ETag)),
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list objects")
+	}
+	return objects, nil
+}
+
+// s3ETagMD5 returns the md5 from an etag. the etags of multipart uploads and of objects encrypted with kms
+// are not an md5 of the content. the former are recognized by their "-<parts>" suffix, the latter can only
+// cause an object to be copied again.
+func s3ETagMD5(etag string) string {
+	etag = strings.Trim(etag, `"`)
+	if len(etag) != hex.EncodedLen(md5.Size) {
+		return ""
+	}
+	if _, err := hex.DecodeString(etag); err != nil {
+		return ""
+	}
+	return strings.ToLower(etag)
+}
+
+func (s *s3ObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+	return output.Body, nil
+}
+
+func (s *s3ObjectStore) PutObject(ctx context.Context, key string, body io.Reader, size int64) error {
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to upload object")
+	}
+	return nil
+}
+
+type gcsObjectStore struct {
+	bucket *gcpstorage.BucketHandle
+}
+
+func (s *gcsObjectStore) ListObjects(ctx context.Context, prefix string) (map[string]objectInfo, error) {
+	objects := map[string]objectInfo{}
+	it := s.bucket.Objects(ctx, &gcpstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list objects")
+		}
+		objects[attrs.Name] = objectInfo{
+			Size: attrs.Size,
+			MD5:  hex.EncodeToString(attrs.MD5),
+		}
+	}
+	return objects, nil
+}
+
+func (s *gcsObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create object reader")
+	}
+	return reader, nil
+}
+
+func (s *gcsObjectStore) PutObject(ctx context.Context, key string, body io.Reader, size int64) error {
+	writer := s.bucket.Object(key).NewWriter(ctx)
+	if _, err := io.Copy(writer, body); err != nil {
+		writer.Close()
+		return errors.Wrap(err, "failed to write object")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "failed to close object writer")
+	}
+	return nil
+}
+
+type azureObjectStore struct {
+	container *storage.Container
+}
+
+func (s *azureObjectStore) ListObjects(ctx context.Context, prefix string) (map[string]objectInfo, error) {
+	objects := map[string]objectInfo{}
+	params := storage.ListBlobsParameters{Prefix: prefix}
+	for {
+		res, err := s.container.ListBlobs(params)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list blobs")
+		}
+		for _, blob := range res.Blobs {
+			info := objectInfo{Size: blob.Properties.ContentLength}
+			if contentMD5, err := base64.StdEncoding.DecodeString(blob.Properties.ContentMD5); err == nil && len(contentMD5) == md5.Size {
+				info.MD5 = hex.EncodeToString(contentMD5)
+			}
+			objects[blob.Name] = info
+		}
+		if res.NextMarker == "" {
+			break
+		}
+		params.Marker = res.NextMarker
+	}
+	return objects, nil
+}
+
+func (s *azureObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.container.GetBlobReference(key).Get(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blob")
+	}
+	return reader, nil
+}
+
+func (s *azureObjectStore) PutObject(ctx context.Context, key string, body io.Reader, size int64) error {
+	blob := s.container.GetBlobReference(key)
+	blob.Properties.ContentLength = size
+	if err := blob.CreateBlockBlobFromReader(body, nil); err != nil {
+		return errors.Wrap(err, "failed to create blob")
+	}
+	return nil
+}
+
+// forwardStoreEndpoint port forwards to the service behind an in-cluster object store endpoint
+// and returns the local endpoint to use instead
+func forwardStoreEndpoint(ctx context.Context, endpoint string, clusterIP string, stopCh <-chan struct{}) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse endpoint")
+	}
+
+	port := 80
+	if u.Port() != "" {
+		port, err = strconv.Atoi(u.Port())
+		if err != nil {
+			return "", errors.Wrap(err, "failed to parse endpoint port")
+		}
+	} else if u.Scheme == "https" {
+		port = 443
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	if clusterIP == "" {
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			clusterIP = ip.String()
+		}
+	}
+
+	service, err := findServiceForEndpoint(ctx, clientset, u.Hostname(), clusterIP)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find object store service")
+	}
+
+	pod, err := findReadyPodForService(ctx, clientset, service)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find object store pod")
+	}
+
+	targetPort, err := serviceTargetPort(service, pod, port)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find object store port")
+	}
+
+	localPort, _, err := k8sutil.PortForward(0, targetPort, service.Namespace, pod.Name, false, stopCh, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	return fmt.Sprintf("%s://localhost:%d", u.Scheme, localPort), nil
+}
+
+// findServiceForEndpoint finds a service either by its cluster ip or by a "<service>.<namespace>" hostname
+func findServiceForEndpoint(ctx context.Context, clientset kubernetes.Interface, hostname string, clusterIP string) (*corev1.Service, error) {
+	if clusterIP != "" {
+		services, err := clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list services")
+		}
+		for _, service := range services.Items {
+			if service.Spec.ClusterIP == clusterIP {
+				return &service, nil
+			}
+		}
+		return nil, errors.Errorf("no service found with cluster ip %s", clusterIP)
+	}
+
+	parts := strings.Split(hostname, ".")
+	if len(parts) < 2 {
+		return nil, errors.Errorf("cannot determine service from hostname %s", hostname)
+	}
+
+	service, err := clientset.CoreV1().Services(parts[1]).Get(ctx, parts[0], metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get service")
+	}
+	return service, nil
+}
+
+func findReadyPodForService(ctx context.Context, clientset kubernetes.Interface, service *corev1.Service) (*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	pods, err := clientset.CoreV1().Pods(service.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		ready := true
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				ready = false
+			}
+		}
+		if ready {
+			return &pod, nil
+		}
+	}
+
+	return nil, errors.Errorf("no ready pods found for service %s/%s", service.Namespace, service.Name)
+}
+
+func serviceTargetPort(service *corev1.Service, pod *corev1.Pod, port int) (int, error) {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) != port {
+			continue
+		}
+
+		if servicePort.TargetPort.Type == intstr.Int {
+			if servicePort.TargetPort.IntValue() == 0 {
+				return port, nil
+			}
+			return servicePort.TargetPort.IntValue(), nil
+		}
+
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, errors.Errorf("named port %s not found in pod %s", servicePort.TargetPort.StrVal, pod.Name)
+	}
+
+	return 0, errors.Errorf("port %d not found in service %s/%s", port, service.Namespace, service.Name)
+}
@@ -0,0 +1,146 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/snapshot/types"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryObjectStore struct {
+	objects  map[string][]byte
+	failPuts bool
+	noMD5    bool
+}
+
+func (s *memoryObjectStore) ListObjects(ctx context.Context, prefix string) (map[string]objectInfo, error) {
+	objects := map[string]objectInfo{}
+	for key, data := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			info := objectInfo{Size: int64(len(data))}
+			if !s.noMD5 {
+				sum := md5.Sum(data)
+				info.MD5 = hex.EncodeToString(sum[:])
+			}
+			objects[key] = info
+		}
+	}
+	return objects, nil
+}
+
+func (s *memoryObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.Errorf("%s not found", key)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryObjectStore) PutObject(ctx context.Context, key string, body io.Reader, size int64) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if s.failPuts {
+		// simulate a truncated upload
+		data = data[:len(data)/2]
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func Test_migrateObjects(t *testing.T) {
+	req := require.New(t)
+
+	source := &memoryObjectStore{objects: map[string][]byte{
+		"backups/instance-abcd/velero-backup.json":   []byte(`{"kind":"Backup"}`),
+		"backups/instance-abcd/instance-abcd.tar.gz": []byte("tarball"),
+		"restic/default/config":                      []byte("restic config"),
+		"restores/instance-abcd/restore-logs.gz":     []byte("not migrated"),
+	}}
+	destination := &memoryObjectStore{objects: map[string][]byte{
+		"velero/restic/default/config": []byte("restic config"),
+	}}
+
+	progressUpdates := []StoreMigrationProgress{}
+	options := MigrateStoreOptions{
+		ProgressFn: func(progress StoreMigrationProgress) {
+			progressUpdates = append(progressUpdates, progress)
+		},
+	}
+
+	err := migrateObjects(context.Background(), source, destination, storeKeyPrefix(""), storeKeyPrefix("/velero/"), options)
+	req.NoError(err)
+
+	req.Equal(map[string][]byte{
+		"velero/backups/instance-abcd/velero-backup.json":   []byte(`{"kind":"Backup"}`),
+		"velero/backups/instance-abcd/instance-abcd.tar.gz": []byte("tarball"),
+		"velero/restic/default/config":                      []byte("restic config"),
+	}, destination.objects)
+
+	req.NotEmpty(progressUpdates)
+	req.Equal(StoreMigrationProgress{TotalObjects: 3, CopiedObjects: 2, SkippedObjects: 1, CopiedBytes: 24}, progressUpdates[len(progressUpdates)-1])
+}
+
+func Test_migrateObjectsVerifiesCopy(t *testing.T) {
+	source := &memoryObjectStore{objects: map[string][]byte{
+		"backups/instance-abcd/instance-abcd.tar.gz": []byte("tarball"),
+	}}
+	destination := &memoryObjectStore{objects: map[string][]byte{}, failPuts: true}
+
+	err := migrateObjects(context.Background(), source, destination, "", "", MigrateStoreOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "backups/instance-abcd/instance-abcd.tar.gz")
+}
+
+func Test_migrateObjectsComparesMD5(t *testing.T) {
+	req := require.New(t)
+
+	source := &memoryObjectStore{objects: map[string][]byte{
+		"restic/default/config": []byte("restic config"),
+	}}
+
+	// same size, different content
+	destination := &memoryObjectStore{objects: map[string][]byte{
+		"restic/default/config": []byte("other  config"),
+	}}
+	err := migrateObjects(context.Background(), source, destination, "", "", MigrateStoreOptions{})
+	req.NoError(err)
+	req.Equal([]byte("restic config"), destination.objects["restic/default/config"])
+
+	// without md5s objects of the same size are assumed to be the same
+	destination = &memoryObjectStore{objects: map[string][]byte{
+		"restic/default/config": []byte("other  config"),
+	}, noMD5: true}
+	err = migrateObjects(context.Background(), source, destination, "", "", MigrateStoreOptions{})
+	req.NoError(err)
+	req.Equal([]byte("other  config"), destination.objects["restic/default/config"])
+}
+
+func Test_s3ETagMD5(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal("9e107d9d372bb6826bd81d3542a419d6", s3ETagMD5(`"9E107D9D372BB6826BD81D3542A419D6"`))
+	req.Equal("", s3ETagMD5(`"9e107d9d372bb6826bd81d3542a419d6-3"`))
+	req.Equal("", s3ETagMD5(""))
+}
+
+func Test_storeLocation(t *testing.T) {
+	req := require.New(t)
+
+	s3 := &types.Store{Provider: "aws", Bucket: "snapshots", Path: "/kots/", AWS: &types.StoreAWS{}}
+	req.Equal(storeLocation(s3), storeLocation(&types.Store{Provider: "aws", Bucket: "snapshots", Path: "kots", AWS: &types.StoreAWS{}}))
+	req.NotEqual(storeLocation(s3), storeLocation(&types.Store{Provider: "aws", Bucket: "snapshots", Path: "other", AWS: &types.StoreAWS{}}))
+
+	internal := &types.Store{Provider: "aws", Bucket: "velero", Internal: &types.StoreInternal{Endpoint: "http://rook-ceph-rgw-rook-ceph-store.rook-ceph"}}
+	fileSystem := &types.Store{Provider: "aws", Bucket: "velero", FileSystem: &types.StoreFileSystem{Endpoint: "http://kotsadm-fs-minio.default:9000"}}
+	req.NotEqual(storeLocation(internal), storeLocation(fileSystem))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -19,13 +20,24 @@ import (
 	"github.com/replicatedhq/kots/pkg/logger"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type RestoreInstanceBackupOptions struct {
 	BackupName      string
 	WaitForApps     bool
 	VeleroNamespace string
+
+	// ToCluster indicates that the backup was taken from a different cluster that shares the same snapshot store
+	ToCluster bool
+	// KotsadmNamespace is the namespace to restore the admin console to. defaults to the namespace it was backed up from
+	KotsadmNamespace    string
+	NamespaceMapping    map[string]string
+	StorageClassMapping map[string]string
+	RegistryOptions     *kotsadmtypes.KotsadmOptions
 }
 
 type ListInstanceRestoresOptions struct {
@@ -61,7 +73,13 @@ func RestoreInstanceBackup(ctx context.Context, options RestoreInstanceBackupOpt
 		return nil, errors.Wrap(err, "failed to create velero clientset")
 	}
 
-	backup, err := veleroClient.Backups(veleroNamespace).Get(ctx, options.BackupName, metav1.GetOptions{})
+	var backup *velerov1.Backup
+	if options.ToCluster {
+		// backups taken in another cluster only show up once velero has synced them from the backup storage location
+		backup, err = waitForVeleroBackupSynced(ctx, veleroClient, veleroNamespace, options.BackupName, 2*time.Minute)
+	} else {
+		backup, err = veleroClient.Backups(veleroNamespace).Get(ctx, options.BackupName, metav1.GetOptions{})
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to find backup")
 	}
@@ -76,11 +94,17 @@ func RestoreInstanceBackup(ctx context.Context, options RestoreInstanceBackupOpt
 		return nil, errors.Wrap(err, "failed to find kotsadm image annotation")
 	}
 
-	kotsadmNamespace, _ := backup.Annotations["kots.io/kotsadm-deploy-namespace"]
-	if kotsadmNamespace == "" {
+	sourceKotsadmNamespace, _ := backup.Annotations["kots.io/kotsadm-deploy-namespace"]
+	if sourceKotsadmNamespace == "" {
 		return nil, errors.Wrap(err, "failed to find kotsadm deploy namespace annotation")
 	}
 
+	kotsadmNamespace := sourceKotsadmNamespace
+	if options.KotsadmNamespace != "" {
+		kotsadmNamespace = options.KotsadmNamespace
+	}
+	namespaceMapping := buildRestoreNamespaceMapping(sourceKotsadmNamespace, kotsadmNamespace, options.NamespaceMapping)
+
 	// make sure backup is restorable/complete
 	switch backup.Status.Phase {
 	case velerov1.BackupPhaseCompleted:
@@ -92,6 +116,18 @@ func RestoreInstanceBackup(ctx context.Context, options RestoreInstanceBackupOpt
 	}
 
 	log := logger.NewCLILogger()
+
+	var originalStorageClassMappingConfigMap *corev1.ConfigMap
+	if len(options.StorageClassMapping) > 0 {
+		log.ActionWithSpinner("Configuring storage class mapping")
+		originalStorageClassMappingConfigMap, err = ensureStorageClassMappingConfigMap(ctx, clientset, veleroNamespace, options.StorageClassMapping)
+		if err != nil {
+			log.FinishSpinnerWithError()
+			return nil, errors.Wrap(err, "failed to configure storage class mapping")
+		}
+		log.FinishSpinner()
+	}
+
 	log.ActionWithSpinner("Deleting Admin Console")
 
 	// delete all kotsadm objects before creating the restore
@@ -123,6 +159,7 @@ func RestoreInstanceBackup(ctx context.Context, options RestoreInstanceBackupOpt
 					kotsadmtypes.KotsadmKey: kotsadmtypes.KotsadmLabelValue, // restoring applications is in a separate step after kotsadm spins up
 				},
 			},
+			NamespaceMapping:        namespaceMapping,
 			RestorePVs:              &trueVal,
 			IncludeClusterResources: &trueVal,
 		},
@@ -190,10 +227,30 @@ func RestoreInstanceBackup(ctx context.Context, options RestoreInstanceBackupOpt
 		}
 
 		log.FinishSpinner()
+
+		if options.ToCluster {
+			log.ActionWithSpinner("Redeploying Applications")
+			err := redeployRestoredApplications(backup, kotsadmNamespace, kotsadmPodName, options.RegistryOptions, log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return nil, errors.Wrap(err, "failed to redeploy applications")
+			}
+			log.FinishSpinner()
+		}
+
+		if len(options.StorageClassMapping) > 0 {
+			if err := restoreStorageClassMappingConfigMap(ctx, clientset, veleroNamespace, originalStorageClassMappingConfigMap); err != nil {
+				return nil, errors.Wrap(err, "failed to restore storage class mapping")
+			}
+		}
+
 		log.ActionWithoutSpinner("Restore completed successfully.")
 	} else {
 		log.FinishSpinner()
 		log.ActionWithoutSpinner("Admin Console restored successfully. Applications restore is still in progress.")
+		if options.ToCluster {
+			log.ActionWithoutSpinner("Applications will not be redeployed and registry settings will not be updated until the restore has completed. Re-run with --wait-for-apps to do this automatically.")
+		}
 	}
 
 	return restore, nil
@@ -441,3 +498,277 @@ func waitForKotsadmApplicationsRestore(backupName string, kotsadmNamespace strin
 		time.Sleep(time.Second * 2)
 	}
 }
+
+func waitForVeleroBackupSynced(ctx context.Context, veleroClient veleroclientv1.VeleroV1Interface, veleroNamespace string, backupName string, timeout time.Duration) (*velerov1.Backup, error) {
+	start := time.Now()
+
+	for {
+		backup, err := veleroClient.Backups(veleroNamespace).Get(ctx, backupName, metav1.GetOptions{})
+		if err == nil {
+			return backup, nil
+		}
+		if !kuberneteserrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to get backup")
+		}
+
+		if time.Now().Sub(start) > timeout {
+			return nil, errors.Errorf("backup %s was not synced from the backup storage location within %s", backupName, timeout)
+		}
+
+		time.Sleep(time.Second * 5)
+	}
+}
+
+// buildRestoreNamespaceMapping returns the velero namespace mapping for restoring the admin console.
+// if the admin console is restored to a different namespace than it was backed up from, that namespace is mapped too.
+func buildRestoreNamespaceMapping(sourceKotsadmNamespace string, targetKotsadmNamespace string, namespaceMapping map[string]string) map[string]string {
+	mapping := map[string]string{}
+	for source, target := range namespaceMapping {
+		if source == target {
+			continue
+		}
+		mapping[source] = target
+	}
+
+	if _, ok := mapping[sourceKotsadmNamespace]; !ok && targetKotsadmNamespace != sourceKotsadmNamespace {
+		mapping[sourceKotsadmNamespace] = targetKotsadmNamespace
+	}
+
+	if len(mapping) == 0 {
+		return nil
+	}
+	return mapping
+}
+
+// ensureStorageClassMappingConfigMap configures velero's change-storage-class restore item action
+// https://velero.io/docs/main/restore-reference/#changing-pvpvc-storage-classes
+// The mapping is merged into an existing configmap. The configmap as it was before is returned so that it can be
+// put back with restoreStorageClassMappingConfigMap, or nil if it did not exist.
+func ensureStorageClassMappingConfigMap(ctx context.Context, clientset kubernetes.Interface, veleroNamespace string, storageClassMapping map[string]string) (*corev1.ConfigMap, error) {
+	existingConfigMap, err := clientset.CoreV1().ConfigMaps(veleroNamespace).Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to get existing configmap")
+		}
+
+		newConfigMap := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      StorageClassMappingConfigMapName,
+				Namespace: veleroNamespace,
+				Labels: map[string]string{
+					"velero.io/plugin-config":        "",
+					"velero.io/change-storage-class": "RestoreItemAction",
+				},
+			},
+			Data: storageClassMapping,
+		}
+
+		_, err := clientset.CoreV1().ConfigMaps(veleroNamespace).Create(ctx, newConfigMap, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create configmap")
+		}
+
+		return nil, nil
+	}
+
+	originalConfigMap := existingConfigMap.DeepCopy()
+
+	if existingConfigMap.Data == nil {
+		existingConfigMap.Data = map[string]string{}
+	}
+	for source, target := range storageClassMapping {
+		existingConfigMap.Data[source] = target
+	}
+
+	_, err = clientset.CoreV1().ConfigMaps(veleroNamespace).Update(ctx, existingConfigMap, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update configmap")
+	}
+
+	return originalConfigMap, nil
+}
+
+// restoreStorageClassMappingConfigMap puts back the configmap that was returned by ensureStorageClassMappingConfigMap.
+// The configmap is deleted if it did not exist before.
+func restoreStorageClassMappingConfigMap(ctx context.Context, clientset kubernetes.Interface, veleroNamespace string, originalConfigMap *corev1.ConfigMap) error {
+	if originalConfigMap == nil {
+		err := clientset.CoreV1().ConfigMaps(veleroNamespace).Delete(ctx, StorageClassMappingConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete configmap")
+		}
+		return nil
+	}
+
+	existingConfigMap, err := clientset.CoreV1().ConfigMaps(veleroNamespace).Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get existing configmap")
+		}
+
+		configMap := originalConfigMap.DeepCopy()
+		configMap.ResourceVersion = ""
+		if _, err := clientset.CoreV1().ConfigMaps(veleroNamespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create configmap")
+		}
+		return nil
+	}
+
+	existingConfigMap.Labels = originalConfigMap.Labels
+	existingConfigMap.Data = originalConfigMap.Data
+
+	if _, err := clientset.CoreV1().ConfigMaps(veleroNamespace).Update(ctx, existingConfigMap, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update configmap")
+	}
+
+	return nil
+}
+
+// redeployRestoredApplications redeploys the restored version of each app in the backup
+// and optionally points the apps at a new registry, which creates and deploys a new version.
+func redeployRestoredApplications(backup *velerov1.Backup, kotsadmNamespace string, kotsadmPodName string, registryOptions *kotsadmtypes.KotsadmOptions, log *logger.CLILogger) error {
+	appsSequences := map[string]int64{}
+	if s := backup.Annotations["kots.io/apps-sequences"]; s != "" {
+		if err := json.Unmarshal([]byte(s), &appsSequences); err != nil {
+			return errors.Wrap(err, "failed to unmarshal apps sequences")
+		}
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, errChan, err := k8sutil.PortForward(0, 3000, kotsadmNamespace, kotsadmPodName, false, stopCh, log)
+	if err != nil {
+		return errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, kotsadmNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kotsadm auth slug")
+	}
+
+	for appSlug, sequence := range appsSequences {
+		if registryOptions == nil || registryOptions.OverrideRegistry == "" {
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/redeploy", localPort, appSlug, sequence)
+			if err := doKotsadmRequest("POST", url, authSlug, nil); err != nil {
+				return errors.Wrapf(err, "failed to redeploy app %s", appSlug)
+			}
+			continue
+		}
+
+		// point the restored version at the new registry first. kotsadm creates a new version from it
+		// and deploys that once its preflights pass, so the restored version is deployed only once.
+		url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/registry", localPort, appSlug)
+		requestPayload := map[string]interface{}{
+			"hostname":   registryOptions.OverrideRegistry,
+			"namespace":  registryOptions.OverrideNamespace,
+			"username":   registryOptions.Username,
+			"password":   registryOptions.Password,
+			"isReadOnly": registryOptions.IsReadOnly,
+			"sequence":   sequence,
+			"deploy":     true,
+		}
+		if err := doKotsadmRequest("PUT", url, authSlug, requestPayload); err != nil {
+			return errors.Wrapf(err, "failed to update registry settings for app %s", appSlug)
+		}
+
+		// only one image rewrite can run at a time
+		if err := waitForImageRewrite(localPort, appSlug, authSlug); err != nil {
+			return errors.Wrap(err, "failed to wait for image rewrite")
+		}
+	}
+
+	return nil
+}
+
+func waitForImageRewrite(localPort int, appSlug string, authSlug string) error {
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/imagerewritestatus", localPort, appSlug)
+
+	for {
+		newRequest, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to create request")
+		}
+		newRequest.Header.Add("Authorization", authSlug)
+
+		resp, err := http.DefaultClient.Do(newRequest)
+		if err != nil {
+			return errors.Wrap(err, "failed to get from kotsadm")
+		}
+
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return errors.Wrap(err, "failed to read server response")
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("unexpected status code from %s: %s", url, resp.Status)
+		}
+
+		type ImageRewriteStatusResponse struct {
+			Status         string `json:"status"`
+			CurrentMessage string `json:"currentMessage"`
+		}
+		var imageRewriteStatusResponse ImageRewriteStatusResponse
+		if err := json.Unmarshal(respBody, &imageRewriteStatusResponse); err != nil {
+			return errors.Wrap(err, "failed to unmarshal response")
+		}
+
+		if imageRewriteStatusResponse.Status != "running" {
+			return nil
+		}
+
+		time.Sleep(time.Second * 2)
+	}
+}
+
+func doKotsadmRequest(method string, url string, authSlug string, requestPayload interface{}) error {
+	var body io.Reader
+	if requestPayload != nil {
+		requestBody, err := json.Marshal(requestPayload)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request json")
+		}
+		body = bytes.NewBuffer(requestBody)
+	}
+
+	newRequest, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	if requestPayload != nil {
+		newRequest.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to get from kotsadm")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errors.Errorf("unexpected status code from %s: %s", url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_storageClassMappingConfigMap(t *testing.T) {
+	ctx := context.Background()
+	mapping := map[string]string{"old": "new"}
+
+	t.Run("created and deleted", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+
+		original, err := ensureStorageClassMappingConfigMap(ctx, clientset, "velero", mapping)
+		require.NoError(t, err)
+		require.Nil(t, original)
+
+		configMap, err := clientset.CoreV1().ConfigMaps("velero").Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, mapping, configMap.Data)
+
+		require.NoError(t, restoreStorageClassMappingConfigMap(ctx, clientset, "velero", original))
+
+		_, err = clientset.CoreV1().ConfigMaps("velero").Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+		require.True(t, kuberneteserrors.IsNotFound(err))
+	})
+
+	t.Run("merged and restored", func(t *testing.T) {
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      StorageClassMappingConfigMapName,
+				Namespace: "velero",
+				Labels: map[string]string{
+					"velero.io/plugin-config":        "",
+					"velero.io/change-storage-class": "RestoreItemAction",
+				},
+			},
+			Data: map[string]string{"old": "other", "standard": "gp2"},
+		}
+		clientset := fake.NewSimpleClientset(existing)
+
+		original, err := ensureStorageClassMappingConfigMap(ctx, clientset, "velero", mapping)
+		require.NoError(t, err)
+		require.NotNil(t, original)
+
+		configMap, err := clientset.CoreV1().ConfigMaps("velero").Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"old": "new", "standard": "gp2"}, configMap.Data)
+
+		require.NoError(t, restoreStorageClassMappingConfigMap(ctx, clientset, "velero", original))
+
+		configMap, err = clientset.CoreV1().ConfigMaps("velero").Get(ctx, StorageClassMappingConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"old": "other", "standard": "gp2"}, configMap.Data)
+	})
+}
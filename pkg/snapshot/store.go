@@ -59,6 +59,13 @@ type ConfigureStoreOptions struct {
 	// Will be ignored if SkipValidation is set to true.
 	ValidateUsingAPod bool
 	SkipValidation    bool
+
+	// If set to true, existing backups and restic repositories will be copied to the new store
+	// before velero is switched over to it
+	MigrateBackups bool
+	// If set to true, in-cluster stores will be accessed using a port forward when migrating backups
+	MigrateUsingPortForward bool
+	MigrationProgressFn     func(progress StoreMigrationProgress)
 }
 
 type ValidateStoreOptions struct {
@@ -86,6 +93,15 @@ func ConfigureStore(ctx context.Context, options ConfigureStoreOptions) (*types.
 		return nil, errors.New("store not found")
 	}
 
+	var sourceStore *types.Store
+	if options.MigrateBackups {
+		// the store above is updated in place, so the current store needs to be read again
+		sourceStore, err = GetGlobalStore(ctx, options.KotsadmNamespace, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get current store")
+		}
+	}
+
 	store.Provider = options.Provider
 	store.Bucket = options.Bucket
 	store.Path = options.Path
@@ -296,6 +312,16 @@ func ConfigureStore(ctx context.Context, options ConfigureStoreOptions) (*types.
 		}
 	}
 
+	if options.MigrateBackups {
+		migrateStoreOptions := MigrateStoreOptions{
+			PortForward: options.MigrateUsingPortForward,
+			ProgressFn:  options.MigrationProgressFn,
+		}
+		if err := MigrateStore(ctx, sourceStore, store, migrateStoreOptions); err != nil {
+			return nil, errors.Wrap(err, "failed to migrate backups to the new store")
+		}
+	}
+
 	updatedBackupStorageLocation, err := updateGlobalStore(ctx, store, options.KotsadmNamespace)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to update global store")
@@ -1008,21 +1034,44 @@ func validateAWS(storeAWS *types.StoreAWS, bucket string) error {
 }
 
 func validateAzure(ctx context.Context, storeAzure *types.StoreAzure, bucket string) error {
-	// Mostly copied from Velero Azure plugin
+	storageClient, err := getAzureStorageClient(ctx, storeAzure)
+	if err != nil {
+		return errors.Wrap(err, "failed to get storage client")
+	}
 
+	blobClient := storageClient.GetBlobService()
+	container := blobClient.GetContainerReference(bucket)
+	if container == nil {
+		return errors.Errorf("unable to get container reference for bucket %s", bucket)
+	}
+
+	exists, err := container.Exists()
+	if err != nil {
+		return errors.Wrap(err, "failed to check container existence")
+	}
+
+	if !exists {
+		return errors.New("container does not exist")
+	}
+
+	return nil
+}
+
+// getAzureStorageClient is mostly copied from Velero Azure plugin
+func getAzureStorageClient(ctx context.Context, storeAzure *types.StoreAzure) (*storage.Client, error) {
 	env, err := azure.EnvironmentFromName(storeAzure.CloudName)
 	if err != nil {
-		return errors.Wrap(err, "failed to find azure env")
+		return nil, errors.Wrap(err, "failed to find azure env")
 	}
 
 	oauthConfig, err := adal.NewOAuthConfig(env.ActiveDirectoryEndpoint, storeAzure.TenantID)
 	if err != nil {
-		return errors.Wrap(err, "failed to get OAuthConfig")
+		return nil, errors.Wrap(err, "failed to get OAuthConfig")
 	}
 
 	spt, err := adal.NewServicePrincipalToken(*oauthConfig, storeAzure.ClientID, storeAzure.ClientSecret, env.ResourceManagerEndpoint)
 	if err != nil {
-		return errors.Wrap(err, "failed to get service principal token")
+		return nil, errors.Wrap(err, "failed to get service principal token")
 	}
 
 	storageAccountsClient := storagemgmt.NewAccountsClientWithBaseURI(env.ResourceManagerEndpoint, storeAzure.SubscriptionID)
@@ -1030,10 +1079,10 @@ func validateAzure(ctx context.Context, storeAzure *types.StoreAzure, bucket str
 
 	res, err := storageAccountsClient.ListKeys(ctx, storeAzure.ResourceGroup, storeAzure.StorageAccount)
 	if err != nil {
-		return errors.Wrap(err, "failed to list account keys")
+		return nil, errors.Wrap(err, "failed to list account keys")
 	}
 	if res.Keys == nil || len(*res.Keys) == 0 {
-		return errors.New("No storage keys found")
+		return nil, errors.New("No storage keys found")
 	}
 
 	var storageKey string
@@ -1047,30 +1096,15 @@ func validateAzure(ctx context.Context, storeAzure *types.StoreAzure, bucket str
 	}
 
 	if storageKey == "" {
-		return errors.New("No storage key with Full permissions found")
+		return nil, errors.New("No storage key with Full permissions found")
 	}
 
 	storageClient, err := storage.NewBasicClientOnSovereignCloud(storeAzure.StorageAccount, storageKey, env)
 	if err != nil {
-		return errors.Wrap(err, "failed to get storage client")
+		return nil, errors.Wrap(err, "failed to create storage client")
 	}
 
-	blobClient := storageClient.GetBlobService()
-	container := blobClient.GetContainerReference(bucket)
-	if container == nil {
-		return errors.Errorf("unable to get container reference for bucket %s", bucket)
-	}
-
-	exists, err := container.Exists()
-	if err != nil {
-		return errors.Wrap(err, "failed to check container existence")
-	}
-
-	if !exists {
-		return errors.New("container does not exist")
-	}
-
-	return nil
+	return &storageClient, nil
 }
 
 func validateGCP(storeGoogle *types.StoreGoogle, bucket string) error {
@@ -29,7 +29,8 @@ var (
 )
 
 const (
-	VeleroNamespaceConfigMapName = "kotsadm-velero-namespace"
+	VeleroNamespaceConfigMapName     = "kotsadm-velero-namespace"
+	StorageClassMappingConfigMapName = "change-storage-class-config"
 )
 
 type VeleroStatus struct {
@@ -23,6 +23,7 @@ func Start() error {
 
 	startLoop(appScheduleLoop, 60)
 	startLoop(instanceScheduleLoop, 60)
+	startLoop(verificationScheduleLoop, 60)
 
 	return nil
 }
@@ -67,6 +68,20 @@ func instanceScheduleLoop() {
 	}
 }
 
+func verificationScheduleLoop() {
+	clusters, err := store.GetStore().ListClusters()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list clusters for scheduled backup verification"))
+		return
+	}
+
+	for _, c := range clusters {
+		if err := handleClusterVerification(c); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to handle scheduled backup verification for cluster %s", c.ClusterID))
+		}
+	}
+}
+
 /* App Level Scheduled Snapshots */
 func handleApp(a *apptypes.App) error {
 	if a.SnapshotSchedule == "" {
@@ -83,9 +98,9 @@ func handleApp(a *apptypes.App) error {
 	*
 	* Before taking a snapshot, first check that it's not scheduled for a time in the future, then
 	* check that there is not already another snapshot in progress for the app. If both of those
-	* checks pass, then create the Backup CR for velero, save the Backup name to the row to
-	* mark that it has been handled, then schedule the next snapshot from the app's cron schedule
-	* expression.
+	* checks pass, then prune the backups that are not kept by the app's retention policy, create
+	* the Backup CR for velero, save the Backup name to the row to mark that it has been handled,
+	* then schedule the next snapshot from the app's cron schedule expression.
 	 */
 
 	pending, err := store.GetStore().ListPendingScheduledSnapshots(a.ID)
@@ -120,6 +135,12 @@ func handleApp(a *apptypes.App) error {
 		return nil
 	}
 
+	if !snapshot.IsRetentionPolicyEmpty(a.SnapshotRetention) {
+		if _, err := snapshot.PruneApplicationBackups(context.Background(), util.PodNamespace, a.ID, *a.SnapshotRetention, false); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to prune application backups for app %s", a.ID))
+		}
+	}
+
 	backup, err := snapshot.CreateApplicationBackup(context.Background(), a, true)
 	if err != nil {
 		return errors.Wrap(err, "failed to create backup")
@@ -166,9 +187,9 @@ func handleCluster(c *downstreamtypes.Downstream) error {
 	*
 	* Before taking a snapshot, first check that it's not scheduled for a time in the future, then
 	* check that there is not already another snapshot in progress for the cluster. If both of those
-	* checks pass, then create the Backup CR for velero, save the Backup name to the row to
-	* mark that it has been handled, then schedule the next snapshot from the cluster's cron schedule
-	* expression.
+	* checks pass, then prune the backups that are not kept by the cluster's retention policy, create
+	* the Backup CR for velero, save the Backup name to the row to mark that it has been handled,
+	* then schedule the next snapshot from the cluster's cron schedule expression.
 	 */
 
 	pending, err := store.GetStore().ListPendingScheduledInstanceSnapshots(c.ClusterID)
@@ -203,6 +224,12 @@ func handleCluster(c *downstreamtypes.Downstream) error {
 		return nil
 	}
 
+	if !snapshot.IsRetentionPolicyEmpty(c.SnapshotRetention) {
+		if _, err := snapshot.PruneInstanceBackups(context.Background(), util.PodNamespace, *c.SnapshotRetention, false); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to prune instance backups for cluster %s", c.ClusterID))
+		}
+	}
+
 	backup, err := snapshot.CreateInstanceBackup(context.Background(), c, true)
 	if err != nil {
 		return errors.Wrap(err, "failed to create instance backup")
@@ -233,6 +260,62 @@ func handleCluster(c *downstreamtypes.Downstream) error {
 	return nil
 }
 
+/* Scheduled Backup Verification */
+func handleClusterVerification(c *downstreamtypes.Downstream) error {
+	if c.SnapshotVerification == nil || c.SnapshotVerification.Schedule == "" {
+		return nil
+	}
+
+	/*
+	* Verification does not use a queue table. The verification results are recorded on the backups,
+	* so the next verification is due at the first time in the schedule after the last verification
+	* finished, or after the newest backup started if no backup has been verified yet.
+	*
+	* Only the newest completed instance backup is verified, and only if it has not been verified yet.
+	* Verifications run inline, so this loop is blocked until a verification finishes.
+	 */
+
+	if snapshot.IsVerifyingBackup() {
+		return nil
+	}
+
+	cronSchedule, err := cron.ParseStandard(c.SnapshotVerification.Schedule)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse cron expression")
+	}
+
+	backups, err := snapshot.ListInstanceBackups(context.Background(), util.PodNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list instance backups")
+	}
+
+	backup := snapshot.SelectBackupToVerify(backups)
+	if backup == nil || backup.Verification != nil {
+		return nil
+	}
+
+	since := snapshot.GetLastVerifiedAt(backups)
+	if since == nil {
+		since = backup.StartedAt
+	}
+	if cronSchedule.Next(*since).After(time.Now()) {
+		logger.Debugf("Not yet time to verify instance backups for cluster %s", c.ClusterID)
+		return nil
+	}
+
+	opts := snapshot.VerifyBackupOptions{
+		RunPreflights: c.SnapshotVerification.RunPreflights,
+	}
+	if _, err := snapshot.VerifyBackup(context.Background(), util.PodNamespace, backup.Name, opts); err != nil {
+		if err == snapshot.ErrVerificationInProgress {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to verify backup %s", backup.Name)
+	}
+
+	return nil
+}
+
 func nextScheduledApplicationSnapshot(appID string, cronExpression string) (*snapshottypes.ScheduledSnapshot, error) {
 	cronSchedule, err := cron.ParseStandard(cronExpression)
 	if err != nil {
@@ -0,0 +1,271 @@
+package socketservice
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+)
+
+const defaultHealthWindow = 5 * time.Minute
+
+// defaultUnhealthyGracePeriod is how long a watched version can stay in a failure state before it is
+// rolled back without waiting for the rest of the health window. this gives pods time to start.
+const defaultUnhealthyGracePeriod = 2 * time.Minute
+
+var defaultFailureStates = []appstatustypes.State{
+	appstatustypes.StateDegraded,
+	appstatustypes.StateUnavailable,
+}
+
+// rollbackWatch tracks a deployed version that has automatic rollback enabled
+// until it is either healthy at the end of the health window or rolled back.
+// watches are not stored separately: the deploy time comes from the version's applied_at,
+// so the watch is rebuilt when the version is sent to the operator again after a restart.
+type rollbackWatch struct {
+	clusterID            string
+	sequence             int64
+	deployedAt           time.Time
+	healthWindow         time.Duration
+	unhealthyGracePeriod time.Duration
+	failureStates        []appstatustypes.State
+	unhealthySince       time.Time
+}
+
+var rollbackWatches = map[string]*rollbackWatch{}
+
+// rollbackTargets holds the sequences that were deployed by an automatic rollback. these are not watched
+// so that a rollback to a version that is also unhealthy does not roll forward again.
+var rollbackTargets = map[string]int64{}
+var rollbackMtx sync.Mutex
+
+// watchForRollback starts watching the version if its health window, measured from deployedAt, has not passed yet
+func watchForRollback(appID string, clusterID string, sequence int64, deployedAt time.Time, policy *kotsv1beta1.AutomaticRollback) {
+	rollbackMtx.Lock()
+	defer rollbackMtx.Unlock()
+
+	delete(rollbackWatches, appID)
+
+	if target, ok := rollbackTargets[appID]; ok && target == sequence {
+		delete(rollbackTargets, appID)
+		return
+	}
+
+	if policy == nil || !policy.Enabled {
+		return
+	}
+
+	healthWindow, unhealthyGracePeriod, failureStates, err := parseRollbackPolicy(policy)
+	if err != nil {
+		logger.Error(errors.Wrapf(err, "failed to parse automatic rollback policy for app %s", appID))
+		return
+	}
+
+	if time.Since(deployedAt) >= healthWindow {
+		return
+	}
+
+	rollbackWatches[appID] = &rollbackWatch{
+		clusterID:            clusterID,
+		sequence:             sequence,
+		deployedAt:           deployedAt,
+		healthWindow:         healthWindow,
+		unhealthyGracePeriod: unhealthyGracePeriod,
+		failureStates:        failureStates,
+	}
+}
+
+func parseRollbackPolicy(policy *kotsv1beta1.AutomaticRollback) (time.Duration, time.Duration, []appstatustypes.State, error) {
+	healthWindow := defaultHealthWindow
+	if policy.HealthWindow != "" {
+		d, err := time.ParseDuration(policy.HealthWindow)
+		if err != nil {
+			return 0, 0, nil, errors.Wrapf(err, "failed to parse health window %q", policy.HealthWindow)
+		}
+		healthWindow = d
+	}
+
+	unhealthyGracePeriod := defaultUnhealthyGracePeriod
+	if policy.UnhealthyGracePeriod != "" {
+		d, err := time.ParseDuration(policy.UnhealthyGracePeriod)
+		if err != nil {
+			return 0, 0, nil, errors.Wrapf(err, "failed to parse unhealthy grace period %q", policy.UnhealthyGracePeriod)
+		}
+		unhealthyGracePeriod = d
+	}
+
+	failureStates := defaultFailureStates
+	if len(policy.FailureStates) > 0 {
+		failureStates = []appstatustypes.State{}
+		for _, state := range policy.FailureStates {
+			failureStates = append(failureStates, appstatustypes.State(state))
+		}
+	}
+
+	return healthWindow, unhealthyGracePeriod, failureStates, nil
+}
+
+// HandleDeployResult is called when the operator reports the result of a deploy.
+// A failed apply of a watched version is rolled back immediately.
+func HandleDeployResult(appID string, clusterID string, sequence int64, isError bool) {
+	if !isError {
+		return
+	}
+
+	notifications.Emit(notificationtypes.EventDeployFailed, appID, map[string]interface{}{
+		"sequence":  sequence,
+		"clusterId": clusterID,
+	})
+
+	stopRolloutGates(appID, sequence)
+	rollbackIfWatched(appID, clusterID, sequence, "deploy failed")
+}
+
+// rollbackIfWatched rolls back the version immediately if it has automatic rollback enabled
+func rollbackIfWatched(appID string, clusterID string, sequence int64, reason string) {
+	rollbackMtx.Lock()
+	watch, ok := rollbackWatches[appID]
+	if !ok || watch.clusterID != clusterID || watch.sequence != sequence {
+		rollbackMtx.Unlock()
+		return
+	}
+	delete(rollbackWatches, appID)
+	rollbackMtx.Unlock()
+
+	if err := rollbackAppVersion(appID, clusterID, sequence, reason); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to roll back app %s", appID))
+	}
+}
+
+func rollbackLoop() {
+	rollbackMtx.Lock()
+	watches := map[string]*rollbackWatch{}
+	for appID, watch := range rollbackWatches {
+		watches[appID] = watch
+	}
+	rollbackMtx.Unlock()
+
+	for appID, watch := range watches {
+		done, err := processRollbackForApp(appID, watch)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to process automatic rollback for app %s", appID))
+		}
+		if !done {
+			continue
+		}
+
+		rollbackMtx.Lock()
+		if rollbackWatches[appID] == watch {
+			delete(rollbackWatches, appID)
+		}
+		rollbackMtx.Unlock()
+	}
+}
+
+// processRollbackForApp rolls back the version once it has been in a failure state for the grace period,
+// or if it is in a failure state at the end of the health window. it returns true when the watch is done.
+func processRollbackForApp(appID string, watch *rollbackWatch) (bool, error) {
+	windowPassed := time.Since(watch.deployedAt) >= watch.healthWindow
+
+	appStatus, err := store.GetStore().GetAppStatus(appID)
+	if err != nil {
+		return windowPassed, errors.Wrap(err, "failed to get app status")
+	}
+
+	if appStatus.Sequence != watch.sequence {
+		// the status has not been reported for this version yet
+		return windowPassed, nil
+	}
+
+	if !isFailureState(appStatus.State, watch.failureStates) {
+		watch.unhealthySince = time.Time{}
+		return windowPassed, nil
+	}
+
+	if watch.unhealthySince.IsZero() {
+		watch.unhealthySince = time.Now()
+	}
+	unhealthyFor := time.Since(watch.unhealthySince)
+
+	var reason string
+	if windowPassed {
+		reason = fmt.Sprintf("app state was %s after %s", appStatus.State, watch.healthWindow)
+	} else if unhealthyFor >= watch.unhealthyGracePeriod {
+		reason = fmt.Sprintf("app state was %s for %s", appStatus.State, unhealthyFor.Round(time.Second))
+	} else {
+		return false, nil
+	}
+
+	return true, rollbackAppVersion(appID, watch.clusterID, watch.sequence, reason)
+}
+
+func isFailureState(state appstatustypes.State, failureStates []appstatustypes.State) bool {
+	for _, failureState := range failureStates {
+		if state == failureState {
+			return true
+		}
+	}
+	return false
+}
+
+func rollbackAppVersion(appID string, clusterID string, sequence int64, reason string) error {
+	currentSequence, err := store.GetStore().GetCurrentSequence(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current sequence")
+	}
+	if currentSequence != sequence {
+		return nil
+	}
+
+	previousSequence, err := store.GetStore().GetPreviouslyDeployedSequence(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get previously deployed sequence")
+	}
+	if previousSequence == -1 {
+		logger.Infof("not rolling back app %s sequence %d (%s): no previously deployed version", appID, sequence, reason)
+		return nil
+	}
+
+	// the previous version is marked as failed when it was rolled back itself.
+	// this is checked in addition to rollbackTargets because that does not survive a restart.
+	previousStatus, err := store.GetStore().GetStatusForVersion(appID, clusterID, previousSequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get previously deployed version status")
+	}
+	if previousStatus == storetypes.VersionFailed {
+		logger.Infof("not rolling back app %s sequence %d (%s): previously deployed version %d failed", appID, sequence, reason, previousSequence)
+		return nil
+	}
+
+	logger.Infof("automatically rolling back app %s from sequence %d to %d: %s", appID, sequence, previousSequence, reason)
+
+	if err := store.GetStore().DeleteDownstreamDeployStatus(appID, clusterID, previousSequence); err != nil {
+		return errors.Wrap(err, "failed to delete downstream deploy status")
+	}
+
+	rollbackMtx.Lock()
+	rollbackTargets[appID] = previousSequence
+	rollbackMtx.Unlock()
+
+	if err := RedeployAppVersion(appID, previousSequence, nil); err != nil {
+		rollbackMtx.Lock()
+		delete(rollbackTargets, appID)
+		rollbackMtx.Unlock()
+		return errors.Wrap(err, "failed to redeploy previous version")
+	}
+
+	statusInfo := fmt.Sprintf("Automatically rolled back to sequence %d: %s", previousSequence, reason)
+	if err := store.GetStore().UpdateDownstreamVersionStatus(appID, sequence, "failed", statusInfo); err != nil {
+		return errors.Wrap(err, "failed to update downstream version status")
+	}
+
+	return nil
+}
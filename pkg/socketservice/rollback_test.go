@@ -0,0 +1,97 @@
+package socketservice
+
+import (
+	"testing"
+	"time"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRollbackPolicy(t *testing.T) {
+	tests := []struct {
+		name                     string
+		policy                   *kotsv1beta1.AutomaticRollback
+		wantHealthWindow         time.Duration
+		wantUnhealthyGracePeriod time.Duration
+		wantFailureStates        []appstatustypes.State
+		wantErr                  bool
+	}{
+		{
+			name:                     "defaults",
+			policy:                   &kotsv1beta1.AutomaticRollback{Enabled: true},
+			wantHealthWindow:         5 * time.Minute,
+			wantUnhealthyGracePeriod: 2 * time.Minute,
+			wantFailureStates:        []appstatustypes.State{appstatustypes.StateDegraded, appstatustypes.StateUnavailable},
+		},
+		{
+			name: "custom",
+			policy: &kotsv1beta1.AutomaticRollback{
+				Enabled:              true,
+				HealthWindow:         "15m",
+				UnhealthyGracePeriod: "10m",
+				FailureStates:        []string{"unavailable"},
+			},
+			wantHealthWindow:         15 * time.Minute,
+			wantUnhealthyGracePeriod: 10 * time.Minute,
+			wantFailureStates:        []appstatustypes.State{appstatustypes.StateUnavailable},
+		},
+		{
+			name: "invalid health window",
+			policy: &kotsv1beta1.AutomaticRollback{
+				Enabled:      true,
+				HealthWindow: "five minutes",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid unhealthy grace period",
+			policy: &kotsv1beta1.AutomaticRollback{
+				Enabled:              true,
+				UnhealthyGracePeriod: "two minutes",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthWindow, unhealthyGracePeriod, failureStates, err := parseRollbackPolicy(tt.policy)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHealthWindow, healthWindow)
+			assert.Equal(t, tt.wantUnhealthyGracePeriod, unhealthyGracePeriod)
+			assert.Equal(t, tt.wantFailureStates, failureStates)
+		})
+	}
+}
+
+func Test_watchForRollback(t *testing.T) {
+	policy := &kotsv1beta1.AutomaticRollback{Enabled: true}
+
+	watchForRollback("app", "cluster", 2, time.Now(), policy)
+	require.Contains(t, rollbackWatches, "app")
+	assert.Equal(t, int64(2), rollbackWatches["app"].sequence)
+
+	// a version deployed by a rollback is not watched
+	rollbackTargets["app"] = 1
+	watchForRollback("app", "cluster", 1, time.Now(), policy)
+	assert.NotContains(t, rollbackWatches, "app")
+	assert.NotContains(t, rollbackTargets, "app")
+
+	watchForRollback("app", "cluster", 3, time.Now(), &kotsv1beta1.AutomaticRollback{})
+	assert.NotContains(t, rollbackWatches, "app")
+
+	// a version that was deployed before the health window started is not watched again after a restart
+	watchForRollback("app", "cluster", 4, time.Now().Add(-10*time.Minute), policy)
+	assert.NotContains(t, rollbackWatches, "app")
+
+	deployedAt := time.Now().Add(-time.Minute)
+	watchForRollback("app", "cluster", 5, deployedAt, policy)
+	require.Contains(t, rollbackWatches, "app")
+	assert.Equal(t, deployedAt, rollbackWatches["app"].deployedAt)
+}
@@ -0,0 +1,279 @@
+package socketservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/replicatedhq/kots/pkg/version"
+)
+
+const defaultRolloutGatesTimeout = 10 * time.Minute
+
+var rolloutGatesHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// pendingRollout is a deployed version that is waiting for its rollout gates to pass
+type pendingRollout struct {
+	clusterID          string
+	sequence           int64
+	startedAt          time.Time
+	timeout            time.Duration
+	statusInformers    bool
+	prometheusQuery    string
+	httpURL            string
+	expectedStatusCode int
+}
+
+// pendingRollouts is rebuilt from the store when the current versions are sent to the operator
+// again after a restart. see hasOpenRolloutGates.
+var pendingRollouts = map[string]*pendingRollout{}
+var rolloutGatesMtx sync.Mutex
+
+// startRolloutGates holds the version in the deploying state until the gates pass.
+// a nil rollout means the version has no rollout gates.
+func startRolloutGates(appID string, rollout *pendingRollout) error {
+	stopRolloutGates(appID, -1)
+
+	if rollout == nil {
+		return nil
+	}
+
+	if err := store.GetStore().UpdateDownstreamVersionStatus(appID, rollout.sequence, string(storetypes.VersionDeploying), "Waiting for rollout gates"); err != nil {
+		return errors.Wrap(err, "failed to update downstream version status")
+	}
+
+	rolloutGatesMtx.Lock()
+	pendingRollouts[appID] = rollout
+	rolloutGatesMtx.Unlock()
+
+	return nil
+}
+
+// hasOpenRolloutGates returns true if the version has not passed or failed its rollout gates yet.
+// the status is deploying both for a version that has not been applied yet, and for one that was
+// still waiting on its gates when kotsadm restarted.
+func hasOpenRolloutGates(appID string, clusterID string, sequence int64) (bool, error) {
+	status, err := store.GetStore().GetStatusForVersion(appID, clusterID, sequence)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get version status")
+	}
+	return status == storetypes.VersionDeploying, nil
+}
+
+// newPendingRollout creates the rollout for a version. startedAt is the time the version was deployed
+// so that the timeout is not reset when the rollout is rebuilt after a restart.
+func newPendingRollout(clusterID string, sequence int64, startedAt time.Time, gates *kotsv1beta1.RolloutGates, builder *template.Builder) (*pendingRollout, error) {
+	rollout := &pendingRollout{
+		clusterID:       clusterID,
+		sequence:        sequence,
+		startedAt:       startedAt,
+		timeout:         defaultRolloutGatesTimeout,
+		statusInformers: gates.StatusInformers,
+	}
+
+	if gates.Timeout != "" {
+		d, err := time.ParseDuration(gates.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse timeout %q", gates.Timeout)
+		}
+		rollout.timeout = d
+	}
+
+	if gates.Prometheus != nil && gates.Prometheus.Query != "" {
+		query, err := builder.String(gates.Prometheus.Query)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render prometheus query")
+		}
+		rollout.prometheusQuery = query
+	}
+
+	if gates.HTTP != nil && gates.HTTP.URL != "" {
+		url, err := builder.String(gates.HTTP.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render http url")
+		}
+		rollout.httpURL = url
+		rollout.expectedStatusCode = gates.HTTP.ExpectedStatusCode
+	}
+
+	return rollout, nil
+}
+
+// stopRolloutGates stops waiting on the gates for the app. a sequence of -1 matches any sequence.
+func stopRolloutGates(appID string, sequence int64) {
+	rolloutGatesMtx.Lock()
+	defer rolloutGatesMtx.Unlock()
+
+	rollout, ok := pendingRollouts[appID]
+	if !ok {
+		return
+	}
+	if sequence == -1 || rollout.sequence == sequence {
+		delete(pendingRollouts, appID)
+	}
+}
+
+func rolloutGatesLoop() {
+	rolloutGatesMtx.Lock()
+	rollouts := map[string]*pendingRollout{}
+	for appID, rollout := range pendingRollouts {
+		rollouts[appID] = rollout
+	}
+	rolloutGatesMtx.Unlock()
+
+	for appID, rollout := range rollouts {
+		if err := processRolloutGatesForApp(appID, rollout); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to process rollout gates for app %s", appID))
+		}
+	}
+}
+
+func processRolloutGatesForApp(appID string, rollout *pendingRollout) error {
+	passed, reason, err := checkRolloutGates(appID, rollout)
+	if err != nil {
+		return errors.Wrap(err, "failed to check rollout gates")
+	}
+
+	if passed {
+		stopRolloutGates(appID, rollout.sequence)
+		logger.Infof("Rollout gates passed for app %s sequence %d", appID, rollout.sequence)
+		err := store.GetStore().UpdateDownstreamVersionStatus(appID, rollout.sequence, string(storetypes.VersionDeployed), "")
+		return errors.Wrap(err, "failed to update downstream version status")
+	}
+
+	if time.Since(rollout.startedAt) < rollout.timeout {
+		return nil
+	}
+
+	stopRolloutGates(appID, rollout.sequence)
+
+	statusInfo := fmt.Sprintf("Rollout gates did not pass within %s: %s", rollout.timeout, reason)
+	if err := store.GetStore().UpdateDownstreamVersionStatus(appID, rollout.sequence, string(storetypes.VersionFailed), statusInfo); err != nil {
+		return errors.Wrap(err, "failed to update downstream version status")
+	}
+
+	notifications.Emit(notificationtypes.EventDeployFailed, appID, map[string]interface{}{
+		"sequence":  rollout.sequence,
+		"clusterId": rollout.clusterID,
+		"error":     statusInfo,
+	})
+
+	rollbackIfWatched(appID, rollout.clusterID, rollout.sequence, fmt.Sprintf("rollout gates did not pass: %s", reason))
+
+	return nil
+}
+
+// checkRolloutGates returns true if all gates pass, or the reason the first failing gate did not
+func checkRolloutGates(appID string, rollout *pendingRollout) (bool, string, error) {
+	isDeployed, err := store.GetStore().IsDownstreamDeploySuccessful(appID, rollout.clusterID, rollout.sequence)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to check deploy successful")
+	}
+	if !isDeployed {
+		return false, "deploy result has not been reported", nil
+	}
+
+	if rollout.statusInformers {
+		appStatus, err := store.GetStore().GetAppStatus(appID)
+		if err != nil {
+			return false, "", errors.Wrap(err, "failed to get app status")
+		}
+		if appStatus.Sequence != rollout.sequence {
+			return false, "status informers have not reported", nil
+		}
+		if appStatus.State != appstatustypes.StateReady {
+			return false, fmt.Sprintf("app state is %s", appStatus.State), nil
+		}
+	}
+
+	if rollout.prometheusQuery != "" {
+		passed, reason := checkPrometheusGate(rollout.prometheusQuery)
+		if !passed {
+			return false, reason, nil
+		}
+	}
+
+	if rollout.httpURL != "" {
+		passed, reason := checkHTTPGate(rollout.httpURL, rollout.expectedStatusCode)
+		if !passed {
+			return false, reason, nil
+		}
+	}
+
+	return true, "", nil
+}
+
+func checkPrometheusGate(query string) (bool, string) {
+	prometheusAddress, err := store.GetStore().GetPrometheusAddress()
+	if err != nil {
+		return false, fmt.Sprintf("failed to get prometheus address: %v", err)
+	}
+	if prometheusAddress == "" {
+		prometheusAddress = os.Getenv("PROMETHEUS_ADDRESS")
+	}
+	if prometheusAddress == "" {
+		return false, "prometheus address is not configured"
+	}
+
+	samples, err := version.PrometheusQuery(prometheusAddress, query)
+	if err != nil {
+		return false, fmt.Sprintf("prometheus query failed: %v", err)
+	}
+
+	return prometheusSamplesPassed(query, samples)
+}
+
+func prometheusSamplesPassed(query string, samples []version.Sample) (bool, string) {
+	if len(samples) == 0 {
+		return false, fmt.Sprintf("prometheus query %q returned no results", query)
+	}
+
+	for _, sample := range samples {
+		s, ok := sample.Value[1].(string)
+		if !ok {
+			return false, fmt.Sprintf("prometheus query %q returned an invalid value", query)
+		}
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return false, fmt.Sprintf("prometheus query %q returned an invalid value %q", query, s)
+		}
+		if value == 0 {
+			return false, fmt.Sprintf("prometheus query %q returned 0", query)
+		}
+	}
+
+	return true, ""
+}
+
+func checkHTTPGate(url string, expectedStatusCode int) (bool, string) {
+	resp, err := rolloutGatesHTTPClient.Get(url)
+	if err != nil {
+		return false, fmt.Sprintf("http probe %s failed: %v", url, err)
+	}
+	resp.Body.Close()
+
+	if !httpStatusPassed(resp.StatusCode, expectedStatusCode) {
+		return false, fmt.Sprintf("http probe %s returned status code %d", url, resp.StatusCode)
+	}
+
+	return true, ""
+}
+
+func httpStatusPassed(statusCode int, expectedStatusCode int) bool {
+	if expectedStatusCode == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return statusCode == expectedStatusCode
+}
@@ -0,0 +1,91 @@
+package socketservice
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_prometheusSamplesPassed(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []version.Sample
+		want    bool
+	}{
+		{
+			name:    "no results",
+			samples: []version.Sample{},
+			want:    false,
+		},
+		{
+			name: "all non-zero",
+			samples: []version.Sample{
+				{Value: [2]interface{}{1630000000.0, "1"}},
+				{Value: [2]interface{}{1630000000.0, "0.5"}},
+			},
+			want: true,
+		},
+		{
+			name: "one zero",
+			samples: []version.Sample{
+				{Value: [2]interface{}{1630000000.0, "1"}},
+				{Value: [2]interface{}{1630000000.0, "0"}},
+			},
+			want: false,
+		},
+		{
+			name: "not a number",
+			samples: []version.Sample{
+				{Value: [2]interface{}{1630000000.0, "NaN?"}},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := prometheusSamplesPassed("up", tt.samples)
+			assert.Equal(t, tt.want, got)
+			if !tt.want {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func Test_httpStatusPassed(t *testing.T) {
+	tests := []struct {
+		name               string
+		statusCode         int
+		expectedStatusCode int
+		want               bool
+	}{
+		{
+			name:       "any 2xx",
+			statusCode: 204,
+			want:       true,
+		},
+		{
+			name:       "5xx",
+			statusCode: 503,
+			want:       false,
+		},
+		{
+			name:               "expected status code",
+			statusCode:         401,
+			expectedStatusCode: 401,
+			want:               true,
+		},
+		{
+			name:               "unexpected status code",
+			statusCode:         200,
+			expectedStatusCode: 401,
+			want:               false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, httpStatusPassed(tt.statusCode, tt.expectedStatusCode))
+		})
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/replicatedhq/kots/kotskinds/multitype"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
-	"github.com/replicatedhq/kots/pkg/app"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	identitydeploy "github.com/replicatedhq/kots/pkg/identity/deploy"
 	identitytypes "github.com/replicatedhq/kots/pkg/identity/types"
@@ -25,6 +24,8 @@ import (
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/midstream"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
 	"github.com/replicatedhq/kots/pkg/redact"
 	"github.com/replicatedhq/kots/pkg/render"
 	"github.com/replicatedhq/kots/pkg/reporting"
@@ -48,6 +49,7 @@ type DeployArgs struct {
 	AppID                string                `json:"app_id"`
 	AppSlug              string                `json:"app_slug"`
 	KubectlVersion       string                `json:"kubectl_version"`
+	Applier              string                `json:"applier"`
 	AdditionalNamespaces []string              `json:"additional_namespaces"`
 	ImagePullSecret      string                `json:"image_pull_secret"`
 	Namespace            string                `json:"namespace"`
@@ -116,6 +118,8 @@ func Start() *socket.Server {
 
 	startLoop(deployLoop, 1)
 	startLoop(restoreLoop, 1)
+	startLoop(rollbackLoop, 5)
+	startLoop(rolloutGatesLoop, 5)
 
 	return server
 }
@@ -194,6 +198,10 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 			if err != nil {
 				logger.Error(errors.Wrap(err, "failed to update downstream status"))
 			}
+			notifications.Emit(notificationtypes.EventDeployFailed, a.ID, map[string]interface{}{
+				"sequence": deployedVersion.Sequence,
+				"error":    deployError.Error(),
+			})
 		}
 	}()
 
@@ -342,10 +350,31 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 		}
 	}
 
+	deployedAt := time.Now()
+	if deployedVersion.DeployedAt != nil {
+		deployedAt = *deployedVersion.DeployedAt
+	}
+
+	var rollout *pendingRollout
+	if kotsKinds.KotsApplication.Spec.RolloutGates != nil {
+		hasOpenGates, err := hasOpenRolloutGates(a.ID, clusterSocket.ClusterID, deployedVersion.Sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to check rollout gates")
+		}
+		if hasOpenGates {
+			rollout, err = newPendingRollout(clusterSocket.ClusterID, deployedVersion.Sequence, deployedAt, kotsKinds.KotsApplication.Spec.RolloutGates, builder)
+			if err != nil {
+				deployError = errors.Wrap(err, "failed to parse rollout gates")
+				return deployError
+			}
+		}
+	}
+
 	deployArgs := DeployArgs{
 		AppID:                a.ID,
 		AppSlug:              a.Slug,
 		KubectlVersion:       kotsKinds.KotsApplication.Spec.KubectlVersion,
+		Applier:              kotsKinds.KotsApplication.Spec.Applier,
 		AdditionalNamespaces: kotsKinds.KotsApplication.Spec.AdditionalNamespaces,
 		ImagePullSecret:      imagePullSecret,
 		Namespace:            ".",
@@ -369,6 +398,12 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 	clusterSocket.LastDeployedSequences[a.ID] = deployedVersion.ParentSequence
 	socketMtx.Unlock()
 
+	watchForRollback(a.ID, clusterSocket.ClusterID, deployedVersion.Sequence, deployedAt, kotsKinds.KotsApplication.Spec.AutomaticRollback)
+
+	if err := startRolloutGates(a.ID, rollout); err != nil {
+		return errors.Wrap(err, "failed to start rollout gates")
+	}
+
 	renderedInformers := []string{}
 
 	// deploy status informers
@@ -565,7 +600,7 @@ func checkRestoreComplete(clusterSocket *ClusterSocket, a *apptypes.App, restore
 			logger.Error(errors.Wrapf(err, "failed to create support bundle for sequence %d post restore", sequence))
 		}
 
-		if err := app.ResetRestore(a.ID); err != nil {
+		if err := store.GetStore().ResetAppRestore(a.ID); err != nil {
 			return errors.Wrap(err, "failed to reset restore")
 		}
 		break
@@ -573,7 +608,7 @@ func checkRestoreComplete(clusterSocket *ClusterSocket, a *apptypes.App, restore
 	case velerov1.RestorePhaseFailed, velerov1.RestorePhasePartiallyFailed:
 		logger.Info("restore failed, resetting app restore")
 
-		if err := app.ResetRestore(a.ID); err != nil {
+		if err := store.GetStore().ResetAppRestore(a.ID); err != nil {
 			return errors.Wrap(err, "failed to reset restore")
 		}
 		break
@@ -671,16 +706,30 @@ func undeployApp(a *apptypes.App, d *downstreamtypes.Downstream, clusterSocket *
 	}
 	restoreLabelSelector.MatchLabels["kots.io/app-slug"] = a.Slug
 
+	// when restoring to a different cluster or namespace, the namespaces to clear are the ones the app will be restored to
+	namespaceMapping, err := snapshot.GetRestoreNamespaceMapping(context.Background(), util.PodNamespace, backup)
+	if err != nil {
+		return errors.Wrap(err, "failed to get restore namespace mapping")
+	}
+	var clearNamespaces []string
+	for _, namespace := range backup.Spec.IncludedNamespaces {
+		if mapped, ok := namespaceMapping[namespace]; ok {
+			namespace = mapped
+		}
+		clearNamespaces = append(clearNamespaces, namespace)
+	}
+
 	args := DeployArgs{
 		AppID:                a.ID,
 		AppSlug:              a.Slug,
 		KubectlVersion:       kotsKinds.KotsApplication.Spec.KubectlVersion,
+		Applier:              kotsKinds.KotsApplication.Spec.Applier,
 		Namespace:            ".",
 		Manifests:            "",
 		PreviousManifests:    base64EncodedManifests,
 		ResultCallback:       "/api/v1/undeploy/result",
 		Wait:                 true,
-		ClearNamespaces:      backup.Spec.IncludedNamespaces,
+		ClearNamespaces:      clearNamespaces,
 		ClearPVCs:            true,
 		IsRestore:            isRestore,
 		RestoreLabelSelector: restoreLabelSelector,
@@ -692,7 +741,7 @@ func undeployApp(a *apptypes.App, d *downstreamtypes.Downstream, clusterSocket *
 	}
 	c.Emit("deploy", args)
 
-	if err := app.SetRestoreUndeployStatus(a.ID, apptypes.UndeployInProcess); err != nil {
+	if err := store.GetStore().SetAppRestoreUndeployStatus(a.ID, apptypes.UndeployInProcess); err != nil {
 		return errors.Wrap(err, "failed to set restore undeploy status")
 	}
 
@@ -0,0 +1,116 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+func (s *KOTSStore) ListAPITokens() ([]*apitokentypes.APIToken, error) {
+	db := persistence.MustGetDBSession()
+	query := `select id, name, secret_sha256, role_ids, created_by, created_at, expires_at, revoked_at from api_token order by created_at asc`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	tokens := []*apitokentypes.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan token")
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func (s *KOTSStore) GetAPIToken(tokenID string) (*apitokentypes.APIToken, error) {
+	db := persistence.MustGetDBSession()
+	query := `select id, name, secret_sha256, role_ids, created_by, created_at, expires_at, revoked_at from api_token where id = $1`
+	row := db.QueryRow(query, tokenID)
+
+	token, err := scanAPIToken(row)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to scan token")
+	}
+
+	return token, nil
+}
+
+func (s *KOTSStore) CreateAPIToken(token *apitokentypes.APIToken) error {
+	marshalledRoleIDs, err := json.Marshal(token.RoleIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal role ids")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `insert into api_token (id, name, secret_sha256, role_ids, created_by, created_at, expires_at) values ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = db.Exec(query, token.ID, token.Name, token.SecretSHA256, string(marshalledRoleIDs), token.CreatedBy, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) RevokeAPIToken(tokenID string) error {
+	db := persistence.MustGetDBSession()
+	query := `update api_token set revoked_at = $2 where id = $1 and revoked_at is null`
+	result, err := db.Exec(query, tokenID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		// revoking an already revoked token is not an error
+		if _, err := s.GetAPIToken(tokenID); err != nil {
+			return errors.Wrap(err, "failed to get token")
+		}
+	}
+
+	return nil
+}
+
+func scanAPIToken(row scannable) (*apitokentypes.APIToken, error) {
+	token := apitokentypes.APIToken{}
+
+	var roleIDs sql.NullString
+	var createdBy sql.NullString
+	var createdAt persistence.StringTime
+	var expiresAt persistence.StringTime
+	var revokedAt persistence.NullStringTime
+
+	if err := row.Scan(&token.ID, &token.Name, &token.SecretSHA256, &roleIDs, &createdBy, &createdAt, &expiresAt, &revokedAt); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	token.CreatedBy = createdBy.String
+	token.CreatedAt = createdAt.Time
+	token.ExpiresAt = expiresAt.Time
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	token.RoleIDs = []string{}
+	if roleIDs.Valid && roleIDs.String != "" {
+		if err := json.Unmarshal([]byte(roleIDs.String), &token.RoleIDs); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal role ids")
+		}
+	}
+
+	return &token, nil
+}
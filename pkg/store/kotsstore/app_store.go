@@ -2,6 +2,7 @@ package kotsstore
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	"github.com/replicatedhq/kots/pkg/gitops"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
@@ -113,7 +115,7 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	// 	zap.String("id", id))
 
 	db := persistence.MustGetDBSession()
-	query := `select id, name, license, upstream_uri, icon_uri, created_at, updated_at, slug, current_sequence, last_update_check_at, is_airgap, snapshot_ttl_new, snapshot_schedule, restore_in_progress_name, restore_undeploy_status, update_checker_spec, install_state from app where id = $1`
+	query := `select id, name, license, upstream_uri, icon_uri, created_at, updated_at, slug, current_sequence, last_update_check_at, is_airgap, snapshot_ttl_new, snapshot_schedule, snapshot_retention, restore_in_progress_name, restore_undeploy_status, update_checker_spec, auto_deploy_policy, install_state from app where id = $1`
 	row := db.QueryRow(query, id)
 
 	app := apptypes.App{}
@@ -127,11 +129,13 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	var lastUpdateCheckAt sql.NullString
 	var snapshotTTLNew sql.NullString
 	var snapshotSchedule sql.NullString
+	var snapshotRetention sql.NullString
 	var restoreInProgressName sql.NullString
 	var restoreUndeployStatus sql.NullString
 	var updateCheckerSpec sql.NullString
+	var autoDeployPolicy sql.NullString
 
-	if err := row.Scan(&app.ID, &app.Name, &licenseStr, &upstreamURI, &iconURI, &createdAt, &updatedAt, &app.Slug, &currentSequence, &lastUpdateCheckAt, &app.IsAirgap, &snapshotTTLNew, &snapshotSchedule, &restoreInProgressName, &restoreUndeployStatus, &updateCheckerSpec, &app.InstallState); err != nil {
+	if err := row.Scan(&app.ID, &app.Name, &licenseStr, &upstreamURI, &iconURI, &createdAt, &updatedAt, &app.Slug, &currentSequence, &lastUpdateCheckAt, &app.IsAirgap, &snapshotTTLNew, &snapshotSchedule, &snapshotRetention, &restoreInProgressName, &restoreUndeployStatus, &updateCheckerSpec, &autoDeployPolicy, &app.InstallState); err != nil {
 		return nil, errors.Wrap(err, "failed to scan app")
 	}
 
@@ -146,6 +150,22 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	app.RestoreUndeployStatus = apptypes.UndeployStatus(restoreUndeployStatus.String)
 	app.UpdateCheckerSpec = updateCheckerSpec.String
 
+	if autoDeployPolicy.String != "" {
+		policy := apptypes.AutoDeployPolicy{}
+		if err := json.Unmarshal([]byte(autoDeployPolicy.String), &policy); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal auto deploy policy")
+		}
+		app.AutoDeployPolicy = &policy
+	}
+
+	if snapshotRetention.String != "" {
+		retention := snapshottypes.RetentionPolicy{}
+		if err := json.Unmarshal([]byte(snapshotRetention.String), &retention); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal snapshot retention policy")
+		}
+		app.SnapshotRetention = &retention
+	}
+
 	if updatedAt.Valid {
 		app.UpdatedAt = &updatedAt.Time
 	}
@@ -379,6 +399,29 @@ func (s *KOTSStore) SetUpdateCheckerSpec(appID string, updateCheckerSpec string)
 	return nil
 }
 
+func (s *KOTSStore) SetAutoDeployPolicy(appID string, policy *apptypes.AutoDeployPolicy) error {
+	logger.Debug("setting auto deploy policy",
+		zap.String("appID", appID))
+
+	var marshalledPolicy sql.NullString
+	if policy != nil {
+		b, err := json.Marshal(policy)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal auto deploy policy")
+		}
+		marshalledPolicy = sql.NullString{String: string(b), Valid: true}
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update app set auto_deploy_policy = $1 where id = $2`
+	_, err := db.Exec(query, marshalledPolicy, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
 	logger.Debug("Setting snapshot TTL",
 		zap.String("appID", appID))
@@ -392,6 +435,29 @@ func (s *KOTSStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
 	return nil
 }
 
+func (s *KOTSStore) SetSnapshotRetentionPolicy(appID string, policy *snapshottypes.RetentionPolicy) error {
+	logger.Debug("Setting snapshot retention policy",
+		zap.String("appID", appID))
+
+	var marshalledPolicy sql.NullString
+	if policy != nil {
+		b, err := json.Marshal(policy)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal snapshot retention policy")
+		}
+		marshalledPolicy = sql.NullString{String: string(b), Valid: true}
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update app set snapshot_retention = $1 where id = $2`
+	_, err := db.Exec(query, marshalledPolicy, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) SetSnapshotSchedule(appID string, snapshotSchedule string) error {
 	logger.Debug("Setting snapshot Schedule",
 		zap.String("appID", appID))
@@ -405,6 +471,50 @@ func (s *KOTSStore) SetSnapshotSchedule(appID string, snapshotSchedule string) e
 	return nil
 }
 
+func (s *KOTSStore) SetLastUpdateCheckAt(appID string, checkedAt time.Time) error {
+	db := persistence.MustGetDBSession()
+	query := `update app set last_update_check_at = $1 where id = $2`
+	_, err := db.Exec(query, checkedAt, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update last_update_check_at")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppRestoreInProgress(appID string, snapshotName string) error {
+	db := persistence.MustGetDBSession()
+	query := `update app set restore_in_progress_name = $1 where id = $2`
+	_, err := db.Exec(query, snapshotName, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update restore_in_progress_name")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppRestoreUndeployStatus(appID string, undeployStatus apptypes.UndeployStatus) error {
+	db := persistence.MustGetDBSession()
+	query := `update app set restore_undeploy_status = $1 where id = $2`
+	_, err := db.Exec(query, undeployStatus, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ResetAppRestore(appID string) error {
+	db := persistence.MustGetDBSession()
+	query := `update app set restore_in_progress_name = NULL, restore_undeploy_status = '' where id = $1`
+	_, err := db.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) RemoveApp(appID string) error {
 	logger.Debug("Removing app",
 		zap.String("appID", appID))
@@ -0,0 +1,106 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/segmentio/ksuid"
+)
+
+// the audit_event table is append only, there are intentionally no update or delete queries for it
+
+func (s *KOTSStore) CreateAuditEvent(event *audittypes.Event) error {
+	if event.ID == "" {
+		event.ID = ksuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	marshalledRoles, err := json.Marshal(event.Roles)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal roles")
+	}
+
+	var sequence sql.NullInt64
+	if event.Sequence != nil {
+		sequence = sql.NullInt64{Int64: *event.Sequence, Valid: true}
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `insert into audit_event (id, created_at, session_id, actor, roles, action, resource, method, path, app_slug, sequence, request_body_sha256, status_code)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+	_, err = db.Exec(query, event.ID, event.CreatedAt, event.SessionID, event.Actor, string(marshalledRoles), event.Action, event.Resource, event.Method, event.Path, event.AppSlug, sequence, event.RequestBodySHA256, event.StatusCode)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ListAuditEvents(opts audittypes.ListEventsOptions) ([]*audittypes.Event, int64, error) {
+	db := persistence.MustGetDBSession()
+
+	var totalCount int64
+	query := `select count(1) from audit_event where ($1 = '' or app_slug = $1)`
+	row := db.QueryRow(query, opts.AppSlug)
+	if err := row.Scan(&totalCount); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to scan count")
+	}
+
+	query = `select id, created_at, session_id, actor, roles, action, resource, method, path, app_slug, sequence, request_body_sha256, status_code
+	from audit_event where ($1 = '' or app_slug = $1) order by created_at desc, id desc limit $2 offset $3`
+	rows, err := db.Query(query, opts.AppSlug, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	events := []*audittypes.Event{}
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to scan event")
+		}
+		events = append(events, event)
+	}
+
+	return events, totalCount, nil
+}
+
+func scanAuditEvent(row scannable) (*audittypes.Event, error) {
+	event := audittypes.Event{}
+
+	var createdAt persistence.StringTime
+	var sessionID sql.NullString
+	var actor sql.NullString
+	var roles sql.NullString
+	var appSlug sql.NullString
+	var sequence sql.NullInt64
+	var requestBodySHA256 sql.NullString
+
+	if err := row.Scan(&event.ID, &createdAt, &sessionID, &actor, &roles, &event.Action, &event.Resource, &event.Method, &event.Path, &appSlug, &sequence, &requestBodySHA256, &event.StatusCode); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	event.CreatedAt = createdAt.Time
+	event.SessionID = sessionID.String
+	event.Actor = actor.String
+	event.AppSlug = appSlug.String
+	event.RequestBodySHA256 = requestBodySHA256.String
+	if sequence.Valid {
+		event.Sequence = &sequence.Int64
+	}
+
+	if roles.Valid && roles.String != "" && roles.String != "null" {
+		if err := json.Unmarshal([]byte(roles.String), &event.Roles); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal roles")
+		}
+	}
+
+	return &event, nil
+}
@@ -2,12 +2,14 @@ package kotsstore
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/gosimple/slug"
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
 	"github.com/replicatedhq/kots/pkg/rand"
@@ -17,7 +19,7 @@ import (
 func (s *KOTSStore) ListClusters() ([]*downstreamtypes.Downstream, error) {
 	db := persistence.MustGetDBSession()
 
-	query := `select id, slug, title, snapshot_schedule, snapshot_ttl from cluster` // TODO the current sequence
+	query := `select id, slug, title, snapshot_schedule, snapshot_ttl, snapshot_retention, snapshot_verification from cluster` // TODO the current sequence
 	rows, err := db.Query(query)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query clusters")
@@ -30,14 +32,32 @@ func (s *KOTSStore) ListClusters() ([]*downstreamtypes.Downstream, error) {
 
 		var snapshotSchedule sql.NullString
 		var snapshotTTL sql.NullString
+		var snapshotRetention sql.NullString
+		var snapshotVerification sql.NullString
 
-		if err := rows.Scan(&cluster.ClusterID, &cluster.ClusterSlug, &cluster.Name, &snapshotSchedule, &snapshotTTL); err != nil {
+		if err := rows.Scan(&cluster.ClusterID, &cluster.ClusterSlug, &cluster.Name, &snapshotSchedule, &snapshotTTL, &snapshotRetention, &snapshotVerification); err != nil {
 			return nil, errors.Wrap(err, "failed to scan row")
 		}
 
 		cluster.SnapshotSchedule = snapshotSchedule.String
 		cluster.SnapshotTTL = snapshotTTL.String
 
+		if snapshotRetention.String != "" {
+			retention := snapshottypes.RetentionPolicy{}
+			if err := json.Unmarshal([]byte(snapshotRetention.String), &retention); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal snapshot retention policy")
+			}
+			cluster.SnapshotRetention = &retention
+		}
+
+		if snapshotVerification.String != "" {
+			verification := snapshottypes.VerificationPolicy{}
+			if err := json.Unmarshal([]byte(snapshotVerification.String), &verification); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal snapshot verification policy")
+			}
+			cluster.SnapshotVerification = &verification
+		}
+
 		clusters = append(clusters, &cluster)
 	}
 
@@ -141,6 +161,52 @@ func (s *KOTSStore) SetInstanceSnapshotTTL(clusterID string, snapshotTTL string)
 	return nil
 }
 
+func (s *KOTSStore) SetInstanceSnapshotRetentionPolicy(clusterID string, policy *snapshottypes.RetentionPolicy) error {
+	logger.Debug("Setting instance snapshot retention policy",
+		zap.String("clusterID", clusterID))
+
+	var marshalledPolicy sql.NullString
+	if policy != nil {
+		b, err := json.Marshal(policy)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal snapshot retention policy")
+		}
+		marshalledPolicy = sql.NullString{String: string(b), Valid: true}
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update cluster set snapshot_retention = $1 where id = $2`
+	_, err := db.Exec(query, marshalledPolicy, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetInstanceSnapshotVerificationPolicy(clusterID string, policy *snapshottypes.VerificationPolicy) error {
+	logger.Debug("Setting instance snapshot verification policy",
+		zap.String("clusterID", clusterID))
+
+	var marshalledPolicy sql.NullString
+	if policy != nil {
+		b, err := json.Marshal(policy)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal snapshot verification policy")
+		}
+		marshalledPolicy = sql.NullString{String: string(b), Valid: true}
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update cluster set snapshot_verification = $1 where id = $2`
+	_, err := db.Exec(query, marshalledPolicy, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) SetInstanceSnapshotSchedule(clusterID string, snapshotSchedule string) error {
 	logger.Debug("Setting instance snapshot Schedule",
 		zap.String("clusterID", clusterID))
@@ -3,6 +3,8 @@ package kotsstore
 import (
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
+	"time"
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
@@ -504,7 +506,8 @@ func (s *KOTSStore) GetDownstreamOutput(appID string, clusterID string, sequence
 	ado.apply_stdout,
 	ado.apply_stderr,
 	ado.helm_stdout,
-	ado.helm_stderr
+	ado.helm_stderr,
+	ado.resources
 FROM
 	app_downstream_version adv
 LEFT JOIN
@@ -525,8 +528,9 @@ WHERE
 	var applyStderr sql.NullString
 	var helmStdout sql.NullString
 	var helmStderr sql.NullString
+	var resources sql.NullString
 
-	if err := row.Scan(&status, &statusInfo, &dryrunStdout, &dryrunStderr, &applyStdout, &applyStderr, &helmStdout, &helmStderr); err != nil {
+	if err := row.Scan(&status, &statusInfo, &dryrunStdout, &dryrunStderr, &applyStdout, &applyStderr, &helmStdout, &helmStderr, &resources); err != nil {
 		if err == sql.ErrNoRows {
 			return &downstreamtypes.DownstreamOutput{}, nil
 		}
@@ -574,6 +578,13 @@ WHERE
 		helmStderrDecoded = []byte("")
 	}
 
+	deployedResources := []downstreamtypes.DeployedResource{}
+	if resources.Valid && resources.String != "" {
+		if err := json.Unmarshal([]byte(resources.String), &deployedResources); err != nil {
+			logger.Error(errors.Wrap(err, "failed to unmarshal deployed resources"))
+		}
+	}
+
 	output := &downstreamtypes.DownstreamOutput{
 		DryrunStdout: string(dryrunStdoutDecoded),
 		DryrunStderr: string(dryrunStderrDecoded),
@@ -582,6 +593,7 @@ WHERE
 		HelmStdout:   string(helmStdoutDecoded),
 		HelmStderr:   string(helmStderrDecoded),
 		RenderError:  string(renderError),
+		Resources:    deployedResources,
 	}
 
 	return output, nil
@@ -610,12 +622,17 @@ func (s *KOTSStore) IsDownstreamDeploySuccessful(appID string, clusterID string,
 func (s *KOTSStore) UpdateDownstreamDeployStatus(appID string, clusterID string, sequence int64, isError bool, output downstreamtypes.DownstreamOutput) error {
 	db := persistence.MustGetDBSession()
 
-	query := `insert into app_downstream_output (app_id, cluster_id, downstream_sequence, is_error, dryrun_stdout, dryrun_stderr, apply_stdout, apply_stderr, helm_stdout, helm_stderr)
-	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) on conflict (app_id, cluster_id, downstream_sequence) do update set is_error = EXCLUDED.is_error,
+	resources, err := json.Marshal(output.Resources)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal deployed resources")
+	}
+
+	query := `insert into app_downstream_output (app_id, cluster_id, downstream_sequence, is_error, dryrun_stdout, dryrun_stderr, apply_stdout, apply_stderr, helm_stdout, helm_stderr, resources)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) on conflict (app_id, cluster_id, downstream_sequence) do update set is_error = EXCLUDED.is_error,
 	dryrun_stdout = EXCLUDED.dryrun_stdout, dryrun_stderr = EXCLUDED.dryrun_stderr, apply_stdout = EXCLUDED.apply_stdout, apply_stderr = EXCLUDED.apply_stderr,
-	helm_stdout = EXCLUDED.helm_stdout, helm_stderr = EXCLUDED.helm_stderr`
+	helm_stdout = EXCLUDED.helm_stdout, helm_stderr = EXCLUDED.helm_stderr, resources = EXCLUDED.resources`
 
-	_, err := db.Exec(query, appID, clusterID, sequence, isError, output.DryrunStdout, output.DryrunStderr, output.ApplyStdout, output.ApplyStderr, output.HelmStdout, output.HelmStderr)
+	_, err = db.Exec(query, appID, clusterID, sequence, isError, output.DryrunStdout, output.DryrunStderr, output.ApplyStdout, output.ApplyStderr, output.HelmStdout, output.HelmStderr, string(resources))
 	if err != nil {
 		return errors.Wrap(err, "failed to exec")
 	}
@@ -635,3 +652,31 @@ func (s *KOTSStore) DeleteDownstreamDeployStatus(appID string, clusterID string,
 
 	return nil
 }
+
+func (s *KOTSStore) MarkAsCurrentDownstreamVersion(appID string, sequence int64) error {
+	db := persistence.MustGetDBSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	query := `update app_downstream set current_sequence = $1 where app_id = $2`
+	_, err = tx.Exec(query, sequence, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update app downstream current sequence")
+	}
+
+	query = `update app_downstream_version set status = 'deployed', applied_at = $3 where sequence = $1 and app_id = $2`
+	_, err = tx.Exec(query, sequence, appID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to update app downstream version status")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package kotsstore
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kotsstore")
+	require.NoError(t, err)
+
+	persistence.SQLiteURI = filepath.Join(dir, "kotsadm.db")
+	t.Cleanup(func() {
+		persistence.SQLiteURI = ""
+		os.RemoveAll(dir)
+	})
+}
+
+func Test_DownstreamDeployStatus(t *testing.T) {
+	req := require.New(t)
+	useSQLite(t)
+
+	db := persistence.MustGetDBSession()
+	_, err := db.Exec(`insert into app_downstream_version (app_id, cluster_id, sequence, parent_sequence, created_at, version_label, status) values ($1, $2, $3, $4, $5, $6, $7)`,
+		"app-id", "cluster-id", 1, 1, time.Now(), "1.0.0", "deployed")
+	req.NoError(err)
+
+	s := StoreFromEnv()
+
+	output := downstreamtypes.DownstreamOutput{
+		ApplyStdout: base64.StdEncoding.EncodeToString([]byte("deployment.apps/web configured")),
+		Resources: []downstreamtypes.DeployedResource{
+			{
+				Phase:   downstreamtypes.DeployPhaseApply,
+				Group:   "apps",
+				Version: "v1",
+				Kind:    "Deployment",
+				Name:    "web",
+				Action:  "configured",
+			},
+		},
+	}
+	req.NoError(s.UpdateDownstreamDeployStatus("app-id", "cluster-id", 1, false, output))
+
+	actual, err := s.GetDownstreamOutput("app-id", "cluster-id", 1)
+	req.NoError(err)
+	assert.Equal(t, "deployment.apps/web configured", actual.ApplyStdout)
+	assert.Equal(t, output.Resources, actual.Resources)
+
+	// updating the status again replaces the output
+	output.Resources[0].Action = "unchanged"
+	req.NoError(s.UpdateDownstreamDeployStatus("app-id", "cluster-id", 1, false, output))
+
+	actual, err = s.GetDownstreamOutput("app-id", "cluster-id", 1)
+	req.NoError(err)
+	assert.Equal(t, output.Resources, actual.Resources)
+}
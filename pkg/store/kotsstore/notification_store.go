@@ -0,0 +1,225 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/segmentio/ksuid"
+)
+
+func (s *KOTSStore) ListNotificationEndpoints() ([]*notificationtypes.Endpoint, error) {
+	db := persistence.MustGetDBSession()
+	query := `select id, url, secret_enc, events, is_enabled, created_at from notification_endpoint order by created_at asc`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	endpoints := []*notificationtypes.Endpoint{}
+	for rows.Next() {
+		endpoint, err := scanNotificationEndpoint(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan endpoint")
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (s *KOTSStore) GetNotificationEndpoint(endpointID string) (*notificationtypes.Endpoint, error) {
+	db := persistence.MustGetDBSession()
+	query := `select id, url, secret_enc, events, is_enabled, created_at from notification_endpoint where id = $1`
+	row := db.QueryRow(query, endpointID)
+
+	endpoint, err := scanNotificationEndpoint(row)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to scan endpoint")
+	}
+
+	return endpoint, nil
+}
+
+func (s *KOTSStore) CreateNotificationEndpoint(url string, secret string, events []notificationtypes.EventType) (*notificationtypes.Endpoint, error) {
+	endpoint := &notificationtypes.Endpoint{
+		ID:        ksuid.New().String(),
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		IsEnabled: true,
+		CreatedAt: time.Now(),
+	}
+
+	secretEnc, marshalledEvents, err := encodeNotificationEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode endpoint")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `insert into notification_endpoint (id, url, secret_enc, events, is_enabled, created_at) values ($1, $2, $3, $4, $5, $6)`
+	_, err = db.Exec(query, endpoint.ID, endpoint.URL, secretEnc, marshalledEvents, endpoint.IsEnabled, endpoint.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exec")
+	}
+
+	return endpoint, nil
+}
+
+func (s *KOTSStore) UpdateNotificationEndpoint(endpoint *notificationtypes.Endpoint) error {
+	secretEnc, marshalledEvents, err := encodeNotificationEndpoint(endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode endpoint")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update notification_endpoint set url = $2, secret_enc = $3, events = $4, is_enabled = $5 where id = $1`
+	_, err = db.Exec(query, endpoint.ID, endpoint.URL, secretEnc, marshalledEvents, endpoint.IsEnabled)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) DeleteNotificationEndpoint(endpointID string) error {
+	db := persistence.MustGetDBSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`delete from notification_delivery where endpoint_id = $1`, endpointID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete deliveries")
+	}
+
+	_, err = tx.Exec(`delete from notification_endpoint where id = $1`, endpointID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete endpoint")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) CreateNotificationDelivery(delivery *notificationtypes.Delivery) error {
+	if delivery.ID == "" {
+		delivery.ID = ksuid.New().String()
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `insert into notification_delivery (id, endpoint_id, event_id, event_type, payload, attempt, status_code, error, is_success, delivered_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := db.Exec(query, delivery.ID, delivery.EndpointID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Attempt, delivery.StatusCode, delivery.Error, delivery.IsSuccess, delivery.DeliveredAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ListNotificationDeliveries(endpointID string, limit int) ([]*notificationtypes.Delivery, error) {
+	db := persistence.MustGetDBSession()
+	query := `select id, endpoint_id, event_id, event_type, payload, attempt, status_code, error, is_success, delivered_at
+	from notification_delivery where endpoint_id = $1 order by delivered_at desc limit $2`
+	rows, err := db.Query(query, endpointID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	deliveries := []*notificationtypes.Delivery{}
+	for rows.Next() {
+		delivery := notificationtypes.Delivery{}
+
+		var payload sql.NullString
+		var statusCode sql.NullInt64
+		var deliveryError sql.NullString
+		var deliveredAt persistence.StringTime
+		if err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.EventType, &payload, &delivery.Attempt, &statusCode, &deliveryError, &delivery.IsSuccess, &deliveredAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		delivery.DeliveredAt = deliveredAt.Time
+		delivery.Payload = payload.String
+		delivery.StatusCode = int(statusCode.Int64)
+		delivery.Error = deliveryError.String
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+func scanNotificationEndpoint(row scannable) (*notificationtypes.Endpoint, error) {
+	endpoint := notificationtypes.Endpoint{}
+
+	var secretEnc sql.NullString
+	var events sql.NullString
+	var createdAt persistence.StringTime
+	if err := row.Scan(&endpoint.ID, &endpoint.URL, &secretEnc, &events, &endpoint.IsEnabled, &createdAt); err != nil {
+		return nil, err
+	}
+	endpoint.CreatedAt = createdAt.Time
+
+	endpoint.Events = []notificationtypes.EventType{}
+	if events.String != "" {
+		if err := json.Unmarshal([]byte(events.String), &endpoint.Events); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal events")
+		}
+	}
+
+	if secretEnc.String != "" {
+		apiCipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load apiCipher")
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(secretEnc.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode secret")
+		}
+
+		decrypted, err := apiCipher.Decrypt(decoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt secret")
+		}
+		endpoint.Secret = string(decrypted)
+	}
+
+	return &endpoint, nil
+}
+
+func encodeNotificationEndpoint(endpoint *notificationtypes.Endpoint) (string, string, error) {
+	marshalledEvents, err := json.Marshal(endpoint.Events)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to marshal events")
+	}
+
+	if endpoint.Secret == "" {
+		return "", string(marshalledEvents), nil
+	}
+
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to create aes cipher")
+	}
+	secretEnc := base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte(endpoint.Secret)))
+
+	return secretEnc, string(marshalledEvents), nil
+}
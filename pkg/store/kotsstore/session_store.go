@@ -127,6 +127,7 @@ func (s *KOTSStore) CreateSession(forUser *usertypes.User, issuedAt time.Time, e
 
 	session := sessiontypes.Session{
 		ID:        id,
+		UserID:    forUser.ID,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
 		Roles:     roles,
@@ -198,6 +199,35 @@ func (s *KOTSStore) DeleteSession(id string) error {
 	return nil
 }
 
+func (s *KOTSStore) DeleteUserSessions(userID string) error {
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	s.sessionSecret = nil
+
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	for id, data := range secret.Data {
+		session := sessiontypes.Session{}
+		if err := json.Unmarshal(data, &session); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to unmarshal session %s", id))
+			continue
+		}
+		if session.UserID == userID {
+			delete(secret.Data, id)
+		}
+	}
+
+	if err := s.saveSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) getSessionSecret() (*corev1.Secret, error) {
 	if s.sessionSecret != nil && time.Now().Before(s.sessionExpiration) {
 		return s.sessionSecret, nil
@@ -3,6 +3,7 @@ package kotsstore
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/persistence"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
 	"github.com/replicatedhq/kots/pkg/util"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -100,7 +102,11 @@ func (s *KOTSStore) getSharedPaswordBcryptFromDatabase() ([]byte, error) {
 	return hash, nil
 }
 
-func (s *KOTSStore) FlagInvalidPassword() error {
+func (s *KOTSStore) FlagInvalidPassword(username string) error {
+	if username != "" {
+		return s.flagInvalidLocalUserPassword(username)
+	}
+
 	if persistence.IsSQlite() {
 		return s.flagInvalidPasswordInDatabase()
 	}
@@ -175,7 +181,11 @@ func (s *KOTSStore) flagInvalidPasswordInDatabase() error {
 
 }
 
-func (s *KOTSStore) FlagSuccessfulLogin() error {
+func (s *KOTSStore) FlagSuccessfulLogin(username string) error {
+	if username != "" {
+		return s.flagSuccessfulLocalUserLogin(username)
+	}
+
 	if persistence.IsSQlite() {
 		return s.flagSuccessfulLoginInDatabase()
 	}
@@ -231,3 +241,160 @@ func (s *KOTSStore) flagSuccessfulLoginInDatabase() error {
 
 	return nil
 }
+
+func (s *KOTSStore) ListLocalUsers() ([]*usertypes.LocalUser, error) {
+	db := persistence.MustGetDBSession()
+	query := `select username, password_bcrypt, role_ids, must_change_password, failed_login_count, created_at, last_login_at from kotsadm_user order by username asc`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	users := []*usertypes.LocalUser{}
+	for rows.Next() {
+		user, err := scanLocalUser(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan user")
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (s *KOTSStore) GetLocalUser(username string) (*usertypes.LocalUser, error) {
+	db := persistence.MustGetDBSession()
+	query := `select username, password_bcrypt, role_ids, must_change_password, failed_login_count, created_at, last_login_at from kotsadm_user where username = $1`
+	row := db.QueryRow(query, username)
+
+	user, err := scanLocalUser(row)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to scan user")
+	}
+
+	return user, nil
+}
+
+func (s *KOTSStore) CreateLocalUser(username string, passwordBcrypt []byte, roleIDs []string) (*usertypes.LocalUser, error) {
+	user := &usertypes.LocalUser{
+		Username:           username,
+		PasswordBcrypt:     passwordBcrypt,
+		RoleIDs:            roleIDs,
+		MustChangePassword: true,
+		CreatedAt:          time.Now(),
+	}
+
+	marshalledRoleIDs, err := json.Marshal(user.RoleIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal role ids")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `insert into kotsadm_user (username, password_bcrypt, role_ids, must_change_password, failed_login_count, created_at) values ($1, $2, $3, $4, $5, $6)`
+	_, err = db.Exec(query, user.Username, string(user.PasswordBcrypt), string(marshalledRoleIDs), user.MustChangePassword, 0, user.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exec")
+	}
+
+	return user, nil
+}
+
+func (s *KOTSStore) DeleteLocalUser(username string) error {
+	db := persistence.MustGetDBSession()
+	query := `delete from kotsadm_user where username = $1`
+	if _, err := db.Exec(query, username); err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetLocalUserRoles(username string, roleIDs []string) error {
+	marshalledRoleIDs, err := json.Marshal(roleIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal role ids")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update kotsadm_user set role_ids = $2 where username = $1`
+	result, err := db.Exec(query, username, string(marshalledRoleIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return errors.Wrap(checkLocalUserUpdated(result), "failed to update roles")
+}
+
+func (s *KOTSStore) SetLocalUserPassword(username string, passwordBcrypt []byte, mustChangePassword bool) error {
+	db := persistence.MustGetDBSession()
+	query := `update kotsadm_user set password_bcrypt = $2, must_change_password = $3, failed_login_count = 0 where username = $1`
+	result, err := db.Exec(query, username, string(passwordBcrypt), mustChangePassword)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return errors.Wrap(checkLocalUserUpdated(result), "failed to update password")
+}
+
+func (s *KOTSStore) flagInvalidLocalUserPassword(username string) error {
+	db := persistence.MustGetDBSession()
+	query := `update kotsadm_user set failed_login_count = failed_login_count + 1 where username = $1`
+	if _, err := db.Exec(query, username); err != nil {
+		return errors.Wrap(err, "failed to update failed login count")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) flagSuccessfulLocalUserLogin(username string) error {
+	db := persistence.MustGetDBSession()
+	query := `update kotsadm_user set failed_login_count = 0, last_login_at = $2 where username = $1`
+	if _, err := db.Exec(query, username, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to reset failed login count")
+	}
+
+	return nil
+}
+
+func checkLocalUserUpdated(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanLocalUser(row scannable) (*usertypes.LocalUser, error) {
+	user := usertypes.LocalUser{}
+
+	var passwordBcrypt string
+	var roleIDs sql.NullString
+	var createdAt persistence.StringTime
+	var lastLoginAt persistence.NullStringTime
+
+	if err := row.Scan(&user.Username, &passwordBcrypt, &roleIDs, &user.MustChangePassword, &user.FailedLoginCount, &createdAt, &lastLoginAt); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	user.PasswordBcrypt = []byte(passwordBcrypt)
+	user.CreatedAt = createdAt.Time
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+
+	user.RoleIDs = []string{}
+	if roleIDs.Valid && roleIDs.String != "" {
+		if err := json.Unmarshal([]byte(roleIDs.String), &user.RoleIDs); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal role ids")
+		}
+	}
+
+	return &user, nil
+}
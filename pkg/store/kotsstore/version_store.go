@@ -33,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/client-go/kubernetes/scheme"
+	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 )
 
 func (s *KOTSStore) ensureApplicationMetadata(applicationMetadata string, namespace string, upstreamURI string) error {
@@ -361,10 +362,14 @@ func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *
 			}
 		}
 
-		commitURL, err := gitops.CreateGitOpsDownstreamCommit(appID, d.ClusterID, int(newSequence), filesInDir, d.Name)
+		commitURL, isPullRequest, err := gitops.CreateGitOpsDownstreamCommit(appID, d.ClusterID, int(newSequence), filesInDir, d.Name)
 		if err != nil {
 			return int64(0), errors.Wrap(err, "failed to create gitops commit")
 		}
+		if isPullRequest && commitURL != "" {
+			// the version is deployed when the pull request is merged
+			downstreamStatus = types.VersionPullRequestOpened
+		}
 
 		err = s.addAppVersionToDownstream(tx, appID, d.ClusterID, newSequence,
 			kotsKinds.Installation.Spec.VersionLabel, downstreamStatus, source,
@@ -532,7 +537,7 @@ func (s *KOTSStore) addAppVersionToDownstream(tx *sql.Tx, appID string, clusterI
 
 func (s *KOTSStore) GetAppVersion(appID string, sequence int64) (*versiontypes.AppVersion, error) {
 	db := persistence.MustGetDBSession()
-	query := `select sequence, created_at, status, applied_at, kots_installation_spec, kots_app_spec from app_version where app_id = $1 and sequence = $2`
+	query := `select sequence, created_at, status, applied_at, kots_installation_spec, kots_app_spec, app_spec from app_version where app_id = $1 and sequence = $2`
 	row := db.QueryRow(query, appID, sequence)
 
 	var status sql.NullString
@@ -540,11 +545,12 @@ func (s *KOTSStore) GetAppVersion(appID string, sequence int64) (*versiontypes.A
 	var createdAt persistence.NullStringTime
 	var installationSpec sql.NullString
 	var kotsAppSpec sql.NullString
+	var appSpec sql.NullString
 
 	v := versiontypes.AppVersion{
 		AppID: appID,
 	}
-	if err := row.Scan(&v.Sequence, &createdAt, &status, &deployedAt, &installationSpec, &kotsAppSpec); err != nil {
+	if err := row.Scan(&v.Sequence, &createdAt, &status, &deployedAt, &installationSpec, &kotsAppSpec, &appSpec); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
@@ -570,6 +576,17 @@ func (s *KOTSStore) GetAppVersion(appID string, sequence int64) (*versiontypes.A
 		}
 	}
 
+	if appSpec.String != "" {
+		decode := scheme.Codecs.UniversalDeserializer().Decode
+		obj, _, err := decode([]byte(appSpec.String), nil, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode app spec")
+		}
+		if app, ok := obj.(*applicationv1beta1.Application); ok {
+			kotsKinds.Application = app
+		}
+	}
+
 	v.CreatedOn = createdAt.Time
 	if deployedAt.Valid {
 		v.DeployedAt = &deployedAt.Time
@@ -624,6 +641,42 @@ func (s *KOTSStore) GetAppVersionsAfter(appID string, sequence int64) ([]*versio
 	return versions, nil
 }
 
+func (s *KOTSStore) GetNextAppSequence(appID string) (int64, error) {
+	db := persistence.MustGetDBSession()
+	row := db.QueryRow(`select max(sequence) from app_version where app_id = $1`, appID)
+
+	var maxSequence sql.NullInt64
+	if err := row.Scan(&maxSequence); err != nil {
+		return 0, errors.Wrap(err, "failed to find current max sequence in row")
+	}
+	if !maxSequence.Valid {
+		return 0, nil
+	}
+
+	return maxSequence.Int64 + 1, nil
+}
+
+func (s *KOTSStore) UpdateAppVersionConfigValues(appID string, sequence int64, filesInDir string) error {
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(filesInDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kots kinds")
+	}
+
+	configValues, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal configvalues spec")
+	}
+
+	db := persistence.MustGetDBSession()
+	query := `update app_version set config_values = $1 where app_id = $2 and sequence = $3`
+	_, err = db.Exec(query, configValues, appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to update config values in db")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) UpdateAppVersionInstallationSpec(appID string, sequence int64, installation kotsv1beta1.Installation) error {
 	ser := serializer.NewYAMLSerializer(serializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
 	var b bytes.Buffer
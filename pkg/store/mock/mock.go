@@ -15,17 +15,20 @@ import (
 	types0 "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	types1 "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	types2 "github.com/replicatedhq/kots/pkg/api/version/types"
-	types3 "github.com/replicatedhq/kots/pkg/app/types"
-	types4 "github.com/replicatedhq/kots/pkg/gitops/types"
-	types5 "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
-	types6 "github.com/replicatedhq/kots/pkg/online/types"
-	types7 "github.com/replicatedhq/kots/pkg/preflight/types"
-	types8 "github.com/replicatedhq/kots/pkg/registry/types"
-	types9 "github.com/replicatedhq/kots/pkg/render/types"
-	types10 "github.com/replicatedhq/kots/pkg/session/types"
-	types11 "github.com/replicatedhq/kots/pkg/store/types"
-	types12 "github.com/replicatedhq/kots/pkg/supportbundle/types"
-	types13 "github.com/replicatedhq/kots/pkg/user/types"
+	types3 "github.com/replicatedhq/kots/pkg/apitoken/types"
+	types4 "github.com/replicatedhq/kots/pkg/app/types"
+	types5 "github.com/replicatedhq/kots/pkg/audit/types"
+	types6 "github.com/replicatedhq/kots/pkg/gitops/types"
+	types7 "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	types8 "github.com/replicatedhq/kots/pkg/notifications/types"
+	types9 "github.com/replicatedhq/kots/pkg/online/types"
+	types10 "github.com/replicatedhq/kots/pkg/preflight/types"
+	types11 "github.com/replicatedhq/kots/pkg/registry/types"
+	types12 "github.com/replicatedhq/kots/pkg/render/types"
+	types13 "github.com/replicatedhq/kots/pkg/session/types"
+	types14 "github.com/replicatedhq/kots/pkg/store/types"
+	types15 "github.com/replicatedhq/kots/pkg/supportbundle/types"
+	types16 "github.com/replicatedhq/kots/pkg/user/types"
 	redact "github.com/replicatedhq/troubleshoot/pkg/redact"
 )
 
@@ -80,11 +83,25 @@ func (mr *MockStoreMockRecorder) ClearTaskStatus(taskID interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearTaskStatus", reflect.TypeOf((*MockStore)(nil).ClearTaskStatus), taskID)
 }
 
+// CreateAPIToken mocks base method.
+func (m *MockStore) CreateAPIToken(token *types3.APIToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockStoreMockRecorder) CreateAPIToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockStore)(nil).CreateAPIToken), token)
+}
+
 // CreateApp mocks base method.
-func (m *MockStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types3.App, error) {
+func (m *MockStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateApp", name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -96,7 +113,7 @@ func (mr *MockStoreMockRecorder) CreateApp(name, upstreamURI, licenseData, isAir
 }
 
 // CreateAppVersion mocks base method.
-func (m *MockStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types4.DownstreamGitOps) (int64, error) {
+func (m *MockStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types6.DownstreamGitOps) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
 	ret0, _ := ret[0].(int64)
@@ -124,8 +141,22 @@ func (mr *MockStoreMockRecorder) CreateAppVersionArchive(appID, sequence, archiv
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersionArchive", reflect.TypeOf((*MockStore)(nil).CreateAppVersionArchive), appID, sequence, archivePath)
 }
 
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(event *types5.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), event)
+}
+
 // CreateInProgressSupportBundle mocks base method.
-func (m *MockStore) CreateInProgressSupportBundle(supportBundle *types12.SupportBundle) error {
+func (m *MockStore) CreateInProgressSupportBundle(supportBundle *types15.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInProgressSupportBundle", supportBundle)
 	ret0, _ := ret[0].(error)
@@ -138,6 +169,21 @@ func (mr *MockStoreMockRecorder) CreateInProgressSupportBundle(supportBundle int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInProgressSupportBundle", reflect.TypeOf((*MockStore)(nil).CreateInProgressSupportBundle), supportBundle)
 }
 
+// CreateLocalUser mocks base method.
+func (m *MockStore) CreateLocalUser(username string, passwordBcrypt []byte, roleIDs []string) (*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLocalUser", username, passwordBcrypt, roleIDs)
+	ret0, _ := ret[0].(*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLocalUser indicates an expected call of CreateLocalUser.
+func (mr *MockStoreMockRecorder) CreateLocalUser(username, passwordBcrypt, roleIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLocalUser", reflect.TypeOf((*MockStore)(nil).CreateLocalUser), username, passwordBcrypt, roleIDs)
+}
+
 // CreateNewCluster mocks base method.
 func (m *MockStore) CreateNewCluster(userID string, isAllUsers bool, title, token string) (string, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +199,35 @@ func (mr *MockStoreMockRecorder) CreateNewCluster(userID, isAllUsers, title, tok
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewCluster", reflect.TypeOf((*MockStore)(nil).CreateNewCluster), userID, isAllUsers, title, token)
 }
 
+// CreateNotificationDelivery mocks base method.
+func (m *MockStore) CreateNotificationDelivery(delivery *types8.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotificationDelivery indicates an expected call of CreateNotificationDelivery.
+func (mr *MockStoreMockRecorder) CreateNotificationDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationDelivery", reflect.TypeOf((*MockStore)(nil).CreateNotificationDelivery), delivery)
+}
+
+// CreateNotificationEndpoint mocks base method.
+func (m *MockStore) CreateNotificationEndpoint(url, secret string, events []types8.EventType) (*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationEndpoint", url, secret, events)
+	ret0, _ := ret[0].(*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotificationEndpoint indicates an expected call of CreateNotificationEndpoint.
+func (mr *MockStoreMockRecorder) CreateNotificationEndpoint(url, secret, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationEndpoint", reflect.TypeOf((*MockStore)(nil).CreateNotificationEndpoint), url, secret, events)
+}
+
 // CreateScheduledInstanceSnapshot mocks base method.
 func (m *MockStore) CreateScheduledInstanceSnapshot(snapshotID, clusterID string, timestamp time.Time) error {
 	m.ctrl.T.Helper()
@@ -182,10 +257,10 @@ func (mr *MockStoreMockRecorder) CreateScheduledSnapshot(snapshotID, appID, time
 }
 
 // CreateSession mocks base method.
-func (m *MockStore) CreateSession(user *types13.User, issuedAt, expiresAt time.Time, roles []string) (*types10.Session, error) {
+func (m *MockStore) CreateSession(user *types16.User, issuedAt, expiresAt time.Time, roles []string) (*types13.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSession", user, issuedAt, expiresAt, roles)
-	ret0, _ := ret[0].(*types10.Session)
+	ret0, _ := ret[0].(*types13.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -197,10 +272,10 @@ func (mr *MockStoreMockRecorder) CreateSession(user, issuedAt, expiresAt, roles
 }
 
 // CreateSupportBundle mocks base method.
-func (m *MockStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types12.SupportBundle, error) {
+func (m *MockStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSupportBundle", bundleID, appID, archivePath, marshalledTree)
-	ret0, _ := ret[0].(*types12.SupportBundle)
+	ret0, _ := ret[0].(*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -225,6 +300,34 @@ func (mr *MockStoreMockRecorder) DeleteDownstreamDeployStatus(appID, clusterID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamDeployStatus", reflect.TypeOf((*MockStore)(nil).DeleteDownstreamDeployStatus), appID, clusterID, sequence)
 }
 
+// DeleteLocalUser mocks base method.
+func (m *MockStore) DeleteLocalUser(username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocalUser", username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocalUser indicates an expected call of DeleteLocalUser.
+func (mr *MockStoreMockRecorder) DeleteLocalUser(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocalUser", reflect.TypeOf((*MockStore)(nil).DeleteLocalUser), username)
+}
+
+// DeleteNotificationEndpoint mocks base method.
+func (m *MockStore) DeleteNotificationEndpoint(endpointID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationEndpoint", endpointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationEndpoint indicates an expected call of DeleteNotificationEndpoint.
+func (mr *MockStoreMockRecorder) DeleteNotificationEndpoint(endpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteNotificationEndpoint), endpointID)
+}
+
 // DeletePendingScheduledInstanceSnapshots mocks base method.
 func (m *MockStore) DeletePendingScheduledInstanceSnapshots(clusterID string) error {
 	m.ctrl.T.Helper()
@@ -267,32 +370,61 @@ func (mr *MockStoreMockRecorder) DeleteSession(sessionID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), sessionID)
 }
 
+// DeleteUserSessions mocks base method.
+func (m *MockStore) DeleteUserSessions(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockStoreMockRecorder) DeleteUserSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockStore)(nil).DeleteUserSessions), userID)
+}
+
 // FlagInvalidPassword mocks base method.
-func (m *MockStore) FlagInvalidPassword() error {
+func (m *MockStore) FlagInvalidPassword(username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FlagInvalidPassword")
+	ret := m.ctrl.Call(m, "FlagInvalidPassword", username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FlagInvalidPassword indicates an expected call of FlagInvalidPassword.
-func (mr *MockStoreMockRecorder) FlagInvalidPassword() *gomock.Call {
+func (mr *MockStoreMockRecorder) FlagInvalidPassword(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagInvalidPassword", reflect.TypeOf((*MockStore)(nil).FlagInvalidPassword))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagInvalidPassword", reflect.TypeOf((*MockStore)(nil).FlagInvalidPassword), username)
 }
 
 // FlagSuccessfulLogin mocks base method.
-func (m *MockStore) FlagSuccessfulLogin() error {
+func (m *MockStore) FlagSuccessfulLogin(username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FlagSuccessfulLogin")
+	ret := m.ctrl.Call(m, "FlagSuccessfulLogin", username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FlagSuccessfulLogin indicates an expected call of FlagSuccessfulLogin.
-func (mr *MockStoreMockRecorder) FlagSuccessfulLogin() *gomock.Call {
+func (mr *MockStoreMockRecorder) FlagSuccessfulLogin(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagSuccessfulLogin", reflect.TypeOf((*MockStore)(nil).FlagSuccessfulLogin))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagSuccessfulLogin", reflect.TypeOf((*MockStore)(nil).FlagSuccessfulLogin), username)
+}
+
+// GetAPIToken mocks base method.
+func (m *MockStore) GetAPIToken(tokenID string) (*types3.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIToken", tokenID)
+	ret0, _ := ret[0].(*types3.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIToken indicates an expected call of GetAPIToken.
+func (mr *MockStoreMockRecorder) GetAPIToken(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIToken", reflect.TypeOf((*MockStore)(nil).GetAPIToken), tokenID)
 }
 
 // GetAirgapInstallStatus mocks base method.
@@ -326,10 +458,10 @@ func (mr *MockStoreMockRecorder) GetAllAppLicenses() *gomock.Call {
 }
 
 // GetApp mocks base method.
-func (m *MockStore) GetApp(appID string) (*types3.App, error) {
+func (m *MockStore) GetApp(appID string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetApp", appID)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -341,10 +473,10 @@ func (mr *MockStoreMockRecorder) GetApp(appID interface{}) *gomock.Call {
 }
 
 // GetAppFromSlug mocks base method.
-func (m *MockStore) GetAppFromSlug(slug string) (*types3.App, error) {
+func (m *MockStore) GetAppFromSlug(slug string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppFromSlug", slug)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -550,10 +682,10 @@ func (mr *MockStoreMockRecorder) GetDownstreamOutput(appID, clusterID, sequence
 }
 
 // GetDownstreamVersionStatus mocks base method.
-func (m *MockStore) GetDownstreamVersionStatus(appID string, sequence int64) (types11.DownstreamVersionStatus, error) {
+func (m *MockStore) GetDownstreamVersionStatus(appID string, sequence int64) (types14.DownstreamVersionStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDownstreamVersionStatus", appID, sequence)
-	ret0, _ := ret[0].(types11.DownstreamVersionStatus)
+	ret0, _ := ret[0].(types14.DownstreamVersionStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -609,6 +741,51 @@ func (mr *MockStoreMockRecorder) GetLicenseForAppVersion(appID, sequence interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseForAppVersion", reflect.TypeOf((*MockStore)(nil).GetLicenseForAppVersion), appID, sequence)
 }
 
+// GetLocalUser mocks base method.
+func (m *MockStore) GetLocalUser(username string) (*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalUser", username)
+	ret0, _ := ret[0].(*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalUser indicates an expected call of GetLocalUser.
+func (mr *MockStoreMockRecorder) GetLocalUser(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalUser", reflect.TypeOf((*MockStore)(nil).GetLocalUser), username)
+}
+
+// GetNextAppSequence mocks base method.
+func (m *MockStore) GetNextAppSequence(appID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextAppSequence", appID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextAppSequence indicates an expected call of GetNextAppSequence.
+func (mr *MockStoreMockRecorder) GetNextAppSequence(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextAppSequence", reflect.TypeOf((*MockStore)(nil).GetNextAppSequence), appID)
+}
+
+// GetNotificationEndpoint mocks base method.
+func (m *MockStore) GetNotificationEndpoint(endpointID string) (*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationEndpoint", endpointID)
+	ret0, _ := ret[0].(*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationEndpoint indicates an expected call of GetNotificationEndpoint.
+func (mr *MockStoreMockRecorder) GetNotificationEndpoint(endpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEndpoint", reflect.TypeOf((*MockStore)(nil).GetNotificationEndpoint), endpointID)
+}
+
 // GetParentSequenceForSequence mocks base method.
 func (m *MockStore) GetParentSequenceForSequence(appID, clusterID string, sequence int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -655,10 +832,10 @@ func (mr *MockStoreMockRecorder) GetPendingAirgapUploadApp() *gomock.Call {
 }
 
 // GetPendingInstallationStatus mocks base method.
-func (m *MockStore) GetPendingInstallationStatus() (*types6.InstallStatus, error) {
+func (m *MockStore) GetPendingInstallationStatus() (*types9.InstallStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPendingInstallationStatus")
-	ret0, _ := ret[0].(*types6.InstallStatus)
+	ret0, _ := ret[0].(*types9.InstallStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -700,10 +877,10 @@ func (mr *MockStoreMockRecorder) GetPreflightProgress(appID, sequence interface{
 }
 
 // GetPreflightResults mocks base method.
-func (m *MockStore) GetPreflightResults(appID string, sequence int64) (*types7.PreflightResult, error) {
+func (m *MockStore) GetPreflightResults(appID string, sequence int64) (*types10.PreflightResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPreflightResults", appID, sequence)
-	ret0, _ := ret[0].(*types7.PreflightResult)
+	ret0, _ := ret[0].(*types10.PreflightResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -760,10 +937,10 @@ func (mr *MockStoreMockRecorder) GetRedactions(bundleID interface{}) *gomock.Cal
 }
 
 // GetRegistryDetailsForApp mocks base method.
-func (m *MockStore) GetRegistryDetailsForApp(appID string) (types8.RegistrySettings, error) {
+func (m *MockStore) GetRegistryDetailsForApp(appID string) (types11.RegistrySettings, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetRegistryDetailsForApp", appID)
-	ret0, _ := ret[0].(types8.RegistrySettings)
+	ret0, _ := ret[0].(types11.RegistrySettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -775,10 +952,10 @@ func (mr *MockStoreMockRecorder) GetRegistryDetailsForApp(appID interface{}) *go
 }
 
 // GetSession mocks base method.
-func (m *MockStore) GetSession(sessionID string) (*types10.Session, error) {
+func (m *MockStore) GetSession(sessionID string) (*types13.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSession", sessionID)
-	ret0, _ := ret[0].(*types10.Session)
+	ret0, _ := ret[0].(*types13.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -805,10 +982,10 @@ func (mr *MockStoreMockRecorder) GetSharedPasswordBcrypt() *gomock.Call {
 }
 
 // GetStatusForVersion mocks base method.
-func (m *MockStore) GetStatusForVersion(appID, clusterID string, sequence int64) (types11.DownstreamVersionStatus, error) {
+func (m *MockStore) GetStatusForVersion(appID, clusterID string, sequence int64) (types14.DownstreamVersionStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStatusForVersion", appID, clusterID, sequence)
-	ret0, _ := ret[0].(types11.DownstreamVersionStatus)
+	ret0, _ := ret[0].(types14.DownstreamVersionStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -820,10 +997,10 @@ func (mr *MockStoreMockRecorder) GetStatusForVersion(appID, clusterID, sequence
 }
 
 // GetSupportBundle mocks base method.
-func (m *MockStore) GetSupportBundle(bundleID string) (*types12.SupportBundle, error) {
+func (m *MockStore) GetSupportBundle(bundleID string) (*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundle", bundleID)
-	ret0, _ := ret[0].(*types12.SupportBundle)
+	ret0, _ := ret[0].(*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -835,10 +1012,10 @@ func (mr *MockStoreMockRecorder) GetSupportBundle(bundleID interface{}) *gomock.
 }
 
 // GetSupportBundleAnalysis mocks base method.
-func (m *MockStore) GetSupportBundleAnalysis(bundleID string) (*types12.SupportBundleAnalysis, error) {
+func (m *MockStore) GetSupportBundleAnalysis(bundleID string) (*types15.SupportBundleAnalysis, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundleAnalysis", bundleID)
-	ret0, _ := ret[0].(*types12.SupportBundleAnalysis)
+	ret0, _ := ret[0].(*types15.SupportBundleAnalysis)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -984,7 +1161,7 @@ func (mr *MockStoreMockRecorder) IsRollbackSupportedForVersion(appID, sequence i
 }
 
 // IsSnapshotsSupportedForVersion mocks base method.
-func (m *MockStore) IsSnapshotsSupportedForVersion(a *types3.App, sequence int64, renderer types9.Renderer) (bool, error) {
+func (m *MockStore) IsSnapshotsSupportedForVersion(a *types4.App, sequence int64, renderer types12.Renderer) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
 	ret0, _ := ret[0].(bool)
@@ -998,11 +1175,26 @@ func (mr *MockStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequence, ren
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
 }
 
+// ListAPITokens mocks base method.
+func (m *MockStore) ListAPITokens() ([]*types3.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPITokens")
+	ret0, _ := ret[0].([]*types3.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPITokens indicates an expected call of ListAPITokens.
+func (mr *MockStoreMockRecorder) ListAPITokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPITokens", reflect.TypeOf((*MockStore)(nil).ListAPITokens))
+}
+
 // ListAppsForDownstream mocks base method.
-func (m *MockStore) ListAppsForDownstream(clusterID string) ([]*types3.App, error) {
+func (m *MockStore) ListAppsForDownstream(clusterID string) ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListAppsForDownstream", clusterID)
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1013,6 +1205,22 @@ func (mr *MockStoreMockRecorder) ListAppsForDownstream(clusterID interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppsForDownstream", reflect.TypeOf((*MockStore)(nil).ListAppsForDownstream), clusterID)
 }
 
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(opts types5.ListEventsOptions) ([]*types5.Event, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", opts)
+	ret0, _ := ret[0].([]*types5.Event)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), opts)
+}
+
 // ListClusters mocks base method.
 func (m *MockStore) ListClusters() ([]*types1.Downstream, error) {
 	m.ctrl.T.Helper()
@@ -1059,10 +1267,10 @@ func (mr *MockStoreMockRecorder) ListInstalledAppSlugs() *gomock.Call {
 }
 
 // ListInstalledApps mocks base method.
-func (m *MockStore) ListInstalledApps() ([]*types3.App, error) {
+func (m *MockStore) ListInstalledApps() ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInstalledApps")
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1073,11 +1281,56 @@ func (mr *MockStoreMockRecorder) ListInstalledApps() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstalledApps", reflect.TypeOf((*MockStore)(nil).ListInstalledApps))
 }
 
+// ListLocalUsers mocks base method.
+func (m *MockStore) ListLocalUsers() ([]*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocalUsers")
+	ret0, _ := ret[0].([]*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocalUsers indicates an expected call of ListLocalUsers.
+func (mr *MockStoreMockRecorder) ListLocalUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocalUsers", reflect.TypeOf((*MockStore)(nil).ListLocalUsers))
+}
+
+// ListNotificationDeliveries mocks base method.
+func (m *MockStore) ListNotificationDeliveries(endpointID string, limit int) ([]*types8.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationDeliveries", endpointID, limit)
+	ret0, _ := ret[0].([]*types8.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationDeliveries indicates an expected call of ListNotificationDeliveries.
+func (mr *MockStoreMockRecorder) ListNotificationDeliveries(endpointID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationDeliveries", reflect.TypeOf((*MockStore)(nil).ListNotificationDeliveries), endpointID, limit)
+}
+
+// ListNotificationEndpoints mocks base method.
+func (m *MockStore) ListNotificationEndpoints() ([]*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationEndpoints")
+	ret0, _ := ret[0].([]*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationEndpoints indicates an expected call of ListNotificationEndpoints.
+func (mr *MockStoreMockRecorder) ListNotificationEndpoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationEndpoints", reflect.TypeOf((*MockStore)(nil).ListNotificationEndpoints))
+}
+
 // ListPendingScheduledInstanceSnapshots mocks base method.
-func (m *MockStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types5.ScheduledInstanceSnapshot, error) {
+func (m *MockStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types7.ScheduledInstanceSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledInstanceSnapshots", clusterID)
-	ret0, _ := ret[0].([]types5.ScheduledInstanceSnapshot)
+	ret0, _ := ret[0].([]types7.ScheduledInstanceSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1089,10 +1342,10 @@ func (mr *MockStoreMockRecorder) ListPendingScheduledInstanceSnapshots(clusterID
 }
 
 // ListPendingScheduledSnapshots mocks base method.
-func (m *MockStore) ListPendingScheduledSnapshots(appID string) ([]types5.ScheduledSnapshot, error) {
+func (m *MockStore) ListPendingScheduledSnapshots(appID string) ([]types7.ScheduledSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledSnapshots", appID)
-	ret0, _ := ret[0].([]types5.ScheduledSnapshot)
+	ret0, _ := ret[0].([]types7.ScheduledSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1104,10 +1357,10 @@ func (mr *MockStoreMockRecorder) ListPendingScheduledSnapshots(appID interface{}
 }
 
 // ListSupportBundles mocks base method.
-func (m *MockStore) ListSupportBundles(appID string) ([]*types12.SupportBundle, error) {
+func (m *MockStore) ListSupportBundles(appID string) ([]*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSupportBundles", appID)
-	ret0, _ := ret[0].([]*types12.SupportBundle)
+	ret0, _ := ret[0].([]*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1118,6 +1371,20 @@ func (mr *MockStoreMockRecorder) ListSupportBundles(appID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSupportBundles", reflect.TypeOf((*MockStore)(nil).ListSupportBundles), appID)
 }
 
+// MarkAsCurrentDownstreamVersion mocks base method.
+func (m *MockStore) MarkAsCurrentDownstreamVersion(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsCurrentDownstreamVersion", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsCurrentDownstreamVersion indicates an expected call of MarkAsCurrentDownstreamVersion.
+func (mr *MockStoreMockRecorder) MarkAsCurrentDownstreamVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsCurrentDownstreamVersion", reflect.TypeOf((*MockStore)(nil).MarkAsCurrentDownstreamVersion), appID, sequence)
+}
+
 // RemoveApp mocks base method.
 func (m *MockStore) RemoveApp(appID string) error {
 	m.ctrl.T.Helper()
@@ -1146,6 +1413,20 @@ func (mr *MockStoreMockRecorder) ResetAirgapInstallInProgress(appID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAirgapInstallInProgress", reflect.TypeOf((*MockStore)(nil).ResetAirgapInstallInProgress), appID)
 }
 
+// ResetAppRestore mocks base method.
+func (m *MockStore) ResetAppRestore(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAppRestore", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAppRestore indicates an expected call of ResetAppRestore.
+func (mr *MockStoreMockRecorder) ResetAppRestore(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAppRestore", reflect.TypeOf((*MockStore)(nil).ResetAppRestore), appID)
+}
+
 // ResetPreflightResults mocks base method.
 func (m *MockStore) ResetPreflightResults(appID string, sequence int64) error {
 	m.ctrl.T.Helper()
@@ -1160,6 +1441,20 @@ func (mr *MockStoreMockRecorder) ResetPreflightResults(appID, sequence interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPreflightResults", reflect.TypeOf((*MockStore)(nil).ResetPreflightResults), appID, sequence)
 }
 
+// RevokeAPIToken mocks base method.
+func (m *MockStore) RevokeAPIToken(tokenID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIToken", tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockStoreMockRecorder) RevokeAPIToken(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockStore)(nil).RevokeAPIToken), tokenID)
+}
+
 // RunMigrations mocks base method.
 func (m *MockStore) RunMigrations() {
 	m.ctrl.T.Helper()
@@ -1200,6 +1495,34 @@ func (mr *MockStoreMockRecorder) SetAppIsAirgap(appID, isAirgap interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppIsAirgap", reflect.TypeOf((*MockStore)(nil).SetAppIsAirgap), appID, isAirgap)
 }
 
+// SetAppRestoreInProgress mocks base method.
+func (m *MockStore) SetAppRestoreInProgress(appID, snapshotName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppRestoreInProgress", appID, snapshotName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppRestoreInProgress indicates an expected call of SetAppRestoreInProgress.
+func (mr *MockStoreMockRecorder) SetAppRestoreInProgress(appID, snapshotName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppRestoreInProgress", reflect.TypeOf((*MockStore)(nil).SetAppRestoreInProgress), appID, snapshotName)
+}
+
+// SetAppRestoreUndeployStatus mocks base method.
+func (m *MockStore) SetAppRestoreUndeployStatus(appID string, undeployStatus types4.UndeployStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppRestoreUndeployStatus", appID, undeployStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppRestoreUndeployStatus indicates an expected call of SetAppRestoreUndeployStatus.
+func (mr *MockStoreMockRecorder) SetAppRestoreUndeployStatus(appID, undeployStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppRestoreUndeployStatus", reflect.TypeOf((*MockStore)(nil).SetAppRestoreUndeployStatus), appID, undeployStatus)
+}
+
 // SetAppStatus mocks base method.
 func (m *MockStore) SetAppStatus(appID string, resourceStates []types0.ResourceState, updatedAt time.Time, sequence int64) error {
 	m.ctrl.T.Helper()
@@ -1214,6 +1537,20 @@ func (mr *MockStoreMockRecorder) SetAppStatus(appID, resourceStates, updatedAt,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppStatus", reflect.TypeOf((*MockStore)(nil).SetAppStatus), appID, resourceStates, updatedAt, sequence)
 }
 
+// SetAutoDeployPolicy mocks base method.
+func (m *MockStore) SetAutoDeployPolicy(appID string, policy *types4.AutoDeployPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAutoDeployPolicy", appID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAutoDeployPolicy indicates an expected call of SetAutoDeployPolicy.
+func (mr *MockStoreMockRecorder) SetAutoDeployPolicy(appID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAutoDeployPolicy", reflect.TypeOf((*MockStore)(nil).SetAutoDeployPolicy), appID, policy)
+}
+
 // SetDownstreamVersionPendingPreflight mocks base method.
 func (m *MockStore) SetDownstreamVersionPendingPreflight(appID string, sequence int64) error {
 	m.ctrl.T.Helper()
@@ -1256,6 +1593,20 @@ func (mr *MockStoreMockRecorder) SetIgnorePreflightPermissionErrors(appID, seque
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIgnorePreflightPermissionErrors", reflect.TypeOf((*MockStore)(nil).SetIgnorePreflightPermissionErrors), appID, sequence)
 }
 
+// SetInstanceSnapshotRetentionPolicy mocks base method.
+func (m *MockStore) SetInstanceSnapshotRetentionPolicy(clusterID string, policy *types7.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotRetentionPolicy", clusterID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotRetentionPolicy indicates an expected call of SetInstanceSnapshotRetentionPolicy.
+func (mr *MockStoreMockRecorder) SetInstanceSnapshotRetentionPolicy(clusterID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotRetentionPolicy", reflect.TypeOf((*MockStore)(nil).SetInstanceSnapshotRetentionPolicy), clusterID, policy)
+}
+
 // SetInstanceSnapshotSchedule mocks base method.
 func (m *MockStore) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule string) error {
 	m.ctrl.T.Helper()
@@ -1284,6 +1635,20 @@ func (mr *MockStoreMockRecorder) SetInstanceSnapshotTTL(clusterID, snapshotTTL i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotTTL", reflect.TypeOf((*MockStore)(nil).SetInstanceSnapshotTTL), clusterID, snapshotTTL)
 }
 
+// SetInstanceSnapshotVerificationPolicy mocks base method.
+func (m *MockStore) SetInstanceSnapshotVerificationPolicy(clusterID string, policy *types7.VerificationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotVerificationPolicy", clusterID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotVerificationPolicy indicates an expected call of SetInstanceSnapshotVerificationPolicy.
+func (mr *MockStoreMockRecorder) SetInstanceSnapshotVerificationPolicy(clusterID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotVerificationPolicy", reflect.TypeOf((*MockStore)(nil).SetInstanceSnapshotVerificationPolicy), clusterID, policy)
+}
+
 // SetIsKotsadmIDGenerated mocks base method.
 func (m *MockStore) SetIsKotsadmIDGenerated() error {
 	m.ctrl.T.Helper()
@@ -1298,6 +1663,48 @@ func (mr *MockStoreMockRecorder) SetIsKotsadmIDGenerated() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsKotsadmIDGenerated", reflect.TypeOf((*MockStore)(nil).SetIsKotsadmIDGenerated))
 }
 
+// SetLastUpdateCheckAt mocks base method.
+func (m *MockStore) SetLastUpdateCheckAt(appID string, checkedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastUpdateCheckAt", appID, checkedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastUpdateCheckAt indicates an expected call of SetLastUpdateCheckAt.
+func (mr *MockStoreMockRecorder) SetLastUpdateCheckAt(appID, checkedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastUpdateCheckAt", reflect.TypeOf((*MockStore)(nil).SetLastUpdateCheckAt), appID, checkedAt)
+}
+
+// SetLocalUserPassword mocks base method.
+func (m *MockStore) SetLocalUserPassword(username string, passwordBcrypt []byte, mustChangePassword bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocalUserPassword", username, passwordBcrypt, mustChangePassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocalUserPassword indicates an expected call of SetLocalUserPassword.
+func (mr *MockStoreMockRecorder) SetLocalUserPassword(username, passwordBcrypt, mustChangePassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalUserPassword", reflect.TypeOf((*MockStore)(nil).SetLocalUserPassword), username, passwordBcrypt, mustChangePassword)
+}
+
+// SetLocalUserRoles mocks base method.
+func (m *MockStore) SetLocalUserRoles(username string, roleIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocalUserRoles", username, roleIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocalUserRoles indicates an expected call of SetLocalUserRoles.
+func (mr *MockStoreMockRecorder) SetLocalUserRoles(username, roleIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalUserRoles", reflect.TypeOf((*MockStore)(nil).SetLocalUserRoles), username, roleIDs)
+}
+
 // SetPreflightProgress mocks base method.
 func (m *MockStore) SetPreflightProgress(appID string, sequence int64, progress string) error {
 	m.ctrl.T.Helper()
@@ -1354,6 +1761,20 @@ func (mr *MockStoreMockRecorder) SetRedactions(bundleID, redacts interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRedactions", reflect.TypeOf((*MockStore)(nil).SetRedactions), bundleID, redacts)
 }
 
+// SetSnapshotRetentionPolicy mocks base method.
+func (m *MockStore) SetSnapshotRetentionPolicy(appID string, policy *types7.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSnapshotRetentionPolicy", appID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSnapshotRetentionPolicy indicates an expected call of SetSnapshotRetentionPolicy.
+func (mr *MockStoreMockRecorder) SetSnapshotRetentionPolicy(appID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotRetentionPolicy", reflect.TypeOf((*MockStore)(nil).SetSnapshotRetentionPolicy), appID, policy)
+}
+
 // SetSnapshotSchedule mocks base method.
 func (m *MockStore) SetSnapshotSchedule(appID, snapshotSchedule string) error {
 	m.ctrl.T.Helper()
@@ -1425,7 +1846,7 @@ func (mr *MockStoreMockRecorder) SetUpdateCheckerSpec(appID, updateCheckerSpec i
 }
 
 // UpdateAppLicense mocks base method.
-func (m *MockStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types4.DownstreamGitOps, renderer types9.Renderer) (int64, error) {
+func (m *MockStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types6.DownstreamGitOps, renderer types12.Renderer) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateAppLicense", appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
 	ret0, _ := ret[0].(int64)
@@ -1439,6 +1860,20 @@ func (mr *MockStoreMockRecorder) UpdateAppLicense(appID, sequence, archiveDir, n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppLicense", reflect.TypeOf((*MockStore)(nil).UpdateAppLicense), appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
 }
 
+// UpdateAppVersionConfigValues mocks base method.
+func (m *MockStore) UpdateAppVersionConfigValues(appID string, sequence int64, filesInDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppVersionConfigValues", appID, sequence, filesInDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppVersionConfigValues indicates an expected call of UpdateAppVersionConfigValues.
+func (mr *MockStoreMockRecorder) UpdateAppVersionConfigValues(appID, sequence, filesInDir interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppVersionConfigValues", reflect.TypeOf((*MockStore)(nil).UpdateAppVersionConfigValues), appID, sequence, filesInDir)
+}
+
 // UpdateAppVersionInstallationSpec mocks base method.
 func (m *MockStore) UpdateAppVersionInstallationSpec(appID string, sequence int64, spec v1beta1.Installation) error {
 	m.ctrl.T.Helper()
@@ -1481,6 +1916,20 @@ func (mr *MockStoreMockRecorder) UpdateDownstreamVersionStatus(appID, sequence,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDownstreamVersionStatus", reflect.TypeOf((*MockStore)(nil).UpdateDownstreamVersionStatus), appID, sequence, status, statusInfo)
 }
 
+// UpdateNotificationEndpoint mocks base method.
+func (m *MockStore) UpdateNotificationEndpoint(endpoint *types8.Endpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationEndpoint", endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationEndpoint indicates an expected call of UpdateNotificationEndpoint.
+func (mr *MockStoreMockRecorder) UpdateNotificationEndpoint(endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationEndpoint", reflect.TypeOf((*MockStore)(nil).UpdateNotificationEndpoint), endpoint)
+}
+
 // UpdateRegistry mocks base method.
 func (m *MockStore) UpdateRegistry(appID, hostname, username, password, namespace string, isReadOnly bool) error {
 	m.ctrl.T.Helper()
@@ -1524,7 +1973,7 @@ func (mr *MockStoreMockRecorder) UpdateScheduledSnapshot(snapshotID, backupName
 }
 
 // UpdateSupportBundle mocks base method.
-func (m *MockStore) UpdateSupportBundle(bundle *types12.SupportBundle) error {
+func (m *MockStore) UpdateSupportBundle(bundle *types15.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSupportBundle", bundle)
 	ret0, _ := ret[0].(error)
@@ -1653,10 +2102,10 @@ func (mr *MockRegistryStoreMockRecorder) GetAppIDsFromRegistry(hostname interfac
 }
 
 // GetRegistryDetailsForApp mocks base method.
-func (m *MockRegistryStore) GetRegistryDetailsForApp(appID string) (types8.RegistrySettings, error) {
+func (m *MockRegistryStore) GetRegistryDetailsForApp(appID string) (types11.RegistrySettings, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetRegistryDetailsForApp", appID)
-	ret0, _ := ret[0].(types8.RegistrySettings)
+	ret0, _ := ret[0].(types11.RegistrySettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1705,7 +2154,7 @@ func (m *MockSupportBundleStore) EXPECT() *MockSupportBundleStoreMockRecorder {
 }
 
 // CreateInProgressSupportBundle mocks base method.
-func (m *MockSupportBundleStore) CreateInProgressSupportBundle(supportBundle *types12.SupportBundle) error {
+func (m *MockSupportBundleStore) CreateInProgressSupportBundle(supportBundle *types15.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInProgressSupportBundle", supportBundle)
 	ret0, _ := ret[0].(error)
@@ -1719,10 +2168,10 @@ func (mr *MockSupportBundleStoreMockRecorder) CreateInProgressSupportBundle(supp
 }
 
 // CreateSupportBundle mocks base method.
-func (m *MockSupportBundleStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types12.SupportBundle, error) {
+func (m *MockSupportBundleStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSupportBundle", bundleID, appID, archivePath, marshalledTree)
-	ret0, _ := ret[0].(*types12.SupportBundle)
+	ret0, _ := ret[0].(*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1749,10 +2198,10 @@ func (mr *MockSupportBundleStoreMockRecorder) GetRedactions(bundleID interface{}
 }
 
 // GetSupportBundle mocks base method.
-func (m *MockSupportBundleStore) GetSupportBundle(bundleID string) (*types12.SupportBundle, error) {
+func (m *MockSupportBundleStore) GetSupportBundle(bundleID string) (*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundle", bundleID)
-	ret0, _ := ret[0].(*types12.SupportBundle)
+	ret0, _ := ret[0].(*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1764,10 +2213,10 @@ func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundle(bundleID interfac
 }
 
 // GetSupportBundleAnalysis mocks base method.
-func (m *MockSupportBundleStore) GetSupportBundleAnalysis(bundleID string) (*types12.SupportBundleAnalysis, error) {
+func (m *MockSupportBundleStore) GetSupportBundleAnalysis(bundleID string) (*types15.SupportBundleAnalysis, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundleAnalysis", bundleID)
-	ret0, _ := ret[0].(*types12.SupportBundleAnalysis)
+	ret0, _ := ret[0].(*types15.SupportBundleAnalysis)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1794,10 +2243,10 @@ func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundleArchive(bundleID i
 }
 
 // ListSupportBundles mocks base method.
-func (m *MockSupportBundleStore) ListSupportBundles(appID string) ([]*types12.SupportBundle, error) {
+func (m *MockSupportBundleStore) ListSupportBundles(appID string) ([]*types15.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSupportBundles", appID)
-	ret0, _ := ret[0].([]*types12.SupportBundle)
+	ret0, _ := ret[0].([]*types15.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1837,7 +2286,7 @@ func (mr *MockSupportBundleStoreMockRecorder) SetSupportBundleAnalysis(bundleID,
 }
 
 // UpdateSupportBundle mocks base method.
-func (m *MockSupportBundleStore) UpdateSupportBundle(bundle *types12.SupportBundle) error {
+func (m *MockSupportBundleStore) UpdateSupportBundle(bundle *types15.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSupportBundle", bundle)
 	ret0, _ := ret[0].(error)
@@ -1903,10 +2352,10 @@ func (mr *MockPreflightStoreMockRecorder) GetPreflightProgress(appID, sequence i
 }
 
 // GetPreflightResults mocks base method.
-func (m *MockPreflightStore) GetPreflightResults(appID string, sequence int64) (*types7.PreflightResult, error) {
+func (m *MockPreflightStore) GetPreflightResults(appID string, sequence int64) (*types10.PreflightResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPreflightResults", appID, sequence)
-	ret0, _ := ret[0].(*types7.PreflightResult)
+	ret0, _ := ret[0].(*types10.PreflightResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2211,10 +2660,10 @@ func (m *MockSessionStore) EXPECT() *MockSessionStoreMockRecorder {
 }
 
 // CreateSession mocks base method.
-func (m *MockSessionStore) CreateSession(user *types13.User, issuedAt, expiresAt time.Time, roles []string) (*types10.Session, error) {
+func (m *MockSessionStore) CreateSession(user *types16.User, issuedAt, expiresAt time.Time, roles []string) (*types13.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSession", user, issuedAt, expiresAt, roles)
-	ret0, _ := ret[0].(*types10.Session)
+	ret0, _ := ret[0].(*types13.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2239,16 +2688,30 @@ func (mr *MockSessionStoreMockRecorder) DeleteSession(sessionID interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionStore)(nil).DeleteSession), sessionID)
 }
 
-// GetSession mocks base method.
-func (m *MockSessionStore) GetSession(sessionID string) (*types10.Session, error) {
+// DeleteUserSessions mocks base method.
+func (m *MockSessionStore) DeleteUserSessions(userID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", sessionID)
-	ret0, _ := ret[0].(*types10.Session)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteUserSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetSession indicates an expected call of GetSession.
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockSessionStoreMockRecorder) DeleteUserSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockSessionStore)(nil).DeleteUserSessions), userID)
+}
+
+// GetSession mocks base method.
+func (m *MockSessionStore) GetSession(sessionID string) (*types13.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", sessionID)
+	ret0, _ := ret[0].(*types13.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
 func (mr *MockSessionStoreMockRecorder) GetSession(sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionStore)(nil).GetSession), sessionID)
@@ -2344,10 +2807,10 @@ func (mr *MockAppStoreMockRecorder) AddAppToAllDownstreams(appID interface{}) *g
 }
 
 // CreateApp mocks base method.
-func (m *MockAppStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types3.App, error) {
+func (m *MockAppStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateApp", name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2359,10 +2822,10 @@ func (mr *MockAppStoreMockRecorder) CreateApp(name, upstreamURI, licenseData, is
 }
 
 // GetApp mocks base method.
-func (m *MockAppStore) GetApp(appID string) (*types3.App, error) {
+func (m *MockAppStore) GetApp(appID string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetApp", appID)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2374,10 +2837,10 @@ func (mr *MockAppStoreMockRecorder) GetApp(appID interface{}) *gomock.Call {
 }
 
 // GetAppFromSlug mocks base method.
-func (m *MockAppStore) GetAppFromSlug(slug string) (*types3.App, error) {
+func (m *MockAppStore) GetAppFromSlug(slug string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppFromSlug", slug)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2434,10 +2897,10 @@ func (mr *MockAppStoreMockRecorder) IsGitOpsEnabledForApp(appID interface{}) *go
 }
 
 // ListAppsForDownstream mocks base method.
-func (m *MockAppStore) ListAppsForDownstream(clusterID string) ([]*types3.App, error) {
+func (m *MockAppStore) ListAppsForDownstream(clusterID string) ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListAppsForDownstream", clusterID)
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2479,10 +2942,10 @@ func (mr *MockAppStoreMockRecorder) ListInstalledAppSlugs() *gomock.Call {
 }
 
 // ListInstalledApps mocks base method.
-func (m *MockAppStore) ListInstalledApps() ([]*types3.App, error) {
+func (m *MockAppStore) ListInstalledApps() ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInstalledApps")
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2507,6 +2970,20 @@ func (mr *MockAppStoreMockRecorder) RemoveApp(appID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveApp", reflect.TypeOf((*MockAppStore)(nil).RemoveApp), appID)
 }
 
+// ResetAppRestore mocks base method.
+func (m *MockAppStore) ResetAppRestore(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAppRestore", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAppRestore indicates an expected call of ResetAppRestore.
+func (mr *MockAppStoreMockRecorder) ResetAppRestore(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAppRestore", reflect.TypeOf((*MockAppStore)(nil).ResetAppRestore), appID)
+}
+
 // SetAppInstallState mocks base method.
 func (m *MockAppStore) SetAppInstallState(appID, state string) error {
 	m.ctrl.T.Helper()
@@ -2521,6 +2998,76 @@ func (mr *MockAppStoreMockRecorder) SetAppInstallState(appID, state interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppInstallState", reflect.TypeOf((*MockAppStore)(nil).SetAppInstallState), appID, state)
 }
 
+// SetAppRestoreInProgress mocks base method.
+func (m *MockAppStore) SetAppRestoreInProgress(appID, snapshotName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppRestoreInProgress", appID, snapshotName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppRestoreInProgress indicates an expected call of SetAppRestoreInProgress.
+func (mr *MockAppStoreMockRecorder) SetAppRestoreInProgress(appID, snapshotName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppRestoreInProgress", reflect.TypeOf((*MockAppStore)(nil).SetAppRestoreInProgress), appID, snapshotName)
+}
+
+// SetAppRestoreUndeployStatus mocks base method.
+func (m *MockAppStore) SetAppRestoreUndeployStatus(appID string, undeployStatus types4.UndeployStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppRestoreUndeployStatus", appID, undeployStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppRestoreUndeployStatus indicates an expected call of SetAppRestoreUndeployStatus.
+func (mr *MockAppStoreMockRecorder) SetAppRestoreUndeployStatus(appID, undeployStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppRestoreUndeployStatus", reflect.TypeOf((*MockAppStore)(nil).SetAppRestoreUndeployStatus), appID, undeployStatus)
+}
+
+// SetAutoDeployPolicy mocks base method.
+func (m *MockAppStore) SetAutoDeployPolicy(appID string, policy *types4.AutoDeployPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAutoDeployPolicy", appID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAutoDeployPolicy indicates an expected call of SetAutoDeployPolicy.
+func (mr *MockAppStoreMockRecorder) SetAutoDeployPolicy(appID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAutoDeployPolicy", reflect.TypeOf((*MockAppStore)(nil).SetAutoDeployPolicy), appID, policy)
+}
+
+// SetLastUpdateCheckAt mocks base method.
+func (m *MockAppStore) SetLastUpdateCheckAt(appID string, checkedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastUpdateCheckAt", appID, checkedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastUpdateCheckAt indicates an expected call of SetLastUpdateCheckAt.
+func (mr *MockAppStoreMockRecorder) SetLastUpdateCheckAt(appID, checkedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastUpdateCheckAt", reflect.TypeOf((*MockAppStore)(nil).SetLastUpdateCheckAt), appID, checkedAt)
+}
+
+// SetSnapshotRetentionPolicy mocks base method.
+func (m *MockAppStore) SetSnapshotRetentionPolicy(appID string, policy *types7.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSnapshotRetentionPolicy", appID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSnapshotRetentionPolicy indicates an expected call of SetSnapshotRetentionPolicy.
+func (mr *MockAppStoreMockRecorder) SetSnapshotRetentionPolicy(appID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotRetentionPolicy", reflect.TypeOf((*MockAppStore)(nil).SetSnapshotRetentionPolicy), appID, policy)
+}
+
 // SetSnapshotSchedule mocks base method.
 func (m *MockAppStore) SetSnapshotSchedule(appID, snapshotSchedule string) error {
 	m.ctrl.T.Helper()
@@ -2661,10 +3208,10 @@ func (mr *MockDownstreamStoreMockRecorder) GetDownstreamOutput(appID, clusterID,
 }
 
 // GetDownstreamVersionStatus mocks base method.
-func (m *MockDownstreamStore) GetDownstreamVersionStatus(appID string, sequence int64) (types11.DownstreamVersionStatus, error) {
+func (m *MockDownstreamStore) GetDownstreamVersionStatus(appID string, sequence int64) (types14.DownstreamVersionStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDownstreamVersionStatus", appID, sequence)
-	ret0, _ := ret[0].(types11.DownstreamVersionStatus)
+	ret0, _ := ret[0].(types14.DownstreamVersionStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2751,10 +3298,10 @@ func (mr *MockDownstreamStoreMockRecorder) GetPreviouslyDeployedSequence(appID,
 }
 
 // GetStatusForVersion mocks base method.
-func (m *MockDownstreamStore) GetStatusForVersion(appID, clusterID string, sequence int64) (types11.DownstreamVersionStatus, error) {
+func (m *MockDownstreamStore) GetStatusForVersion(appID, clusterID string, sequence int64) (types14.DownstreamVersionStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStatusForVersion", appID, clusterID, sequence)
-	ret0, _ := ret[0].(types11.DownstreamVersionStatus)
+	ret0, _ := ret[0].(types14.DownstreamVersionStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2780,6 +3327,20 @@ func (mr *MockDownstreamStoreMockRecorder) IsDownstreamDeploySuccessful(appID, c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDownstreamDeploySuccessful", reflect.TypeOf((*MockDownstreamStore)(nil).IsDownstreamDeploySuccessful), appID, clusterID, sequence)
 }
 
+// MarkAsCurrentDownstreamVersion mocks base method.
+func (m *MockDownstreamStore) MarkAsCurrentDownstreamVersion(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsCurrentDownstreamVersion", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsCurrentDownstreamVersion indicates an expected call of MarkAsCurrentDownstreamVersion.
+func (mr *MockDownstreamStoreMockRecorder) MarkAsCurrentDownstreamVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsCurrentDownstreamVersion", reflect.TypeOf((*MockDownstreamStore)(nil).MarkAsCurrentDownstreamVersion), appID, sequence)
+}
+
 // SetDownstreamVersionPendingPreflight mocks base method.
 func (m *MockDownstreamStore) SetDownstreamVersionPendingPreflight(appID string, sequence int64) error {
 	m.ctrl.T.Helper()
@@ -2916,10 +3477,10 @@ func (mr *MockSnapshotStoreMockRecorder) DeletePendingScheduledSnapshots(appID i
 }
 
 // ListPendingScheduledInstanceSnapshots mocks base method.
-func (m *MockSnapshotStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types5.ScheduledInstanceSnapshot, error) {
+func (m *MockSnapshotStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types7.ScheduledInstanceSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledInstanceSnapshots", clusterID)
-	ret0, _ := ret[0].([]types5.ScheduledInstanceSnapshot)
+	ret0, _ := ret[0].([]types7.ScheduledInstanceSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2931,10 +3492,10 @@ func (mr *MockSnapshotStoreMockRecorder) ListPendingScheduledInstanceSnapshots(c
 }
 
 // ListPendingScheduledSnapshots mocks base method.
-func (m *MockSnapshotStore) ListPendingScheduledSnapshots(appID string) ([]types5.ScheduledSnapshot, error) {
+func (m *MockSnapshotStore) ListPendingScheduledSnapshots(appID string) ([]types7.ScheduledSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledSnapshots", appID)
-	ret0, _ := ret[0].([]types5.ScheduledSnapshot)
+	ret0, _ := ret[0].([]types7.ScheduledSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -2997,7 +3558,7 @@ func (m *MockVersionStore) EXPECT() *MockVersionStoreMockRecorder {
 }
 
 // CreateAppVersion mocks base method.
-func (m *MockVersionStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types4.DownstreamGitOps) (int64, error) {
+func (m *MockVersionStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types6.DownstreamGitOps) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
 	ret0, _ := ret[0].(int64)
@@ -3069,6 +3630,21 @@ func (mr *MockVersionStoreMockRecorder) GetAppVersionsAfter(appID, sequence inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionsAfter", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionsAfter), appID, sequence)
 }
 
+// GetNextAppSequence mocks base method.
+func (m *MockVersionStore) GetNextAppSequence(appID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextAppSequence", appID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextAppSequence indicates an expected call of GetNextAppSequence.
+func (mr *MockVersionStoreMockRecorder) GetNextAppSequence(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextAppSequence", reflect.TypeOf((*MockVersionStore)(nil).GetNextAppSequence), appID)
+}
+
 // IsIdentityServiceSupportedForVersion mocks base method.
 func (m *MockVersionStore) IsIdentityServiceSupportedForVersion(appID string, sequence int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -3100,7 +3676,7 @@ func (mr *MockVersionStoreMockRecorder) IsRollbackSupportedForVersion(appID, seq
 }
 
 // IsSnapshotsSupportedForVersion mocks base method.
-func (m *MockVersionStore) IsSnapshotsSupportedForVersion(a *types3.App, sequence int64, renderer types9.Renderer) (bool, error) {
+func (m *MockVersionStore) IsSnapshotsSupportedForVersion(a *types4.App, sequence int64, renderer types12.Renderer) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
 	ret0, _ := ret[0].(bool)
@@ -3114,6 +3690,20 @@ func (mr *MockVersionStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequen
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
 }
 
+// UpdateAppVersionConfigValues mocks base method.
+func (m *MockVersionStore) UpdateAppVersionConfigValues(appID string, sequence int64, filesInDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppVersionConfigValues", appID, sequence, filesInDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppVersionConfigValues indicates an expected call of UpdateAppVersionConfigValues.
+func (mr *MockVersionStoreMockRecorder) UpdateAppVersionConfigValues(appID, sequence, filesInDir interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppVersionConfigValues", reflect.TypeOf((*MockVersionStore)(nil).UpdateAppVersionConfigValues), appID, sequence, filesInDir)
+}
+
 // UpdateAppVersionInstallationSpec mocks base method.
 func (m *MockVersionStore) UpdateAppVersionInstallationSpec(appID string, sequence int64, spec v1beta1.Installation) error {
 	m.ctrl.T.Helper()
@@ -3197,7 +3787,7 @@ func (mr *MockLicenseStoreMockRecorder) GetLicenseForAppVersion(appID, sequence
 }
 
 // UpdateAppLicense mocks base method.
-func (m *MockLicenseStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types4.DownstreamGitOps, renderer types9.Renderer) (int64, error) {
+func (m *MockLicenseStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types6.DownstreamGitOps, renderer types12.Renderer) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateAppLicense", appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
 	ret0, _ := ret[0].(int64)
@@ -3234,32 +3824,76 @@ func (m *MockUserStore) EXPECT() *MockUserStoreMockRecorder {
 	return m.recorder
 }
 
+// CreateLocalUser mocks base method.
+func (m *MockUserStore) CreateLocalUser(username string, passwordBcrypt []byte, roleIDs []string) (*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLocalUser", username, passwordBcrypt, roleIDs)
+	ret0, _ := ret[0].(*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLocalUser indicates an expected call of CreateLocalUser.
+func (mr *MockUserStoreMockRecorder) CreateLocalUser(username, passwordBcrypt, roleIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLocalUser", reflect.TypeOf((*MockUserStore)(nil).CreateLocalUser), username, passwordBcrypt, roleIDs)
+}
+
+// DeleteLocalUser mocks base method.
+func (m *MockUserStore) DeleteLocalUser(username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocalUser", username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocalUser indicates an expected call of DeleteLocalUser.
+func (mr *MockUserStoreMockRecorder) DeleteLocalUser(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocalUser", reflect.TypeOf((*MockUserStore)(nil).DeleteLocalUser), username)
+}
+
 // FlagInvalidPassword mocks base method.
-func (m *MockUserStore) FlagInvalidPassword() error {
+func (m *MockUserStore) FlagInvalidPassword(username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FlagInvalidPassword")
+	ret := m.ctrl.Call(m, "FlagInvalidPassword", username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FlagInvalidPassword indicates an expected call of FlagInvalidPassword.
-func (mr *MockUserStoreMockRecorder) FlagInvalidPassword() *gomock.Call {
+func (mr *MockUserStoreMockRecorder) FlagInvalidPassword(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagInvalidPassword", reflect.TypeOf((*MockUserStore)(nil).FlagInvalidPassword))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagInvalidPassword", reflect.TypeOf((*MockUserStore)(nil).FlagInvalidPassword), username)
 }
 
 // FlagSuccessfulLogin mocks base method.
-func (m *MockUserStore) FlagSuccessfulLogin() error {
+func (m *MockUserStore) FlagSuccessfulLogin(username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FlagSuccessfulLogin")
+	ret := m.ctrl.Call(m, "FlagSuccessfulLogin", username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FlagSuccessfulLogin indicates an expected call of FlagSuccessfulLogin.
-func (mr *MockUserStoreMockRecorder) FlagSuccessfulLogin() *gomock.Call {
+func (mr *MockUserStoreMockRecorder) FlagSuccessfulLogin(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagSuccessfulLogin", reflect.TypeOf((*MockUserStore)(nil).FlagSuccessfulLogin), username)
+}
+
+// GetLocalUser mocks base method.
+func (m *MockUserStore) GetLocalUser(username string) (*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalUser", username)
+	ret0, _ := ret[0].(*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalUser indicates an expected call of GetLocalUser.
+func (mr *MockUserStoreMockRecorder) GetLocalUser(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagSuccessfulLogin", reflect.TypeOf((*MockUserStore)(nil).FlagSuccessfulLogin))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalUser", reflect.TypeOf((*MockUserStore)(nil).GetLocalUser), username)
 }
 
 // GetSharedPasswordBcrypt mocks base method.
@@ -3277,6 +3911,49 @@ func (mr *MockUserStoreMockRecorder) GetSharedPasswordBcrypt() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedPasswordBcrypt", reflect.TypeOf((*MockUserStore)(nil).GetSharedPasswordBcrypt))
 }
 
+// ListLocalUsers mocks base method.
+func (m *MockUserStore) ListLocalUsers() ([]*types16.LocalUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocalUsers")
+	ret0, _ := ret[0].([]*types16.LocalUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocalUsers indicates an expected call of ListLocalUsers.
+func (mr *MockUserStoreMockRecorder) ListLocalUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocalUsers", reflect.TypeOf((*MockUserStore)(nil).ListLocalUsers))
+}
+
+// SetLocalUserPassword mocks base method.
+func (m *MockUserStore) SetLocalUserPassword(username string, passwordBcrypt []byte, mustChangePassword bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocalUserPassword", username, passwordBcrypt, mustChangePassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocalUserPassword indicates an expected call of SetLocalUserPassword.
+func (mr *MockUserStoreMockRecorder) SetLocalUserPassword(username, passwordBcrypt, mustChangePassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalUserPassword", reflect.TypeOf((*MockUserStore)(nil).SetLocalUserPassword), username, passwordBcrypt, mustChangePassword)
+}
+
+// SetLocalUserRoles mocks base method.
+func (m *MockUserStore) SetLocalUserRoles(username string, roleIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocalUserRoles", username, roleIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocalUserRoles indicates an expected call of SetLocalUserRoles.
+func (mr *MockUserStoreMockRecorder) SetLocalUserRoles(username, roleIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocalUserRoles", reflect.TypeOf((*MockUserStore)(nil).SetLocalUserRoles), username, roleIDs)
+}
+
 // MockClusterStore is a mock of ClusterStore interface.
 type MockClusterStore struct {
 	ctrl     *gomock.Controller
@@ -3360,6 +4037,20 @@ func (mr *MockClusterStoreMockRecorder) ListClusters() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockClusterStore)(nil).ListClusters))
 }
 
+// SetInstanceSnapshotRetentionPolicy mocks base method.
+func (m *MockClusterStore) SetInstanceSnapshotRetentionPolicy(clusterID string, policy *types7.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotRetentionPolicy", clusterID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotRetentionPolicy indicates an expected call of SetInstanceSnapshotRetentionPolicy.
+func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotRetentionPolicy(clusterID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotRetentionPolicy", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotRetentionPolicy), clusterID, policy)
+}
+
 // SetInstanceSnapshotSchedule mocks base method.
 func (m *MockClusterStore) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule string) error {
 	m.ctrl.T.Helper()
@@ -3388,6 +4079,20 @@ func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotTTL(clusterID, snapsh
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotTTL", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotTTL), clusterID, snapshotTTL)
 }
 
+// SetInstanceSnapshotVerificationPolicy mocks base method.
+func (m *MockClusterStore) SetInstanceSnapshotVerificationPolicy(clusterID string, policy *types7.VerificationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotVerificationPolicy", clusterID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotVerificationPolicy indicates an expected call of SetInstanceSnapshotVerificationPolicy.
+func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotVerificationPolicy(clusterID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotVerificationPolicy", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotVerificationPolicy), clusterID, policy)
+}
+
 // MockInstallationStore is a mock of InstallationStore interface.
 type MockInstallationStore struct {
 	ctrl     *gomock.Controller
@@ -3412,10 +4117,10 @@ func (m *MockInstallationStore) EXPECT() *MockInstallationStoreMockRecorder {
 }
 
 // GetPendingInstallationStatus mocks base method.
-func (m *MockInstallationStore) GetPendingInstallationStatus() (*types6.InstallStatus, error) {
+func (m *MockInstallationStore) GetPendingInstallationStatus() (*types9.InstallStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPendingInstallationStatus")
-	ret0, _ := ret[0].(*types6.InstallStatus)
+	ret0, _ := ret[0].(*types9.InstallStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -3477,3 +4182,262 @@ func (mr *MockKotsadmParamsStoreMockRecorder) SetIsKotsadmIDGenerated() *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsKotsadmIDGenerated", reflect.TypeOf((*MockKotsadmParamsStore)(nil).SetIsKotsadmIDGenerated))
 }
+
+// MockNotificationStore is a mock of NotificationStore interface.
+type MockNotificationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationStoreMockRecorder
+}
+
+// MockNotificationStoreMockRecorder is the mock recorder for MockNotificationStore.
+type MockNotificationStoreMockRecorder struct {
+	mock *MockNotificationStore
+}
+
+// NewMockNotificationStore creates a new mock instance.
+func NewMockNotificationStore(ctrl *gomock.Controller) *MockNotificationStore {
+	mock := &MockNotificationStore{ctrl: ctrl}
+	mock.recorder = &MockNotificationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationStore) EXPECT() *MockNotificationStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateNotificationDelivery mocks base method.
+func (m *MockNotificationStore) CreateNotificationDelivery(delivery *types8.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotificationDelivery indicates an expected call of CreateNotificationDelivery.
+func (mr *MockNotificationStoreMockRecorder) CreateNotificationDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationDelivery", reflect.TypeOf((*MockNotificationStore)(nil).CreateNotificationDelivery), delivery)
+}
+
+// CreateNotificationEndpoint mocks base method.
+func (m *MockNotificationStore) CreateNotificationEndpoint(url, secret string, events []types8.EventType) (*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationEndpoint", url, secret, events)
+	ret0, _ := ret[0].(*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotificationEndpoint indicates an expected call of CreateNotificationEndpoint.
+func (mr *MockNotificationStoreMockRecorder) CreateNotificationEndpoint(url, secret, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationEndpoint", reflect.TypeOf((*MockNotificationStore)(nil).CreateNotificationEndpoint), url, secret, events)
+}
+
+// DeleteNotificationEndpoint mocks base method.
+func (m *MockNotificationStore) DeleteNotificationEndpoint(endpointID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationEndpoint", endpointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationEndpoint indicates an expected call of DeleteNotificationEndpoint.
+func (mr *MockNotificationStoreMockRecorder) DeleteNotificationEndpoint(endpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationEndpoint", reflect.TypeOf((*MockNotificationStore)(nil).DeleteNotificationEndpoint), endpointID)
+}
+
+// GetNotificationEndpoint mocks base method.
+func (m *MockNotificationStore) GetNotificationEndpoint(endpointID string) (*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationEndpoint", endpointID)
+	ret0, _ := ret[0].(*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationEndpoint indicates an expected call of GetNotificationEndpoint.
+func (mr *MockNotificationStoreMockRecorder) GetNotificationEndpoint(endpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEndpoint", reflect.TypeOf((*MockNotificationStore)(nil).GetNotificationEndpoint), endpointID)
+}
+
+// ListNotificationDeliveries mocks base method.
+func (m *MockNotificationStore) ListNotificationDeliveries(endpointID string, limit int) ([]*types8.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationDeliveries", endpointID, limit)
+	ret0, _ := ret[0].([]*types8.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationDeliveries indicates an expected call of ListNotificationDeliveries.
+func (mr *MockNotificationStoreMockRecorder) ListNotificationDeliveries(endpointID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationDeliveries", reflect.TypeOf((*MockNotificationStore)(nil).ListNotificationDeliveries), endpointID, limit)
+}
+
+// ListNotificationEndpoints mocks base method.
+func (m *MockNotificationStore) ListNotificationEndpoints() ([]*types8.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationEndpoints")
+	ret0, _ := ret[0].([]*types8.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationEndpoints indicates an expected call of ListNotificationEndpoints.
+func (mr *MockNotificationStoreMockRecorder) ListNotificationEndpoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationEndpoints", reflect.TypeOf((*MockNotificationStore)(nil).ListNotificationEndpoints))
+}
+
+// UpdateNotificationEndpoint mocks base method.
+func (m *MockNotificationStore) UpdateNotificationEndpoint(endpoint *types8.Endpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationEndpoint", endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationEndpoint indicates an expected call of UpdateNotificationEndpoint.
+func (mr *MockNotificationStoreMockRecorder) UpdateNotificationEndpoint(endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationEndpoint", reflect.TypeOf((*MockNotificationStore)(nil).UpdateNotificationEndpoint), endpoint)
+}
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockAuditStore) CreateAuditEvent(event *types5.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockAuditStoreMockRecorder) CreateAuditEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockAuditStore)(nil).CreateAuditEvent), event)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockAuditStore) ListAuditEvents(opts types5.ListEventsOptions) ([]*types5.Event, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", opts)
+	ret0, _ := ret[0].([]*types5.Event)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockAuditStoreMockRecorder) ListAuditEvents(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockAuditStore)(nil).ListAuditEvents), opts)
+}
+
+// MockAPITokenStore is a mock of APITokenStore interface.
+type MockAPITokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPITokenStoreMockRecorder
+}
+
+// MockAPITokenStoreMockRecorder is the mock recorder for MockAPITokenStore.
+type MockAPITokenStoreMockRecorder struct {
+	mock *MockAPITokenStore
+}
+
+// NewMockAPITokenStore creates a new mock instance.
+func NewMockAPITokenStore(ctrl *gomock.Controller) *MockAPITokenStore {
+	mock := &MockAPITokenStore{ctrl: ctrl}
+	mock.recorder = &MockAPITokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPITokenStore) EXPECT() *MockAPITokenStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIToken mocks base method.
+func (m *MockAPITokenStore) CreateAPIToken(token *types3.APIToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockAPITokenStoreMockRecorder) CreateAPIToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockAPITokenStore)(nil).CreateAPIToken), token)
+}
+
+// GetAPIToken mocks base method.
+func (m *MockAPITokenStore) GetAPIToken(tokenID string) (*types3.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIToken", tokenID)
+	ret0, _ := ret[0].(*types3.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIToken indicates an expected call of GetAPIToken.
+func (mr *MockAPITokenStoreMockRecorder) GetAPIToken(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIToken", reflect.TypeOf((*MockAPITokenStore)(nil).GetAPIToken), tokenID)
+}
+
+// ListAPITokens mocks base method.
+func (m *MockAPITokenStore) ListAPITokens() ([]*types3.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPITokens")
+	ret0, _ := ret[0].([]*types3.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPITokens indicates an expected call of ListAPITokens.
+func (mr *MockAPITokenStoreMockRecorder) ListAPITokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPITokens", reflect.TypeOf((*MockAPITokenStore)(nil).ListAPITokens))
+}
+
+// RevokeAPIToken mocks base method.
+func (m *MockAPITokenStore) RevokeAPIToken(tokenID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIToken", tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIToken indicates an expected call of RevokeAPIToken.
+func (mr *MockAPITokenStoreMockRecorder) RevokeAPIToken(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIToken", reflect.TypeOf((*MockAPITokenStore)(nil).RevokeAPIToken), tokenID)
+}
@@ -1,20 +1,70 @@
 package ocistore
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/pkg/errors"
 	airgaptypes "github.com/replicatedhq/kots/pkg/airgap/types"
 )
 
 func (s *OCIStore) GetPendingAirgapUploadApp() (*airgaptypes.PendingApp, error) {
-	return nil, ErrNotImplemented
+	apps, err := s.listApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list apps")
+	}
+
+	// most recently created first
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].CreatedAt.After(apps[j].CreatedAt)
+	})
+
+	for _, app := range apps {
+		switch app.InstallState {
+		case "airgap_upload_pending", "airgap_upload_in_progress", "airgap_upload_error":
+			return &airgaptypes.PendingApp{
+				ID:          app.ID,
+				Slug:        app.Slug,
+				Name:        app.Name,
+				LicenseData: app.License,
+			}, nil
+		}
+	}
+
+	return nil, ErrNotFound
 }
 
 func (s *OCIStore) GetAirgapInstallStatus(appID string) (*airgaptypes.InstallStatus, error) {
-	return nil, ErrNotImplemented
+	app, err := s.GetApp(appID)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return &airgaptypes.InstallStatus{
+				InstallStatus:  "not_installed",
+				CurrentMessage: "",
+			}, nil
+		}
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	_, message, err := s.GetTaskStatus(fmt.Sprintf("airgap-install-slug-%s", app.Slug))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get task status")
+	}
+
+	status := &airgaptypes.InstallStatus{
+		InstallStatus:  app.InstallState,
+		CurrentMessage: message,
+	}
+
+	return status, nil
 }
 
 func (s *OCIStore) ResetAirgapInstallInProgress(appID string) error {
-	return ErrNotImplemented
+	if err := s.SetAppInstallState(appID, "airgap_upload_in_progress"); err != nil {
+		return errors.Wrap(err, "failed to set update airgap install status")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) SetAppIsAirgap(appID string, isAirgap bool) error {
@@ -0,0 +1,122 @@
+package ocistore
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+)
+
+/* APITokenStore
+   API tokens are stored in a single secret keyed by token id. Revoked tokens are kept so they
+   remain visible in the token list.
+*/
+
+const (
+	APITokensSecretName = "kotsadm-api-tokens"
+)
+
+func (s *OCIStore) ListAPITokens() ([]*apitokentypes.APIToken, error) {
+	secret, err := s.getSecret(APITokensSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api tokens secret")
+	}
+
+	tokens := []*apitokentypes.APIToken{}
+	for _, data := range secret.Data {
+		token := apitokentypes.APIToken{}
+		if err := json.Unmarshal(data, &token); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal token")
+		}
+		tokens = append(tokens, &token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+func (s *OCIStore) GetAPIToken(tokenID string) (*apitokentypes.APIToken, error) {
+	secret, err := s.getSecret(APITokensSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api tokens secret")
+	}
+
+	data, ok := secret.Data[tokenID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	token := apitokentypes.APIToken{}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal token")
+	}
+
+	return &token, nil
+}
+
+func (s *OCIStore) CreateAPIToken(token *apitokentypes.APIToken) error {
+	secret, err := s.getSecret(APITokensSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get api tokens secret")
+	}
+
+	if _, ok := secret.Data[token.ID]; ok {
+		return errors.Errorf("token %s already exists", token.ID)
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal token")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[token.ID] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update api tokens secret")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) RevokeAPIToken(tokenID string) error {
+	secret, err := s.getSecret(APITokensSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get api tokens secret")
+	}
+
+	data, ok := secret.Data[tokenID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	token := apitokentypes.APIToken{}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return errors.Wrap(err, "failed to unmarshal token")
+	}
+
+	if token.RevokedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal token")
+	}
+	secret.Data[tokenID] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update api tokens secret")
+	}
+
+	return nil
+}
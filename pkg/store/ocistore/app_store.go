@@ -10,14 +10,19 @@ import (
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/gitops"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
 )
 
 /* AppStore
    The app store stores each version archive a single artifact in the registry
    The list of all apps is stored in a config map
    The list of all downstreams is stored in a config map
-   The relation of apps->downstreams is stored in a config map
+   The relation of apps->downstreams is stored in a config map, keyed by "app.<appID>"
+   and "downstream.<clusterID>"
 */
 
 const (
@@ -43,6 +48,18 @@ func (s *OCIStore) AddAppToAllDownstreams(appID string) error {
 	clusterIDs := []string{}
 	for _, cluster := range clusters {
 		clusterIDs = append(clusterIDs, cluster.ClusterID)
+
+		appIDs, err := unmarshalIDList(configMap.Data[fmt.Sprintf("downstream.%s", cluster.ClusterID)])
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal app ids for downstream")
+		}
+
+		b, err := json.Marshal(appendUniqueID(appIDs, appID))
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal app ids")
+		}
+
+		configMap.Data[fmt.Sprintf("downstream.%s", cluster.ClusterID)] = string(b)
 	}
 
 	b, err := json.Marshal(clusterIDs)
@@ -75,6 +92,23 @@ func (s *OCIStore) SetAppInstallState(appID string, state string) error {
 }
 
 func (s *OCIStore) ListInstalledApps() ([]*apptypes.App, error) {
+	apps, err := s.listApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list apps")
+	}
+
+	installedApps := []*apptypes.App{}
+	for _, app := range apps {
+		if app.InstallState == "installed" {
+			installedApps = append(installedApps, app)
+		}
+	}
+
+	return installedApps, nil
+}
+
+// listApps returns all apps, regardless of their install state
+func (s *OCIStore) listApps() ([]*apptypes.App, error) {
 	appListConfigmap, err := s.getConfigmap(AppListConfigmapName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get app list configmap")
@@ -131,11 +165,22 @@ func (s *OCIStore) GetApp(id string) (*apptypes.App, error) {
 		return nil, errors.Wrap(err, "failed to get app list configmap")
 	}
 
+	appData, ok := appListConfigmap.Data[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
 	app := apptypes.App{}
-	if err := json.Unmarshal([]byte(appListConfigmap.Data[id]), &app); err != nil {
+	if err := json.Unmarshal([]byte(appData), &app); err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal app data")
 	}
 
+	isGitOps, err := s.IsGitOpsEnabledForApp(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check if gitops is enabled")
+	}
+	app.IsGitOps = isGitOps
+
 	return &app, nil
 }
 
@@ -191,6 +236,10 @@ func (s *OCIStore) CreateApp(name string, upstreamURI string, licenseData string
 				foundUniqueSlug = false
 			}
 		}
+
+		if !foundUniqueSlug {
+			i++
+		}
 	}
 
 	installState := ""
@@ -285,52 +334,236 @@ func (s *OCIStore) ListAppsForDownstream(clusterID string) ([]*apptypes.App, err
 		return nil, errors.Wrap(err, "failed to get app downstreams list configmap")
 	}
 
-	key := fmt.Sprintf("downstream:%s", clusterID)
-	appIDsMarshaled, ok := appDownstreamsConfigMap.Data[key]
-	if !ok {
-		return []*apptypes.App{}, nil
+	key := fmt.Sprintf("downstream.%s", clusterID)
+	appIDs, err := unmarshalIDList(appDownstreamsConfigMap.Data[key])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal app ids for downstream")
 	}
-	appIDs := []string{}
-	if err := json.Unmarshal([]byte(appIDsMarshaled), &appIDs); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal downstream ids for app")
+
+	apps := []*apptypes.App{}
+	for _, appID := range appIDs {
+		app, err := s.GetApp(appID)
+		if err != nil {
+			if s.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get app %s", appID)
+		}
+
+		if app.InstallState == "installed" {
+			apps = append(apps, app)
+		}
 	}
 
-	appsConfigmap, err := s.getConfigmap(AppListConfigmapName)
+	return apps, nil
+}
+
+func (s *OCIStore) GetDownstream(clusterID string) (*downstreamtypes.Downstream, error) {
+	clusters, err := s.ListClusters()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get downsteams config map")
+		return nil, errors.Wrap(err, "failed to list clusters")
 	}
 
-	apps := []*apptypes.App{}
-	for _, appData := range appsConfigmap.Data {
-		app := apptypes.App{}
-		if err := json.Unmarshal([]byte(appData), &app); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal app data")
+	for _, cluster := range clusters {
+		if cluster.ClusterID != clusterID {
+			continue
 		}
 
-		apps = append(apps, &app)
+		downstream := *cluster
+		downstream.CurrentSequence = -1
+
+		appDownstreamsConfigMap, err := s.getConfigmap(AppDownstreamsConfigMapName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get app downstreams list configmap")
+		}
+
+		appIDs, err := unmarshalIDList(appDownstreamsConfigMap.Data[fmt.Sprintf("downstream.%s", clusterID)])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal app ids for downstream")
+		}
+
+		if len(appIDs) > 0 {
+			currentSequence, err := s.GetCurrentSequence(appIDs[0], clusterID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get current sequence")
+			}
+			downstream.CurrentSequence = currentSequence
+		}
+
+		return &downstream, nil
 	}
 
-	return apps, nil
+	return nil, nil
+}
+
+func (s *OCIStore) IsGitOpsEnabledForApp(appID string) (bool, error) {
+	downstreams, err := s.ListDownstreamsForApp(appID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list downstreams")
+	}
+
+	for _, d := range downstreams {
+		downstreamGitOps, err := gitops.GetDownstreamGitOps(appID, d.ClusterID)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get downstream gitops")
+		}
+		if downstreamGitOps != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *OCIStore) SetUpdateCheckerSpec(appID string, updateCheckerSpec string) error {
+	logger.Debug("Setting update checker spec",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.UpdateCheckerSpec = updateCheckerSpec
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetAutoDeployPolicy(appID string, policy *apptypes.AutoDeployPolicy) error {
+	logger.Debug("Setting auto deploy policy",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.AutoDeployPolicy = policy
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetSnapshotSchedule(appID string, snapshotSchedule string) error {
+	logger.Debug("Setting snapshot schedule",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.SnapshotSchedule = snapshotSchedule
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetLastUpdateCheckAt(appID string, checkedAt time.Time) error {
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.LastUpdateCheckAt = checkedAt.Format(time.RFC3339)
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
-func (c OCIStore) GetDownstream(clusterID string) (*downstreamtypes.Downstream, error) {
-	return nil, ErrNotImplemented
+func (s *OCIStore) SetAppRestoreInProgress(appID string, snapshotName string) error {
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.RestoreInProgressName = snapshotName
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
-func (c OCIStore) IsGitOpsEnabledForApp(appID string) (bool, error) {
-	return false, ErrNotImplemented
+func (s *OCIStore) SetAppRestoreUndeployStatus(appID string, undeployStatus apptypes.UndeployStatus) error {
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.RestoreUndeployStatus = undeployStatus
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
-func (c OCIStore) SetUpdateCheckerSpec(appID string, updateCheckerSpec string) error {
-	return ErrNotImplemented
+func (s *OCIStore) ResetAppRestore(appID string) error {
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.RestoreInProgressName = ""
+	app.RestoreUndeployStatus = ""
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
-func (c OCIStore) SetSnapshotSchedule(appID string, snapshotSchedule string) error {
-	return ErrNotImplemented
+func (s *OCIStore) SetSnapshotRetentionPolicy(appID string, policy *snapshottypes.RetentionPolicy) error {
+	logger.Debug("Setting snapshot retention policy",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.SnapshotRetention = policy
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
-func (c OCIStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
-	return ErrNotImplemented
+func (s *OCIStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
+	logger.Debug("Setting snapshot TTL",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	app.SnapshotTTL = snapshotTTL
+
+	if err := s.updateApp(app); err != nil {
+		return errors.Wrap(err, "failed to update app")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) updateApp(app *apptypes.App) error {
@@ -358,5 +591,115 @@ func (s *OCIStore) updateApp(app *apptypes.App) error {
 }
 
 func (s *OCIStore) RemoveApp(appID string) error {
-	return ErrNotImplemented
+	logger.Debug("Removing app",
+		zap.String("appID", appID))
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	// per app config maps
+	configMapNames := []string{
+		fmt.Sprintf("%s%s", AppVersionConfigmapPrefix, app.Slug),
+		fmt.Sprintf("%s%s", AppDownstreamVersionsConfigMapPrefix, app.Slug),
+		fmt.Sprintf("%s%s", AppDownstreamOutputsConfigMapPrefix, app.Slug),
+	}
+	for _, configMapName := range configMapNames {
+		if err := s.deleteConfigmap(configMapName); err != nil {
+			return errors.Wrapf(err, "failed to delete config map %s", configMapName)
+		}
+	}
+
+	appStatusConfigMap, err := s.getConfigmap(AppStatusConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app status config map")
+	}
+	if _, ok := appStatusConfigMap.Data[appID]; ok {
+		delete(appStatusConfigMap.Data, appID)
+		if err := s.updateConfigmap(appStatusConfigMap); err != nil {
+			return errors.Wrap(err, "failed to update app status config map")
+		}
+	}
+
+	if err := s.DeletePendingScheduledSnapshots(appID); err != nil {
+		return errors.Wrap(err, "failed to delete pending scheduled snapshots")
+	}
+
+	if err := s.deleteSupportBundlesForApp(appID); err != nil {
+		return errors.Wrap(err, "failed to delete support bundles")
+	}
+
+	if err := s.deleteRegistryDetailsForApp(appID); err != nil {
+		return errors.Wrap(err, "failed to delete registry details")
+	}
+
+	appDownstreamsConfigMap, err := s.getConfigmap(AppDownstreamsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app downstreams list configmap")
+	}
+	for key, data := range appDownstreamsConfigMap.Data {
+		if !strings.HasPrefix(key, "downstream.") {
+			continue
+		}
+
+		appIDs, err := unmarshalIDList(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal app ids for downstream")
+		}
+
+		b, err := json.Marshal(removeID(appIDs, appID))
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal app ids")
+		}
+
+		appDownstreamsConfigMap.Data[key] = string(b)
+	}
+	delete(appDownstreamsConfigMap.Data, fmt.Sprintf("app.%s", appID))
+	if err := s.updateConfigmap(appDownstreamsConfigMap); err != nil {
+		return errors.Wrap(err, "failed to update app downstreams config map")
+	}
+
+	appListConfigmap, err := s.getConfigmap(AppListConfigmapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app list configmap")
+	}
+	delete(appListConfigmap.Data, appID)
+	if err := s.updateConfigmap(appListConfigmap); err != nil {
+		return errors.Wrap(err, "failed to update app list config map")
+	}
+
+	return nil
+}
+
+func unmarshalIDList(data string) ([]string, error) {
+	ids := []string{}
+	if data == "" {
+		return ids, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func appendUniqueID(ids []string, id string) []string {
+	for _, existingID := range ids {
+		if existingID == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func removeID(ids []string, id string) []string {
+	result := []string{}
+	for _, existingID := range ids {
+		if existingID != id {
+			result = append(result, existingID)
+		}
+	}
+	return result
 }
@@ -1,15 +1,71 @@
 package ocistore
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/pkg/errors"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/replicatedhq/kots/pkg/appstatus"
+)
+
+const (
+	AppStatusConfigMapName = "kotsadm-appstatus"
 )
 
 func (s *OCIStore) GetAppStatus(appID string) (*appstatustypes.AppStatus, error) {
-	return nil, ErrNotImplemented
+	configMap, err := s.getConfigmap(AppStatusConfigMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app status config map")
+	}
+
+	data, ok := configMap.Data[appID]
+	if !ok {
+		return &appstatustypes.AppStatus{
+			AppID:          appID,
+			UpdatedAt:      time.Time{},
+			ResourceStates: []appstatustypes.ResourceState{},
+			State:          appstatustypes.StateMissing,
+			Sequence:       0,
+		}, nil
+	}
+
+	appStatus := appstatustypes.AppStatus{}
+	if err := json.Unmarshal([]byte(data), &appStatus); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal app status")
+	}
+	appStatus.AppID = appID
+	appStatus.State = appstatus.GetState(appStatus.ResourceStates)
+
+	return &appStatus, nil
 }
 
 func (s *OCIStore) SetAppStatus(appID string, resourceStates []appstatustypes.ResourceState, updatedAt time.Time, sequence int64) error {
-	return ErrNotImplemented
+	appStatus := appstatustypes.AppStatus{
+		AppID:          appID,
+		UpdatedAt:      updatedAt,
+		ResourceStates: resourceStates,
+		Sequence:       sequence,
+	}
+	b, err := json.Marshal(appStatus)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal app status")
+	}
+
+	configMap, err := s.getConfigmap(AppStatusConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app status config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[appID] = string(b)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update app status config map")
+	}
+
+	return nil
 }
@@ -0,0 +1,276 @@
+package ocistore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/segmentio/ksuid"
+)
+
+/* AuditStore
+   The newest audit events are in the kotsadm-audit-events configmap. The keys are the event ids and the values are
+   the JSON marshalled events. Event ids are ksuids, so sorting the keys sorts the events by time.
+   Once the configmap holds maxAuditEventsPerChunk events, they are pushed to the registry as a chunk that is never
+   written to again, tagged with the sha256 of its contents, and removed from the configmap.
+   The chunks key of the same configmap indexes the chunks, oldest first, with the number of events of each app, so that
+   moving events to a chunk is a single update and listing a page only pulls the chunks that the page has events from.
+   Only the newest maxAuditEventChunks chunks are indexed. Older chunks are dropped from the index and are no longer listed.
+*/
+
+const (
+	AuditEventsConfigmapName = "kotsadm-audit-events"
+
+	auditEventsRepository = "kotsadm-audit-events"
+	auditEventsChunksKey  = "chunks"
+
+	maxAuditEventsPerChunk = 1000
+	maxAuditEventChunks    = 500
+)
+
+var (
+	auditEventsLock = sync.Mutex{}
+)
+
+type auditEventsChunk struct {
+	Digest    string           `json:"digest"`
+	Count     int64            `json:"count"`
+	AppCounts map[string]int64 `json:"appCounts,omitempty"`
+}
+
+// countFor returns the number of events in the chunk for the app, or all events if appSlug is empty
+func (c auditEventsChunk) countFor(appSlug string) int64 {
+	if appSlug == "" {
+		return c.Count
+	}
+	return c.AppCounts[appSlug]
+}
+
+func (s *OCIStore) CreateAuditEvent(event *audittypes.Event) error {
+	auditEventsLock.Lock()
+	defer auditEventsLock.Unlock()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.ID == "" {
+		id, err := ksuid.NewRandomWithTime(event.CreatedAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate event id")
+		}
+		event.ID = id.String()
+	}
+
+	configmap, err := s.getConfigmap(AuditEventsConfigmapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get audit events configmap")
+	}
+
+	if configmap.Data == nil {
+		configmap.Data = map[string]string{}
+	}
+
+	if len(pendingAuditEventIDs(configmap.Data)) >= maxAuditEventsPerChunk {
+		contents, chunk, err := newAuditEventsChunk(configmap.Data)
+		if err != nil {
+			return errors.Wrap(err, "failed to create audit events chunk")
+		}
+		if err := s.pushFile(s.refFor(auditEventsRepository, chunk.Digest), "events", "application/json", contents); err != nil {
+			return errors.Wrap(err, "failed to push audit events chunk")
+		}
+		if err := addAuditEventsChunk(configmap.Data, chunk); err != nil {
+			return errors.Wrap(err, "failed to add audit events chunk")
+		}
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+	configmap.Data[event.ID] = string(b)
+
+	if err := s.updateConfigmap(configmap); err != nil {
+		return errors.Wrap(err, "failed to update audit events configmap")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) ListAuditEvents(opts audittypes.ListEventsOptions) ([]*audittypes.Event, int64, error) {
+	configmap, err := s.getConfigmap(AuditEventsConfigmapName)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get audit events configmap")
+	}
+
+	return listAuditEvents(configmap.Data, opts, s.pullAuditEventsChunk)
+}
+
+func (s *OCIStore) pullAuditEventsChunk(chunk auditEventsChunk) ([]*audittypes.Event, error) {
+	contents, err := s.pullFile(s.refFor(auditEventsRepository, chunk.Digest), "events", "application/json")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pull chunk %s", chunk.Digest)
+	}
+
+	events := []*audittypes.Event{}
+	if err := json.Unmarshal(contents, &events); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal chunk %s", chunk.Digest)
+	}
+
+	return events, nil
+}
+
+// listAuditEvents returns a page of the events in the configmap data and its chunks, newest first.
+// Chunks before the page are skipped using the counts in the index, and no chunks are pulled once the page is full.
+func listAuditEvents(data map[string]string, opts audittypes.ListEventsOptions, pullChunk func(chunk auditEventsChunk) ([]*audittypes.Event, error)) ([]*audittypes.Event, int64, error) {
+	pending := []*audittypes.Event{}
+	for _, id := range pendingAuditEventIDs(data) {
+		event := audittypes.Event{}
+		if err := json.Unmarshal([]byte(data[id]), &event); err != nil {
+			return nil, 0, errors.Wrap(err, "failed to unmarshal event")
+		}
+		pending = append(pending, &event)
+	}
+
+	chunks, err := getAuditEventsChunks(data)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get audit events chunks")
+	}
+
+	pending = filterAuditEvents(pending, opts.AppSlug)
+	totalCount := int64(len(pending))
+	for _, chunk := range chunks {
+		totalCount += chunk.countFor(opts.AppSlug)
+	}
+
+	events := []*audittypes.Event{}
+	skip := int64(opts.Offset)
+	isFull := func() bool {
+		return opts.Limit > 0 && len(events) >= opts.Limit
+	}
+	addEvents := func(matching []*audittypes.Event) {
+		for _, event := range matching {
+			if isFull() {
+				return
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	addEvents(pending)
+	for i := len(chunks) - 1; i >= 0 && !isFull(); i-- {
+		count := chunks[i].countFor(opts.AppSlug)
+		if skip >= count {
+			skip -= count
+			continue
+		}
+		chunkEvents, err := pullChunk(chunks[i])
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to pull audit events chunk")
+		}
+		addEvents(filterAuditEvents(chunkEvents, opts.AppSlug))
+	}
+
+	return events, totalCount, nil
+}
+
+// newAuditEventsChunk returns the contents and the index entry of a chunk with all events in the configmap data
+func newAuditEventsChunk(data map[string]string) ([]byte, auditEventsChunk, error) {
+	chunk := auditEventsChunk{
+		AppCounts: map[string]int64{},
+	}
+
+	events := []json.RawMessage{}
+	for _, id := range pendingAuditEventIDs(data) {
+		event := audittypes.Event{}
+		if err := json.Unmarshal([]byte(data[id]), &event); err != nil {
+			return nil, chunk, errors.Wrap(err, "failed to unmarshal event")
+		}
+		chunk.Count++
+		if event.AppSlug != "" {
+			chunk.AppCounts[event.AppSlug]++
+		}
+		events = append(events, json.RawMessage(data[id]))
+	}
+
+	contents, err := json.Marshal(events)
+	if err != nil {
+		return nil, chunk, errors.Wrap(err, "failed to marshal events")
+	}
+
+	digest := sha256.Sum256(contents)
+	chunk.Digest = hex.EncodeToString(digest[:])
+
+	return contents, chunk, nil
+}
+
+// addAuditEventsChunk removes the events of the chunk from the configmap data and adds the chunk to the index
+func addAuditEventsChunk(data map[string]string, chunk auditEventsChunk) error {
+	chunks, err := getAuditEventsChunks(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to get audit events chunks")
+	}
+
+	chunks = append(chunks, chunk)
+	if len(chunks) > maxAuditEventChunks {
+		chunks = chunks[len(chunks)-maxAuditEventChunks:]
+	}
+
+	b, err := json.Marshal(chunks)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chunks")
+	}
+
+	for _, id := range pendingAuditEventIDs(data) {
+		delete(data, id)
+	}
+	data[auditEventsChunksKey] = string(b)
+
+	return nil
+}
+
+// getAuditEventsChunks returns the chunks in the index, oldest first
+func getAuditEventsChunks(data map[string]string) ([]auditEventsChunk, error) {
+	chunks := []auditEventsChunk{}
+	if data[auditEventsChunksKey] == "" {
+		return chunks, nil
+	}
+	if err := json.Unmarshal([]byte(data[auditEventsChunksKey]), &chunks); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chunks")
+	}
+	return chunks, nil
+}
+
+// pendingAuditEventIDs returns the ids of the events that have not been pushed to a chunk, newest first
+func pendingAuditEventIDs(data map[string]string) []string {
+	ids := []string{}
+	for id := range data {
+		if id == auditEventsChunksKey {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids
+}
+
+func filterAuditEvents(events []*audittypes.Event, appSlug string) []*audittypes.Event {
+	if appSlug == "" {
+		return events
+	}
+	matching := []*audittypes.Event{}
+	for _, event := range events {
+		if event.AppSlug == appSlug {
+			matching = append(matching, event)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,156 @@
+package ocistore
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_addAuditEventsChunk(t *testing.T) {
+	data := auditEventsData(t, 0, 3, "app-a")
+	data[auditEventID(3)] = marshalAuditEvent(t, auditEventID(3), "app-b")
+
+	contents, chunk, err := newAuditEventsChunk(data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), chunk.Count)
+	assert.Equal(t, map[string]int64{"app-a": 3, "app-b": 1}, chunk.AppCounts)
+	assert.Len(t, chunk.Digest, 64)
+
+	events := []*audittypes.Event{}
+	require.NoError(t, json.Unmarshal(contents, &events))
+	require.Len(t, events, 4)
+	assert.Equal(t, auditEventID(3), events[0].ID)
+	assert.Equal(t, auditEventID(0), events[3].ID)
+
+	require.NoError(t, addAuditEventsChunk(data, chunk))
+	assert.Empty(t, pendingAuditEventIDs(data))
+
+	chunks, err := getAuditEventsChunks(data)
+	require.NoError(t, err)
+	assert.Equal(t, []auditEventsChunk{chunk}, chunks)
+}
+
+func Test_addAuditEventsChunkDropsOldestChunks(t *testing.T) {
+	data := map[string]string{}
+	for i := 0; i < maxAuditEventChunks+1; i++ {
+		require.NoError(t, addAuditEventsChunk(data, auditEventsChunk{Digest: fmt.Sprintf("%d", i), Count: 1}))
+	}
+
+	chunks, err := getAuditEventsChunks(data)
+	require.NoError(t, err)
+	require.Len(t, chunks, maxAuditEventChunks)
+	assert.Equal(t, "1", chunks[0].Digest)
+	assert.Equal(t, fmt.Sprintf("%d", maxAuditEventChunks), chunks[len(chunks)-1].Digest)
+}
+
+func Test_listAuditEvents(t *testing.T) {
+	// two chunks of 10 events each and 5 pending events, all for app-a except every other event of the newest chunk
+	chunkEvents := map[string][]*audittypes.Event{}
+	data := map[string]string{}
+	for c := 0; c < 2; c++ {
+		chunkData := map[string]string{}
+		for i := c * 10; i < (c+1)*10; i++ {
+			appSlug := "app-a"
+			if c == 1 && i%2 == 0 {
+				appSlug = "app-b"
+			}
+			chunkData[auditEventID(i)] = marshalAuditEvent(t, auditEventID(i), appSlug)
+		}
+		contents, chunk, err := newAuditEventsChunk(chunkData)
+		require.NoError(t, err)
+		events := []*audittypes.Event{}
+		require.NoError(t, json.Unmarshal(contents, &events))
+		chunkEvents[chunk.Digest] = events
+		require.NoError(t, addAuditEventsChunk(data, chunk))
+	}
+	for id, event := range auditEventsData(t, 20, 25, "app-a") {
+		data[id] = event
+	}
+
+	tests := []struct {
+		name           string
+		opts           audittypes.ListEventsOptions
+		wantIDs        []string
+		wantTotalCount int64
+		wantPulls      int
+	}{
+		{
+			name:           "first page",
+			opts:           audittypes.ListEventsOptions{Limit: 3},
+			wantIDs:        []string{auditEventID(24), auditEventID(23), auditEventID(22)},
+			wantTotalCount: 25,
+			wantPulls:      0,
+		},
+		{
+			name:           "page across pending events and a chunk",
+			opts:           audittypes.ListEventsOptions{Offset: 3, Limit: 4},
+			wantIDs:        []string{auditEventID(21), auditEventID(20), auditEventID(19), auditEventID(18)},
+			wantTotalCount: 25,
+			wantPulls:      1,
+		},
+		{
+			name:           "page in the oldest chunk",
+			opts:           audittypes.ListEventsOptions{Offset: 16, Limit: 2},
+			wantIDs:        []string{auditEventID(8), auditEventID(7)},
+			wantTotalCount: 25,
+			wantPulls:      1,
+		},
+		{
+			name:           "page for an app",
+			opts:           audittypes.ListEventsOptions{AppSlug: "app-b", Offset: 1, Limit: 2},
+			wantIDs:        []string{auditEventID(16), auditEventID(14)},
+			wantTotalCount: 5,
+			wantPulls:      1,
+		},
+		{
+			name:           "offset after the last event",
+			opts:           audittypes.ListEventsOptions{Offset: 25, Limit: 10},
+			wantIDs:        []string{},
+			wantTotalCount: 25,
+			wantPulls:      0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pulls := 0
+			pullChunk := func(chunk auditEventsChunk) ([]*audittypes.Event, error) {
+				pulls++
+				return chunkEvents[chunk.Digest], nil
+			}
+
+			events, totalCount, err := listAuditEvents(data, test.opts, pullChunk)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, test.wantIDs, ids)
+			assert.Equal(t, test.wantTotalCount, totalCount)
+			assert.Equal(t, test.wantPulls, pulls)
+		})
+	}
+}
+
+// auditEventID returns ids that sort in the same order as i, like ksuids sort by time
+func auditEventID(i int) string {
+	return fmt.Sprintf("event-%04d", i)
+}
+
+func marshalAuditEvent(t *testing.T, id string, appSlug string) string {
+	b, err := json.Marshal(audittypes.Event{ID: id, AppSlug: appSlug})
+	require.NoError(t, err)
+	return string(b)
+}
+
+func auditEventsData(t *testing.T, from int, to int, appSlug string) map[string]string {
+	data := map[string]string{}
+	for i := from; i < to; i++ {
+		data[auditEventID(i)] = marshalAuditEvent(t, auditEventID(i), appSlug)
+	}
+	return data
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/rand"
+	"go.uber.org/zap"
 )
 
 const (
@@ -39,7 +42,18 @@ func (s *OCIStore) ListClusters() ([]*downstreamtypes.Downstream, error) {
 }
 
 func (s *OCIStore) GetClusterIDFromSlug(slug string) (string, error) {
-	return "", ErrNotImplemented
+	clusters, err := s.ListClusters()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list clusters")
+	}
+
+	for _, cluster := range clusters {
+		if cluster.ClusterSlug == slug {
+			return cluster.ClusterID, nil
+		}
+	}
+
+	return "", ErrNotFound
 }
 
 func (s *OCIStore) GetClusterIDFromDeployToken(deployToken string) (string, error) {
@@ -83,7 +97,7 @@ func (s *OCIStore) CreateNewCluster(userID string, isAllUsers bool, title string
 			slugProposal = fmt.Sprintf("%s-%d", downstream.ClusterSlug, i)
 		}
 
-		foundUniqueSlug := true
+		foundUniqueSlug = true
 		for _, existingClusterSlug := range existingClusterSlugs {
 			if slugProposal == existingClusterSlug {
 				foundUniqueSlug = false
@@ -119,13 +133,108 @@ func (s *OCIStore) CreateNewCluster(userID string, isAllUsers bool, title string
 		return "", errors.Wrap(err, "failed to update config map")
 	}
 
+	secret, err := s.getSecret(ClusterDeployTokenSecret)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cluster deploy token secret")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	secret.Data[token] = []byte(downstream.ClusterID)
+
+	if err := s.updateSecret(secret); err != nil {
+		return "", errors.Wrap(err, "failed to update cluster deploy token secret")
+	}
+
 	return downstream.ClusterID, nil
 }
 
 func (s *OCIStore) SetInstanceSnapshotTTL(clusterID string, snapshotTTL string) error {
-	return ErrNotImplemented
+	logger.Debug("Setting instance snapshot TTL",
+		zap.String("clusterID", clusterID))
+
+	err := s.updateCluster(clusterID, func(cluster *downstreamtypes.Downstream) {
+		cluster.SnapshotTTL = snapshotTTL
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetInstanceSnapshotRetentionPolicy(clusterID string, policy *snapshottypes.RetentionPolicy) error {
+	logger.Debug("Setting instance snapshot retention policy",
+		zap.String("clusterID", clusterID))
+
+	err := s.updateCluster(clusterID, func(cluster *downstreamtypes.Downstream) {
+		cluster.SnapshotRetention = policy
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetInstanceSnapshotVerificationPolicy(clusterID string, policy *snapshottypes.VerificationPolicy) error {
+	logger.Debug("Setting instance snapshot verification policy",
+		zap.String("clusterID", clusterID))
+
+	err := s.updateCluster(clusterID, func(cluster *downstreamtypes.Downstream) {
+		cluster.SnapshotVerification = policy
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) SetInstanceSnapshotSchedule(clusterID string, snapshotSchedule string) error {
-	return ErrNotImplemented
+	logger.Debug("Setting instance snapshot schedule",
+		zap.String("clusterID", clusterID))
+
+	err := s.updateCluster(clusterID, func(cluster *downstreamtypes.Downstream) {
+		cluster.SnapshotSchedule = snapshotSchedule
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) updateCluster(clusterID string, update func(cluster *downstreamtypes.Downstream)) error {
+	configMap, err := s.getConfigmap(ClusterListConfigmapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get clusters config map")
+	}
+
+	data, ok := configMap.Data[clusterID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	cluster := downstreamtypes.Downstream{}
+	if err := json.Unmarshal([]byte(data), &cluster); err != nil {
+		return errors.Wrap(err, "failed to unmarshal cluster")
+	}
+
+	update(&cluster)
+
+	b, err := json.Marshal(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cluster")
+	}
+
+	configMap.Data[clusterID] = string(b)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update config map")
+	}
+
+	return nil
 }
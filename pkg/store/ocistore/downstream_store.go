@@ -1,74 +1,805 @@
 package ocistore
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/store/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+/* DownstreamStore
+   The downstream versions of an app are stored in a single config map per app,
+   keyed by "<clusterID>.<sequence>". The current sequence for each downstream is
+   stored in the same config map, keyed by "current.<clusterID>".
+   The deploy output of each downstream version is stored in a separate config map
+   per app, keyed by "<clusterID>.<sequence>". To keep that config map below the
+   object size limit, large outputs are truncated and only the outputs of the most
+   recent sequences are kept.
+*/
+
+const (
+	AppDownstreamVersionsConfigMapPrefix = "kotsadm-appdownstreamversions-"
+	AppDownstreamOutputsConfigMapPrefix  = "kotsadm-appdownstreamoutputs-"
+
+	maxDownstreamOutputsPerCluster = 10
+	maxDownstreamOutputBytes       = 32 * 1024
+	maxDownstreamOutputResources   = 1000
+	maxDownstreamOutputsDataBytes  = 768 * 1024
 )
 
+type downstreamVersion struct {
+	ClusterID                  string                        `json:"clusterId"`
+	Sequence                   int64                         `json:"sequence"`
+	ParentSequence             int64                         `json:"parentSequence"`
+	CreatedAt                  time.Time                     `json:"createdAt"`
+	VersionLabel               string                        `json:"versionLabel"`
+	Status                     types.DownstreamVersionStatus `json:"status"`
+	StatusInfo                 string                        `json:"statusInfo,omitempty"`
+	Source                     string                        `json:"source"`
+	DiffSummary                string                        `json:"diffSummary,omitempty"`
+	DiffSummaryError           string                        `json:"diffSummaryError,omitempty"`
+	CommitURL                  string                        `json:"commitUrl,omitempty"`
+	GitDeployable              bool                          `json:"gitDeployable,omitempty"`
+	PreflightSkipped           bool                          `json:"preflightSkipped"`
+	PreflightResult            string                        `json:"preflightResult,omitempty"`
+	PreflightResultCreatedAt   *time.Time                    `json:"preflightResultCreatedAt,omitempty"`
+	PreflightProgress          string                        `json:"preflightProgress,omitempty"`
+	PreflightIgnorePermissions bool                          `json:"preflightIgnorePermissions,omitempty"`
+	AppliedAt                  *time.Time                    `json:"appliedAt,omitempty"`
+}
+
+type downstreamOutput struct {
+	IsError bool                             `json:"isError"`
+	Output  downstreamtypes.DownstreamOutput `json:"output"`
+}
+
+func downstreamVersionKey(clusterID string, sequence int64) string {
+	return fmt.Sprintf("%s.%d", clusterID, sequence)
+}
+
+func currentSequenceKey(clusterID string) string {
+	return fmt.Sprintf("current.%s", clusterID)
+}
+
+func (s *OCIStore) appDownstreamVersionsConfigMapNameForApp(appID string) (string, error) {
+	a, err := s.GetApp(appID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get app")
+	}
+
+	return fmt.Sprintf("%s%s", AppDownstreamVersionsConfigMapPrefix, a.Slug), nil
+}
+
+func (s *OCIStore) appDownstreamOutputsConfigMapNameForApp(appID string) (string, error) {
+	a, err := s.GetApp(appID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get app")
+	}
+
+	return fmt.Sprintf("%s%s", AppDownstreamOutputsConfigMapPrefix, a.Slug), nil
+}
+
+func (s *OCIStore) getAppDownstreamVersionsConfigMap(appID string) (*corev1.ConfigMap, error) {
+	configMapName, err := s.appDownstreamVersionsConfigMapNameForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions config map name")
+	}
+
+	configMap, err := s.getConfigmap(configMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	return configMap, nil
+}
+
+func (s *OCIStore) getAppDownstreamOutputsConfigMap(appID string) (*corev1.ConfigMap, error) {
+	configMapName, err := s.appDownstreamOutputsConfigMapNameForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream outputs config map name")
+	}
+
+	configMap, err := s.getConfigmap(configMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream outputs config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	return configMap, nil
+}
+
+// listDownstreamVersions returns the downstream versions in the config map for the cluster, sorted by sequence descending.
+// All clusters are included when clusterID is empty.
+func listDownstreamVersions(configMap *corev1.ConfigMap, clusterID string) ([]downstreamVersion, error) {
+	versions := []downstreamVersion{}
+	for key, data := range configMap.Data {
+		if strings.HasPrefix(key, "current.") {
+			continue
+		}
+
+		v := downstreamVersion{}
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal downstream version")
+		}
+
+		if clusterID != "" && v.ClusterID != clusterID {
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Sequence > versions[j].Sequence
+	})
+
+	return versions, nil
+}
+
+func getDownstreamVersion(configMap *corev1.ConfigMap, clusterID string, sequence int64) (*downstreamVersion, error) {
+	data, ok := configMap.Data[downstreamVersionKey(clusterID, sequence)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	v := downstreamVersion{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal downstream version")
+	}
+
+	return &v, nil
+}
+
+func setDownstreamVersion(configMap *corev1.ConfigMap, v downstreamVersion) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal downstream version")
+	}
+
+	configMap.Data[downstreamVersionKey(v.ClusterID, v.Sequence)] = string(b)
+
+	return nil
+}
+
+// updateDownstreamVersions calls update on the downstream versions with the sequence in all clusters
+// and returns ErrNotFound if there are no downstream versions with the sequence
+func (s *OCIStore) updateDownstreamVersions(appID string, sequence int64, update func(v *downstreamVersion)) error {
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	found := false
+	for _, v := range versions {
+		if v.Sequence != sequence {
+			continue
+		}
+
+		update(&v)
+		if err := setDownstreamVersion(configMap, v); err != nil {
+			return errors.Wrap(err, "failed to set downstream version")
+		}
+		found = true
+	}
+
+	if !found {
+		return ErrNotFound
+	}
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update downstream versions config map")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) getDownstreamOutput(appID string, clusterID string, sequence int64) (*downstreamOutput, error) {
+	configMap, err := s.getAppDownstreamOutputsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream outputs")
+	}
+
+	data, ok := configMap.Data[downstreamVersionKey(clusterID, sequence)]
+	if !ok {
+		return nil, nil
+	}
+
+	output := downstreamOutput{}
+	if err := json.Unmarshal([]byte(data), &output); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal downstream output")
+	}
+
+	return &output, nil
+}
+
 func (s *OCIStore) GetCurrentSequence(appID string, clusterID string) (int64, error) {
-	return 0, ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	data, ok := configMap.Data[currentSequenceKey(clusterID)]
+	if !ok {
+		return -1, nil
+	}
+
+	currentSequence, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to parse current sequence")
+	}
+
+	return currentSequence, nil
 }
 
 func (s *OCIStore) GetCurrentParentSequence(appID string, clusterID string) (int64, error) {
-	return 0, ErrNotImplemented
+	currentSequence, err := s.GetCurrentSequence(appID, clusterID)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to get current sequence")
+	}
+	if currentSequence == -1 {
+		return -1, nil
+	}
+
+	return s.GetParentSequenceForSequence(appID, clusterID, currentSequence)
 }
 
 func (s *OCIStore) GetParentSequenceForSequence(appID string, clusterID string, sequence int64) (int64, error) {
-	return 0, ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	v, err := getDownstreamVersion(configMap, clusterID, sequence)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to get downstream version")
+	}
+
+	return v.ParentSequence, nil
 }
 
 func (s *OCIStore) GetPreviouslyDeployedSequence(appID string, clusterID string) (int64, error) {
-	return 0, ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, clusterID)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	appliedVersions := []downstreamVersion{}
+	for _, v := range versions {
+		if v.AppliedAt != nil {
+			appliedVersions = append(appliedVersions, v)
+		}
+	}
+
+	if len(appliedVersions) < 2 {
+		return -1, nil
+	}
+
+	sort.Slice(appliedVersions, func(i, j int) bool {
+		return appliedVersions[i].AppliedAt.After(*appliedVersions[j].AppliedAt)
+	})
+
+	return appliedVersions[1].Sequence, nil
 }
 
+// SetDownstreamVersionReady sets the status for the downstream version with the given sequence and app id to "pending"
 func (s *OCIStore) SetDownstreamVersionReady(appID string, sequence int64) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.Status = types.VersionPending
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to set downstream version ready")
+	}
+
+	return nil
 }
 
+// SetDownstreamVersionPendingPreflight sets the status for the downstream version with the given sequence and app id to "pending_preflight"
 func (s *OCIStore) SetDownstreamVersionPendingPreflight(appID string, sequence int64) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.Status = types.VersionPendingPreflight
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to set downstream version pending preflight")
+	}
+
+	return nil
 }
 
+// UpdateDownstreamVersionStatus updates the status and status info for the downstream version with the given sequence and app id
 func (s *OCIStore) UpdateDownstreamVersionStatus(appID string, sequence int64, status string, statusInfo string) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.Status = types.DownstreamVersionStatus(status)
+		v.StatusInfo = statusInfo
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to update downstream version status")
+	}
+
+	return nil
 }
 
+// GetDownstreamVersionStatus gets the status for the downstream version with the given sequence and app id
 func (s *OCIStore) GetDownstreamVersionStatus(appID string, sequence int64) (types.DownstreamVersionStatus, error) {
-	return types.DownstreamVersionStatus(""), ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	for _, v := range versions {
+		if v.Sequence == sequence {
+			return v.Status, nil
+		}
+	}
+
+	return "", nil
 }
 
 func (s *OCIStore) GetIgnoreRBACErrors(appID string, sequence int64) (bool, error) {
-	return false, ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	for _, v := range versions {
+		if v.Sequence == sequence {
+			return v.PreflightIgnorePermissions, nil
+		}
+	}
+
+	return false, ErrNotFound
 }
 
 func (s *OCIStore) GetCurrentVersion(appID string, clusterID string) (*downstreamtypes.DownstreamVersion, error) {
-	return nil, ErrNotImplemented
+	currentSequence, err := s.GetCurrentSequence(appID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current sequence")
+	}
+	if currentSequence == -1 {
+		return nil, nil
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	v, err := getDownstreamVersion(configMap, clusterID, currentSequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream version")
+	}
+
+	versions, err := s.downstreamVersionsForAPI(appID, clusterID, []downstreamVersion{*v})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get version")
+	}
+
+	return &versions[0], nil
 }
 
 func (s *OCIStore) GetStatusForVersion(appID string, clusterID string, sequence int64) (types.DownstreamVersionStatus, error) {
-	return types.DownstreamVersionStatus(""), ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	v, err := getDownstreamVersion(configMap, clusterID, sequence)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream version")
+	}
+
+	output, err := s.getDownstreamOutput(appID, clusterID, sequence)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream output")
+	}
+
+	return getDownstreamVersionStatus(v.Status, output), nil
 }
 
 func (s *OCIStore) GetPendingVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error) {
-	return nil, ErrNotImplemented
+	currentSequence, err := s.GetCurrentSequence(appID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current sequence")
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	pendingVersions := []downstreamVersion{}
+	for _, v := range versions {
+		if v.Sequence > currentSequence {
+			pendingVersions = append(pendingVersions, v)
+		}
+	}
+
+	return s.downstreamVersionsForAPI(appID, clusterID, pendingVersions)
 }
 
 func (s *OCIStore) GetPastVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error) {
-	return nil, ErrNotImplemented
+	currentSequence, err := s.GetCurrentSequence(appID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current sequence")
+	}
+	if currentSequence == -1 {
+		return []downstreamtypes.DownstreamVersion{}, nil
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	pastVersions := []downstreamVersion{}
+	for _, v := range versions {
+		if v.Sequence < currentSequence {
+			pastVersions = append(pastVersions, v)
+		}
+	}
+
+	return s.downstreamVersionsForAPI(appID, clusterID, pastVersions)
+}
+
+// downstreamVersionsForAPI joins the downstream versions with the app versions and deploy outputs
+func (s *OCIStore) downstreamVersionsForAPI(appID string, clusterID string, versions []downstreamVersion) ([]downstreamtypes.DownstreamVersion, error) {
+	appVersions, err := s.listAppVersions(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list app versions")
+	}
+
+	outputsConfigMap, err := s.getAppDownstreamOutputsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream outputs")
+	}
+
+	result := []downstreamtypes.DownstreamVersion{}
+	for _, v := range versions {
+		var output *downstreamOutput
+		if data, ok := outputsConfigMap.Data[downstreamVersionKey(clusterID, v.Sequence)]; ok {
+			output = &downstreamOutput{}
+			if err := json.Unmarshal([]byte(data), output); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal downstream output")
+			}
+		}
+
+		createdOn := v.CreatedAt
+		apiVersion := downstreamtypes.DownstreamVersion{
+			VersionLabel:             v.VersionLabel,
+			Status:                   getDownstreamVersionStatus(v.Status, output),
+			CreatedOn:                &createdOn,
+			ParentSequence:           v.ParentSequence,
+			Sequence:                 v.Sequence,
+			DeployedAt:               v.AppliedAt,
+			Source:                   v.Source,
+			PreflightResult:          v.PreflightResult,
+			PreflightResultCreatedAt: v.PreflightResultCreatedAt,
+			PreflightSkipped:         v.PreflightSkipped,
+			DiffSummary:              v.DiffSummary,
+			DiffSummaryError:         v.DiffSummaryError,
+			CommitURL:                v.CommitURL,
+			GitDeployable:            v.GitDeployable,
+		}
+
+		if appVersion := findAppVersion(appVersions, v.ParentSequence); appVersion != nil && appVersion.KOTSKinds != nil {
+			installationSpec := appVersion.KOTSKinds.Installation.Spec
+			apiVersion.ReleaseNotes = installationSpec.ReleaseNotes
+			if installationSpec.ReleasedAt != nil {
+				apiVersion.UpstreamReleasedAt = &installationSpec.ReleasedAt.Time
+			}
+			apiVersion.YamlErrors = installationSpec.YAMLErrors
+		}
+
+		result = append(result, apiVersion)
+	}
+
+	return result, nil
+}
+
+func findAppVersion(appVersions []*versiontypes.AppVersion, sequence int64) *versiontypes.AppVersion {
+	for _, appVersion := range appVersions {
+		if appVersion.Sequence == sequence {
+			return appVersion
+		}
+	}
+	return nil
+}
+
+func getDownstreamVersionStatus(status types.DownstreamVersionStatus, output *downstreamOutput) types.DownstreamVersionStatus {
+	s := types.VersionUnknown
+
+	// first check if operator has reported back.
+	// and if it hasn't, we should not show "deployed" to the user.
+
+	if output != nil && !output.IsError {
+		s = status
+	} else if output != nil && output.IsError {
+		s = types.VersionFailed
+	} else if status == types.VersionDeployed {
+		s = types.VersionDeploying
+	} else if status != types.DownstreamVersionStatus("") {
+		s = status
+	}
+
+	return s
 }
 
 func (s *OCIStore) GetDownstreamOutput(appID string, clusterID string, sequence int64) (*downstreamtypes.DownstreamOutput, error) {
-	return nil, ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	v, err := getDownstreamVersion(configMap, clusterID, sequence)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return &downstreamtypes.DownstreamOutput{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to get downstream version")
+	}
+
+	output, err := s.getDownstreamOutput(appID, clusterID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream output")
+	}
+	if output == nil {
+		output = &downstreamOutput{}
+	}
+
+	renderError := ""
+	if v.Status == types.VersionFailed {
+		renderError = v.StatusInfo
+	}
+
+	return &downstreamtypes.DownstreamOutput{
+		DryrunStdout: decodeDownstreamOutput(output.Output.DryrunStdout, "dryrun stdout"),
+		DryrunStderr: decodeDownstreamOutput(output.Output.DryrunStderr, "dryrun stderr"),
+		ApplyStdout:  decodeDownstreamOutput(output.Output.ApplyStdout, "apply stdout"),
+		ApplyStderr:  decodeDownstreamOutput(output.Output.ApplyStderr, "apply stderr"),
+		HelmStdout:   decodeDownstreamOutput(output.Output.HelmStdout, "helm stdout"),
+		HelmStderr:   decodeDownstreamOutput(output.Output.HelmStderr, "helm stderr"),
+		RenderError:  renderError,
+		Resources:    output.Output.Resources,
+	}, nil
+}
+
+func decodeDownstreamOutput(encoded string, name string) string {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		logger.Error(errors.Wrapf(err, "failed to decode %s", name))
+		return ""
+	}
+	return string(decoded)
 }
 
 func (s *OCIStore) IsDownstreamDeploySuccessful(appID string, clusterID string, sequence int64) (bool, error) {
-	return false, ErrNotImplemented
+	output, err := s.getDownstreamOutput(appID, clusterID, sequence)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get downstream output")
+	}
+	if output == nil {
+		return false, nil
+	}
+
+	return !output.IsError, nil
 }
 
 func (s *OCIStore) UpdateDownstreamDeployStatus(appID string, clusterID string, sequence int64, isError bool, output downstreamtypes.DownstreamOutput) error {
-	return ErrNotImplemented
+	configMap, err := s.getAppDownstreamOutputsConfigMap(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get downstream outputs")
+	}
+
+	b, err := json.Marshal(downstreamOutput{
+		IsError: isError,
+		Output:  truncateDownstreamOutput(output),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal downstream output")
+	}
+
+	key := downstreamVersionKey(clusterID, sequence)
+	configMap.Data[key] = string(b)
+	pruneDownstreamOutputs(configMap.Data, key)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update downstream outputs config map")
+	}
+
+	return nil
+}
+
+// truncateDownstreamOutput keeps the end of each base64 encoded output, where errors are reported,
+// and the resources that failed before the ones that were applied.
+func truncateDownstreamOutput(output downstreamtypes.DownstreamOutput) downstreamtypes.DownstreamOutput {
+	output.DryrunStdout = truncateEncodedOutput(output.DryrunStdout)
+	output.DryrunStderr = truncateEncodedOutput(output.DryrunStderr)
+	output.ApplyStdout = truncateEncodedOutput(output.ApplyStdout)
+	output.ApplyStderr = truncateEncodedOutput(output.ApplyStderr)
+	output.HelmStdout = truncateEncodedOutput(output.HelmStdout)
+	output.HelmStderr = truncateEncodedOutput(output.HelmStderr)
+
+	if len(output.Resources) > maxDownstreamOutputResources {
+		resources := make([]downstreamtypes.DeployedResource, 0, maxDownstreamOutputResources)
+		for _, r := range output.Resources {
+			if r.Error != nil && len(resources) < maxDownstreamOutputResources {
+				resources = append(resources, r)
+			}
+		}
+		for _, r := range output.Resources {
+			if r.Error == nil && len(resources) < maxDownstreamOutputResources {
+				resources = append(resources, r)
+			}
+		}
+		output.Resources = resources
+	}
+
+	return output
+}
+
+func truncateEncodedOutput(encoded string) string {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return encoded
+	}
+	if len(decoded) <= maxDownstreamOutputBytes {
+		return encoded
+	}
+
+	truncated := append([]byte("(output truncated)\n"), decoded[len(decoded)-maxDownstreamOutputBytes:]...)
+	return base64.StdEncoding.EncodeToString(truncated)
+}
+
+// pruneDownstreamOutputs removes the outputs of the oldest sequences, keeping at most maxDownstreamOutputsPerCluster
+// per cluster and the config map data below maxDownstreamOutputsDataBytes. The output for keepKey is never removed.
+func pruneDownstreamOutputs(data map[string]string, keepKey string) {
+	type outputKey struct {
+		key       string
+		clusterID string
+		sequence  int64
+	}
+
+	keys := []outputKey{}
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+
+		idx := strings.LastIndex(key, ".")
+		if idx == -1 {
+			continue
+		}
+		sequence, err := strconv.ParseInt(key[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, outputKey{key: key, clusterID: key[:idx], sequence: sequence})
+	}
+
+	// newest first
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].sequence > keys[j].sequence
+	})
+
+	perCluster := map[string]int{}
+	for _, k := range keys {
+		perCluster[k.clusterID]++
+		if k.key == keepKey || perCluster[k.clusterID] <= maxDownstreamOutputsPerCluster {
+			continue
+		}
+		size -= len(k.key) + len(data[k.key])
+		delete(data, k.key)
+	}
+
+	for i := len(keys) - 1; i >= 0 && size > maxDownstreamOutputsDataBytes; i-- {
+		k := keys[i]
+		value, ok := data[k.key]
+		if !ok || k.key == keepKey {
+			continue
+		}
+		size -= len(k.key) + len(value)
+		delete(data, k.key)
+	}
 }
 
 func (s *OCIStore) DeleteDownstreamDeployStatus(appID string, clusterID string, sequence int64) error {
-	return ErrNotImplemented
+	configMap, err := s.getAppDownstreamOutputsConfigMap(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get downstream outputs")
+	}
+
+	key := downstreamVersionKey(clusterID, sequence)
+	if _, ok := configMap.Data[key]; !ok {
+		return nil
+	}
+
+	delete(configMap.Data, key)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update downstream outputs config map")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) MarkAsCurrentDownstreamVersion(appID string, sequence int64) error {
+	downstreams, err := s.ListDownstreamsForApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams")
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	now := time.Now()
+	for _, d := range downstreams {
+		configMap.Data[currentSequenceKey(d.ClusterID)] = strconv.FormatInt(sequence, 10)
+
+		v, err := getDownstreamVersion(configMap, d.ClusterID, sequence)
+		if err != nil {
+			if s.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrap(err, "failed to get downstream version")
+		}
+
+		v.Status = types.VersionDeployed
+		v.AppliedAt = &now
+		if err := setDownstreamVersion(configMap, *v); err != nil {
+			return errors.Wrap(err, "failed to set downstream version")
+		}
+	}
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update downstream versions config map")
+	}
+
+	return nil
 }
@@ -0,0 +1,89 @@
+package ocistore
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pruneDownstreamOutputs(t *testing.T) {
+	data := map[string]string{}
+	for i := 0; i < 15; i++ {
+		data[downstreamVersionKey("cluster-a", int64(i))] = "{}"
+	}
+	data[downstreamVersionKey("cluster-b", 0)] = "{}"
+
+	pruneDownstreamOutputs(data, downstreamVersionKey("cluster-a", 14))
+
+	assert.Len(t, data, maxDownstreamOutputsPerCluster+1)
+	for i := 0; i < 5; i++ {
+		assert.NotContains(t, data, downstreamVersionKey("cluster-a", int64(i)))
+	}
+	for i := 5; i < 15; i++ {
+		assert.Contains(t, data, downstreamVersionKey("cluster-a", int64(i)))
+	}
+	assert.Contains(t, data, downstreamVersionKey("cluster-b", 0))
+}
+
+func Test_pruneDownstreamOutputsBySize(t *testing.T) {
+	large := strings.Repeat("x", maxDownstreamOutputsDataBytes/3)
+
+	data := map[string]string{}
+	for i := 0; i < 5; i++ {
+		data[downstreamVersionKey("cluster-a", int64(i))] = large
+	}
+
+	pruneDownstreamOutputs(data, downstreamVersionKey("cluster-a", 4))
+
+	assert.Len(t, data, 2)
+	assert.Contains(t, data, downstreamVersionKey("cluster-a", 3))
+	assert.Contains(t, data, downstreamVersionKey("cluster-a", 4))
+}
+
+func Test_pruneDownstreamOutputsKeepsNewOutput(t *testing.T) {
+	data := map[string]string{
+		downstreamVersionKey("cluster-a", 0): strings.Repeat("x", maxDownstreamOutputsDataBytes),
+		downstreamVersionKey("cluster-a", 1): strings.Repeat("x", maxDownstreamOutputsDataBytes),
+	}
+
+	pruneDownstreamOutputs(data, downstreamVersionKey("cluster-a", 1))
+
+	assert.Len(t, data, 1)
+	assert.Contains(t, data, downstreamVersionKey("cluster-a", 1))
+}
+
+func Test_truncateDownstreamOutput(t *testing.T) {
+	stderr := strings.Repeat("a", maxDownstreamOutputBytes) + "error: the last line"
+
+	resources := []downstreamtypes.DeployedResource{}
+	for i := 0; i < maxDownstreamOutputResources+10; i++ {
+		resources = append(resources, downstreamtypes.DeployedResource{Kind: "ConfigMap", Name: fmt.Sprintf("cm-%d", i)})
+	}
+	resources = append(resources, downstreamtypes.DeployedResource{
+		Kind:  "Deployment",
+		Name:  "failed",
+		Error: &downstreamtypes.DeployedResourceError{Message: "invalid"},
+	})
+
+	output := truncateDownstreamOutput(downstreamtypes.DownstreamOutput{
+		ApplyStdout: base64.StdEncoding.EncodeToString([]byte("small")),
+		ApplyStderr: base64.StdEncoding.EncodeToString([]byte(stderr)),
+		Resources:   resources,
+	})
+
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("small")), output.ApplyStdout)
+
+	decoded, err := base64.StdEncoding.DecodeString(output.ApplyStderr)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(decoded), "(output truncated)\n"))
+	assert.True(t, strings.HasSuffix(string(decoded), "error: the last line"))
+	assert.Len(t, decoded, len("(output truncated)\n")+maxDownstreamOutputBytes)
+
+	require.Len(t, output.Resources, maxDownstreamOutputResources)
+	assert.Equal(t, "failed", output.Resources[0].Name)
+}
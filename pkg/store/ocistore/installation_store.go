@@ -1,6 +1,8 @@
 package ocistore
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 	installationtypes "github.com/replicatedhq/kots/pkg/online/types"
 )
@@ -10,9 +12,9 @@ const (
 )
 
 func (s *OCIStore) GetPendingInstallationStatus() (*installationtypes.InstallStatus, error) {
-	apps, err := s.ListInstalledApps()
+	apps, err := s.listApps()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to list installed apps")
+		return nil, errors.Wrap(err, "failed to list apps")
 	}
 
 	if len(apps) == 0 {
@@ -22,6 +24,10 @@ func (s *OCIStore) GetPendingInstallationStatus() (*installationtypes.InstallSta
 		}, nil
 	}
 
+	// the most recently created app
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].CreatedAt.After(apps[j].CreatedAt)
+	})
 	app := apps[0]
 
 	_, message, err := s.GetTaskStatus("online-install")
@@ -1,9 +1,54 @@
 package ocistore
 
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	KotsadmParamsConfigMapName = "kotsadm-params"
+)
+
 func (s *OCIStore) IsKotsadmIDGenerated() (bool, error) {
-	return false, ErrNotImplemented
+	value, err := s.getKotsadmParam("IS_KOTSADM_ID_GENERATED")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get kotsadm param")
+	}
+
+	return value != "", nil
 }
 
 func (s *OCIStore) SetIsKotsadmIDGenerated() error {
-	return ErrNotImplemented
+	if err := s.setKotsadmParam("IS_KOTSADM_ID_GENERATED", "true"); err != nil {
+		return errors.Wrap(err, "failed to set kotsadm param")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) getKotsadmParam(key string) (string, error) {
+	configMap, err := s.getConfigmap(KotsadmParamsConfigMapName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get kotsadm params config map")
+	}
+
+	return configMap.Data[key], nil
+}
+
+func (s *OCIStore) setKotsadmParam(key string, value string) error {
+	configMap, err := s.getConfigmap(KotsadmParamsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kotsadm params config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[key] = value
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update kotsadm params config map")
+	}
+
+	return nil
 }
@@ -1,14 +1,31 @@
 package ocistore
 
 import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
 	gitopstypes "github.com/replicatedhq/kots/pkg/gitops/types"
+	"github.com/replicatedhq/kots/pkg/logger"
 	rendertypes "github.com/replicatedhq/kots/pkg/render/types"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 )
 
 func (s *OCIStore) GetLatestLicenseForApp(appID string) (*kotsv1beta1.License, error) {
-	return nil, ErrNotImplemented
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	license, err := decodeLicense(app.License)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode license")
+	}
+
+	return license, nil
 }
 
 func (s *OCIStore) GetLicenseForAppVersion(appID string, sequence int64) (*kotsv1beta1.License, error) {
@@ -25,9 +42,102 @@ func (s *OCIStore) GetLicenseForAppVersion(appID string, sequence int64) (*kotsv
 }
 
 func (s *OCIStore) GetAllAppLicenses() ([]*kotsv1beta1.License, error) {
-	return nil, ErrNotImplemented
+	apps, err := s.listApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list apps")
+	}
+
+	licenses := []*kotsv1beta1.License{}
+	for _, app := range apps {
+		if app.License == "" {
+			continue
+		}
+
+		license, err := decodeLicense(app.License)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode license")
+		}
+		licenses = append(licenses, license)
+	}
+
+	return licenses, nil
 }
 
 func (s *OCIStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *kotsv1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops gitopstypes.DownstreamGitOps, renderer rendertypes.Renderer) (int64, error) {
-	return int64(0), ErrNotImplemented
+	ser := serializer.NewYAMLSerializer(serializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	var b bytes.Buffer
+	if err := ser.Encode(newLicense, &b); err != nil {
+		return int64(0), errors.Wrap(err, "failed to encode license")
+	}
+	encodedLicense := b.Bytes()
+	if err := ioutil.WriteFile(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"), encodedLicense, 0644); err != nil {
+		return int64(0), errors.Wrap(err, "failed to write new license")
+	}
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to get app")
+	}
+
+	//  app has the original license data received from the server
+	app.License = originalLicenseData
+
+	if err := s.updateApp(app); err != nil {
+		return int64(0), errors.Wrapf(err, "update app %q license", appID)
+	}
+
+	newSeq, err := s.createNewVersionForLicenseChange(appID, sequence, archiveDir, gitops, renderer)
+	if err != nil {
+		// ignore error here to prevent a failure to render the current version
+		// preventing the end-user from updating the application
+		if failOnVersionCreate {
+			return int64(0), errors.Wrap(err, "failed to create new version")
+		}
+		logger.Errorf("Failed to create new version from license sync: %v", err)
+	}
+
+	return newSeq, nil
+}
+
+func (s *OCIStore) createNewVersionForLicenseChange(appID string, sequence int64, archiveDir string, gitops gitopstypes.DownstreamGitOps, renderer rendertypes.Renderer) (int64, error) {
+	registrySettings, err := s.GetRegistryDetailsForApp(appID)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to get registry settings for app")
+	}
+
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to get app")
+	}
+
+	downstreams, err := s.ListDownstreamsForApp(appID)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to list downstreams")
+	}
+
+	if err := renderer.RenderDir(archiveDir, app, downstreams, registrySettings, true); err != nil {
+		return int64(0), errors.Wrap(err, "failed to render new version")
+	}
+
+	newSequence, err := s.CreateAppVersion(appID, &sequence, archiveDir, "License Change", false, gitops)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to create new version")
+	}
+
+	return newSequence, nil
+}
+
+func decodeLicense(licenseData string) (*kotsv1beta1.License, error) {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, _, err := decode([]byte(licenseData), nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode license yaml")
+	}
+
+	license, ok := obj.(*kotsv1beta1.License)
+	if !ok {
+		return nil, errors.New("not a license")
+	}
+
+	return license, nil
 }
@@ -0,0 +1,204 @@
+package ocistore
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/segmentio/ksuid"
+)
+
+/* NotificationStore
+   Endpoints are stored in a single secret because they include the signing secret.
+   The keys are the endpoint ids and the values are the JSON marshalled endpoints.
+   Deliveries are stored in a single configmap keyed by endpoint id, and only the most
+   recent deliveries for each endpoint are kept.
+*/
+
+const (
+	NotificationEndpointsSecretName     = "kotsadm-notification-endpoints"
+	NotificationDeliveriesConfigmapName = "kotsadm-notification-deliveries"
+
+	maxNotificationDeliveriesPerEndpoint = 100
+)
+
+func (s *OCIStore) ListNotificationEndpoints() ([]*notificationtypes.Endpoint, error) {
+	secret, err := s.getSecret(NotificationEndpointsSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get notification endpoints secret")
+	}
+
+	endpoints := []*notificationtypes.Endpoint{}
+	for _, data := range secret.Data {
+		endpoint := notificationtypes.Endpoint{}
+		if err := json.Unmarshal(data, &endpoint); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal endpoint")
+		}
+		endpoints = append(endpoints, &endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+
+	return endpoints, nil
+}
+
+func (s *OCIStore) GetNotificationEndpoint(endpointID string) (*notificationtypes.Endpoint, error) {
+	secret, err := s.getSecret(NotificationEndpointsSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get notification endpoints secret")
+	}
+
+	data, ok := secret.Data[endpointID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	endpoint := notificationtypes.Endpoint{}
+	if err := json.Unmarshal(data, &endpoint); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal endpoint")
+	}
+
+	return &endpoint, nil
+}
+
+func (s *OCIStore) CreateNotificationEndpoint(url string, secret string, events []notificationtypes.EventType) (*notificationtypes.Endpoint, error) {
+	endpoint := &notificationtypes.Endpoint{
+		ID:        ksuid.New().String(),
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		IsEnabled: true,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.saveNotificationEndpoint(endpoint); err != nil {
+		return nil, errors.Wrap(err, "failed to save endpoint")
+	}
+
+	return endpoint, nil
+}
+
+func (s *OCIStore) UpdateNotificationEndpoint(endpoint *notificationtypes.Endpoint) error {
+	if _, err := s.GetNotificationEndpoint(endpoint.ID); err != nil {
+		return errors.Wrap(err, "failed to get endpoint")
+	}
+
+	return errors.Wrap(s.saveNotificationEndpoint(endpoint), "failed to save endpoint")
+}
+
+func (s *OCIStore) DeleteNotificationEndpoint(endpointID string) error {
+	secret, err := s.getSecret(NotificationEndpointsSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get notification endpoints secret")
+	}
+
+	delete(secret.Data, endpointID)
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update notification endpoints secret")
+	}
+
+	configmap, err := s.getConfigmap(NotificationDeliveriesConfigmapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get notification deliveries configmap")
+	}
+
+	if _, ok := configmap.Data[endpointID]; !ok {
+		return nil
+	}
+
+	delete(configmap.Data, endpointID)
+
+	if err := s.updateConfigmap(configmap); err != nil {
+		return errors.Wrap(err, "failed to update notification deliveries configmap")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) CreateNotificationDelivery(delivery *notificationtypes.Delivery) error {
+	if delivery.ID == "" {
+		delivery.ID = ksuid.New().String()
+	}
+
+	configmap, err := s.getConfigmap(NotificationDeliveriesConfigmapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get notification deliveries configmap")
+	}
+
+	deliveries := []*notificationtypes.Delivery{}
+	if data, ok := configmap.Data[delivery.EndpointID]; ok {
+		if err := json.Unmarshal([]byte(data), &deliveries); err != nil {
+			return errors.Wrap(err, "failed to unmarshal deliveries")
+		}
+	}
+
+	// newest first
+	deliveries = append([]*notificationtypes.Delivery{delivery}, deliveries...)
+	if len(deliveries) > maxNotificationDeliveriesPerEndpoint {
+		deliveries = deliveries[:maxNotificationDeliveriesPerEndpoint]
+	}
+
+	b, err := json.Marshal(deliveries)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal deliveries")
+	}
+
+	if configmap.Data == nil {
+		configmap.Data = map[string]string{}
+	}
+	configmap.Data[delivery.EndpointID] = string(b)
+
+	if err := s.updateConfigmap(configmap); err != nil {
+		return errors.Wrap(err, "failed to update notification deliveries configmap")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) ListNotificationDeliveries(endpointID string, limit int) ([]*notificationtypes.Delivery, error) {
+	configmap, err := s.getConfigmap(NotificationDeliveriesConfigmapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get notification deliveries configmap")
+	}
+
+	deliveries := []*notificationtypes.Delivery{}
+	if data, ok := configmap.Data[endpointID]; ok {
+		if err := json.Unmarshal([]byte(data), &deliveries); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal deliveries")
+		}
+	}
+
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (s *OCIStore) saveNotificationEndpoint(endpoint *notificationtypes.Endpoint) error {
+	secret, err := s.getSecret(NotificationEndpointsSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get notification endpoints secret")
+	}
+
+	b, err := json.Marshal(endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal endpoint")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[endpoint.ID] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update notification endpoints secret")
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package ocistore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/deislabs/oras/pkg/content"
+	"github.com/deislabs/oras/pkg/oras"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (s *OCIStore) getResolver(capabilities docker.HostCapabilities) remotes.Resolver {
+	options := docker.ResolverOptions{}
+
+	registryHosts := func(host string) ([]docker.RegistryHost, error) {
+		registryHost := docker.RegistryHost{
+			Client:       http.DefaultClient,
+			Host:         host,
+			Scheme:       "https",
+			Path:         "/v2",
+			Capabilities: capabilities,
+		}
+
+		if s.PlainHTTP {
+			registryHost.Scheme = "http"
+		}
+
+		return []docker.RegistryHost{
+			registryHost,
+		}, nil
+	}
+
+	options.Hosts = registryHosts
+
+	return docker.NewResolver(options)
+}
+
+// refFor returns a reference in the storage registry
+// docker images don't allow a large charset so this names it registry.host/base/repository:tag
+func (s *OCIStore) refFor(repository string, tag string) string {
+	baseURI := strings.TrimSuffix(s.BaseURI, "/")
+	return fmt.Sprintf("%s/%s:%s", strings.TrimPrefix(baseURI, "docker://"), repository, strings.ToLower(tag))
+}
+
+// pushFile pushes a single file to the storage registry
+func (s *OCIStore) pushFile(ref string, filename string, mediaType string, fileContents []byte) error {
+	logger.Debug("pushing file to docker registry",
+		zap.String("ref", ref))
+
+	resolver := s.getResolver(docker.HostCapabilityPush)
+
+	memoryStore := content.NewMemoryStore()
+	desc := memoryStore.Add(filename, mediaType, fileContents)
+	pushContents := []ocispec.Descriptor{desc}
+	pushedDescriptor, err := oras.Push(context.Background(), resolver, ref, memoryStore, pushContents)
+	if err != nil {
+		return errors.Wrap(err, "failed to push to docker registry")
+	}
+
+	logger.Debug("pushed file to docker registry",
+		zap.String("ref", ref),
+		zap.String("digest", pushedDescriptor.Digest.String()))
+
+	return nil
+}
+
+// pullFile pulls a single file pushed with pushFile from the storage registry
+func (s *OCIStore) pullFile(ref string, filename string, mediaType string) ([]byte, error) {
+	resolver := s.getResolver(docker.HostCapabilityResolve | docker.HostCapabilityPull)
+
+	memoryStore := content.NewMemoryStore()
+	allowedMediaTypes := []string{mediaType}
+	if _, _, err := oras.Pull(context.Background(), resolver, ref, memoryStore, oras.WithAllowedMediaTypes(allowedMediaTypes)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to pull from registry storage")
+	}
+
+	_, fileContents, ok := memoryStore.GetByName(filename)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return fileContents, nil
+}
@@ -105,8 +105,9 @@ func canIgnoreEtcdError(err error) bool {
 
 func StoreFromEnv() *OCIStore {
 	return &OCIStore{
-		BaseURI:   os.Getenv("STORAGE_BASEURI"),
-		PlainHTTP: os.Getenv("STORAGE_BASEURI_PLAINHTTP") == "true",
+		BaseURI:          os.Getenv("STORAGE_BASEURI"),
+		PlainHTTP:        os.Getenv("STORAGE_BASEURI_PLAINHTTP") == "true",
+		cachedTaskStatus: map[string]*cachedTaskStatus{},
 	}
 }
 
@@ -196,6 +197,34 @@ func (s *OCIStore) updateConfigmap(configmap *corev1.ConfigMap) error {
 	return nil
 }
 
+func (s *OCIStore) updateSecret(secret *corev1.Secret) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	_, err = clientset.CoreV1().Secrets(util.PodNamespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) deleteConfigmap(name string) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	err = clientset.CoreV1().ConfigMaps(util.PodNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete config map")
+	}
+
+	return nil
+}
+
 func (s *OCIStore) ensureApplicationMetadata(applicationMetadata string, namespace string, upstreamURI string) error {
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
@@ -1,29 +1,129 @@
 package ocistore
 
 import (
+	"time"
+
+	"github.com/pkg/errors"
 	preflighttypes "github.com/replicatedhq/kots/pkg/preflight/types"
+	"github.com/replicatedhq/kots/pkg/store/types"
 )
 
 func (s *OCIStore) SetPreflightProgress(appID string, sequence int64, progress string) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.PreflightProgress = progress
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to write preflight progress")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) GetPreflightProgress(appID string, sequence int64) (string, error) {
-	return "", ErrNotImplemented
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	for _, v := range versions {
+		if v.Sequence == sequence {
+			return v.PreflightProgress, nil
+		}
+	}
+
+	return "", ErrNotFound
 }
 
 func (s *OCIStore) SetPreflightResults(appID string, sequence int64, results []byte) error {
-	return ErrNotImplemented
+	now := time.Now()
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.PreflightResult = string(results)
+		v.PreflightResultCreatedAt = &now
+		if v.Status != types.VersionDeployed {
+			v.Status = types.VersionPending
+		}
+		v.PreflightProgress = ""
+		v.PreflightSkipped = false
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to write preflight results")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) GetPreflightResults(appID string, sequence int64) (*preflighttypes.PreflightResult, error) {
-	return nil, ErrNotImplemented
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	versions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	clusters, err := s.ListClusters()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+
+	for _, v := range versions {
+		if v.Sequence != sequence {
+			continue
+		}
+
+		for _, cluster := range clusters {
+			if cluster.ClusterID != v.ClusterID {
+				continue
+			}
+
+			return &preflighttypes.PreflightResult{
+				Result:      v.PreflightResult,
+				CreatedAt:   v.PreflightResultCreatedAt,
+				AppSlug:     app.Slug,
+				ClusterSlug: cluster.ClusterSlug,
+				Skipped:     v.PreflightSkipped,
+			}, nil
+		}
+	}
+
+	return nil, ErrNotFound
 }
 
 func (s *OCIStore) ResetPreflightResults(appID string, sequence int64) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.PreflightResult = ""
+		v.PreflightResultCreatedAt = nil
+		v.PreflightSkipped = false
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to reset preflight results")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) SetIgnorePreflightPermissionErrors(appID string, sequence int64) error {
-	return ErrNotImplemented
+	err := s.updateDownstreamVersions(appID, sequence, func(v *downstreamVersion) {
+		v.Status = types.VersionPendingPreflight
+		v.PreflightIgnorePermissions = true
+		v.PreflightResult = ""
+		v.PreflightSkipped = false
+	})
+	if err != nil && !s.IsNotFound(err) {
+		return errors.Wrap(err, "failed to set downstream version ignore rbac errors")
+	}
+
+	return nil
 }
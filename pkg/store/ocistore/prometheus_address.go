@@ -1,9 +1,22 @@
 package ocistore
 
+import (
+	"github.com/pkg/errors"
+)
+
 func (s *OCIStore) GetPrometheusAddress() (string, error) {
-	return "", ErrNotImplemented
+	address, err := s.getKotsadmParam("PROMETHEUS_ADDRESS")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get kotsadm param")
+	}
+
+	return address, nil
 }
 
 func (s *OCIStore) SetPrometheusAddress(address string) error {
-	return ErrNotImplemented
+	if err := s.setKotsadmParam("PROMETHEUS_ADDRESS", address); err != nil {
+		return errors.Wrap(err, "failed to set kotsadm param")
+	}
+
+	return nil
 }
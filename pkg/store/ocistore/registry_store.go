@@ -1,17 +1,166 @@
 package ocistore
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/logger"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
+	"go.uber.org/zap"
+)
+
+const (
+	RegistrySettingsSecretName = "kotsadm-registries"
 )
 
+// storedRegistrySettings is the representation of an app's registry settings in the secret
+type storedRegistrySettings struct {
+	Hostname    string `json:"hostname"`
+	Username    string `json:"username"`
+	PasswordEnc string `json:"passwordEnc"`
+	Namespace   string `json:"namespace"`
+	IsReadOnly  bool   `json:"isReadOnly"`
+}
+
 func (s *OCIStore) GetRegistryDetailsForApp(appID string) (registrytypes.RegistrySettings, error) {
-	return registrytypes.RegistrySettings{}, ErrNotImplemented
+	secret, err := s.getSecret(RegistrySettingsSecretName)
+	if err != nil {
+		return registrytypes.RegistrySettings{}, errors.Wrap(err, "failed to get registry settings secret")
+	}
+
+	data, ok := secret.Data[appID]
+	if !ok {
+		return registrytypes.RegistrySettings{}, nil
+	}
+
+	settings := storedRegistrySettings{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return registrytypes.RegistrySettings{}, errors.Wrap(err, "failed to unmarshal registry settings")
+	}
+
+	registrySettings := registrytypes.RegistrySettings{
+		Hostname:    settings.Hostname,
+		Username:    settings.Username,
+		PasswordEnc: settings.PasswordEnc,
+		Namespace:   settings.Namespace,
+		IsReadOnly:  settings.IsReadOnly,
+	}
+
+	if settings.PasswordEnc == "" {
+		return registrySettings, nil
+	}
+
+	apiCipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return registrytypes.RegistrySettings{}, errors.Wrap(err, "failed to load apiCipher")
+	}
+
+	decodedPassword, err := base64.StdEncoding.DecodeString(registrySettings.PasswordEnc)
+	if err != nil {
+		return registrytypes.RegistrySettings{}, errors.Wrap(err, "failed to decode")
+	}
+
+	decryptedPassword, err := apiCipher.Decrypt([]byte(decodedPassword))
+	if err != nil {
+		return registrytypes.RegistrySettings{}, errors.Wrap(err, "failed to decrypt")
+	}
+
+	registrySettings.Password = string(decryptedPassword)
+
+	return registrySettings, nil
 }
 
 func (s *OCIStore) UpdateRegistry(appID string, hostname string, username string, password string, namespace string, isReadOnly bool) error {
-	return ErrNotImplemented
+	logger.Debug("updating app registry",
+		zap.String("appID", appID))
+
+	secret, err := s.getSecret(RegistrySettingsSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get registry settings secret")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	settings := storedRegistrySettings{}
+	if data, ok := secret.Data[appID]; ok {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return errors.Wrap(err, "failed to unmarshal registry settings")
+		}
+	}
+
+	settings.Hostname = hostname
+	settings.Username = username
+	settings.Namespace = namespace
+	settings.IsReadOnly = isReadOnly
+
+	if password != registrytypes.PasswordMask {
+		// password changed
+		settings.PasswordEnc = ""
+		if password != "" {
+			cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+			if err != nil {
+				return errors.Wrap(err, "failed to create aes cipher")
+			}
+
+			settings.PasswordEnc = base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte(password)))
+		}
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal registry settings")
+	}
+
+	secret.Data[appID] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update registry settings secret")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) GetAppIDsFromRegistry(hostname string) ([]string, error) {
-	return nil, ErrNotImplemented
+	secret, err := s.getSecret(RegistrySettingsSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings secret")
+	}
+
+	appIDs := []string{}
+	for appID, data := range secret.Data {
+		settings := storedRegistrySettings{}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal registry settings")
+		}
+
+		if settings.Hostname == hostname {
+			appIDs = append(appIDs, appID)
+		}
+	}
+
+	return appIDs, nil
+}
+
+func (s *OCIStore) deleteRegistryDetailsForApp(appID string) error {
+	secret, err := s.getSecret(RegistrySettingsSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get registry settings secret")
+	}
+
+	if _, ok := secret.Data[appID]; !ok {
+		return nil
+	}
+
+	delete(secret.Data, appID)
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update registry settings secret")
+	}
+
+	return nil
 }
@@ -44,6 +44,7 @@ func (s *OCIStore) CreateSession(forUser *usertypes.User, issuedAt time.Time, ex
 
 	session := sessiontypes.Session{
 		ID:        id,
+		UserID:    forUser.ID,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
 		Roles:     roles,
@@ -107,6 +108,30 @@ func (s *OCIStore) DeleteSession(id string) error {
 	return nil
 }
 
+func (s *OCIStore) DeleteUserSessions(userID string) error {
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	for id, data := range secret.Data {
+		session := sessiontypes.Session{}
+		if err := json.Unmarshal(data, &session); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to unmarshal session %s", id))
+			continue
+		}
+		if session.UserID == userID {
+			delete(secret.Data, id)
+		}
+	}
+
+	if err := s.updateSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
 func (s *OCIStore) getSessionSecret() (*corev1.Secret, error) {
 	if s.sessionSecret != nil && time.Now().Before(s.sessionExpiration) {
 		return s.sessionSecret, nil
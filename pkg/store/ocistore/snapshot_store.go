@@ -1,39 +1,233 @@
 package ocistore
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/pkg/errors"
 	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"go.uber.org/zap"
 )
 
-func (c OCIStore) ListPendingScheduledSnapshots(appID string) ([]snapshottypes.ScheduledSnapshot, error) {
-	return nil, ErrNotImplemented
+/* SnapshotStore
+   Pending scheduled snapshots are stored in a config map keyed by snapshot id.
+   Once a backup has been created for a scheduled snapshot, it's no longer pending
+   and is removed from the config map so that the number of entries doesn't grow
+   with the time the application has been running.
+*/
+
+const (
+	ScheduledSnapshotsConfigMapName         = "kotsadm-scheduledsnapshots"
+	ScheduledInstanceSnapshotsConfigMapName = "kotsadm-scheduledinstancesnapshots"
+)
+
+func (s *OCIStore) ListPendingScheduledSnapshots(appID string) ([]snapshottypes.ScheduledSnapshot, error) {
+	logger.Debug("Listing pending scheduled snapshots",
+		zap.String("appID", appID))
+
+	configMap, err := s.getConfigmap(ScheduledSnapshotsConfigMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scheduled snapshots config map")
+	}
+
+	scheduledSnapshots := []snapshottypes.ScheduledSnapshot{}
+	for _, data := range configMap.Data {
+		scheduledSnapshot := snapshottypes.ScheduledSnapshot{}
+		if err := json.Unmarshal([]byte(data), &scheduledSnapshot); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal scheduled snapshot")
+		}
+
+		if scheduledSnapshot.AppID == appID && scheduledSnapshot.BackupName == "" {
+			scheduledSnapshots = append(scheduledSnapshots, scheduledSnapshot)
+		}
+	}
+
+	return scheduledSnapshots, nil
 }
 
-func (c OCIStore) UpdateScheduledSnapshot(snapshotID string, backupName string) error {
-	return ErrNotImplemented
+func (s *OCIStore) UpdateScheduledSnapshot(snapshotID string, backupName string) error {
+	logger.Debug("Updating scheduled snapshot",
+		zap.String("ID", snapshotID))
+
+	configMap, err := s.getConfigmap(ScheduledSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled snapshots config map")
+	}
+
+	if _, ok := configMap.Data[snapshotID]; !ok {
+		return nil
+	}
+
+	delete(configMap.Data, snapshotID)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled snapshots config map")
+	}
+
+	return nil
 }
 
-func (c OCIStore) DeletePendingScheduledSnapshots(appID string) error {
-	return ErrNotImplemented
+func (s *OCIStore) DeletePendingScheduledSnapshots(appID string) error {
+	logger.Debug("Deleting pending scheduled snapshots",
+		zap.String("appID", appID))
+
+	configMap, err := s.getConfigmap(ScheduledSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled snapshots config map")
+	}
+
+	for id, data := range configMap.Data {
+		scheduledSnapshot := snapshottypes.ScheduledSnapshot{}
+		if err := json.Unmarshal([]byte(data), &scheduledSnapshot); err != nil {
+			return errors.Wrap(err, "failed to unmarshal scheduled snapshot")
+		}
+
+		if scheduledSnapshot.AppID == appID {
+			delete(configMap.Data, id)
+		}
+	}
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled snapshots config map")
+	}
+
+	return nil
 }
 
-func (c OCIStore) CreateScheduledSnapshot(snapshotID string, appID string, timestamp time.Time) error {
-	return ErrNotImplemented
+func (s *OCIStore) CreateScheduledSnapshot(snapshotID string, appID string, timestamp time.Time) error {
+	logger.Debug("Creating scheduled snapshot",
+		zap.String("appID", appID))
+
+	scheduledSnapshot := snapshottypes.ScheduledSnapshot{
+		ID:                 snapshotID,
+		AppID:              appID,
+		ScheduledTimestamp: timestamp,
+	}
+	b, err := json.Marshal(scheduledSnapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal scheduled snapshot")
+	}
+
+	configMap, err := s.getConfigmap(ScheduledSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled snapshots config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[snapshotID] = string(b)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled snapshots config map")
+	}
+
+	return nil
 }
 
-func (c OCIStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]snapshottypes.ScheduledInstanceSnapshot, error) {
-	return nil, ErrNotImplemented
+func (s *OCIStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]snapshottypes.ScheduledInstanceSnapshot, error) {
+	logger.Debug("Listing pending scheduled instance snapshots",
+		zap.String("clusterID", clusterID))
+
+	configMap, err := s.getConfigmap(ScheduledInstanceSnapshotsConfigMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scheduled instance snapshots config map")
+	}
+
+	scheduledSnapshots := []snapshottypes.ScheduledInstanceSnapshot{}
+	for _, data := range configMap.Data {
+		scheduledSnapshot := snapshottypes.ScheduledInstanceSnapshot{}
+		if err := json.Unmarshal([]byte(data), &scheduledSnapshot); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal scheduled instance snapshot")
+		}
+
+		if scheduledSnapshot.ClusterID == clusterID && scheduledSnapshot.BackupName == "" {
+			scheduledSnapshots = append(scheduledSnapshots, scheduledSnapshot)
+		}
+	}
+
+	return scheduledSnapshots, nil
 }
 
-func (c OCIStore) UpdateScheduledInstanceSnapshot(snapshotID string, backupName string) error {
-	return ErrNotImplemented
+func (s *OCIStore) UpdateScheduledInstanceSnapshot(snapshotID string, backupName string) error {
+	logger.Debug("Updating scheduled instance snapshot",
+		zap.String("ID", snapshotID))
+
+	configMap, err := s.getConfigmap(ScheduledInstanceSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled instance snapshots config map")
+	}
+
+	if _, ok := configMap.Data[snapshotID]; !ok {
+		return nil
+	}
+
+	delete(configMap.Data, snapshotID)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled instance snapshots config map")
+	}
+
+	return nil
 }
 
-func (c OCIStore) DeletePendingScheduledInstanceSnapshots(clusterID string) error {
-	return ErrNotImplemented
+func (s *OCIStore) DeletePendingScheduledInstanceSnapshots(clusterID string) error {
+	logger.Debug("Deleting pending scheduled instance snapshots",
+		zap.String("clusterID", clusterID))
+
+	configMap, err := s.getConfigmap(ScheduledInstanceSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled instance snapshots config map")
+	}
+
+	for id, data := range configMap.Data {
+		scheduledSnapshot := snapshottypes.ScheduledInstanceSnapshot{}
+		if err := json.Unmarshal([]byte(data), &scheduledSnapshot); err != nil {
+			return errors.Wrap(err, "failed to unmarshal scheduled instance snapshot")
+		}
+
+		if scheduledSnapshot.ClusterID == clusterID {
+			delete(configMap.Data, id)
+		}
+	}
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled instance snapshots config map")
+	}
+
+	return nil
 }
 
-func (c OCIStore) CreateScheduledInstanceSnapshot(snapshotID string, clusterID string, timestamp time.Time) error {
-	return ErrNotImplemented
+func (s *OCIStore) CreateScheduledInstanceSnapshot(snapshotID string, clusterID string, timestamp time.Time) error {
+	logger.Debug("Creating scheduled instance snapshot",
+		zap.String("clusterID", clusterID))
+
+	scheduledSnapshot := snapshottypes.ScheduledInstanceSnapshot{
+		ID:                 snapshotID,
+		ClusterID:          clusterID,
+		ScheduledTimestamp: timestamp,
+	}
+	b, err := json.Marshal(scheduledSnapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal scheduled instance snapshot")
+	}
+
+	configMap, err := s.getConfigmap(ScheduledInstanceSnapshotsConfigMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get scheduled instance snapshots config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[snapshotID] = string(b)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update scheduled instance snapshots config map")
+	}
+
+	return nil
 }
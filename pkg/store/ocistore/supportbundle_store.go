@@ -1,18 +1,16 @@
 package ocistore
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/containerd/containerd/remotes/docker"
-	"github.com/deislabs/oras/pkg/content"
-	"github.com/deislabs/oras/pkg/oras"
-	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/logger"
 	supportbundletypes "github.com/replicatedhq/kots/pkg/supportbundle/types"
@@ -20,161 +18,359 @@ import (
 	"go.uber.org/zap"
 )
 
+/* SupportBundleStore
+   The metadata and analysis of all support bundles are stored in a single secret,
+   keyed by "<bundleID>.bundle" and "<bundleID>.analysis".
+   The archive, tree index and redactions of each support bundle are stored in the registry
+   as registry.host/base/supportbundle:<bundleID>, supportbundle:<bundleID>-treeindex and
+   supportbundle:<bundleID>-redactions
+*/
+
+const (
+	SupportBundlesSecretName = "kotsadm-supportbundles"
+
+	supportBundleRepository = "supportbundle"
+)
+
+func supportBundleKey(bundleID string) string {
+	return fmt.Sprintf("%s.bundle", bundleID)
+}
+
+func supportBundleAnalysisKey(bundleID string) string {
+	return fmt.Sprintf("%s.analysis", bundleID)
+}
+
 func (s *OCIStore) ListSupportBundles(appID string) ([]*supportbundletypes.SupportBundle, error) {
-	return nil, ErrNotImplemented
+	secret, err := s.getSecret(SupportBundlesSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get support bundles secret")
+	}
+
+	supportBundles := []*supportbundletypes.SupportBundle{}
+	for key, data := range secret.Data {
+		if !strings.HasSuffix(key, ".bundle") {
+			continue
+		}
+
+		supportBundle := supportbundletypes.SupportBundle{}
+		if err := json.Unmarshal(data, &supportBundle); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal support bundle")
+		}
+
+		if supportBundle.AppID == appID {
+			supportBundles = append(supportBundles, &supportBundle)
+		}
+	}
+
+	// sort the bundles here by date, since we don't have a sort order otherwise
+	sort.Sort(sort.Reverse(supportbundletypes.ByCreated(supportBundles)))
+
+	return supportBundles, nil
 }
 
 func (s *OCIStore) GetSupportBundle(id string) (*supportbundletypes.SupportBundle, error) {
-	return nil, ErrNotImplemented
+	secret, err := s.getSecret(SupportBundlesSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get support bundles secret")
+	}
+
+	data, ok := secret.Data[supportBundleKey(id)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	supportBundle := supportbundletypes.SupportBundle{}
+	if err := json.Unmarshal(data, &supportBundle); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	treeindex, err := s.pullFile(s.refFor(supportBundleRepository, fmt.Sprintf("%s-treeindex", id)), "treeindex", "application/json")
+	if err != nil {
+		if s.IsNotFound(err) {
+			return &supportBundle, nil
+		}
+		return nil, errors.Wrap(err, "failed to get treeindex")
+	}
+	supportBundle.TreeIndex = string(treeindex)
+
+	return &supportBundle, nil
 }
 
 func (s *OCIStore) CreateInProgressSupportBundle(supportBundle *supportbundletypes.SupportBundle) error {
-	return ErrNotImplemented
-}
+	supportBundle.Status = supportbundletypes.BUNDLE_RUNNING
+	supportBundle.CreatedAt = time.Now()
 
-func (s *OCIStore) UploadSupportBundle(bundleID string, archivePath string, marshalledTree []byte) error {
-	return ErrNotImplemented
-}
+	if err := s.saveSupportBundle(supportBundle); err != nil {
+		return errors.Wrap(err, "failed to save support bundle")
+	}
 
-func (s *OCIStore) UpdateSupportBundle(bundle *supportbundletypes.SupportBundle) error {
-	return ErrNotImplemented
+	return nil
 }
 
-func (s *OCIStore) CreateSupportBundle(id string, appID string, archivePath string, marshalledTree []byte) (*supportbundletypes.SupportBundle, error) {
+func (s *OCIStore) UploadSupportBundle(bundleID string, archivePath string, marshalledTree []byte) error {
+	if err := s.pushFile(s.refFor(supportBundleRepository, fmt.Sprintf("%s-treeindex", bundleID)), "treeindex", "application/json", marshalledTree); err != nil {
+		return errors.Wrap(err, "failed to save treeindex")
+	}
 
 	fileContents, err := ioutil.ReadFile(archivePath)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read archive file")
+		return errors.Wrap(err, "failed to read archive file")
 	}
 
-	baseURI := os.Getenv("STORAGE_BASEURI")
-	baseURI = strings.TrimSuffix(baseURI, "/")
-
-	// docker images don't allow a large charset
-	// so this names it registry.host/base/supportbundle:{bundle-id}
-	ref := fmt.Sprintf("%s/supportbundle:%s", strings.TrimPrefix(baseURI, "docker://"), strings.ToLower(id))
-
-	logger.Debug("pushing support bundle to docker registry",
-		zap.String("ref", ref))
+	if err := s.pushFile(s.refFor(supportBundleRepository, bundleID), "supportbundle.tar.gz", "application/gzip", fileContents); err != nil {
+		return errors.Wrap(err, "failed to push archive to docker registry")
+	}
 
-	options := docker.ResolverOptions{}
+	logger.Info("pushed support bundle to docker registry",
+		zap.String("bundleID", bundleID))
 
-	registryHosts := func(host string) ([]docker.RegistryHost, error) {
-		registryHost := docker.RegistryHost{
-			Client:       http.DefaultClient,
-			Host:         host,
-			Scheme:       "https",
-			Path:         "/v2",
-			Capabilities: docker.HostCapabilityPush,
-		}
+	return nil
+}
 
-		if os.Getenv("STORAGE_BASEURI_PLAINHTTP") == "true" {
-			registryHost.Scheme = "http"
-		}
+// UpdateSupportBundle updates the support bundle definition in the secret
+func (s *OCIStore) UpdateSupportBundle(bundle *supportbundletypes.SupportBundle) error {
+	now := time.Now()
+	bundle.UpdatedAt = &now
 
-		return []docker.RegistryHost{
-			registryHost,
-		}, nil
+	if err := s.saveSupportBundle(bundle); err != nil {
+		return errors.Wrap(err, "failed to save support bundle")
 	}
 
-	options.Hosts = registryHosts
-
-	resolver := docker.NewResolver(options)
+	return nil
+}
 
-	memoryStore := content.NewMemoryStore()
-	desc := memoryStore.Add("supportbundle.tar.gz", "application/gzip", fileContents)
-	pushContents := []ocispec.Descriptor{desc}
-	pushedDescriptor, err := oras.Push(context.Background(), resolver, ref, memoryStore, pushContents)
+func (s *OCIStore) CreateSupportBundle(id string, appID string, archivePath string, marshalledTree []byte) (*supportbundletypes.SupportBundle, error) {
+	fi, err := os.Stat(archivePath)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to push archive to docker registry")
+		return nil, errors.Wrap(err, "failed to read archive")
 	}
 
-	logger.Info("pushed support bundle to docker registry",
-		zap.String("bundleID", id),
-		zap.String("ref", ref),
-		zap.String("digest", pushedDescriptor.Digest.String()))
+	if err := s.UploadSupportBundle(id, archivePath, marshalledTree); err != nil {
+		return nil, errors.Wrap(err, "failed to upload support bundle")
+	}
+
+	supportBundle := supportbundletypes.SupportBundle{
+		ID:        id,
+		Slug:      id,
+		AppID:     appID,
+		Size:      float64(fi.Size()),
+		Status:    supportbundletypes.BUNDLE_UPLOADED,
+		CreatedAt: time.Now(),
+	}
 
-	// return &types.SupportBundle{
-	// 	ID: id,
-	// }, nil
+	if err := s.saveSupportBundle(&supportBundle); err != nil {
+		return nil, errors.Wrap(err, "failed to save support bundle")
+	}
 
-	return nil, ErrNotImplemented
+	return &supportBundle, nil
 }
 
-// GetSupportBundle will fetch the bundle archive and return a path to where it
+// GetSupportBundleArchive will fetch the bundle archive and return a path to where it
 // is stored. The caller is responsible for deleting.
 func (s *OCIStore) GetSupportBundleArchive(bundleID string) (string, error) {
 	logger.Debug("getting support bundle",
 		zap.String("bundleID", bundleID))
 
+	fileContents, err := s.pullFile(s.refFor(supportBundleRepository, bundleID), "supportbundle.tar.gz", "application/gzip")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to pull from registry storage")
+	}
+
 	tmpDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create temp dir")
 	}
 
-	fileStore := content.NewFileStore(tmpDir)
-	defer fileStore.Close()
+	archivePath := filepath.Join(tmpDir, "supportbundle.tar.gz")
+	if err := ioutil.WriteFile(archivePath, fileContents, 0644); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", errors.Wrap(err, "failed to write archive")
+	}
 
-	allowedMediaTypes := []string{"application/gzip"}
+	return archivePath, nil
+}
 
-	options := docker.ResolverOptions{}
+func (s *OCIStore) GetSupportBundleAnalysis(id string) (*supportbundletypes.SupportBundleAnalysis, error) {
+	secret, err := s.getSecret(SupportBundlesSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get support bundles secret")
+	}
 
-	registryHosts := func(host string) ([]docker.RegistryHost, error) {
-		registryHost := docker.RegistryHost{
-			Client:       http.DefaultClient,
-			Host:         host,
-			Scheme:       "https",
-			Path:         "/v2",
-			Capabilities: docker.HostCapabilityResolve | docker.HostCapabilityPull,
-		}
+	if _, ok := secret.Data[supportBundleKey(id)]; !ok {
+		return nil, ErrNotFound
+	}
 
-		if os.Getenv("STORAGE_BASEURI_PLAINHTTP") == "true" {
-			registryHost.Scheme = "http"
-		}
+	data := secret.Data[supportBundleAnalysisKey(id)]
+	if len(data) == 0 {
+		return nil, nil
+	}
 
-		return []docker.RegistryHost{
-			registryHost,
-		}, nil
+	a := &supportbundletypes.SupportBundleAnalysis{}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal analysis")
 	}
 
-	options.Hosts = registryHosts
+	return a, nil
+}
 
-	resolver := docker.NewResolver(options)
+func (s *OCIStore) SetSupportBundleAnalysis(id string, insights []byte) error {
+	a := supportbundletypes.SupportBundleAnalysis{
+		CreatedAt: time.Now(),
+		Insights:  insightsFromResults(insights),
+	}
 
-	baseURI := strings.TrimSuffix(os.Getenv("STORAGE_BASEURI"), "/")
-	// docker images don't allow a large charset
-	// so this names it registry.host/base/supportbundle:{bundle-id}
-	ref := fmt.Sprintf("%s/supportbundle:%s", strings.TrimPrefix(baseURI, "docker://"), strings.ToLower(bundleID))
+	b, err := json.Marshal(a)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal analysis")
+	}
 
-	pulledDescriptor, _, err := oras.Pull(context.Background(), resolver, ref, fileStore, oras.WithAllowedMediaTypes(allowedMediaTypes))
+	secret, err := s.getSecret(SupportBundlesSecretName)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to pull from registry storage")
+		return errors.Wrap(err, "failed to get support bundles secret")
 	}
 
-	logger.Debug("pulled support bundle from docker registry",
-		zap.String("bundleID", bundleID),
-		zap.String("ref", ref),
-		zap.String("digest", pulledDescriptor.Digest.String()))
+	if _, ok := secret.Data[supportBundleKey(id)]; !ok {
+		return ErrNotFound
+	}
 
-	return filepath.Join(tmpDir, "supportbundle.tar.gz"), nil
-}
+	secret.Data[supportBundleAnalysisKey(id)] = b
 
-func (s *OCIStore) GetSupportBundleAnalysis(id string) (*supportbundletypes.SupportBundleAnalysis, error) {
-	return nil, ErrNotImplemented
-}
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update support bundles secret")
+	}
 
-func (s *OCIStore) SetSupportBundleAnalysis(id string, insights []byte) error {
-	return ErrNotImplemented
+	return nil
 }
 
 func (s *OCIStore) GetRedactions(bundleID string) (troubleshootredact.RedactionList, error) {
-	return troubleshootredact.RedactionList{}, ErrNotImplemented
+	emptyRedactions := troubleshootredact.RedactionList{
+		ByRedactor: map[string][]troubleshootredact.Redaction{},
+		ByFile:     map[string][]troubleshootredact.Redaction{},
+	}
+
+	redactions, err := s.pullFile(s.refFor(supportBundleRepository, fmt.Sprintf("%s-redactions", bundleID)), "redactions", "application/json")
+	if err != nil {
+		if s.IsNotFound(err) {
+			return emptyRedactions, nil
+		}
+		return troubleshootredact.RedactionList{}, errors.Wrap(err, "failed to get redactions from registry")
+	}
+
+	if len(redactions) == 0 {
+		return emptyRedactions, nil
+	}
+
+	redacts := troubleshootredact.RedactionList{}
+	if err := json.Unmarshal(redactions, &redacts); err != nil {
+		return troubleshootredact.RedactionList{}, errors.Wrap(err, "failed to unmarshal redact report")
+	}
+
+	return redacts, nil
 }
 
 func (s *OCIStore) SetRedactions(bundleID string, redacts troubleshootredact.RedactionList) error {
-	return ErrNotImplemented
+	redactBytes, err := json.Marshal(redacts)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal redactionlist")
+	}
+
+	if err := s.pushFile(s.refFor(supportBundleRepository, fmt.Sprintf("%s-redactions", bundleID)), "redactions", "application/json", redactBytes); err != nil {
+		return errors.Wrap(err, "failed to save redactions to registry")
+	}
+
+	return nil
 }
 
-func (s *OCIStore) GetSupportBundleSpecForApp(id string) (string, error) {
-	return "", ErrNotImplemented
+func (s *OCIStore) saveSupportBundle(supportBundle *supportbundletypes.SupportBundle) error {
+	b, err := json.Marshal(supportBundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal support bundle")
+	}
+
+	secret, err := s.getSecret(SupportBundlesSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get support bundles secret")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	secret.Data[supportBundleKey(supportBundle.ID)] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update support bundles secret")
+	}
+
+	return nil
+}
+
+// deleteSupportBundlesForApp removes the metadata of all support bundles for the app.
+// Archives are left in the registry.
+func (s *OCIStore) deleteSupportBundlesForApp(appID string) error {
+	supportBundles, err := s.ListSupportBundles(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list support bundles")
+	}
+
+	if len(supportBundles) == 0 {
+		return nil
+	}
+
+	secret, err := s.getSecret(SupportBundlesSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get support bundles secret")
+	}
+
+	for _, supportBundle := range supportBundles {
+		delete(secret.Data, supportBundleKey(supportBundle.ID))
+		delete(secret.Data, supportBundleAnalysisKey(supportBundle.ID))
+	}
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update support bundles secret")
+	}
+
+	return nil
+}
+
+func insightsFromResults(results []byte) []supportbundletypes.SupportBundleInsight {
+	type Insight struct {
+		Primary string `json:"primary"`
+		Detail  string `json:"detail"`
+	}
+	type Labels struct {
+		IconUri         string `json:"iconUri"`
+		IconKey         string `json:"iconKey"`
+		DesiredPosition string `json:"desiredPosition"`
+	}
+	type DBInsight struct {
+		Name     string  `json:"name"`
+		Severity string  `json:"severity"`
+		Insight  Insight `json:"insight"`
+		Labels   Labels  `json:"labels"`
+	}
+
+	dbInsights := []DBInsight{}
+	if err := json.Unmarshal(results, &dbInsights); err != nil {
+		logger.Error(errors.Wrap(err, "failed to unmarshal db insights"))
+		dbInsights = []DBInsight{}
+	}
+
+	insights := []supportbundletypes.SupportBundleInsight{}
+	for _, dbInsight := range dbInsights {
+		desiredPosition, _ := strconv.ParseFloat(dbInsight.Labels.DesiredPosition, 64)
+		insight := supportbundletypes.SupportBundleInsight{
+			Key:             dbInsight.Name,
+			Severity:        dbInsight.Severity,
+			Primary:         dbInsight.Insight.Primary,
+			Detail:          dbInsight.Insight.Detail,
+			Icon:            dbInsight.Labels.IconUri,
+			IconKey:         dbInsight.Labels.IconKey,
+			DesiredPosition: desiredPosition,
+		}
+		insights = append(insights, insight)
+	}
+
+	return insights
 }
@@ -1,13 +1,286 @@
 package ocistore
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+	"github.com/replicatedhq/kots/pkg/util"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/* UserStore
+   Local users are stored in a single secret because they include the password hashes.
+   The keys are the usernames and the values are the JSON marshalled users.
+*/
+
+const (
+	LocalUsersSecretName = "kotsadm-users"
+)
+
+var (
+	ErrTooManyAttempts = errors.New("too many attempts")
+	passwordSecretName = "kotsadm-password"
+)
+
+// GetSharedPasswordBcrypt will return the hash of the current password
+// that can be used to validate an auth request
 func (s *OCIStore) GetSharedPasswordBcrypt() ([]byte, error) {
-	return nil, ErrNotImplemented
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	var shaBytes []byte
+	passwordSecret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), passwordSecretName, metav1.GetOptions{})
+	if err != nil {
+		// either no existing password secret or unable to get it
+		// so instead we fallback to the environment variable
+		shaBytes = []byte(os.Getenv("SHARED_PASSWORD_BCRYPT"))
+	} else {
+		if passwordSecret.Labels == nil {
+			passwordSecret.Labels = map[string]string{}
+		}
+
+		numAttempts, _ := strconv.Atoi(passwordSecret.Labels["numAttempts"])
+		if numAttempts > 10 {
+			return nil, ErrTooManyAttempts
+		}
+
+		shaBytes = passwordSecret.Data["passwordBcrypt"]
+	}
+
+	return shaBytes, nil
+}
+
+func (s *OCIStore) FlagInvalidPassword(username string) error {
+	if username != "" {
+		return s.updateLocalUser(username, func(user *usertypes.LocalUser) {
+			user.FailedLoginCount++
+		})
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	for i := 0; ; i++ {
+		secret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), passwordSecretName, metav1.GetOptions{})
+		if err != nil {
+			if kuberneteserrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get password secret")
+		}
+
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+
+		secret.Labels["lastFailure"] = fmt.Sprintf("%d", time.Now().Unix())
+		numAttempts, _ := strconv.Atoi(secret.Labels["numAttempts"])
+		secret.Labels["numAttempts"] = strconv.Itoa(numAttempts + 1)
+
+		if _, err := clientset.CoreV1().Secrets(util.PodNamespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			if kuberneteserrors.IsConflict(err) {
+				if i > 2 {
+					return errors.New("failed to update password secret due to conflicts")
+				}
+				continue
+			}
+			return errors.Wrap(err, "failed to update password secret")
+		}
+
+		return nil
+	}
+}
+
+func (s *OCIStore) FlagSuccessfulLogin(username string) error {
+	if username != "" {
+		return s.updateLocalUser(username, func(user *usertypes.LocalUser) {
+			now := time.Now()
+			user.FailedLoginCount = 0
+			user.LastLoginAt = &now
+		})
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	for i := 0; ; i++ {
+		secret, err := clientset.CoreV1().Secrets(util.PodNamespace).Get(context.TODO(), passwordSecretName, metav1.GetOptions{})
+		if err != nil {
+			if kuberneteserrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get password secret")
+		}
+
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+
+		secret.Labels["numAttempts"] = "0"
+		if _, err := clientset.CoreV1().Secrets(util.PodNamespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			if kuberneteserrors.IsConflict(err) {
+				if i > 2 {
+					return errors.New("failed to update password secret due to conflicts")
+				}
+				continue
+			}
+			return errors.Wrap(err, "failed to update password secret")
+		}
+
+		return nil
+	}
 }
 
-func (s *OCIStore) FlagInvalidPassword() error {
-	return ErrNotFound
+func (s *OCIStore) ListLocalUsers() ([]*usertypes.LocalUser, error) {
+	secret, err := s.getSecret(LocalUsersSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get users secret")
+	}
+
+	users := []*usertypes.LocalUser{}
+	for _, data := range secret.Data {
+		user := usertypes.LocalUser{}
+		if err := json.Unmarshal(data, &user); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal user")
+		}
+		users = append(users, &user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return users, nil
 }
 
-func (s *OCIStore) FlagSuccessfulLogin() error {
-	return ErrNotImplemented
+func (s *OCIStore) GetLocalUser(username string) (*usertypes.LocalUser, error) {
+	secret, err := s.getSecret(LocalUsersSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get users secret")
+	}
+
+	data, ok := secret.Data[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	user := usertypes.LocalUser{}
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal user")
+	}
+
+	return &user, nil
+}
+
+func (s *OCIStore) CreateLocalUser(username string, passwordBcrypt []byte, roleIDs []string) (*usertypes.LocalUser, error) {
+	secret, err := s.getSecret(LocalUsersSecretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get users secret")
+	}
+
+	if _, ok := secret.Data[username]; ok {
+		return nil, errors.Errorf("user %s already exists", username)
+	}
+
+	user := &usertypes.LocalUser{
+		Username:           username,
+		PasswordBcrypt:     passwordBcrypt,
+		RoleIDs:            roleIDs,
+		MustChangePassword: true,
+		CreatedAt:          time.Now(),
+	}
+
+	b, err := json.Marshal(user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal user")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[username] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return nil, errors.Wrap(err, "failed to update users secret")
+	}
+
+	return user, nil
+}
+
+func (s *OCIStore) DeleteLocalUser(username string) error {
+	secret, err := s.getSecret(LocalUsersSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get users secret")
+	}
+
+	if _, ok := secret.Data[username]; !ok {
+		return nil
+	}
+	delete(secret.Data, username)
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update users secret")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) SetLocalUserRoles(username string, roleIDs []string) error {
+	return s.updateLocalUser(username, func(user *usertypes.LocalUser) {
+		user.RoleIDs = roleIDs
+	})
+}
+
+func (s *OCIStore) SetLocalUserPassword(username string, passwordBcrypt []byte, mustChangePassword bool) error {
+	return s.updateLocalUser(username, func(user *usertypes.LocalUser) {
+		user.PasswordBcrypt = passwordBcrypt
+		user.MustChangePassword = mustChangePassword
+		user.FailedLoginCount = 0
+	})
+}
+
+func (s *OCIStore) updateLocalUser(username string, update func(user *usertypes.LocalUser)) error {
+	secret, err := s.getSecret(LocalUsersSecretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get users secret")
+	}
+
+	data, ok := secret.Data[username]
+	if !ok {
+		return ErrNotFound
+	}
+
+	user := usertypes.LocalUser{}
+	if err := json.Unmarshal(data, &user); err != nil {
+		return errors.Wrap(err, "failed to unmarshal user")
+	}
+
+	update(&user)
+
+	b, err := json.Marshal(user)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal user")
+	}
+	secret.Data[username] = b
+
+	if err := s.updateSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update users secret")
+	}
+
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -30,7 +31,9 @@ import (
 	"github.com/replicatedhq/kots/pkg/secrets"
 	"github.com/replicatedhq/kots/pkg/store/types"
 	"github.com/replicatedhq/kots/pkg/util"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
 const (
@@ -141,7 +144,55 @@ func (s *OCIStore) IsRollbackSupportedForVersion(appID string, sequence int64) (
 }
 
 func (s *OCIStore) IsSnapshotsSupportedForVersion(a *apptypes.App, sequence int64, renderer rendertypes.Renderer) (bool, error) {
-	return false, ErrNotImplemented
+	appVersion, err := s.GetAppVersion(a.ID, sequence)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to get app version")
+	}
+
+	if appVersion.KOTSKinds == nil || appVersion.KOTSKinds.Backup == nil {
+		return false, nil
+	}
+
+	backupSpec, err := appVersion.KOTSKinds.Marshal("velero.io", "v1", "Backup")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal backup spec")
+	}
+
+	registrySettings, err := s.GetRegistryDetailsForApp(a.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get registry settings for app")
+	}
+
+	rendered, err := renderer.RenderFile(appVersion.KOTSKinds, registrySettings, a.Slug, sequence, a.IsAirgap, util.PodNamespace, []byte(backupSpec))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to render backup spec")
+	}
+
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, _, err := decode(rendered, nil, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode rendered backup spec yaml")
+	}
+	backup := obj.(*velerov1.Backup)
+
+	annotations := backup.ObjectMeta.Annotations
+	if annotations == nil {
+		// Backup exists and there are no annotation overrides so snapshots are enabled
+		return true, nil
+	}
+
+	if exclude, ok := annotations["kots.io/exclude"]; ok && exclude == "true" {
+		return false, nil
+	}
+
+	if when, ok := annotations["kots.io/when"]; ok && when == "false" {
+		return false, nil
+	}
+
+	return true, nil
 }
 
 // CreateAppVersion takes an unarchived app, makes an archive and then uploads it
@@ -391,14 +442,18 @@ func (s *OCIStore) CreateAppVersion(appID string, currentSequence *int64, filesI
 			}
 		}
 
-		commitURL, err := gitops.CreateGitOpsDownstreamCommit(appID, d.ClusterID, int(newSequence), filesInDir, d.Name)
+		commitURL, isPullRequest, err := gitops.CreateGitOpsDownstreamCommit(appID, d.ClusterID, int(newSequence), filesInDir, d.Name)
 		if err != nil {
 			return int64(0), errors.Wrap(err, "failed to create gitops commit")
 		}
+		if isPullRequest && commitURL != "" {
+			// the version is deployed when the pull request is merged
+			downstreamStatus = types.VersionPullRequestOpened
+		}
 
 		err = s.addAppVersionToDownstream(appID, d.ClusterID, newSequence,
 			kotsKinds.Installation.Spec.VersionLabel, downstreamStatus, source,
-			diffSummary, diffSummaryError, commitURL, commitURL != "")
+			diffSummary, diffSummaryError, commitURL, commitURL != "", skipPreflights)
 		if err != nil {
 			return int64(0), errors.Wrap(err, "failed to create downstream version")
 		}
@@ -421,7 +476,7 @@ func (s *OCIStore) createAppVersion(appID string, currentSequence *int64, appNam
 	// NOTE that this experimental store doesn't have a tx and it's possible that this
 	// could overwrite if there are multiple updates happening concurrently
 	latestAppVersion, err := s.getLatestAppVersion(appID)
-	if !s.IsNotFound(err) {
+	if err != nil && !s.IsNotFound(err) {
 		return int64(0), errors.Wrap(err, "failed to get latest app version")
 	}
 
@@ -461,11 +516,51 @@ func (s *OCIStore) createAppVersion(appID string, currentSequence *int64, appNam
 		return int64(0), errors.Wrap(err, "failed to update app version configmap")
 	}
 
+	a, err := s.GetApp(appID)
+	if err != nil {
+		return int64(0), errors.Wrap(err, "failed to get app")
+	}
+
+	a.CurrentSequence = newSequence
+	a.Name = appName
+	a.IconURI = appIcon
+
+	if err := s.updateApp(a); err != nil {
+		return int64(0), errors.Wrap(err, "failed to update app")
+	}
+
 	return newSequence, nil
 }
 
-func (s *OCIStore) addAppVersionToDownstream(appID string, clusterID string, sequence int64, versionLabel string, status types.DownstreamVersionStatus, source string, diffSummary string, diffSummaryError string, commitURL string, gitDeployable bool) error {
-	return ErrNotImplemented
+func (s *OCIStore) addAppVersionToDownstream(appID string, clusterID string, sequence int64, versionLabel string, status types.DownstreamVersionStatus, source string, diffSummary string, diffSummaryError string, commitURL string, gitDeployable bool, preflightsSkipped bool) error {
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	v := downstreamVersion{
+		ClusterID:        clusterID,
+		Sequence:         sequence,
+		ParentSequence:   sequence,
+		CreatedAt:        time.Now(),
+		VersionLabel:     versionLabel,
+		Status:           status,
+		Source:           source,
+		DiffSummary:      diffSummary,
+		DiffSummaryError: diffSummaryError,
+		CommitURL:        commitURL,
+		GitDeployable:    gitDeployable,
+		PreflightSkipped: preflightsSkipped,
+	}
+	if err := setDownstreamVersion(configMap, v); err != nil {
+		return errors.Wrap(err, "failed to set downstream version")
+	}
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update downstream versions config map")
+	}
+
+	return nil
 }
 
 func (s *OCIStore) GetAppVersion(appID string, sequence int64) (*versiontypes.AppVersion, error) {
@@ -498,7 +593,104 @@ func (s *OCIStore) GetAppVersion(appID string, sequence int64) (*versiontypes.Ap
 }
 
 func (s *OCIStore) GetAppVersionsAfter(appID string, sequence int64) ([]*versiontypes.AppVersion, error) {
-	return nil, ErrNotImplemented
+	appVersions, err := s.listAppVersions(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list app versions")
+	}
+
+	configMap, err := s.getAppDownstreamVersionsConfigMap(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get downstream versions")
+	}
+
+	downstreamVersions, err := listDownstreamVersions(configMap, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstream versions")
+	}
+
+	versions := []*versiontypes.AppVersion{}
+	for _, appVersion := range appVersions {
+		if appVersion.Sequence <= sequence {
+			continue
+		}
+
+		for _, downstreamVersion := range downstreamVersions {
+			if downstreamVersion.ParentSequence == appVersion.Sequence {
+				appVersion.Status = string(downstreamVersion.Status)
+				appVersion.DeployedAt = downstreamVersion.AppliedAt
+				break
+			}
+		}
+
+		versions = append(versions, appVersion)
+	}
+
+	return versions, nil
+}
+
+// listAppVersions returns all versions of the app sorted by sequence ascending
+func (s *OCIStore) listAppVersions(appID string) ([]*versiontypes.AppVersion, error) {
+	configMapName, err := s.appVersionConfigMapNameForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get appversion config map name")
+	}
+
+	configMap, err := s.getConfigmap(configMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app version config map")
+	}
+
+	appVersions := []*versiontypes.AppVersion{}
+	for _, data := range configMap.Data {
+		appVersion := versiontypes.AppVersion{}
+		if err := json.Unmarshal([]byte(data), &appVersion); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal app version")
+		}
+		appVersion.AppID = appID
+
+		appVersions = append(appVersions, &appVersion)
+	}
+
+	sort.Slice(appVersions, func(i, j int) bool {
+		return appVersions[i].Sequence < appVersions[j].Sequence
+	})
+
+	return appVersions, nil
+}
+
+func (s *OCIStore) GetNextAppSequence(appID string) (int64, error) {
+	latestAppVersion, err := s.getLatestAppVersion(appID)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "failed to get latest app version")
+	}
+
+	return latestAppVersion.Sequence + 1, nil
+}
+
+func (s *OCIStore) UpdateAppVersionConfigValues(appID string, sequence int64, filesInDir string) error {
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(filesInDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kots kinds")
+	}
+
+	appVersion, err := s.GetAppVersion(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version")
+	}
+
+	if appVersion.KOTSKinds == nil {
+		appVersion.KOTSKinds = &kotsutil.KotsKinds{}
+	}
+	appVersion.KOTSKinds.ConfigValues = kotsKinds.ConfigValues
+
+	if err := s.updateAppVersion(appVersion); err != nil {
+		return errors.Wrap(err, "failed to update app version")
+	}
+
+	return nil
 }
 
 func refFromAppVersion(appID string, sequence int64, baseURI string) string {
@@ -512,5 +704,48 @@ func refFromAppVersion(appID string, sequence int64, baseURI string) string {
 }
 
 func (s *OCIStore) UpdateAppVersionInstallationSpec(appID string, sequence int64, installation kotsv1beta1.Installation) error {
-	return ErrNotImplemented
+	appVersion, err := s.GetAppVersion(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version")
+	}
+
+	if appVersion.KOTSKinds == nil {
+		appVersion.KOTSKinds = &kotsutil.KotsKinds{}
+	}
+	appVersion.KOTSKinds.Installation = installation
+
+	if err := s.updateAppVersion(appVersion); err != nil {
+		return errors.Wrap(err, "failed to update app version")
+	}
+
+	return nil
+}
+
+func (s *OCIStore) updateAppVersion(appVersion *versiontypes.AppVersion) error {
+	b, err := json.Marshal(appVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal app version")
+	}
+
+	configMapName, err := s.appVersionConfigMapNameForApp(appVersion.AppID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get appversion config map name")
+	}
+
+	configMap, err := s.getConfigmap(configMapName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[strconv.FormatInt(appVersion.Sequence, 10)] = string(b)
+
+	if err := s.updateConfigmap(configMap); err != nil {
+		return errors.Wrap(err, "failed to update app version configmap")
+	}
+
+	return nil
 }
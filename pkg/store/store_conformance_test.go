@@ -0,0 +1,550 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/replicatedhq/kots/pkg/store/kotsstore"
+	"github.com/replicatedhq/kots/pkg/store/ocistore"
+	supportbundletypes "github.com/replicatedhq/kots/pkg/supportbundle/types"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+	"github.com/stretchr/testify/require"
+)
+
+type conformanceStore struct {
+	store Store
+	// sqliteURI is set as persistence.SQLiteURI while the store is tested
+	sqliteURI string
+	// hasCluster is false when there is no kubernetes cluster to store secrets and configmaps in
+	hasCluster bool
+}
+
+// conformanceStores returns the store implementations that can be tested in this environment.
+// kotsstore is always tested against sqlite. It is also tested against Postgres if POSTGRES_URI is set,
+// and ocistore is tested if STORAGE_BASEURI is set. Both of these also require access to a kubernetes cluster.
+func conformanceStores(t *testing.T) map[string]conformanceStore {
+	sqliteDir, err := ioutil.TempDir("", "kotsadm-conformance")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(sqliteDir)
+	})
+
+	stores := map[string]conformanceStore{
+		"kotsstore-sqlite": {
+			store:     kotsstore.StoreFromEnv(),
+			sqliteURI: filepath.Join(sqliteDir, "kotsadm.db"),
+		},
+	}
+
+	if os.Getenv("POSTGRES_URI") != "" {
+		stores["kotsstore"] = conformanceStore{store: kotsstore.StoreFromEnv(), hasCluster: true}
+	}
+	if os.Getenv("STORAGE_BASEURI") != "" {
+		stores["ocistore"] = conformanceStore{store: ocistore.StoreFromEnv(), hasCluster: true}
+	}
+
+	if os.Getenv("API_ENCRYPTION_KEY") == "" {
+		cipher, err := crypto.NewAESCipher()
+		require.NoError(t, err)
+		os.Setenv("API_ENCRYPTION_KEY", cipher.ToString())
+	}
+
+	return stores
+}
+
+func Test_StoreConformance(t *testing.T) {
+	tests := []struct {
+		name            string
+		requiresCluster bool
+		run             func(t *testing.T, s Store)
+	}{
+		{
+			name:            "sessions",
+			requiresCluster: true,
+			run: func(t *testing.T, s Store) {
+				issuedAt := time.Now().Truncate(time.Second)
+				expiresAt := issuedAt.Add(time.Hour)
+
+				session, err := s.CreateSession(&usertypes.User{ID: "conformance"}, issuedAt, expiresAt, nil)
+				require.NoError(t, err)
+
+				actual, err := s.GetSession(session.ID)
+				require.NoError(t, err)
+				require.Equal(t, session.ID, actual.ID)
+				require.Equal(t, "conformance", actual.UserID)
+				require.True(t, expiresAt.Equal(actual.ExpiresAt))
+
+				require.NoError(t, s.DeleteSession(session.ID))
+
+				userSession, err := s.CreateSession(&usertypes.User{ID: "conformance-user"}, issuedAt, expiresAt, nil)
+				require.NoError(t, err)
+				otherSession, err := s.CreateSession(&usertypes.User{ID: "conformance-other"}, issuedAt, expiresAt, nil)
+				require.NoError(t, err)
+
+				require.NoError(t, s.DeleteUserSessions("conformance-user"))
+
+				actual, err = s.GetSession(userSession.ID)
+				require.NoError(t, err)
+				require.Nil(t, actual)
+
+				actual, err = s.GetSession(otherSession.ID)
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+
+				require.NoError(t, s.DeleteSession(otherSession.ID))
+			},
+		},
+		{
+			name: "local users",
+			run: func(t *testing.T, s Store) {
+				username := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+				created, err := s.CreateLocalUser(username, []byte("hash"), []string{"support"})
+				require.NoError(t, err)
+				require.True(t, created.MustChangePassword)
+
+				require.NoError(t, s.FlagInvalidPassword(username))
+				user, err := s.GetLocalUser(username)
+				require.NoError(t, err)
+				require.Equal(t, 1, user.FailedLoginCount)
+				require.Equal(t, []string{"support"}, user.RoleIDs)
+
+				require.NoError(t, s.SetLocalUserRoles(username, []string{"cluster-admin"}))
+				require.NoError(t, s.SetLocalUserPassword(username, []byte("newhash"), false))
+				require.NoError(t, s.FlagSuccessfulLogin(username))
+
+				user, err = s.GetLocalUser(username)
+				require.NoError(t, err)
+				require.Equal(t, []byte("newhash"), user.PasswordBcrypt)
+				require.Equal(t, []string{"cluster-admin"}, user.RoleIDs)
+				require.False(t, user.MustChangePassword)
+				require.Equal(t, 0, user.FailedLoginCount)
+				require.NotNil(t, user.LastLoginAt)
+
+				require.NoError(t, s.DeleteLocalUser(username))
+				_, err = s.GetLocalUser(username)
+				require.True(t, s.IsNotFound(err))
+				require.True(t, s.IsNotFound(s.SetLocalUserRoles(username, nil)))
+			},
+		},
+		{
+			name: "audit events",
+			run: func(t *testing.T, s Store) {
+				appSlug := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+				sequence := int64(2)
+
+				for i := 0; i < 3; i++ {
+					require.NoError(t, s.CreateAuditEvent(&audittypes.Event{
+						CreatedAt:  time.Now().Add(time.Duration(i) * time.Second),
+						SessionID:  "conformance",
+						Actor:      "conformance",
+						Action:     "write",
+						Resource:   fmt.Sprintf("app.%s.downstream.", appSlug),
+						Method:     "POST",
+						Path:       fmt.Sprintf("/api/v1/app/%s/sequence/%d/deploy", appSlug, i),
+						AppSlug:    appSlug,
+						Sequence:   &sequence,
+						StatusCode: 200,
+					}))
+				}
+
+				events, totalCount, err := s.ListAuditEvents(audittypes.ListEventsOptions{AppSlug: appSlug, Offset: 1, Limit: 1})
+				require.NoError(t, err)
+				require.Equal(t, int64(3), totalCount)
+				require.Len(t, events, 1)
+				require.Equal(t, fmt.Sprintf("/api/v1/app/%s/sequence/1/deploy", appSlug), events[0].Path)
+				require.Equal(t, &sequence, events[0].Sequence)
+			},
+		},
+		{
+			name: "api tokens",
+			run: func(t *testing.T, s Store) {
+				tokenID := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+				require.NoError(t, s.CreateAPIToken(&apitokentypes.APIToken{
+					ID:           tokenID,
+					Name:         "ci",
+					SecretSHA256: "abc123",
+					RoleIDs:      []string{"cluster-admin"},
+					CreatedBy:    "conformance",
+					CreatedAt:    time.Now(),
+					ExpiresAt:    time.Now().Add(time.Hour),
+				}))
+
+				token, err := s.GetAPIToken(tokenID)
+				require.NoError(t, err)
+				require.Equal(t, "abc123", token.SecretSHA256)
+				require.Equal(t, []string{"cluster-admin"}, token.RoleIDs)
+				require.True(t, token.IsActive(time.Now()))
+
+				require.NoError(t, s.RevokeAPIToken(tokenID))
+				require.NoError(t, s.RevokeAPIToken(tokenID))
+
+				token, err = s.GetAPIToken(tokenID)
+				require.NoError(t, err)
+				require.NotNil(t, token.RevokedAt)
+				require.False(t, token.IsActive(time.Now()))
+
+				_, err = s.GetAPIToken(tokenID + "-missing")
+				require.True(t, s.IsNotFound(err))
+			},
+		},
+		{
+			name:            "task status",
+			requiresCluster: true,
+			run: func(t *testing.T, s Store) {
+				taskID := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+				require.NoError(t, s.SetTaskStatus(taskID, "working", "running"))
+
+				status, message, err := s.GetTaskStatus(taskID)
+				require.NoError(t, err)
+				require.Equal(t, "running", status)
+				require.Equal(t, "working", message)
+
+				require.NoError(t, s.ClearTaskStatus(taskID))
+
+				status, _, err = s.GetTaskStatus(taskID)
+				require.NoError(t, err)
+				require.Equal(t, "", status)
+			},
+		},
+		{
+			name: "apps",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				appID, err := s.GetAppIDFromSlug(app.Slug)
+				require.NoError(t, err)
+				require.Equal(t, app.ID, appID)
+
+				actual, err := s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, app.Name, actual.Name)
+
+				require.NoError(t, s.SetAppInstallState(app.ID, "installed"))
+
+				slugs, err := s.ListInstalledAppSlugs()
+				require.NoError(t, err)
+				require.Contains(t, slugs, app.Slug)
+
+				require.NoError(t, s.SetSnapshotTTL(app.ID, "168h"))
+				require.NoError(t, s.SetSnapshotSchedule(app.ID, "0 0 * * *"))
+
+				actual, err = s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, "168h", actual.SnapshotTTL)
+				require.Equal(t, "0 0 * * *", actual.SnapshotSchedule)
+			},
+		},
+		{
+			name: "clusters",
+			run: func(t *testing.T, s Store) {
+				title := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+				token := fmt.Sprintf("token-%d", time.Now().UnixNano())
+
+				clusterID, err := s.CreateNewCluster("", true, title, token)
+				require.NoError(t, err)
+
+				actualID, err := s.GetClusterIDFromDeployToken(token)
+				require.NoError(t, err)
+				require.Equal(t, clusterID, actualID)
+
+				require.NoError(t, s.SetInstanceSnapshotTTL(clusterID, "720h"))
+				require.NoError(t, s.SetInstanceSnapshotSchedule(clusterID, "0 0 * * *"))
+
+				clusters, err := s.ListClusters()
+				require.NoError(t, err)
+
+				var cluster *downstreamtypes.Downstream
+				for _, c := range clusters {
+					if c.ClusterID == clusterID {
+						cluster = c
+					}
+				}
+				require.NotNil(t, cluster)
+				require.Equal(t, title, cluster.Name)
+				require.Equal(t, "720h", cluster.SnapshotTTL)
+				require.Equal(t, "0 0 * * *", cluster.SnapshotSchedule)
+
+				actualID, err = s.GetClusterIDFromSlug(cluster.ClusterSlug)
+				require.NoError(t, err)
+				require.Equal(t, clusterID, actualID)
+			},
+		},
+		{
+			name: "app status",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				appStatus, err := s.GetAppStatus(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, appstatustypes.StateMissing, appStatus.State)
+
+				resourceStates := []appstatustypes.ResourceState{
+					{
+						Kind:      "deployment",
+						Name:      "web",
+						Namespace: "default",
+						State:     appstatustypes.StateDegraded,
+					},
+				}
+				require.NoError(t, s.SetAppStatus(app.ID, resourceStates, time.Now(), 1))
+
+				appStatus, err = s.GetAppStatus(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, appstatustypes.StateDegraded, appStatus.State)
+				require.Equal(t, int64(1), appStatus.Sequence)
+				require.Equal(t, resourceStates, appStatus.ResourceStates)
+			},
+		},
+		{
+			name: "prometheus address",
+			run: func(t *testing.T, s Store) {
+				require.NoError(t, s.SetPrometheusAddress("http://prometheus:9090"))
+
+				address, err := s.GetPrometheusAddress()
+				require.NoError(t, err)
+				require.Equal(t, "http://prometheus:9090", address)
+			},
+		},
+		{
+			name: "kotsadm params",
+			run: func(t *testing.T, s Store) {
+				require.NoError(t, s.SetIsKotsadmIDGenerated())
+
+				isGenerated, err := s.IsKotsadmIDGenerated()
+				require.NoError(t, err)
+				require.True(t, isGenerated)
+			},
+		},
+		{
+			name: "registry",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+				hostname := fmt.Sprintf("registry-%d.example.com", time.Now().UnixNano())
+
+				require.NoError(t, s.UpdateRegistry(app.ID, hostname, "user", "password", "ns", false))
+
+				registrySettings, err := s.GetRegistryDetailsForApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, hostname, registrySettings.Hostname)
+				require.Equal(t, "user", registrySettings.Username)
+				require.Equal(t, "password", registrySettings.Password)
+				require.Equal(t, "ns", registrySettings.Namespace)
+
+				appIDs, err := s.GetAppIDsFromRegistry(hostname)
+				require.NoError(t, err)
+				require.Equal(t, []string{app.ID}, appIDs)
+			},
+		},
+		{
+			name: "scheduled snapshots",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+				snapshotID := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+				require.NoError(t, s.CreateScheduledSnapshot(snapshotID, app.ID, time.Now()))
+
+				pending, err := s.ListPendingScheduledSnapshots(app.ID)
+				require.NoError(t, err)
+				require.Len(t, pending, 1)
+				require.Equal(t, snapshotID, pending[0].ID)
+
+				require.NoError(t, s.UpdateScheduledSnapshot(snapshotID, "backup"))
+
+				pending, err = s.ListPendingScheduledSnapshots(app.ID)
+				require.NoError(t, err)
+				require.Len(t, pending, 0)
+			},
+		},
+		{
+			name:            "support bundle metadata",
+			requiresCluster: true,
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+				bundleID := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+				require.NoError(t, s.CreateInProgressSupportBundle(&supportbundletypes.SupportBundle{
+					ID:    bundleID,
+					Slug:  bundleID,
+					AppID: app.ID,
+				}))
+
+				bundles, err := s.ListSupportBundles(app.ID)
+				require.NoError(t, err)
+				require.Len(t, bundles, 1)
+				require.Equal(t, supportbundletypes.BUNDLE_RUNNING, bundles[0].Status)
+
+				bundle := bundles[0]
+				bundle.Status = supportbundletypes.BUNDLE_UPLOADED
+				require.NoError(t, s.UpdateSupportBundle(bundle))
+
+				actual, err := s.GetSupportBundle(bundleID)
+				require.NoError(t, err)
+				require.Equal(t, supportbundletypes.BUNDLE_UPLOADED, actual.Status)
+
+				require.NoError(t, s.SetSupportBundleAnalysis(bundleID, []byte(`[{"name":"check","severity":"warn","insight":{"primary":"p","detail":"d"}}]`)))
+
+				analysis, err := s.GetSupportBundleAnalysis(bundleID)
+				require.NoError(t, err)
+				require.Len(t, analysis.Insights, 1)
+				require.Equal(t, "check", analysis.Insights[0].Key)
+			},
+		},
+		{
+			name: "auto deploy policy",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+				require.Nil(t, app.AutoDeployPolicy)
+
+				policy := &apptypes.AutoDeployPolicy{
+					Mode: apptypes.AutoDeployPatch,
+					MaintenanceWindows: []apptypes.MaintenanceWindow{
+						{Schedule: "0 2 * * *", Duration: "2h"},
+					},
+				}
+				require.NoError(t, s.SetAutoDeployPolicy(app.ID, policy))
+
+				actual, err := s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, policy, actual.AutoDeployPolicy)
+
+				require.NoError(t, s.SetAutoDeployPolicy(app.ID, nil))
+
+				actual, err = s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Nil(t, actual.AutoDeployPolicy)
+			},
+		},
+		{
+			name: "app restore",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				require.NoError(t, s.SetAppRestoreInProgress(app.ID, "backup-1"))
+				require.NoError(t, s.SetAppRestoreUndeployStatus(app.ID, apptypes.UndeployInProcess))
+
+				actual, err := s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, "backup-1", actual.RestoreInProgressName)
+				require.Equal(t, apptypes.UndeployInProcess, actual.RestoreUndeployStatus)
+
+				require.NoError(t, s.ResetAppRestore(app.ID))
+
+				actual, err = s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, "", actual.RestoreInProgressName)
+				require.Equal(t, apptypes.UndeployStatus(""), actual.RestoreUndeployStatus)
+			},
+		},
+		{
+			name: "last update check",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				require.NoError(t, s.SetLastUpdateCheckAt(app.ID, time.Now()))
+
+				actual, err := s.GetApp(app.ID)
+				require.NoError(t, err)
+				require.NotEmpty(t, actual.LastUpdateCheckAt)
+			},
+		},
+		{
+			name: "next app sequence",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				sequence, err := s.GetNextAppSequence(app.ID)
+				require.NoError(t, err)
+				require.Equal(t, int64(0), sequence)
+			},
+		},
+		{
+			name: "notification endpoints",
+			run: func(t *testing.T, s Store) {
+				endpoint, err := s.CreateNotificationEndpoint("https://example.com/hook", "secret", []notificationtypes.EventType{notificationtypes.EventDeployFailed})
+				require.NoError(t, err)
+
+				got, err := s.GetNotificationEndpoint(endpoint.ID)
+				require.NoError(t, err)
+				require.Equal(t, "secret", got.Secret)
+				require.Equal(t, []notificationtypes.EventType{notificationtypes.EventDeployFailed}, got.Events)
+				require.True(t, got.IsEnabled)
+
+				got.IsEnabled = false
+				require.NoError(t, s.UpdateNotificationEndpoint(got))
+
+				endpoints, err := s.ListNotificationEndpoints()
+				require.NoError(t, err)
+				require.NotEmpty(t, endpoints)
+
+				require.NoError(t, s.CreateNotificationDelivery(&notificationtypes.Delivery{
+					EndpointID:  endpoint.ID,
+					EventID:     "event",
+					EventType:   notificationtypes.EventDeployFailed,
+					Attempt:     1,
+					StatusCode:  200,
+					IsSuccess:   true,
+					DeliveredAt: time.Now(),
+				}))
+
+				deliveries, err := s.ListNotificationDeliveries(endpoint.ID, 10)
+				require.NoError(t, err)
+				require.Len(t, deliveries, 1)
+				require.True(t, deliveries[0].IsSuccess)
+				require.Equal(t, 200, deliveries[0].StatusCode)
+
+				require.NoError(t, s.DeleteNotificationEndpoint(endpoint.ID))
+
+				_, err = s.GetNotificationEndpoint(endpoint.ID)
+				require.True(t, s.IsNotFound(err))
+			},
+		},
+		{
+			name: "remove app",
+			run: func(t *testing.T, s Store) {
+				app := createConformanceApp(t, s)
+
+				require.NoError(t, s.RemoveApp(app.ID))
+
+				_, err := s.GetApp(app.ID)
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for storeName, s := range conformanceStores(t) {
+		persistence.SQLiteURI = s.sqliteURI
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%s/%s", storeName, test.name), func(t *testing.T) {
+				if test.requiresCluster && !s.hasCluster {
+					t.Skip("requires a kubernetes cluster")
+				}
+				test.run(t, s.store)
+			})
+		}
+	}
+	persistence.SQLiteURI = ""
+}
+
+func createConformanceApp(t *testing.T, s Store) *apptypes.App {
+	name := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+
+	app, err := s.CreateApp(name, "replicated://conformance", "", false, false, false)
+	require.NoError(t, err)
+
+	return app
+}
@@ -9,9 +9,12 @@ import (
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
+	apitokentypes "github.com/replicatedhq/kots/pkg/apitoken/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
 	gitopstypes "github.com/replicatedhq/kots/pkg/gitops/types"
 	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
 	installationtypes "github.com/replicatedhq/kots/pkg/online/types"
 	preflighttypes "github.com/replicatedhq/kots/pkg/preflight/types"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
@@ -42,6 +45,9 @@ type Store interface {
 	SnapshotStore
 	InstallationStore
 	KotsadmParamsStore
+	NotificationStore
+	AuditStore
+	APITokenStore
 
 	Init() error // this may need options
 	WaitForReady(ctx context.Context) error
@@ -103,6 +109,7 @@ type TaskStore interface {
 type SessionStore interface {
 	CreateSession(user *usertypes.User, issuedAt time.Time, expiresAt time.Time, roles []string) (*sessiontypes.Session, error)
 	DeleteSession(sessionID string) error
+	DeleteUserSessions(userID string) error
 	GetSession(sessionID string) (*sessiontypes.Session, error)
 }
 
@@ -125,8 +132,16 @@ type AppStore interface {
 	GetDownstream(clusterID string) (*downstreamtypes.Downstream, error)
 	IsGitOpsEnabledForApp(appID string) (bool, error)
 	SetUpdateCheckerSpec(appID string, updateCheckerSpec string) error
+	SetAutoDeployPolicy(appID string, policy *apptypes.AutoDeployPolicy) error
 	SetSnapshotTTL(appID string, snapshotTTL string) error
+	// SetSnapshotRetentionPolicy clears the policy if it is nil
+	SetSnapshotRetentionPolicy(appID string, policy *snapshottypes.RetentionPolicy) error
 	SetSnapshotSchedule(appID string, snapshotSchedule string) error
+	SetLastUpdateCheckAt(appID string, checkedAt time.Time) error
+	SetAppRestoreInProgress(appID string, snapshotName string) error
+	SetAppRestoreUndeployStatus(appID string, undeployStatus apptypes.UndeployStatus) error
+	// ResetAppRestore clears the restore in progress and its undeploy status
+	ResetAppRestore(appID string) error
 	RemoveApp(appID string) error
 }
 
@@ -148,6 +163,7 @@ type DownstreamStore interface {
 	IsDownstreamDeploySuccessful(appID string, clusterID string, sequence int64) (bool, error)
 	UpdateDownstreamDeployStatus(appID string, clusterID string, sequence int64, isError bool, output downstreamtypes.DownstreamOutput) error
 	DeleteDownstreamDeployStatus(appID string, clusterID string, sequence int64) error
+	MarkAsCurrentDownstreamVersion(appID string, sequence int64) error
 }
 
 type SnapshotStore interface {
@@ -171,6 +187,9 @@ type VersionStore interface {
 	CreateAppVersion(appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error)
 	GetAppVersion(appID string, sequence int64) (*versiontypes.AppVersion, error)
 	GetAppVersionsAfter(appID string, sequence int64) ([]*versiontypes.AppVersion, error)
+	// GetNextAppSequence returns 0 if the app has no versions
+	GetNextAppSequence(appID string) (int64, error)
+	UpdateAppVersionConfigValues(appID string, sequence int64, filesInDir string) error
 	UpdateAppVersionInstallationSpec(appID string, sequence int64, spec kotsv1beta1.Installation) error
 }
 
@@ -185,8 +204,18 @@ type LicenseStore interface {
 
 type UserStore interface {
 	GetSharedPasswordBcrypt() ([]byte, error)
-	FlagInvalidPassword() error
-	FlagSuccessfulLogin() error
+	// FlagInvalidPassword and FlagSuccessfulLogin track failed login attempts for the local user
+	// with the given username, or for the shared password if username is empty
+	FlagInvalidPassword(username string) error
+	FlagSuccessfulLogin(username string) error
+
+	ListLocalUsers() ([]*usertypes.LocalUser, error)
+	GetLocalUser(username string) (*usertypes.LocalUser, error)
+	CreateLocalUser(username string, passwordBcrypt []byte, roleIDs []string) (*usertypes.LocalUser, error)
+	DeleteLocalUser(username string) error
+	SetLocalUserRoles(username string, roleIDs []string) error
+	// SetLocalUserPassword also resets the failed login count
+	SetLocalUserPassword(username string, passwordBcrypt []byte, mustChangePassword bool) error
 }
 
 type ClusterStore interface {
@@ -195,6 +224,9 @@ type ClusterStore interface {
 	GetClusterIDFromDeployToken(deployToken string) (clusterID string, err error)
 	CreateNewCluster(userID string, isAllUsers bool, title string, token string) (clusterID string, err error)
 	SetInstanceSnapshotTTL(clusterID string, snapshotTTL string) error
+	SetInstanceSnapshotRetentionPolicy(clusterID string, policy *snapshottypes.RetentionPolicy) error
+	// SetInstanceSnapshotVerificationPolicy clears the policy if it is nil
+	SetInstanceSnapshotVerificationPolicy(clusterID string, policy *snapshottypes.VerificationPolicy) error
 	SetInstanceSnapshotSchedule(clusterID string, snapshotSchedule string) error
 }
 
@@ -206,3 +238,26 @@ type KotsadmParamsStore interface {
 	IsKotsadmIDGenerated() (bool, error)
 	SetIsKotsadmIDGenerated() error
 }
+
+type NotificationStore interface {
+	ListNotificationEndpoints() ([]*notificationtypes.Endpoint, error)
+	GetNotificationEndpoint(endpointID string) (*notificationtypes.Endpoint, error)
+	CreateNotificationEndpoint(url string, secret string, events []notificationtypes.EventType) (*notificationtypes.Endpoint, error)
+	UpdateNotificationEndpoint(endpoint *notificationtypes.Endpoint) error
+	DeleteNotificationEndpoint(endpointID string) error
+	CreateNotificationDelivery(delivery *notificationtypes.Delivery) error
+	ListNotificationDeliveries(endpointID string, limit int) ([]*notificationtypes.Delivery, error)
+}
+
+type AuditStore interface {
+	CreateAuditEvent(event *audittypes.Event) error
+	// ListAuditEvents returns the matching events newest first and the total number of matching events
+	ListAuditEvents(opts audittypes.ListEventsOptions) ([]*audittypes.Event, int64, error)
+}
+
+type APITokenStore interface {
+	ListAPITokens() ([]*apitokentypes.APIToken, error)
+	GetAPIToken(tokenID string) (*apitokentypes.APIToken, error)
+	CreateAPIToken(token *apitokentypes.APIToken) error
+	RevokeAPIToken(tokenID string) error
+}
@@ -10,4 +10,9 @@ const (
 	VersionDeploying        DownstreamVersionStatus = "deploying"
 	VersionDeployed         DownstreamVersionStatus = "deployed"
 	VersionFailed           DownstreamVersionStatus = "failed"
+
+	// gitops versions that are delivered as pull requests
+	VersionPullRequestOpened DownstreamVersionStatus = "opened"
+	VersionPullRequestMerged DownstreamVersionStatus = "merged"
+	VersionPullRequestClosed DownstreamVersionStatus = "closed"
 )
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	audittypes "github.com/replicatedhq/kots/pkg/audit/types"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	kotstypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	license "github.com/replicatedhq/kots/pkg/kotsadmlicense"
@@ -302,6 +304,7 @@ func addDefaultTroubleshoot(supportBundle *troubleshootv1beta2.SupportBundle, ap
 		},
 	})
 
+	supportBundle.Spec.Collectors = append(supportBundle.Spec.Collectors, makeAuditCollectors()...)
 	supportBundle.Spec.Collectors = append(supportBundle.Spec.Collectors, makeDbCollectors()...)
 	supportBundle.Spec.Collectors = append(supportBundle.Spec.Collectors, makeKotsadmCollectors()...)
 	supportBundle.Spec.Collectors = append(supportBundle.Spec.Collectors, makeGoRoutineCollectors()...)
@@ -370,6 +373,33 @@ func makeDbCollectors() []*troubleshootv1beta2.Collect {
 	return dbCollectors
 }
 
+// makeAuditCollectors includes the most recent audit events in the bundle
+func makeAuditCollectors() []*troubleshootv1beta2.Collect {
+	events, _, err := store.GetStore().ListAuditEvents(audittypes.ListEventsOptions{Limit: 1000})
+	if err != nil {
+		logger.Errorf("Failed to list audit events: %v", err)
+		return nil
+	}
+
+	b, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		logger.Errorf("Failed to marshal audit events: %v", err)
+		return nil
+	}
+
+	return []*troubleshootv1beta2.Collect{
+		{
+			Data: &troubleshootv1beta2.Data{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{
+					CollectorName: "audit.json",
+				},
+				Name: "kots/admin-console",
+				Data: string(b),
+			},
+		},
+	}
+}
+
 func makeKotsadmCollectors() []*troubleshootv1beta2.Collect {
 	names := []string{
 		"kotsadm-postgres",
@@ -32,6 +32,8 @@ type BuilderOptions struct {
 	VersionInfo     *VersionInfo
 	IdentityConfig  *kotsv1beta1.IdentityConfig
 	Namespace       string
+	// LookupNamespaces are the namespaces other than Namespace that templates can read secrets and configmaps from
+	LookupNamespaces []string
 }
 
 // NewBuilder creates a builder with all available contexts.
@@ -51,7 +53,10 @@ func NewBuilder(opts BuilderOptions) (Builder, map[string]ItemValue, error) {
 		}
 	}
 
-	configCtx, err := b.newConfigContext(opts.ConfigGroups, opts.ExistingValues, opts.LocalRegistry, opts.Cipher, opts.License, opts.VersionInfo, dockerHubRegistry)
+	// cluster lookups are shared by config items and manifests so they are only made once per render
+	clusterCtx := newClusterContext(opts.Namespace, opts.LookupNamespaces)
+
+	configCtx, err := b.newConfigContext(opts.ConfigGroups, opts.ExistingValues, opts.LocalRegistry, opts.Cipher, opts.License, opts.VersionInfo, dockerHubRegistry, clusterCtx)
 	if err != nil {
 		return Builder{}, nil, errors.Wrap(err, "create config context")
 	}
@@ -62,6 +67,7 @@ func NewBuilder(opts BuilderOptions) (Builder, map[string]ItemValue, error) {
 		newKurlContext("base", "default"), // can be hardcoded because kurl always deploys to the default namespace
 		newVersionCtx(opts.VersionInfo),
 		newIdentityCtx(opts.IdentityConfig, opts.ApplicationInfo, opts.Cipher),
+		clusterCtx,
 		configCtx,
 	}
 	return b, configCtx.ItemValues, nil
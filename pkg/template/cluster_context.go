@@ -0,0 +1,243 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clusterCtx provides read-only lookups of cluster data. Results are cached for the
+// lifetime of the context, which is created once per render.
+type clusterCtx struct {
+	getClientset func() (kubernetes.Interface, error)
+
+	// secrets and configmaps can only be read from these namespaces
+	lookupNamespaces map[string]bool
+
+	mu    sync.Mutex
+	cache map[string]interface{}
+}
+
+// newClusterContext creates a context that can read secrets and configmaps from the namespace
+// the app is deployed to and from additionalNamespaces.
+func newClusterContext(namespace string, additionalNamespaces []string) *clusterCtx {
+	if namespace == "" {
+		namespace = util.PodNamespace
+	}
+
+	lookupNamespaces := map[string]bool{namespace: true}
+	for _, ns := range additionalNamespaces {
+		lookupNamespaces[ns] = true
+	}
+
+	return &clusterCtx{
+		getClientset: func() (kubernetes.Interface, error) {
+			return k8sutil.GetClientset()
+		},
+		lookupNamespaces: lookupNamespaces,
+		cache:            map[string]interface{}{},
+	}
+}
+
+// FuncMap represents the available functions in the clusterCtx.
+func (ctx *clusterCtx) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"StorageClasses":    ctx.storageClasses,
+		"HasCRD":            ctx.hasCRD,
+		"KubernetesVersion": ctx.kubernetesVersion,
+		"SecretKey":         ctx.secretKey,
+		"ConfigMapKey":      ctx.configMapKey,
+	}
+}
+
+// cached returns the cached result for key, calling lookup to populate it on first use.
+func (ctx *clusterCtx) cached(key string, lookup func(clientset kubernetes.Interface) interface{}) interface{} {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if result, ok := ctx.cache[key]; ok {
+		return result
+	}
+
+	var result interface{}
+	clientset, err := ctx.getClientset()
+	if err == nil {
+		result = lookup(clientset)
+	}
+	ctx.cache[key] = result
+
+	return result
+}
+
+// canAccess checks that the current service account is allowed to perform verb on resource.
+func canAccess(clientset kubernetes.Interface, verb string, group string, resource string, namespace string) bool {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	}
+
+	result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false
+	}
+
+	return result.Status.Allowed
+}
+
+func (ctx *clusterCtx) storageClasses() []string {
+	result := ctx.cached("storageclasses", func(clientset kubernetes.Interface) interface{} {
+		if !canAccess(clientset, "list", "storage.k8s.io", "storageclasses", "") {
+			return nil
+		}
+
+		storageClasses, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil
+		}
+
+		names := []string{}
+		for _, storageClass := range storageClasses.Items {
+			names = append(names, storageClass.Name)
+		}
+		sort.Strings(names)
+		return names
+	})
+
+	names, _ := result.([]string)
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+// hasCRD returns true if the cluster serves the kind in group, specified as "group/kind".
+// Discovery is used rather than reading CustomResourceDefinitions, since it is available to all authenticated users.
+func (ctx *clusterCtx) hasCRD(groupKind string) bool {
+	parts := strings.SplitN(groupKind, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	group, kind := parts[0], parts[1]
+
+	result := ctx.cached(fmt.Sprintf("crd:%s", groupKind), func(clientset kubernetes.Interface) interface{} {
+		groups, err := clientset.Discovery().ServerGroups()
+		if err != nil {
+			return false
+		}
+
+		for _, apiGroup := range groups.Groups {
+			if apiGroup.Name != group {
+				continue
+			}
+			for _, version := range apiGroup.Versions {
+				resources, err := clientset.Discovery().ServerResourcesForGroupVersion(version.GroupVersion)
+				if err != nil {
+					continue
+				}
+				for _, resource := range resources.APIResources {
+					if strings.EqualFold(resource.Kind, kind) || resource.Name == kind {
+						return true
+					}
+				}
+			}
+		}
+
+		return false
+	})
+
+	found, _ := result.(bool)
+	return found
+}
+
+func (ctx *clusterCtx) kubernetesVersion() string {
+	result := ctx.cached("version", func(clientset kubernetes.Interface) interface{} {
+		version, err := clientset.Discovery().ServerVersion()
+		if err != nil {
+			return ""
+		}
+		return version.GitVersion
+	})
+
+	version, _ := result.(string)
+	return version
+}
+
+// canLookup returns false for objects outside of the lookup namespaces and for objects that belong to kotsadm.
+// kotsadm keeps its own credentials (encryption keys, sessions, the auth slug, database and registry passwords)
+// in objects named "kotsadm..." in the namespace apps are usually deployed to, so these are never readable.
+func (ctx *clusterCtx) canLookup(namespace string, name string) bool {
+	if !ctx.lookupNamespaces[namespace] {
+		return false
+	}
+	return !strings.HasPrefix(name, "kotsadm")
+}
+
+func isKotsadmObject(objectMeta metav1.ObjectMeta) bool {
+	return objectMeta.Labels[kotsadmtypes.KotsadmKey] == kotsadmtypes.KotsadmLabelValue
+}
+
+func (ctx *clusterCtx) secretKey(namespace string, name string, key string) string {
+	if !ctx.canLookup(namespace, name) {
+		return ""
+	}
+
+	result := ctx.cached(fmt.Sprintf("secret:%s/%s", namespace, name), func(clientset kubernetes.Interface) interface{} {
+		if !canAccess(clientset, "get", "", "secrets", namespace) {
+			return nil
+		}
+
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil || isKotsadmObject(secret.ObjectMeta) {
+			return nil
+		}
+		return secret.Data
+	})
+
+	data, _ := result.(map[string][]byte)
+	return string(data[key])
+}
+
+func (ctx *clusterCtx) configMapKey(namespace string, name string, key string) string {
+	if !ctx.canLookup(namespace, name) {
+		return ""
+	}
+
+	result := ctx.cached(fmt.Sprintf("configmap:%s/%s", namespace, name), func(clientset kubernetes.Interface) interface{} {
+		if !canAccess(clientset, "get", "", "configmaps", namespace) {
+			return nil
+		}
+
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil || isKotsadmObject(configMap.ObjectMeta) {
+			return nil
+		}
+
+		data := map[string]string{}
+		for k, v := range configMap.BinaryData {
+			data[k] = string(v)
+		}
+		for k, v := range configMap.Data {
+			data[k] = v
+		}
+		return data
+	})
+
+	data, _ := result.(map[string]string)
+	return data[key]
+}
@@ -0,0 +1,144 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestClusterContext() (*clusterCtx, *fake.Clientset) {
+	clientset := fake.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "kube-system"},
+			Data:       map[string][]byte{"password": []byte("forbidden")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "monitoring"},
+			Data:       map[string][]byte{"password": []byte("monitoring")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "other"},
+			Data:       map[string][]byte{"password": []byte("other")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-encryption", Namespace: "default"},
+			Data:       map[string][]byte{"encryptionKey": []byte("secret")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-creds", Namespace: "default", Labels: map[string]string{"kots.io/kotsadm": "true"}},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data:       map[string]string{"region": "us-east-1"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-tasks", Namespace: "default"},
+			Data:       map[string]string{"image-rewrite": "{}"},
+		},
+	)
+
+	// secrets in kube-system are not readable
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Resource != "secrets" || attributes.Namespace != "kube-system"
+		return true, review, nil
+	})
+
+	fakeDiscovery := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	fakeDiscovery.FakedServerVersion = &version.Info{GitVersion: "v1.21.3"}
+	fakeDiscovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "certificates", Kind: "Certificate"},
+			},
+		},
+	}
+
+	ctx := newClusterContext("default", []string{"monitoring", "kube-system"})
+	ctx.getClientset = func() (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+
+	return ctx, clientset
+}
+
+func TestClusterCtx(t *testing.T) {
+	req := require.New(t)
+	ctx, _ := newTestClusterContext()
+
+	req.Equal([]string{"fast", "standard"}, ctx.storageClasses())
+
+	req.True(ctx.hasCRD("cert-manager.io/Certificate"))
+	req.True(ctx.hasCRD("cert-manager.io/certificates"))
+	req.False(ctx.hasCRD("cert-manager.io/Issuer"))
+	req.False(ctx.hasCRD("Certificate"))
+
+	req.Equal("v1.21.3", ctx.kubernetesVersion())
+
+	req.Equal("hunter2", ctx.secretKey("default", "credentials", "password"))
+	req.Equal("", ctx.secretKey("default", "credentials", "missing"))
+	req.Equal("", ctx.secretKey("kube-system", "credentials", "password"))
+
+	req.Equal("us-east-1", ctx.configMapKey("default", "settings", "region"))
+	req.Equal("", ctx.configMapKey("default", "missing", "region"))
+}
+
+func TestClusterCtxRestrictsLookups(t *testing.T) {
+	req := require.New(t)
+	ctx, clientset := newTestClusterContext()
+
+	// namespaces in the allowlist can be read
+	req.Equal("monitoring", ctx.secretKey("monitoring", "credentials", "password"))
+
+	// other namespaces cannot
+	req.Equal("", ctx.secretKey("other", "credentials", "password"))
+
+	// kotsadm's own secrets and configmaps cannot be read, even from the app's namespace
+	req.Equal("", ctx.secretKey("default", "kotsadm-encryption", "encryptionKey"))
+	req.Equal("", ctx.secretKey("default", "registry-creds", "password"))
+	req.Equal("", ctx.configMapKey("default", "kotsadm-tasks", "image-rewrite"))
+
+	// objects outside of the allowlist and objects named like kotsadm's are not even requested
+	for _, action := range clientset.Actions() {
+		getAction, ok := action.(k8stesting.GetAction)
+		if !ok {
+			continue
+		}
+		req.NotEqual("other", getAction.GetNamespace())
+		req.NotContains(getAction.GetName(), "kotsadm")
+	}
+}
+
+func TestClusterCtxCachesLookups(t *testing.T) {
+	req := require.New(t)
+	ctx, clientset := newTestClusterContext()
+
+	req.Equal("hunter2", ctx.secretKey("default", "credentials", "password"))
+	req.Len(ctx.storageClasses(), 2)
+
+	actionCount := len(clientset.Actions())
+
+	req.Equal("hunter2", ctx.secretKey("default", "credentials", "password"))
+	req.Len(ctx.storageClasses(), 2)
+
+	req.Equal(actionCount, len(clientset.Actions()))
+}
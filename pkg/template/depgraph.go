@@ -65,6 +65,14 @@ func (d *depGraph) funcMap(parent string) template.FuncMap {
 		"TLSCertFromCA":         addCertFromCAFunc,
 		"TLSKey":                addKeyFunc,
 		"TLSKeyFromCA":          addKeyFromCAFunc,
+
+		// cluster lookups do not depend on other config items, but they must be defined for the
+		// template to parse, otherwise any config functions used alongside them are missed
+		"StorageClasses":    func() []string { return []string{} },
+		"HasCRD":            func(string) bool { return false },
+		"KubernetesVersion": func() string { return "" },
+		"SecretKey":         func(string, string, string) string { return "" },
+		"ConfigMapKey":      func(string, string, string) string { return "" },
 	}
 }
 
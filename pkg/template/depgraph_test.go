@@ -287,6 +287,33 @@ func TestDepGraph(t *testing.T) {
 	}
 }
 
+func TestDepGraphClusterLookups(t *testing.T) {
+	req := require.New(t)
+
+	groups := []kotsv1beta1.ConfigGroup{
+		{
+			Items: []kotsv1beta1.ConfigItem{
+				{Type: "text", Name: "alpha"},
+				{
+					Type: "text",
+					Name: "bravo",
+					Default: multitype.FromString(`{{repl range StorageClasses }}{{repl end }}` +
+						`{{repl if HasCRD "cert-manager.io/Certificate" }}{{repl KubernetesVersion }}{{repl end }}` +
+						`{{repl SecretKey (ConfigOption "alpha") "credentials" "password" }}` +
+						`{{repl ConfigMapKey "default" "settings" (ConfigOption "charlie") }}`),
+				},
+				{Type: "text", Name: "charlie"},
+			},
+		},
+	}
+
+	graph := depGraph{}
+	err := graph.ParseConfigGroup(groups)
+	req.NoError(err)
+
+	req.Equal(map[string]struct{}{"alpha": {}, "charlie": {}}, graph.Dependencies["bravo"])
+}
+
 // this makes sure that we test with each of the configOption types, in both Value and Default
 func buildTestConfigGroups(dependencies, certs, keys, cas map[string][]string, caFromCerts, caFromKeys map[string][][2]string, prefix string, suffix string, rotate bool) []kotsv1beta1.ConfigGroup {
 	group := kotsv1beta1.ConfigGroup{}
@@ -0,0 +1,239 @@
+package updatechecker
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/cursor"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+	"github.com/replicatedhq/kots/pkg/version"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	cron "github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// autoDeploySchedule is how often pending versions are evaluated against the auto deploy policies.
+// This is independent of the update checker schedule so that versions downloaded outside of a
+// maintenance window are deployed once the window opens.
+const autoDeploySchedule = "@every 1m"
+
+var autoDeployJob *cron.Cron
+
+func startAutoDeploy() error {
+	autoDeployJob = cron.New(cron.WithChain(
+		cron.Recover(cron.DefaultLogger),
+		cron.SkipIfStillRunning(cron.DefaultLogger),
+	))
+
+	if _, err := autoDeployJob.AddFunc(autoDeploySchedule, autoDeployApps); err != nil {
+		return errors.Wrap(err, "failed to add func")
+	}
+
+	autoDeployJob.Start()
+
+	return nil
+}
+
+func autoDeployApps() {
+	appsList, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list installed apps"))
+		return
+	}
+
+	for _, a := range appsList {
+		if err := autoDeployApp(a, time.Now()); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to auto deploy app %s", a.Slug))
+		}
+	}
+}
+
+func autoDeployApp(a *apptypes.App, now time.Time) error {
+	policy := a.AutoDeployPolicy
+	if policy == nil || policy.Mode == "" || policy.Mode == apptypes.AutoDeployNever {
+		return nil
+	}
+
+	// gitops apps are deployed by committing to the repo
+	if a.IsGitOps {
+		return nil
+	}
+
+	inWindow, err := IsInMaintenanceWindow(policy.MaintenanceWindows, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to check maintenance windows")
+	}
+	if !inWindow {
+		return nil
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams for app")
+	}
+
+	// a sequence that was deployed for one downstream is not deployed again for the next one
+	deployedSequences := map[int64]bool{}
+	for _, d := range downstreams {
+		if err := autoDeployAppToDownstream(a, d.ClusterID, deployedSequences); err != nil {
+			return errors.Wrapf(err, "failed to auto deploy to downstream %s", d.Name)
+		}
+	}
+
+	return nil
+}
+
+func autoDeployAppToDownstream(a *apptypes.App, clusterID string, deployedSequences map[int64]bool) error {
+	currentVersion, err := store.GetStore().GetCurrentVersion(a.ID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current version")
+	}
+	if currentVersion == nil {
+		// the first version is deployed by the install flow
+		return nil
+	}
+	if currentVersion.Status == storetypes.VersionDeploying {
+		return nil
+	}
+
+	pendingVersions, err := store.GetStore().GetPendingVersions(a.ID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending versions")
+	}
+
+	candidate := getAutoDeployCandidate(a.AutoDeployPolicy.Mode, currentVersion, pendingVersions)
+	if candidate == nil || deployedSequences[candidate.Sequence] {
+		return nil
+	}
+
+	logger.Info("automatically deploying version",
+		zap.String("slug", a.Slug),
+		zap.String("clusterId", clusterID),
+		zap.Int64("sequence", candidate.Sequence),
+		zap.String("versionLabel", candidate.VersionLabel))
+
+	if err := version.DeployVersion(a.ID, candidate.Sequence); err != nil {
+		return errors.Wrap(err, "failed to deploy version")
+	}
+	deployedSequences[candidate.Sequence] = true
+
+	return nil
+}
+
+// getAutoDeployCandidate returns the newest pending version that passed preflights and is allowed by the mode.
+// pendingVersions are expected to be sorted newest first.
+func getAutoDeployCandidate(mode apptypes.AutoDeployMode, currentVersion *downstreamtypes.DownstreamVersion, pendingVersions []downstreamtypes.DownstreamVersion) *downstreamtypes.DownstreamVersion {
+	for i := range pendingVersions {
+		v := &pendingVersions[i]
+
+		if v.Status != storetypes.VersionPending {
+			continue
+		}
+
+		if !isPreflightPassed(v) {
+			continue
+		}
+
+		bump := cursor.GetVersionBump(currentVersion.VersionLabel, v.VersionLabel)
+		switch mode {
+		case apptypes.AutoDeployPatch:
+			if bump == cursor.VersionBumpPatch {
+				return v
+			}
+		case apptypes.AutoDeployAny:
+			if bump == cursor.VersionBumpPatch || bump == cursor.VersionBumpMinor || bump == cursor.VersionBumpMajor {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// isPreflightPassed returns false if preflights were skipped or have any failures.
+// Versions without preflights are considered passed.
+func isPreflightPassed(v *downstreamtypes.DownstreamVersion) bool {
+	if v.PreflightSkipped {
+		return false
+	}
+
+	if v.PreflightResult == "" {
+		return true
+	}
+
+	preflightResults := troubleshootpreflight.UploadPreflightResults{}
+	if err := json.Unmarshal([]byte(v.PreflightResult), &preflightResults); err != nil {
+		return false
+	}
+
+	if len(preflightResults.Errors) > 0 {
+		return false
+	}
+	for _, result := range preflightResults.Results {
+		if result.IsFail {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsInMaintenanceWindow returns true if now falls inside any of the windows, or if there are no windows
+func IsInMaintenanceWindow(windows []apptypes.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+
+	for _, window := range windows {
+		schedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse schedule %q", window.Schedule)
+		}
+
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse duration %q", window.Duration)
+		}
+
+		// the window is open if it started within the last duration
+		if !schedule.Next(now.Add(-duration)).After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ValidateAutoDeployPolicy returns an error if the mode is unknown or any maintenance window cannot be parsed
+func ValidateAutoDeployPolicy(policy *apptypes.AutoDeployPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	switch policy.Mode {
+	case apptypes.AutoDeployNever, apptypes.AutoDeployPatch, apptypes.AutoDeployAny:
+	default:
+		return errors.Errorf("unknown mode %q", policy.Mode)
+	}
+
+	for _, window := range policy.MaintenanceWindows {
+		if _, err := cron.ParseStandard(window.Schedule); err != nil {
+			return errors.Wrapf(err, "invalid maintenance window schedule %q", window.Schedule)
+		}
+
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return errors.Wrapf(err, "invalid maintenance window duration %q", window.Duration)
+		}
+		if duration <= 0 {
+			return errors.Errorf("maintenance window duration %q must be positive", window.Duration)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package updatechecker
+
+import (
+	"testing"
+	"time"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	storetypes "github.com/replicatedhq/kots/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsInMaintenanceWindow(t *testing.T) {
+	nightly := []apptypes.MaintenanceWindow{
+		{Schedule: "0 2 * * *", Duration: "2h"},
+	}
+
+	tests := []struct {
+		name    string
+		windows []apptypes.MaintenanceWindow
+		now     time.Time
+		want    bool
+	}{
+		{
+			name:    "no windows",
+			windows: nil,
+			now:     time.Date(2021, 3, 1, 12, 0, 0, 0, time.Local),
+			want:    true,
+		},
+		{
+			name:    "window start",
+			windows: nightly,
+			now:     time.Date(2021, 3, 1, 2, 0, 0, 0, time.Local),
+			want:    true,
+		},
+		{
+			name:    "inside window",
+			windows: nightly,
+			now:     time.Date(2021, 3, 1, 3, 30, 0, 0, time.Local),
+			want:    true,
+		},
+		{
+			name:    "window end",
+			windows: nightly,
+			now:     time.Date(2021, 3, 1, 4, 0, 0, 0, time.Local),
+			want:    false,
+		},
+		{
+			name:    "before window",
+			windows: nightly,
+			now:     time.Date(2021, 3, 1, 1, 59, 0, 0, time.Local),
+			want:    false,
+		},
+		{
+			name: "second window",
+			windows: append([]apptypes.MaintenanceWindow{
+				{Schedule: "0 22 * * 6", Duration: "30m"},
+			}, nightly...),
+			now:  time.Date(2021, 3, 1, 2, 15, 0, 0, time.Local),
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := IsInMaintenanceWindow(test.windows, test.now)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_getAutoDeployCandidate(t *testing.T) {
+	current := &downstreamtypes.DownstreamVersion{VersionLabel: "1.2.3", Sequence: 1}
+
+	failedPreflight := `{"results":[{"isFail":true,"title":"check"}]}`
+	passedPreflight := `{"results":[{"isPass":true,"title":"check"}]}`
+
+	tests := []struct {
+		name    string
+		mode    apptypes.AutoDeployMode
+		pending []downstreamtypes.DownstreamVersion
+		want    int64
+	}{
+		{
+			name: "patch mode deploys patch",
+			mode: apptypes.AutoDeployPatch,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPending, PreflightResult: passedPreflight},
+			},
+			want: 2,
+		},
+		{
+			name: "patch mode skips newer minor",
+			mode: apptypes.AutoDeployPatch,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "1.3.0", Sequence: 3, Status: storetypes.VersionPending},
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPending},
+			},
+			want: 2,
+		},
+		{
+			name: "any mode deploys newest",
+			mode: apptypes.AutoDeployAny,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "2.0.0", Sequence: 3, Status: storetypes.VersionPending},
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPending},
+			},
+			want: 3,
+		},
+		{
+			name: "failed preflights",
+			mode: apptypes.AutoDeployAny,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPending, PreflightResult: failedPreflight},
+			},
+			want: -1,
+		},
+		{
+			name: "skipped preflights",
+			mode: apptypes.AutoDeployAny,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPending, PreflightSkipped: true},
+			},
+			want: -1,
+		},
+		{
+			name: "preflights running",
+			mode: apptypes.AutoDeployAny,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "1.2.4", Sequence: 2, Status: storetypes.VersionPendingPreflight},
+			},
+			want: -1,
+		},
+		{
+			name: "not semver",
+			mode: apptypes.AutoDeployAny,
+			pending: []downstreamtypes.DownstreamVersion{
+				{VersionLabel: "nightly", Sequence: 2, Status: storetypes.VersionPending},
+			},
+			want: -1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getAutoDeployCandidate(test.mode, current, test.pending)
+			if test.want == -1 {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, test.want, got.Sequence)
+		})
+	}
+}
@@ -8,11 +8,12 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/replicatedhq/kots/pkg/app"
 	license "github.com/replicatedhq/kots/pkg/kotsadmlicense"
 	upstream "github.com/replicatedhq/kots/pkg/kotsadmupstream"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/notifications"
+	notificationtypes "github.com/replicatedhq/kots/pkg/notifications/types"
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/store"
@@ -32,6 +33,10 @@ var mtx sync.Mutex
 func Start() error {
 	logger.Debug("starting update checker")
 
+	if err := startAutoDeploy(); err != nil {
+		return errors.Wrap(err, "failed to start auto deploy")
+	}
+
 	appsList, err := store.GetStore().ListInstalledApps()
 	if err != nil {
 		return errors.Wrap(err, "failed to list installed apps")
@@ -164,16 +169,21 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		return 0, errors.Wrap(err, "failed to get app")
 	}
 
-	// sync license, this method is only called when online
-	_, _, err = license.Sync(a, "", false)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to sync license")
-	}
+	isGitUpstream := upstream.IsGitUpstream(a.UpstreamURI)
+	isHTTPUpstream := upstream.IsHTTPUpstream(a.UpstreamURI)
 
-	// reload app because license sync could have created a new release
-	a, err = store.GetStore().GetApp(appID)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to get app")
+	if !isGitUpstream && !isHTTPUpstream {
+		// sync license, this method is only called when online
+		_, _, err = license.Sync(a, "", false)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to sync license")
+		}
+
+		// reload app because license sync could have created a new release
+		a, err = store.GetStore().GetApp(appID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get app")
+		}
 	}
 
 	archiveDir, err := ioutil.TempDir("", "kotsadm")
@@ -199,13 +209,7 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		return 0, errors.Wrap(err, "failed to load kotskinds from path")
 	}
 
-	latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to get latest license")
-	}
-
 	getUpdatesOptions := kotspull.GetUpdatesOptions{
-		License:             latestLicense,
 		CurrentCursor:       kotsKinds.Installation.Spec.UpdateCursor,
 		CurrentChannelID:    kotsKinds.Installation.Spec.ChannelID,
 		CurrentChannelName:  kotsKinds.Installation.Spec.ChannelName,
@@ -214,15 +218,36 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		ReportingInfo:       reporting.GetReportingInfo(a.ID),
 	}
 
+	upstreamURI := a.UpstreamURI
+	if isGitUpstream {
+		gitAuth, err := upstream.GetGitAuth(a.Slug)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get git auth")
+		}
+		getUpdatesOptions.GitAuth = gitAuth
+	} else if isHTTPUpstream {
+		releasePublicKey, err := upstream.GetReleasePublicKey(a.Slug)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get release public key")
+		}
+		getUpdatesOptions.ReleasePublicKey = releasePublicKey
+	} else {
+		latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get latest license")
+		}
+		getUpdatesOptions.License = latestLicense
+		upstreamURI = fmt.Sprintf("replicated://%s", kotsKinds.License.Spec.AppSlug)
+	}
+
 	// get updates
-	updates, err := kotspull.GetUpdates(fmt.Sprintf("replicated://%s", kotsKinds.License.Spec.AppSlug), getUpdatesOptions)
+	updates, err := kotspull.GetUpdates(upstreamURI, getUpdatesOptions)
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to get updates")
 	}
 
 	// update last updated at time
-	t := app.LastUpdateAtTime(a.ID)
-	if t != nil {
+	if err := store.GetStore().SetLastUpdateCheckAt(a.ID, time.Now()); err != nil {
 		return 0, errors.Wrap(err, "failed to update last updated at time")
 	}
 
@@ -299,6 +324,12 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 				continue
 			}
 
+			notifications.Emit(notificationtypes.EventUpdateDownloaded, a.ID, map[string]interface{}{
+				"sequence":     sequence,
+				"cursor":       update.Cursor,
+				"versionLabel": update.VersionLabel,
+			})
+
 			if !deploy || index != len(updates)-1 {
 				continue
 			}
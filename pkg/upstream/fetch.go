@@ -60,10 +60,10 @@ func downloadUpstream(upstreamURI string, fetchOptions *types.FetchOptions) (*ty
 		)
 	}
 	if u.Scheme == "git" {
-		return downloadGit(upstreamURI)
+		return downloadGit(u, fetchOptions, cipher)
 	}
 	if u.Scheme == "http" || u.Scheme == "https" {
-		return downloadHttp(upstreamURI)
+		return downloadHttp(u, fetchOptions, cipher)
 	}
 
 	return nil, errors.Errorf("unknown protocol scheme %q", u.Scheme)
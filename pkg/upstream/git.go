@@ -1,10 +1,388 @@
 package upstream
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/gitops"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
 )
 
-func downloadGit(gitURI string) (*types.Upstream, error) {
-	return nil, errors.New("downloadGit not implemented")
+// gitUpstream is a parsed upstream uri of the form git://<host>/<repo>[//<path>][?ref=<ref>][&protocol=<protocol>],
+// where ref is a branch, tag or commit and protocol is one of https, http, ssh or git.
+// When ref is not set, semver tags are used as versions. When ref is a branch, every new commit is a version.
+type gitUpstream struct {
+	Host     string
+	Repo     string
+	Path     string
+	Ref      string
+	Protocol string
+}
+
+type gitCheckout struct {
+	CommitSHA   string
+	CommittedAt time.Time
+	Tag         string
+	TagMessage  string
+}
+
+func parseGitURL(u *url.URL, auth types.GitAuth) (*gitUpstream, error) {
+	if u.Host == "" {
+		return nil, errors.New("git uri must include a host")
+	}
+
+	repo := strings.TrimPrefix(u.Path, "/")
+	subPath := ""
+	if idx := strings.Index(repo, "//"); idx != -1 {
+		cleanSubPath, err := cleanGitSubPath(repo[idx+2:])
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid path in git uri")
+		}
+		subPath = cleanSubPath
+		repo = repo[:idx]
+	}
+	repo = strings.TrimSuffix(repo, "/")
+	if repo == "" {
+		return nil, errors.New("git uri must include a repository")
+	}
+
+	protocol := u.Query().Get("protocol")
+	if protocol == "" {
+		protocol = "https"
+		if auth.SSHPrivateKey != "" {
+			protocol = "ssh"
+		}
+	}
+	switch protocol {
+	case "https", "http", "ssh", "git":
+	default:
+		return nil, errors.Errorf("unsupported git protocol %q", protocol)
+	}
+
+	return &gitUpstream{
+		Host:     u.Host,
+		Repo:     repo,
+		Path:     subPath,
+		Ref:      u.Query().Get("ref"),
+		Protocol: protocol,
+	}, nil
+}
+
+// cleanGitSubPath cleans the path of the application in the repository. Paths that are absolute
+// or that would leave the repository are refused.
+func cleanGitSubPath(subPath string) (string, error) {
+	if strings.HasPrefix(subPath, "/") {
+		return "", errors.Errorf("path %q must be relative to the repository", subPath)
+	}
+
+	cleaned := path.Clean(subPath)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", errors.Errorf("path %q is outside of the repository", subPath)
+	}
+	if cleaned == "." {
+		return "", nil
+	}
+
+	return cleaned, nil
+}
+
+// CloneURL returns the url that the repository is cloned from.
+func (g *gitUpstream) CloneURL() string {
+	if g.Protocol == "ssh" {
+		return fmt.Sprintf("ssh://git@%s/%s", g.Host, g.Repo)
+	}
+	return fmt.Sprintf("%s://%s/%s", g.Protocol, g.Host, g.Repo)
+}
+
+func getGitAuth(gitAuth types.GitAuth) (transport.AuthMethod, error) {
+	auth, err := gitops.GetAuth(gitAuth.SSHPrivateKey, gitAuth.Username, gitAuth.Token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get git auth")
+	}
+	return auth, nil
+}
+
+func getUpdatesGit(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	g, err := parseGitURL(u, fetchOptions.GitAuth)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse git uri")
+	}
+
+	auth, err := getGitAuth(fetchOptions.GitAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := listGitRefs(g.CloneURL(), auth)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list git refs")
+	}
+
+	if g.Ref != "" {
+		// a branch is followed commit by commit, a tag or commit is pinned and never has updates
+		hash, ok := refs[plumbing.NewBranchReferenceName(g.Ref)]
+		if !ok || hash.String() == fetchOptions.CurrentCursor {
+			return []Update{}, nil
+		}
+		return []Update{{Cursor: hash.String(), VersionLabel: shortCommitSHA(hash.String())}}, nil
+	}
+
+	return pendingSemverUpdates(gitTagNames(refs), fetchOptions.CurrentCursor), nil
+}
+
+func downloadGit(u *url.URL, fetchOptions *types.FetchOptions, cipher *crypto.AESCipher) (*types.Upstream, error) {
+	g, err := parseGitURL(u, fetchOptions.GitAuth)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse git uri")
+	}
+
+	auth, err := getGitAuth(fetchOptions.GitAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor is the tag or commit to check out. without one, use the ref from the uri
+	// or the latest semver tag, falling back to the default branch.
+	ref := fetchOptions.CurrentCursor
+	if ref == "" {
+		ref = g.Ref
+	}
+	if ref == "" {
+		refs, err := listGitRefs(g.CloneURL(), auth)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list git refs")
+		}
+		if updates := pendingSemverUpdates(gitTagNames(refs), ""); len(updates) > 0 {
+			ref = updates[0].Cursor
+		}
+	}
+
+	cloneDir, err := ioutil.TempDir("", "kots-git")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(cloneDir)
+
+	checkout, err := cloneGitRef(cloneDir, g.CloneURL(), ref, auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check out %s", g.CloneURL())
+	}
+
+	// the repository metadata is not part of the application
+	if err := os.RemoveAll(filepath.Join(cloneDir, ".git")); err != nil {
+		return nil, errors.Wrap(err, "failed to remove git metadata")
+	}
+
+	manifestsDir, err := gitManifestsDir(cloneDir, g.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := checkout.CommitSHA
+	versionLabel := shortCommitSHA(checkout.CommitSHA)
+	if checkout.Tag != "" {
+		cursor = checkout.Tag
+		versionLabel = checkout.Tag
+	}
+
+	upstream, err := downloadReplicated(
+		u,
+		manifestsDir,
+		fetchOptions.RootDir,
+		fetchOptions.UseAppDir,
+		fetchOptions.License,
+		fetchOptions.ConfigValues,
+		fetchOptions.IdentityConfig,
+		ReplicatedCursor{Cursor: cursor},
+		versionLabel,
+		cipher,
+		fetchOptions.AppSlug,
+		fetchOptions.AppSequence,
+		false,
+		nil,
+		fetchOptions.LocalRegistry,
+		fetchOptions.ReportingInfo,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read application from repository")
+	}
+
+	upstream.Type = "git"
+	upstream.CommitSHA = checkout.CommitSHA
+	upstream.ReleasedAt = &checkout.CommittedAt
+	if checkout.TagMessage != "" {
+		upstream.ReleaseNotes = checkout.TagMessage
+	}
+
+	return upstream, nil
+}
+
+// gitManifestsDir returns the directory of the application in the clone.
+// The path and the symlinks in the directory are resolved, and an error is returned if any of them point outside of
+// the clone, because the files of the application are read following symlinks.
+func gitManifestsDir(cloneDir string, subPath string) (string, error) {
+	resolvedCloneDir, err := filepath.EvalSymlinks(cloneDir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve clone dir")
+	}
+
+	manifestsDir, err := filepath.EvalSymlinks(filepath.Join(resolvedCloneDir, filepath.FromSlash(subPath)))
+	if err != nil {
+		return "", errors.Errorf("path %q not found in repository", subPath)
+	}
+
+	if !isPathInDir(resolvedCloneDir, manifestsDir) {
+		return "", errors.Errorf("path %q is outside of the repository", subPath)
+	}
+
+	if fi, err := os.Stat(manifestsDir); err != nil || !fi.IsDir() {
+		return "", errors.Errorf("path %q not found in repository", subPath)
+	}
+
+	err = filepath.Walk(manifestsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		linkPath, err := filepath.Rel(resolvedCloneDir, path)
+		if err != nil {
+			return errors.Wrap(err, "failed to get relative path")
+		}
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return errors.Errorf("symlink %q in repository cannot be resolved", filepath.ToSlash(linkPath))
+		}
+		if !isPathInDir(resolvedCloneDir, target) {
+			return errors.Errorf("symlink %q points outside of the repository", filepath.ToSlash(linkPath))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return manifestsDir, nil
+}
+
+// isPathInDir returns true if path is dir or is in dir. Both must be resolved through symlinks.
+func isPathInDir(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cloneGitRef clones the repository into dir and checks out ref, which can be a tag, a branch or a commit.
+// The default branch is checked out when ref is empty.
+func cloneGitRef(dir string, cloneURL string, ref string, auth transport.AuthMethod) (*gitCheckout, error) {
+	r, err := git.PlainClone(dir, false, &git.CloneOptions{
+		URL:        cloneURL,
+		Auth:       auth,
+		RemoteName: git.DefaultRemoteName,
+		Tags:       git.AllTags,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clone repository")
+	}
+
+	checkout := &gitCheckout{}
+
+	var hash plumbing.Hash
+	if ref == "" {
+		head, err := r.Head()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get head")
+		}
+		hash = head.Hash()
+	} else if tagRef, err := r.Tag(ref); err == nil {
+		checkout.Tag = ref
+		hash = tagRef.Hash()
+		// annotated tags point to a tag object rather than to the commit
+		if tagObject, err := r.TagObject(tagRef.Hash()); err == nil {
+			commit, err := tagObject.Commit()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get commit for tag %s", ref)
+			}
+			hash = commit.Hash
+			checkout.TagMessage = strings.TrimSpace(tagObject.Message)
+		}
+	} else if branchRef, err := r.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, ref), true); err == nil {
+		hash = branchRef.Hash()
+	} else {
+		resolved, err := r.ResolveRevision(plumbing.Revision(ref))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find ref %s", ref)
+		}
+		hash = *resolved
+	}
+
+	worktree, err := r.Worktree()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get worktree")
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return nil, errors.Wrapf(err, "failed to checkout %s", hash)
+	}
+
+	commit, err := r.CommitObject(hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get commit %s", hash)
+	}
+	checkout.CommitSHA = commit.Hash.String()
+	checkout.CommittedAt = commit.Committer.When.UTC()
+
+	return checkout, nil
+}
+
+func listGitRefs(cloneURL string, auth transport.AuthMethod) (map[plumbing.ReferenceName]plumbing.Hash, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{cloneURL},
+	})
+
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list remote")
+	}
+
+	result := map[plumbing.ReferenceName]plumbing.Hash{}
+	for _, ref := range refs {
+		if ref.Type() != plumbing.HashReference {
+			continue
+		}
+		result[ref.Name()] = ref.Hash()
+	}
+
+	return result, nil
+}
+
+func gitTagNames(refs map[plumbing.ReferenceName]plumbing.Hash) []string {
+	tags := []string{}
+	for name := range refs {
+		if name.IsTag() {
+			tags = append(tags, name.Short())
+		}
+	}
+	return tags
+}
+
+func shortCommitSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
 }
@@ -0,0 +1,135 @@
+package upstream
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGitURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		uri              string
+		auth             types.GitAuth
+		expectedPath     string
+		expectedRef      string
+		expectedCloneURL string
+	}{
+		{
+			name:             "repo",
+			uri:              "git://github.com/org/app",
+			expectedCloneURL: "https://github.com/org/app",
+		},
+		{
+			name:             "repo with path and ref",
+			uri:              "git://github.com/org/app.git//manifests/kots?ref=v1.2.0",
+			expectedPath:     "manifests/kots",
+			expectedRef:      "v1.2.0",
+			expectedCloneURL: "https://github.com/org/app.git",
+		},
+		{
+			name:             "path is cleaned",
+			uri:              "git://github.com/org/app//manifests/../kots/./?ref=v1.2.0",
+			expectedPath:     "kots",
+			expectedRef:      "v1.2.0",
+			expectedCloneURL: "https://github.com/org/app",
+		},
+		{
+			name:             "ssh key",
+			uri:              "git://github.com/org/app?ref=main",
+			auth:             types.GitAuth{SSHPrivateKey: "key"},
+			expectedRef:      "main",
+			expectedCloneURL: "ssh://git@github.com/org/app",
+		},
+		{
+			name:             "explicit protocol",
+			uri:              "git://git.internal:8080/org/app?protocol=http",
+			expectedCloneURL: "http://git.internal:8080/org/app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			u, err := url.ParseRequestURI(test.uri)
+			req.NoError(err)
+
+			g, err := parseGitURL(u, test.auth)
+			req.NoError(err)
+			assert.Equal(t, test.expectedPath, g.Path)
+			assert.Equal(t, test.expectedRef, g.Ref)
+			assert.Equal(t, test.expectedCloneURL, g.CloneURL())
+		})
+	}
+}
+
+func Test_parseGitURLRejectsPathsOutsideOfRepository(t *testing.T) {
+	for _, uri := range []string{
+		"git://github.com/org/app//../other",
+		"git://github.com/org/app//manifests/../../other",
+		"git://github.com/org/app///etc",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			u, err := url.ParseRequestURI(uri)
+			require.NoError(t, err)
+
+			_, err = parseGitURL(u, types.GitAuth{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_gitManifestsDir(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "kots-git-test")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cloneDir := filepath.Join(dir, "clone")
+	outsideDir := filepath.Join(dir, "outside")
+	req.NoError(os.MkdirAll(filepath.Join(cloneDir, "manifests"), 0755))
+	req.NoError(os.MkdirAll(outsideDir, 0755))
+	req.NoError(os.Symlink(outsideDir, filepath.Join(cloneDir, "escape")))
+
+	manifestsDir, err := gitManifestsDir(cloneDir, "manifests")
+	req.NoError(err)
+	req.Equal("manifests", filepath.Base(manifestsDir))
+
+	_, err = gitManifestsDir(cloneDir, "escape")
+	req.Error(err)
+
+	_, err = gitManifestsDir(cloneDir, "missing")
+	req.Error(err)
+}
+
+func Test_gitManifestsDirSymlinkedFiles(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "kots-git-test")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cloneDir := filepath.Join(dir, "clone")
+	manifestsDir := filepath.Join(cloneDir, "manifests")
+	req.NoError(os.MkdirAll(manifestsDir, 0755))
+	req.NoError(ioutil.WriteFile(filepath.Join(cloneDir, "shared.yaml"), []byte("kind: ConfigMap"), 0644))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("token"), 0644))
+
+	// a link to a file in the repository is allowed
+	req.NoError(os.Symlink(filepath.Join("..", "shared.yaml"), filepath.Join(manifestsDir, "shared.yaml")))
+	_, err = gitManifestsDir(cloneDir, "manifests")
+	req.NoError(err)
+
+	// a link to a file outside of the repository would copy the file into the release
+	req.NoError(os.Symlink(filepath.Join(dir, "secret"), filepath.Join(manifestsDir, "x.yaml")))
+	_, err = gitManifestsDir(cloneDir, "manifests")
+	req.EqualError(err, `symlink "manifests/x.yaml" points outside of the repository`)
+}
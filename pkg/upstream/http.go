@@ -1,10 +1,357 @@
 package upstream
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	cryptoutil "github.com/replicatedhq/kots/pkg/crypto"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
+	"golang.org/x/crypto/openpgp"
 )
 
-func downloadHttp(httpURI string) (*types.Upstream, error) {
-	return nil, errors.New("downloadHttp not implemented")
+// http upstreams are release tarballs, for example https://artifacts.example.com/app/app-1.2.3.tar.gz.
+// Releases are listed in an index.yaml file next to the tarball, which is used to find updates.
+// Every tarball is published with a sha256 checksum (<tarball>.sha256) or, when a public key is configured,
+// with a signature: a cosign signature (<tarball>.sig) for a PEM public key, or a detached GPG signature
+// (<tarball>.asc) for an armored GPG public key.
+const httpReleaseIndexName = "index.yaml"
+
+var httpArchiveVersionRegex = regexp.MustCompile(`-(v?[0-9]+\.[0-9]+\.[0-9]+[^/]*)\.(tar\.gz|tgz)$`)
+
+type httpReleaseIndex struct {
+	Releases []httpRelease `json:"releases"`
+}
+
+type httpRelease struct {
+	Version      string     `json:"version"`
+	URL          string     `json:"url"`
+	ReleaseNotes string     `json:"releaseNotes,omitempty"`
+	ReleasedAt   *time.Time `json:"releasedAt,omitempty"`
+}
+
+func getUpdatesHttp(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	index, err := fetchHttpReleaseIndex(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch release index")
+	}
+
+	versions := []string{}
+	for _, release := range index.Releases {
+		versions = append(versions, release.Version)
+	}
+
+	return pendingSemverUpdates(versions, fetchOptions.CurrentCursor), nil
+}
+
+func downloadHttp(u *url.URL, fetchOptions *types.FetchOptions, cipher *cryptoutil.AESCipher) (*types.Upstream, error) {
+	release, err := findHttpRelease(u, fetchOptions.CurrentCursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find release")
+	}
+
+	archiveFile, err := ioutil.TempFile("", "kots-release")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(archiveFile.Name())
+
+	if err := downloadHttpFile(release.URL, archiveFile); err != nil {
+		archiveFile.Close()
+		return nil, errors.Wrap(err, "failed to download release")
+	}
+	archiveFile.Close()
+
+	if err := verifyHttpRelease(archiveFile.Name(), release.URL, fetchOptions.ReleasePublicKey); err != nil {
+		return nil, errors.Wrap(err, "failed to verify release")
+	}
+
+	files, err := readTarGz(archiveFile.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read release archive")
+	}
+
+	releaseDir, err := ioutil.TempDir("", "kots-release")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(releaseDir)
+
+	for _, file := range files {
+		filePath := filepath.Join(releaseDir, filepath.FromSlash(path.Clean("/"+file.Path)))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed to create directory for %s", file.Path)
+		}
+		if err := ioutil.WriteFile(filePath, file.Content, 0644); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", file.Path)
+		}
+	}
+
+	upstream, err := downloadReplicated(
+		u,
+		releaseDir,
+		fetchOptions.RootDir,
+		fetchOptions.UseAppDir,
+		fetchOptions.License,
+		fetchOptions.ConfigValues,
+		fetchOptions.IdentityConfig,
+		ReplicatedCursor{Cursor: release.Version},
+		release.Version,
+		cipher,
+		fetchOptions.AppSlug,
+		fetchOptions.AppSequence,
+		false,
+		nil,
+		fetchOptions.LocalRegistry,
+		fetchOptions.ReportingInfo,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read application from release")
+	}
+
+	upstream.Type = "http"
+	upstream.ReleasedAt = release.ReleasedAt
+	if release.ReleaseNotes != "" {
+		upstream.ReleaseNotes = release.ReleaseNotes
+	}
+
+	return upstream, nil
+}
+
+// findHttpRelease returns the release for cursor, or the release at u when cursor is empty.
+// The index is required to find a release by cursor. Without an index, the version of the release at u
+// is read from the tarball name.
+func findHttpRelease(u *url.URL, cursor string) (*httpRelease, error) {
+	index, err := fetchHttpReleaseIndex(u)
+	if err != nil && cursor != "" {
+		return nil, errors.Wrap(err, "failed to fetch release index")
+	}
+
+	if index != nil {
+		for _, release := range index.Releases {
+			if cursor != "" && release.Version == cursor {
+				return &release, nil
+			}
+			if cursor == "" && release.URL == u.String() {
+				return &release, nil
+			}
+		}
+	}
+	if cursor != "" {
+		return nil, errors.Errorf("version %s not found in release index", cursor)
+	}
+
+	matches := httpArchiveVersionRegex.FindStringSubmatch(u.Path)
+	if len(matches) < 2 {
+		return nil, errors.Errorf("failed to find version of %s", u.String())
+	}
+
+	return &httpRelease{
+		Version: matches[1],
+		URL:     u.String(),
+	}, nil
+}
+
+// fetchHttpReleaseIndex fetches the index next to the release at u. Release urls in the index
+// are resolved relative to the index.
+func fetchHttpReleaseIndex(u *url.URL) (*httpReleaseIndex, error) {
+	indexURL := u.ResolveReference(&url.URL{Path: httpReleaseIndexName})
+
+	buf := bytes.NewBuffer(nil)
+	if err := downloadHttpFile(indexURL.String(), buf); err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s", indexURL.String())
+	}
+
+	index := httpReleaseIndex{}
+	if err := yaml.Unmarshal(buf.Bytes(), &index); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal release index")
+	}
+
+	for i, release := range index.Releases {
+		releaseURL, err := url.Parse(release.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse url of version %s", release.Version)
+		}
+		index.Releases[i].URL = indexURL.ResolveReference(releaseURL).String()
+	}
+
+	return &index, nil
+}
+
+func verifyHttpRelease(archivePath string, releaseURL string, publicKey string) error {
+	if publicKey == "" {
+		checksum, err := getHttpSidecar(releaseURL + ".sha256")
+		if err != nil {
+			return errors.Wrap(err, "failed to get checksum")
+		}
+		if checksum == nil {
+			return errors.New("release has no sha256 checksum")
+		}
+		return verifySha256Checksum(archivePath, checksum)
+	}
+
+	if strings.Contains(publicKey, "BEGIN PGP PUBLIC KEY BLOCK") {
+		signature, err := getHttpSidecar(releaseURL + ".asc")
+		if err != nil {
+			return errors.Wrap(err, "failed to get signature")
+		}
+		if signature == nil {
+			return errors.New("release has no gpg signature")
+		}
+		return verifyGPGSignature(archivePath, signature, publicKey)
+	}
+
+	signature, err := getHttpSidecar(releaseURL + ".sig")
+	if err != nil {
+		return errors.Wrap(err, "failed to get signature")
+	}
+	if signature == nil {
+		return errors.New("release has no signature")
+	}
+	return verifyCosignSignature(archivePath, signature, publicKey)
+}
+
+// verifySha256Checksum verifies a checksum in the format written by sha256sum.
+func verifySha256Checksum(archivePath string, checksum []byte) error {
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+		return errors.New("checksum is empty")
+	}
+
+	digest, err := sha256File(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(fields[0], hex.EncodeToString(digest)) {
+		return errors.New("checksum does not match")
+	}
+
+	return nil
+}
+
+// verifyCosignSignature verifies a base64 encoded signature of the archive, as written by cosign sign-blob.
+func verifyCosignSignature(archivePath string, signature []byte, publicKeyPEM string) error {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return errors.New("failed to decode public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse public key")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature")
+	}
+
+	digest, err := sha256File(archivePath)
+	if err != nil {
+		return err
+	}
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, decoded) {
+			return errors.New("signature is invalid")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, decoded); err != nil {
+			return errors.Wrap(err, "signature is invalid")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return nil
+}
+
+// verifyGPGSignature verifies an armored detached signature of the archive.
+func verifyGPGSignature(archivePath string, signature []byte, armoredPublicKey string) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPublicKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to read gpg public key")
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open archive")
+	}
+	defer f.Close()
+
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, f, bytes.NewReader(signature)); err != nil {
+		return errors.Wrap(err, "signature is invalid")
+	}
+
+	return nil
+}
+
+func sha256File(filePath string) ([]byte, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, errors.Wrap(err, "failed to hash archive")
+	}
+
+	return h.Sum(nil), nil
+}
+
+// getHttpSidecar returns the contents of a file published next to a release, or nil if it does not exist.
+func getHttpSidecar(sidecarURL string) ([]byte, error) {
+	resp, err := http.Get(sidecarURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute get request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode >= 300 {
+		return nil, errors.Errorf("unexpected result from get request: %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func downloadHttpFile(fileURL string, w io.Writer) error {
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute get request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected result from get request: %d", resp.StatusCode)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	return nil
 }
@@ -0,0 +1,154 @@
+package upstream
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testReleaseIndex = `releases:
+  - version: 1.2.3
+    url: app-1.2.3.tar.gz
+  - version: 1.3.0
+    url: app-1.3.0.tar.gz
+    releaseNotes: Adds a worker
+  - version: 1.4.0
+    url: https://mirror.example.com/app-1.4.0.tar.gz
+`
+
+func newTestReleaseServer(files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+}
+
+func Test_findHttpRelease(t *testing.T) {
+	server := newTestReleaseServer(map[string]string{
+		"/releases/index.yaml": testReleaseIndex,
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		uri             string
+		cursor          string
+		expectedVersion string
+		expectedURL     string
+		expectedNotes   string
+	}{
+		{
+			name:            "release in index",
+			uri:             server.URL + "/releases/app-1.3.0.tar.gz",
+			expectedVersion: "1.3.0",
+			expectedURL:     server.URL + "/releases/app-1.3.0.tar.gz",
+			expectedNotes:   "Adds a worker",
+		},
+		{
+			name:            "cursor",
+			uri:             server.URL + "/releases/app-1.2.3.tar.gz",
+			cursor:          "1.4.0",
+			expectedVersion: "1.4.0",
+			expectedURL:     "https://mirror.example.com/app-1.4.0.tar.gz",
+		},
+		{
+			name:            "no index",
+			uri:             server.URL + "/other/app-v2.0.1.tgz",
+			expectedVersion: "v2.0.1",
+			expectedURL:     server.URL + "/other/app-v2.0.1.tgz",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			u, err := url.ParseRequestURI(test.uri)
+			req.NoError(err)
+
+			release, err := findHttpRelease(u, test.cursor)
+			req.NoError(err)
+			assert.Equal(t, test.expectedVersion, release.Version)
+			assert.Equal(t, test.expectedURL, release.URL)
+			assert.Equal(t, test.expectedNotes, release.ReleaseNotes)
+		})
+	}
+}
+
+func Test_getUpdatesHttp(t *testing.T) {
+	req := require.New(t)
+
+	server := newTestReleaseServer(map[string]string{
+		"/releases/index.yaml": testReleaseIndex,
+	})
+	defer server.Close()
+
+	u, err := url.ParseRequestURI(server.URL + "/releases/app-1.2.3.tar.gz")
+	req.NoError(err)
+
+	updates, err := getUpdatesHttp(u, &types.FetchOptions{CurrentCursor: "1.2.3"})
+	req.NoError(err)
+	assert.Equal(t, []Update{
+		{Cursor: "1.3.0", VersionLabel: "1.3.0"},
+		{Cursor: "1.4.0", VersionLabel: "1.4.0"},
+	}, updates)
+}
+
+func Test_verifyHttpRelease(t *testing.T) {
+	req := require.New(t)
+
+	archive := []byte("release contents")
+	digest := sha256.Sum256(archive)
+
+	archiveDir, err := ioutil.TempDir("", "kots-release")
+	req.NoError(err)
+	defer os.RemoveAll(archiveDir)
+
+	archivePath := filepath.Join(archiveDir, "app-1.2.3.tar.gz")
+	req.NoError(ioutil.WriteFile(archivePath, archive, 0644))
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	req.NoError(err)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	req.NoError(err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}))
+	signature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	req.NoError(err)
+
+	server := newTestReleaseServer(map[string]string{
+		"/signed/app-1.2.3.tar.gz.sha256":   hex.EncodeToString(digest[:]) + "  app-1.2.3.tar.gz\n",
+		"/signed/app-1.2.3.tar.gz.sig":      base64.StdEncoding.EncodeToString(signature),
+		"/tampered/app-1.2.3.tar.gz.sha256": hex.EncodeToString(make([]byte, sha256.Size)),
+		"/tampered/app-1.2.3.tar.gz.sig":    base64.StdEncoding.EncodeToString([]byte("not a signature")),
+	})
+	defer server.Close()
+
+	req.NoError(verifyHttpRelease(archivePath, server.URL+"/signed/app-1.2.3.tar.gz", ""))
+	req.NoError(verifyHttpRelease(archivePath, server.URL+"/signed/app-1.2.3.tar.gz", publicKey))
+
+	req.Error(verifyHttpRelease(archivePath, server.URL+"/tampered/app-1.2.3.tar.gz", ""))
+	req.Error(verifyHttpRelease(archivePath, server.URL+"/tampered/app-1.2.3.tar.gz", publicKey))
+
+	req.Error(verifyHttpRelease(archivePath, server.URL+"/missing/app-1.2.3.tar.gz", ""))
+	req.Error(verifyHttpRelease(archivePath, server.URL+"/missing/app-1.2.3.tar.gz", publicKey))
+}
@@ -2,7 +2,9 @@ package upstream
 
 import (
 	"net/url"
+	"sort"
 
+	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
 	types "github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/replicatedhq/kots/pkg/util"
@@ -43,13 +45,50 @@ func getUpdatesUpstream(upstreamURI string, fetchOptions *types.FetchOptions) ([
 		return getUpdatesReplicated(u, fetchOptions.LocalPath, currentCursor, fetchOptions.CurrentVersionLabel, fetchOptions.License, fetchOptions.ReportingInfo)
 	}
 	if u.Scheme == "git" {
-		// return getUpdatesGit(upstreamURI)
-		// TODO
+		return getUpdatesGit(u, fetchOptions)
 	}
 	if u.Scheme == "http" || u.Scheme == "https" {
-		// return getUpdatesHttp(upstreamURI)
-		// TODO
+		return getUpdatesHttp(u, fetchOptions)
 	}
 
 	return nil, errors.Errorf("unknown protocol scheme %q", u.Scheme)
 }
+
+type semverVersion struct {
+	Name    string
+	Version *semver.Version
+}
+
+// pendingSemverUpdates returns the versions that are newer than currentCursor, oldest first.
+// Prereleases and versions that are not valid semver are ignored. If currentCursor is not a semver version, only the latest version is returned.
+func pendingSemverUpdates(names []string, currentCursor string) []Update {
+	versions := []semverVersion{}
+	for _, name := range names {
+		v, err := semver.NewVersion(name)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		versions = append(versions, semverVersion{Name: name, Version: v})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version.LessThan(versions[j].Version)
+	})
+
+	updates := []Update{}
+	if len(versions) == 0 {
+		return updates
+	}
+
+	current, err := semver.NewVersion(currentCursor)
+	if err != nil {
+		latest := versions[len(versions)-1]
+		return append(updates, Update{Cursor: latest.Name, VersionLabel: latest.Name})
+	}
+
+	for _, v := range versions {
+		if v.Version.GreaterThan(current) {
+			updates = append(updates, Update{Cursor: v.Name, VersionLabel: v.Name})
+		}
+	}
+	return updates
+}
@@ -0,0 +1,51 @@
+package upstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pendingSemverUpdates(t *testing.T) {
+	versions := []string{"v1.10.0", "v1.2.0", "latest", "v1.9.1", "v2.0.0-beta.1"}
+
+	tests := []struct {
+		name          string
+		currentCursor string
+		expected      []Update
+	}{
+		{
+			name:          "no current version",
+			currentCursor: "",
+			expected: []Update{
+				{Cursor: "v1.10.0", VersionLabel: "v1.10.0"},
+			},
+		},
+		{
+			name:          "older version",
+			currentCursor: "v1.2.0",
+			expected: []Update{
+				{Cursor: "v1.9.1", VersionLabel: "v1.9.1"},
+				{Cursor: "v1.10.0", VersionLabel: "v1.10.0"},
+			},
+		},
+		{
+			name:          "latest version",
+			currentCursor: "v1.10.0",
+			expected:      []Update{},
+		},
+		{
+			name:          "commit cursor",
+			currentCursor: "3f786850e387550fdab836ed7e6dc881de23001b",
+			expected: []Update{
+				{Cursor: "v1.10.0", VersionLabel: "v1.10.0"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, pendingSemverUpdates(versions, test.currentCursor))
+		})
+	}
+}
@@ -31,6 +31,7 @@ type Upstream struct {
 	ReleaseNotes  string
 	ReleasedAt    *time.Time
 	EncryptionKey string
+	CommitSHA     string
 }
 
 type WriteOptions struct {
@@ -72,6 +73,16 @@ type FetchOptions struct {
 	LocalRegistry          LocalRegistry
 	ReportingInfo          *reportingtypes.ReportingInfo
 	IdentityPostgresConfig *kotsv1beta1.IdentityPostgresConfig
+	GitAuth                GitAuth
+	ReleasePublicKey       string
+}
+
+// GitAuth holds the credentials used to clone a git upstream.
+// SSHPrivateKey takes precedence over Token when both are set.
+type GitAuth struct {
+	Username      string
+	Token         string
+	SSHPrivateKey string
 }
 
 type LocalRegistry struct {
@@ -106,9 +106,14 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 	}
 
 	channelID, channelName := "", ""
+	commitSHA := u.CommitSHA
 	if prevInstallation != nil && options.PreserveInstallation {
 		channelID = prevInstallation.Spec.ChannelID
 		channelName = prevInstallation.Spec.ChannelName
+		// re-rendering a git upstream from the archive does not know the commit it came from
+		if commitSHA == "" {
+			commitSHA = prevInstallation.Spec.CommitSHA
+		}
 	} else {
 		channelID = u.ChannelID
 		channelName = u.ChannelName
@@ -129,6 +134,7 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 			VersionLabel:  u.VersionLabel,
 			ReleaseNotes:  u.ReleaseNotes,
 			EncryptionKey: encryptionKey,
+			CommitSHA:     commitSHA,
 		},
 	}
 
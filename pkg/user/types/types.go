@@ -1,5 +1,22 @@
 package types
 
+import "time"
+
+// SharedPasswordUserID is the user id of sessions created with the shared password.
+// It is reserved so that no local user has the same identity.
+const SharedPasswordUserID = "000000"
+
 type User struct {
 	ID string
 }
+
+// LocalUser is an admin console account that logs in with its own password instead of the shared password
+type LocalUser struct {
+	Username           string     `json:"username"`
+	PasswordBcrypt     []byte     `json:"passwordBcrypt,omitempty"`
+	RoleIDs            []string   `json:"roleIds"`
+	MustChangePassword bool       `json:"mustChangePassword"`
+	FailedLoginCount   int        `json:"failedLoginCount"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	LastLoginAt        *time.Time `json:"lastLoginAt,omitempty"`
+}
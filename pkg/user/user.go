@@ -1,19 +1,30 @@
 package user
 
 import (
+	"regexp"
 	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/logger"
+	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
 	"github.com/replicatedhq/kots/pkg/store"
 	usertypes "github.com/replicatedhq/kots/pkg/user/types"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// MaxFailedLoginAttempts is the number of failed logins after which a local user is locked out
+	MaxFailedLoginAttempts = 10
+	MinPasswordLength      = 6
+)
+
 var (
-	loginMutex         sync.Mutex
-	ErrInvalidPassword = errors.New("invalid password")
-	ErrTooManyAttempts = errors.New("too many attempts")
+	loginMutex                sync.Mutex
+	ErrInvalidPassword        = errors.New("invalid password")
+	ErrTooManyAttempts        = errors.New("too many attempts")
+	ErrPasswordChangeRequired = errors.New("password change required")
+
+	usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
 )
 
 func LogIn(password string) (*usertypes.User, error) {
@@ -32,7 +43,7 @@ func LogIn(password string) (*usertypes.User, error) {
 
 	if err := bcrypt.CompareHashAndPassword(shaBytes, []byte(password)); err != nil {
 		if err == bcrypt.ErrMismatchedHashAndPassword {
-			if err := store.GetStore().FlagInvalidPassword(); err != nil {
+			if err := store.GetStore().FlagInvalidPassword(""); err != nil {
 				logger.Infof("failed to flag failed login: %v", err)
 			}
 			return nil, ErrInvalidPassword
@@ -41,11 +52,136 @@ func LogIn(password string) (*usertypes.User, error) {
 		return nil, errors.Wrap(err, "failed to compare password")
 	}
 
-	if err := store.GetStore().FlagSuccessfulLogin(); err != nil {
+	if err := store.GetStore().FlagSuccessfulLogin(""); err != nil {
 		logger.Error(errors.Wrap(err, "failed to flag successful login"))
 	}
 
 	return &usertypes.User{
-		ID: "000000",
+		ID: usertypes.SharedPasswordUserID,
 	}, nil
 }
+
+// LogInLocalUser returns ErrPasswordChangeRequired if the password is correct but must be changed
+// with ChangeLocalUserPassword before a session can be created
+func LogInLocalUser(username string, password string) (*usertypes.User, []string, error) {
+	loginMutex.Lock()
+	defer loginMutex.Unlock()
+
+	// a local user created with the reserved id before it was reserved must not share the identity of the shared password
+	if username == usertypes.SharedPasswordUserID {
+		return nil, nil, ErrInvalidPassword
+	}
+
+	localUser, err := checkLocalUserPassword(username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if localUser.MustChangePassword {
+		return nil, nil, ErrPasswordChangeRequired
+	}
+
+	if err := store.GetStore().FlagSuccessfulLogin(username); err != nil {
+		logger.Error(errors.Wrap(err, "failed to flag successful login"))
+	}
+
+	return &usertypes.User{
+		ID: localUser.Username,
+	}, localUser.RoleIDs, nil
+}
+
+// ChangeLocalUserPassword replaces the password of a local user after checking the current one.
+// This is also how users set their own password after an admin creates the account.
+func ChangeLocalUserPassword(username string, currentPassword string, newPassword string) error {
+	loginMutex.Lock()
+	defer loginMutex.Unlock()
+
+	if _, err := checkLocalUserPassword(username, currentPassword); err != nil {
+		return err
+	}
+
+	if err := ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	if newPassword == currentPassword {
+		return errors.New("new password must be different from the current password")
+	}
+
+	passwordBcrypt, err := bcrypt.GenerateFromPassword([]byte(newPassword), 10)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate password hash")
+	}
+
+	if err := store.GetStore().SetLocalUserPassword(username, passwordBcrypt, false); err != nil {
+		return errors.Wrap(err, "failed to set password")
+	}
+
+	return nil
+}
+
+func checkLocalUserPassword(username string, password string) (*usertypes.LocalUser, error) {
+	localUser, err := store.GetStore().GetLocalUser(username)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			// don't reveal which usernames exist
+			return nil, ErrInvalidPassword
+		}
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+
+	if localUser.FailedLoginCount > MaxFailedLoginAttempts {
+		return nil, ErrTooManyAttempts
+	}
+
+	if err := bcrypt.CompareHashAndPassword(localUser.PasswordBcrypt, []byte(password)); err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			if err := store.GetStore().FlagInvalidPassword(username); err != nil {
+				logger.Infof("failed to flag failed login: %v", err)
+			}
+			return nil, ErrInvalidPassword
+		}
+
+		return nil, errors.Wrap(err, "failed to compare password")
+	}
+
+	return localUser, nil
+}
+
+func ValidateUsername(username string) error {
+	if !usernameRegex.MatchString(username) {
+		return errors.New("username must start with a letter or digit and contain only letters, digits, '.', '_' and '-'")
+	}
+	if username == usertypes.SharedPasswordUserID {
+		return errors.Errorf("username %q is reserved", username)
+	}
+	return nil
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return errors.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	return nil
+}
+
+// ValidateRoleIDs returns an error if roleIDs is empty or contains a role that is not defined
+func ValidateRoleIDs(roleIDs []string, roles []rbactypes.Role) error {
+	if len(roleIDs) == 0 {
+		return errors.New("at least one role is required")
+	}
+
+	for _, roleID := range roleIDs {
+		found := false
+		for _, role := range roles {
+			if role.ID == roleID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("unknown role %q", roleID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		wantErr  bool
+	}{
+		{username: "alice", wantErr: false},
+		{username: "alice.smith_2-x", wantErr: false},
+		{username: "", wantErr: true},
+		{username: "-alice", wantErr: true},
+		{username: "alice smith", wantErr: true},
+		{username: "alice/../bob", wantErr: true},
+		{username: "000000", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.username, func(t *testing.T) {
+			err := ValidateUsername(test.username)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRoleIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		roleIDs []string
+		wantErr bool
+	}{
+		{
+			name:    "known roles",
+			roleIDs: []string{rbac.ClusterAdminRoleID, "support"},
+			wantErr: false,
+		},
+		{
+			name:    "no roles",
+			roleIDs: []string{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			roleIDs: []string{"support", "superuser"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateRoleIDs(test.roleIDs, rbac.DefaultRoles())
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package version
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,9 +12,8 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/logger"
-	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/util"
-	"k8s.io/client-go/kubernetes/scheme"
 )
 
 type MetricChart struct {
@@ -84,30 +82,17 @@ func GetMetricCharts(appID string, sequence int64, prometheusAddress string) ([]
 		return []MetricChart{}, nil
 	}
 
-	db := persistence.MustGetDBSession()
-	query := `select kots_app_spec from app_version where app_id = $1 and sequence = $2`
-	row := db.QueryRow(query, appID, sequence)
-
-	var kotsAppSpecStr sql.NullString
-	if err := row.Scan(&kotsAppSpecStr); err != nil {
-		if err == sql.ErrNoRows {
+	appVersion, err := store.GetStore().GetAppVersion(appID, sequence)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
 			return []MetricChart{}, nil
 		}
-		return nil, errors.Wrap(err, "failed to scan")
+		return nil, errors.Wrap(err, "failed to get app version")
 	}
 
 	graphs := DefaultMetricGraphs
-	if kotsAppSpecStr.Valid {
-		decode := scheme.Codecs.UniversalDeserializer().Decode
-		obj, _, err := decode([]byte(kotsAppSpecStr.String), nil, nil)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to decode kots app spec")
-		}
-		a := obj.(*kotsv1beta1.Application)
-
-		if len(a.Spec.Graphs) > 0 {
-			graphs = a.Spec.Graphs
-		}
+	if appVersion.KOTSKinds != nil && len(appVersion.KOTSKinds.KotsApplication.Spec.Graphs) > 0 {
+		graphs = appVersion.KOTSKinds.KotsApplication.Spec.Graphs
 	}
 
 	endTime := uint(time.Now().Unix())
@@ -231,3 +216,56 @@ func prometheusQueryRange(address string, query string, start uint, end uint, st
 
 	return response.Data.Result, nil
 }
+
+type Sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+// PrometheusQuery runs an instant query and returns the resulting vector
+func PrometheusQuery(address string, query string) ([]Sample, error) {
+	host := fmt.Sprintf("%s/api/v1/query", address)
+
+	v := url.Values{}
+	v.Set("query", query)
+
+	uri := host + "?" + v.Encode()
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to do req")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	type ResponseData struct {
+		Result     []Sample `json:"result"`
+		ResultType string   `json:"resultType"`
+	}
+	type Response struct {
+		Data ResponseData `json:"data"`
+	}
+	var response Response
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal response body")
+	}
+
+	if response.Data.ResultType != "vector" {
+		return nil, errors.Errorf("unexpected result type %s", response.Data.ResultType)
+	}
+
+	return response.Data.Result, nil
+}
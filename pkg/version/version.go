@@ -2,84 +2,74 @@ package version
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"time"
+	"sort"
 
 	"github.com/pkg/errors"
-	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/api/version/types"
 	"github.com/replicatedhq/kots/pkg/gitops"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
-	"github.com/replicatedhq/kots/pkg/persistence"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/util"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes/scheme"
-	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 )
 
 // GetNextAppSequence determines next available sequence for this app
 // we shouldn't assume that a.CurrentSequence is accurate. Returns 0 if currentSequence is nil
 func GetNextAppSequence(appID string, currentSequence *int64) (int64, error) {
-	newSequence := 0
-	if currentSequence != nil {
-		db := persistence.MustGetDBSession()
-		row := db.QueryRow(`select max(sequence) from app_version where app_id = $1`, appID)
-		if err := row.Scan(&newSequence); err != nil {
-			return 0, errors.Wrap(err, "failed to find current max sequence in row")
-		}
-		newSequence++
+	if currentSequence == nil {
+		return 0, nil
+	}
+
+	newSequence, err := store.GetStore().GetNextAppSequence(appID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get next app sequence")
 	}
-	return int64(newSequence), nil
+	return newSequence, nil
 }
 
 type DownstreamGitOps struct {
 }
 
-func (d *DownstreamGitOps) CreateGitOpsDownstreamCommit(appID string, clusterID string, newSequence int, filesInDir string, downstreamName string) (string, error) {
+func (d *DownstreamGitOps) CreateGitOpsDownstreamCommit(appID string, clusterID string, newSequence int, filesInDir string, downstreamName string) (string, bool, error) {
 	downstreamGitOps, err := gitops.GetDownstreamGitOps(appID, clusterID)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get downstream gitops")
+		return "", false, errors.Wrap(err, "failed to get downstream gitops")
 	}
 	if downstreamGitOps == nil {
-		return "", nil
+		return "", false, nil
 	}
 
 	a, err := store.GetStore().GetApp(appID)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get app")
+		return "", false, errors.Wrap(err, "failed to get app")
 	}
 	createdCommitURL, err := gitops.CreateGitOpsCommit(downstreamGitOps, a.Slug, a.Name, int(newSequence), filesInDir, downstreamName)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create gitops commit")
+		return "", false, errors.Wrap(err, "failed to create gitops commit")
 	}
 
-	return createdCommitURL, nil
+	return createdCommitURL, downstreamGitOps.Action == gitops.ActionPullRequest, nil
 }
 
 // return the list of versions available for an app
 func GetVersions(appID string) ([]types.AppVersion, error) {
-	db := persistence.MustGetDBSession()
-	query := `select sequence from app_version where app_id = $1 order by sequence asc`
-	rows, err := db.Query(query, appID)
+	appVersions, err := store.GetStore().GetAppVersionsAfter(appID, -1)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to query app_version table")
+		return nil, errors.Wrap(err, "failed to list app versions")
 	}
-	defer rows.Close()
 
-	versions := []types.AppVersion{}
-	for rows.Next() {
-		var sequence int64
-		if err := rows.Scan(&sequence); err != nil {
-			return nil, errors.Wrap(err, "failed to scan sequence from app_version table")
-		}
+	sort.Slice(appVersions, func(i, j int) bool {
+		return appVersions[i].Sequence < appVersions[j].Sequence
+	})
 
-		v, err := store.GetStore().GetAppVersion(appID, sequence)
+	versions := []types.AppVersion{}
+	for _, appVersion := range appVersions {
+		v, err := store.GetStore().GetAppVersion(appID, appVersion.Sequence)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get version")
 		}
@@ -93,63 +83,28 @@ func GetVersions(appID string) ([]types.AppVersion, error) {
 
 // DeployVersion deploys the version for the given sequence
 func DeployVersion(appID string, sequence int64) error {
-	db := persistence.MustGetDBSession()
-
-	tx, err := db.Begin()
-	if err != nil {
-		return errors.Wrap(err, "failed to begin")
-	}
-	defer tx.Rollback()
-
-	query := `update app_downstream set current_sequence = $1 where app_id = $2`
-	_, err = tx.Exec(query, sequence, appID)
-	if err != nil {
-		return errors.Wrap(err, "failed to update app downstream current sequence")
-	}
-
-	query = `update app_downstream_version set status = 'deployed', applied_at = $3 where sequence = $1 and app_id = $2`
-	_, err = tx.Exec(query, sequence, appID, time.Now())
-	if err != nil {
-		return errors.Wrap(err, "failed to update app downstream version status")
-	}
-
-	if err := tx.Commit(); err != nil {
-		return errors.Wrap(err, "failed to commit")
+	if err := store.GetStore().MarkAsCurrentDownstreamVersion(appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to mark as current downstream version")
 	}
 
 	return nil
 }
 
 func GetRealizedLinksFromAppSpec(appID string, sequence int64) ([]types.RealizedLink, error) {
-	db := persistence.MustGetDBSession()
-	query := `select app_spec, kots_app_spec from app_version where app_id = $1 and sequence = $2`
-	row := db.QueryRow(query, appID, sequence)
-
-	var appSpecStr sql.NullString
-	var kotsAppSpecStr sql.NullString
-	if err := row.Scan(&appSpecStr, &kotsAppSpecStr); err != nil {
-		if err == sql.ErrNoRows {
+	appVersion, err := store.GetStore().GetAppVersion(appID, sequence)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
 			return []types.RealizedLink{}, nil
 		}
-		return nil, errors.Wrap(err, "failed to scan")
+		return nil, errors.Wrap(err, "failed to get app version")
 	}
 
-	if appSpecStr.String == "" {
+	if appVersion.KOTSKinds == nil || appVersion.KOTSKinds.Application == nil {
 		return []types.RealizedLink{}, nil
 	}
 
-	decode := scheme.Codecs.UniversalDeserializer().Decode
-	obj, _, err := decode([]byte(appSpecStr.String), nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode app spec yaml")
-	}
-	appSpec := obj.(*applicationv1beta1.Application)
-
-	obj, _, err = decode([]byte(kotsAppSpecStr.String), nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode kots app spec yaml")
-	}
-	kotsAppSpec := obj.(*kotsv1beta1.Application)
+	appSpec := appVersion.KOTSKinds.Application
+	kotsAppSpec := appVersion.KOTSKinds.KotsApplication
 
 	realizedLinks := []types.RealizedLink{}
 	for _, link := range appSpec.Spec.Descriptor.Links {
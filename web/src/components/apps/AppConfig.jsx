@@ -249,6 +249,8 @@ class AppConfig extends Component {
         if (!result.success) {
           if (result.requiredItems?.length) {
             this.markRequiredItems(result.requiredItems);
+          } else if (result.invalidItems?.length) {
+            this.updateUrlWithErrorId(result.invalidItems);
           }
           if (result.error) {
             this.setState({ configError: result.error });
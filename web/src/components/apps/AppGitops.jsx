@@ -34,6 +34,10 @@ const SERVICES = [
     value: "bitbucket_server",
     label: "Bitbucket Server",
   },
+  {
+    value: "generic",
+    label: "Generic Git",
+  },
   // {
   //   value: "other",
   //   label: "Other",
@@ -64,6 +68,10 @@ class AppGitops extends Component {
       return "";
     }
 
+    if (gitops?.provider === "generic") {
+      return gitops.uri;
+    }
+
     let ownerRepo = "";
     const parsed = url.parse(gitops?.uri);
     if (gitops?.provider === "bitbucket_server") {
@@ -156,7 +164,10 @@ class AppGitops extends Component {
       path,
       otherService,
       action,
-      format
+      format,
+      username,
+      token,
+      signingKey
     } = repoDetails;
 
     const { app } = this.props;
@@ -189,12 +200,22 @@ class AppGitops extends Component {
     if (provider === "other") {
       gitOpsInput.otherServiceName = otherService;
     }
+    if (username) {
+      gitOpsInput.username = username;
+    }
+    if (token) {
+      gitOpsInput.token = token;
+    }
+    if (signingKey) {
+      gitOpsInput.signingKey = signingKey;
+    }
 
     this.setState({ errorMsg: "" });
 
     try {
       const oldUri = gitops?.uri;
-      if (newUri !== oldUri) {
+      const usernameChanged = (username || "") !== (gitops?.username || "");
+      if (newUri !== oldUri || token || signingKey || usernameChanged) {
         await this.createGitOpsRepo(gitOpsInput);
       }
       await this.updateAppGitOps(app.id, clusterId, gitOpsInput);
@@ -374,6 +395,7 @@ class AppGitops extends Component {
               path={gitops?.path}
               format={gitops?.format}
               action={gitops?.action}
+              username={gitops?.username}
               selectedService={selectedService}
               onFinishSetup={this.finishGitOpsSetup}
               showCancelBtn={!!ownerRepo}
@@ -408,6 +430,7 @@ class AppGitops extends Component {
                   path={gitops?.path}
                   format={gitops?.format}
                   action={gitops?.action}
+                  username={gitops?.username}
                   selectedService={selectedService}
                   onFinishSetup={this.finishGitOpsSetup}
                   otherService=""
@@ -436,8 +459,14 @@ class AppGitops extends Component {
                     Deployment key
                   </p>
                   <p className="u-fontSize--normal u-fontWeight--normal u-textColor--bodyCopy u-marginBottom--15">
-                    Copy this deploy key to the
-                    <a className="replicated-link" href={addKeyUri} target="_blank" rel="noopener noreferrer"> repo settings page.</a>
+                    {addKeyUri ?
+                      <span>
+                        Copy this deploy key to the
+                        <a className="replicated-link" href={addKeyUri} target="_blank" rel="noopener noreferrer"> repo settings page.</a>
+                      </span>
+                      :
+                      "Add this deploy key with write access to the repository on your git server."
+                    }
                   </p>
                   <CodeSnippet
                     canCopy={true}
@@ -52,6 +52,10 @@ const SERVICES = [
     value: "bitbucket_server",
     label: "Bitbucket Server",
   },
+  {
+    value: "generic",
+    label: "Generic Git",
+  },
   // {
   //   value: "other",
   //   label: "Other",
@@ -214,7 +218,10 @@ class GitOpsDeploymentManager extends React.Component {
       branch = "",
       path = "",
       action = "commit",
-      format = "single"
+      format = "single",
+      username = "",
+      token = "",
+      signingKey = ""
     } = repoDetails;
 
     const {
@@ -228,6 +235,15 @@ class GitOpsDeploymentManager extends React.Component {
     const provider = selectedService.value;
     const repoUri = getGitOpsUri(provider, ownerRepo, hostname, httpPort);
     const gitOpsInput = this.getGitOpsInput(provider, repoUri, branch, path, format, action, hostname, httpPort, sshPort);
+    if (username) {
+      gitOpsInput.username = username;
+    }
+    if (token) {
+      gitOpsInput.token = token;
+    }
+    if (signingKey) {
+      gitOpsInput.signingKey = signingKey;
+    }
 
     try {
       if (this.state.gitops?.enabled && this.providerChanged()) {
@@ -226,6 +226,8 @@ export function getGitOpsUri(provider, ownerRepo, hostname = "", httpPort = "")
       return `https://bitbucket.org/${ownerRepo}`;
     case "bitbucket_server":
       return `https://${hostname}:${httpPort}/projects/${owner}/repos/${repo}`;
+    case "generic":
+      return ownerRepo;
     default:
       return `https://github.com/${ownerRepo}`;
   }
@@ -245,6 +247,8 @@ export function getGitOpsServiceSite(provider, hostname = "") {
       return "bitbucket.org";
     case "bitbucket_server":
       return hostname;
+    case "generic":
+      return "your git server";
     default:
       return "github.com";
   }
@@ -259,6 +263,10 @@ export function getAddKeyUri(gitops, ownerRepo) {
   const isBitbucket = provider === "bitbucket";
   const isBitbucketServer = provider === "bitbucket_server";
 
+  if (provider === "generic") {
+    return "";
+  }
+
   let addKeyUri = `${gitUri}/settings/keys/new`;
   if (isGitlab) {
     addKeyUri = `${gitUri}/-/settings/repository`;